@@ -2,6 +2,8 @@ package bun
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 
 	"github.com/uptrace/bun/internal"
 	"github.com/uptrace/bun/schema"
@@ -12,9 +14,11 @@ type (
 	Ident = schema.Ident
 	Name  = schema.Name
 
-	NullTime  = schema.NullTime
-	BaseModel = schema.BaseModel
-	Query     = schema.Query
+	NullTime       = schema.NullTime
+	EncryptedField = schema.EncryptedField
+	ULID           = schema.ULID
+	BaseModel      = schema.BaseModel
+	Query          = schema.Query
 
 	BeforeAppendModelHook = schema.BeforeAppendModelHook
 
@@ -26,6 +30,44 @@ func SafeQuery(query string, args ...interface{}) schema.QueryWithArgs {
 	return schema.SafeQuery(query, args)
 }
 
+// Check returns a CHECK table constraint for the TableChecks method of a model.
+func Check(name, expr string) schema.CheckDef {
+	return schema.CheckDef{Name: name, Expr: expr}
+}
+
+// colTables resolves the column names for Col. Column names do not depend on the dialect.
+var colTables = schema.NewTables(schema.NewNopFormatter().Dialect())
+
+// Col returns the column name of the model field with the Go name fieldName.
+// It panics if the model does not have the field, so the column names declared as
+// package-level variables are checked at startup:
+//
+//	var userEmail = bun.Col((*User)(nil), "Email")
+//
+//	err := db.NewSelect().Model(&users).Where("? = ?", userEmail, email).Scan(ctx)
+func Col(model interface{}, fieldName string) Ident {
+	typ := reflect.TypeOf(model)
+	for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice) {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		panic(fmt.Errorf("bun: Col(unsupported %T)", model))
+	}
+
+	table := colTables.Get(typ)
+	for _, f := range table.Fields {
+		if f.GoName == fieldName {
+			return Ident(f.Name)
+		}
+	}
+	panic(fmt.Errorf("bun: %s does not have field %s", table.TypeName, fieldName))
+}
+
+// NewULID returns a new ULID. See schema.NewULID.
+func NewULID() ULID {
+	return schema.NewULID()
+}
+
 type BeforeSelectHook interface {
 	BeforeSelect(ctx context.Context, query *SelectQuery) error
 }
@@ -0,0 +1,337 @@
+package bun
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheBackend stores cached query results, for example, in memory or in Redis.
+type CacheBackend interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, val []byte, ttl time.Duration)
+}
+
+const cacheKeyPrefix = "bun:cache:"
+
+type cacheHook struct {
+	backend CacheBackend
+	ttl     time.Duration
+}
+
+var _ QueryHook = (*cacheHook)(nil)
+
+// NewCacheHook returns a query hook that caches the results of SELECT queries
+// for the ttl. Cached results are scanned into the model without executing the query.
+//
+// The cache key is the query fingerprint and the query parameters. INSERT, UPDATE,
+// DELETE and other queries that change the data invalidate the cached results of the
+// queries that select from the same table. Raw queries that change the data invalidate
+// all cached results. Queries executed in transactions are not cached, and changes
+// made in transactions invalidate the cached results again after the commit.
+// DBs that use RLSHook or WithConnectionInitFunc don't cache results, because
+// the selected rows can depend on the session settings.
+//
+// Only the queries executed with Scan and Exec of the query builders are cached.
+func NewCacheHook(backend CacheBackend, ttl time.Duration) QueryHook {
+	return &cacheHook{
+		backend: backend,
+		ttl:     ttl,
+	}
+}
+
+func (h *cacheHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	if strings.ToUpper(event.Operation()) != "SELECT" || isTxConn(event.conn) ||
+		event.DB.HasSessionSetup(ctx) {
+		if queryCacheFromContext(ctx) != nil {
+			// Don't use the cache of the query that started this one, for example, in a model hook.
+			return context.WithValue(ctx, queryCacheKey{}, (*queryCache)(nil))
+		}
+		return ctx
+	}
+
+	key := h.key(ctx, event)
+	cache := &queryCache{
+		store: func(res *cachedResult) {
+			if b, err := res.marshal(); err == nil {
+				h.backend.Set(key, b, h.ttl)
+			}
+		},
+	}
+	if b, ok := h.backend.Get(key); ok {
+		res := new(cachedResult)
+		if err := res.unmarshal(b); err == nil {
+			cache.result = res
+		}
+	}
+	return context.WithValue(ctx, queryCacheKey{}, cache)
+}
+
+func (h *cacheHook) AfterQuery(ctx context.Context, event *QueryEvent) {
+	if event.Err != nil || !isMutation(event.Operation()) {
+		return
+	}
+
+	table := cacheTableName(event.IQuery)
+	h.invalidate(table)
+	if event.Tx != nil {
+		// Queries outside of the transaction can cache the old data until it is committed.
+		event.Tx.OnCommit(func() {
+			h.invalidate(table)
+		})
+	}
+}
+
+// invalidate changes the version of the table or of all tables if the table is empty.
+func (h *cacheHook) invalidate(table string) {
+	version := []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+	if table != "" {
+		h.backend.Set(cacheKeyPrefix+"version:"+table, version, h.ttl)
+	} else {
+		h.backend.Set(cacheKeyPrefix+"version", version, h.ttl)
+	}
+}
+
+// key returns the cache key that includes the versions of the tables, so changing
+// the table invalidates the key. Versions expire with the cached results.
+// The key also includes the search path, because it changes the tables the query reads.
+func (h *cacheHook) key(ctx context.Context, event *QueryEvent) string {
+	hash := sha256.New()
+	_, _ = io.WriteString(hash, event.Fingerprint())
+	for _, s := range []string{
+		event.Query,
+		strings.Join(SearchPathFromContext(ctx), ","),
+		h.version(""),
+		h.version(cacheTableName(event.IQuery)),
+	} {
+		_, _ = hash.Write([]byte{0})
+		_, _ = io.WriteString(hash, s)
+	}
+	if q, ok := event.IQuery.(*SelectQuery); ok && q.tableModel != nil {
+		for _, j := range q.tableModel.getJoins() {
+			_, _ = hash.Write([]byte{0})
+			_, _ = io.WriteString(hash, h.version(j.JoinModel.Table().Name))
+		}
+	}
+	return cacheKeyPrefix + hex.EncodeToString(hash.Sum(nil))
+}
+
+func (h *cacheHook) version(table string) string {
+	key := cacheKeyPrefix + "version"
+	if table != "" {
+		key += ":" + table
+	}
+	b, _ := h.backend.Get(key)
+	return string(b)
+}
+
+func cacheTableName(q Query) string {
+	if q == nil {
+		return ""
+	}
+	return strings.Trim(q.GetTableName(), "\"`[]")
+}
+
+func isMutation(operation string) bool {
+	operation = strings.ToUpper(operation)
+	for _, prefix := range []string{
+		"INSERT", "UPDATE", "DELETE", "MERGE", "REPLACE", "TRUNCATE", "DROP", "ALTER",
+	} {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTxConn(conn IConn) bool {
+	switch conn.(type) {
+	case Tx, *Tx, *sql.Tx:
+		return true
+	default:
+		return false
+	}
+}
+
+//------------------------------------------------------------------------------
+
+type queryCacheKey struct{}
+
+// queryCache is set on the context by the cache hook. If the result is cached,
+// the query is not executed and the rows are read from the cache.
+type queryCache struct {
+	result *cachedResult
+	store  func(*cachedResult)
+}
+
+func queryCacheFromContext(ctx context.Context) *queryCache {
+	cache, _ := ctx.Value(queryCacheKey{}).(*queryCache)
+	return cache
+}
+
+// conn returns the conn that reads the cached rows or caches the rows read from the conn.
+func (c *queryCache) conn(conn IConn) IConn {
+	return &cacheConn{IConn: conn, cache: c}
+}
+
+type cacheConn struct {
+	IConn
+	cache *queryCache
+}
+
+func (c *cacheConn) QueryContext(
+	ctx context.Context, query string, args ...interface{},
+) (*sql.Rows, error) {
+	if c.cache.result == nil {
+		rows, err := c.IConn.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		res, err := readCachedResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		c.cache.result = res
+		c.cache.store(res)
+	}
+	return cachedResultDB().QueryContext(ctx, "", c.cache.result)
+}
+
+//------------------------------------------------------------------------------
+
+type cachedResult struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+func readCachedResult(rows *sql.Rows) (*cachedResult, error) {
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &cachedResult{Columns: columns}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		res.Rows = append(res.Rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func init() {
+	gob.Register(time.Time{})
+}
+
+func (res *cachedResult) marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(res); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (res *cachedResult) unmarshal(b []byte) error {
+	return gob.NewDecoder(bytes.NewReader(b)).Decode(res)
+}
+
+//------------------------------------------------------------------------------
+
+var cachedResultDBOnce struct {
+	sync.Once
+	db *sql.DB
+}
+
+// cachedResultDB returns a database that returns the cached result passed
+// as the query argument, so models can scan it like the rows read from the database.
+func cachedResultDB() *sql.DB {
+	cachedResultDBOnce.Do(func() {
+		cachedResultDBOnce.db = sql.OpenDB(cachedResultConnector{})
+	})
+	return cachedResultDBOnce.db
+}
+
+type cachedResultConnector struct{}
+
+func (cachedResultConnector) Connect(context.Context) (driver.Conn, error) {
+	return cachedResultConn{}, nil
+}
+
+func (cachedResultConnector) Driver() driver.Driver {
+	return cachedResultDriver{}
+}
+
+type cachedResultDriver struct{}
+
+func (cachedResultDriver) Open(string) (driver.Conn, error) {
+	return cachedResultConn{}, nil
+}
+
+var errCachedResultConn = errors.New("bun: cached result conn only supports queries")
+
+type cachedResultConn struct{}
+
+var (
+	_ driver.QueryerContext    = cachedResultConn{}
+	_ driver.NamedValueChecker = cachedResultConn{}
+)
+
+func (cachedResultConn) Prepare(string) (driver.Stmt, error) { return nil, errCachedResultConn }
+func (cachedResultConn) Close() error                        { return nil }
+func (cachedResultConn) Begin() (driver.Tx, error)           { return nil, errCachedResultConn }
+
+// CheckNamedValue passes the cached result to QueryContext as is.
+func (cachedResultConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (cachedResultConn) QueryContext(
+	ctx context.Context, query string, args []driver.NamedValue,
+) (driver.Rows, error) {
+	if len(args) != 1 {
+		return nil, errCachedResultConn
+	}
+	res, ok := args[0].Value.(*cachedResult)
+	if !ok {
+		return nil, errCachedResultConn
+	}
+	return &cachedRows{res: res}, nil
+}
+
+type cachedRows struct {
+	res *cachedResult
+	pos int
+}
+
+func (r *cachedRows) Columns() []string { return r.res.Columns }
+func (r *cachedRows) Close() error      { return nil }
+
+func (r *cachedRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.res.Rows) {
+		return io.EOF
+	}
+	for i, v := range r.res.Rows[r.pos] {
+		dest[i] = v
+	}
+	r.pos++
+	return nil
+}
@@ -0,0 +1,485 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+
+	"github.com/uptrace/bun/dialect"
+)
+
+// NewConnector wraps the connector, so bun can track the session state of each connection
+// in the pool and only execute the statements required by WithConnectionInitSQL,
+// WithSearchPath, and RLSHook when the connection is not in the expected state:
+//
+//	connector := bun.NewConnector(pgdriver.NewConnector(pgdriver.WithDSN(dsn)))
+//	db := bun.NewDB(sql.OpenDB(connector), pgdialect.New())
+//
+// Before a connection is used, the state it has is compared with the state expected
+// by the query. Connections whose state can't be changed in place are reset with
+// RESET ROLE and RESET ALL on PostgreSQL and are closed on other databases.
+//
+// sql.DB.Driver returns a wrapper of the driver and the driver connections passed to
+// sql.Conn.Raw are wrapped too. Use Conn.Raw to access the driver connection.
+func NewConnector(connector driver.Connector) driver.Connector {
+	c := &trackingConnector{Connector: connector}
+	c.driver = &trackingDriver{Driver: connector.Driver(), connector: c}
+	return c
+}
+
+type trackingConnector struct {
+	driver.Connector
+	driver *trackingDriver
+
+	mu           sync.RWMutex
+	resetQueries []string
+}
+
+var _ driver.Connector = (*trackingConnector)(nil)
+
+func (c *trackingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &trackedConn{Conn: conn, connector: c}, nil
+}
+
+func (c *trackingConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func (c *trackingConnector) setResetQueries(queries []string) {
+	c.mu.Lock()
+	c.resetQueries = queries
+	c.mu.Unlock()
+}
+
+func (c *trackingConnector) getResetQueries() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.resetQueries
+}
+
+type trackingDriver struct {
+	driver.Driver
+	connector *trackingConnector
+}
+
+func (d *trackingDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &trackedConn{Conn: conn, connector: d.connector}, nil
+}
+
+type connStateKey struct{}
+
+// connState is the session state a query expects from the connection.
+type connState struct {
+	init       string
+	searchPath string
+	// owner is the query that changes the session settings, see claimSession.
+	owner interface{}
+}
+
+// trackedConn is a driver connection that knows its session state. database/sql
+// does not use driver connections concurrently, so the state is not locked.
+type trackedConn struct {
+	driver.Conn
+	connector *trackingConnector
+
+	init       string
+	searchPath string
+	// owner is the query that changed the session settings, for example,
+	// with RLSHook outside transactions, or nil.
+	owner interface{}
+	inTx  bool
+}
+
+var (
+	_ driver.ConnBeginTx        = (*trackedConn)(nil)
+	_ driver.ConnPrepareContext = (*trackedConn)(nil)
+	_ driver.ExecerContext      = (*trackedConn)(nil)
+	_ driver.QueryerContext     = (*trackedConn)(nil)
+	_ driver.Pinger             = (*trackedConn)(nil)
+	_ driver.SessionResetter    = (*trackedConn)(nil)
+	_ driver.Validator          = (*trackedConn)(nil)
+	_ driver.NamedValueChecker  = (*trackedConn)(nil)
+)
+
+// sync changes the session state to the state expected by the context.
+// Transactions keep the state they were started with.
+func (c *trackedConn) sync(ctx context.Context) error {
+	if c.inTx {
+		return nil
+	}
+
+	var want connState
+	if state, ok := ctx.Value(connStateKey{}).(*connState); ok {
+		want = *state
+	}
+
+	dirty := c.owner != nil && c.owner != want.owner
+	if dirty || c.init != want.init {
+		if dirty || c.init != "" {
+			if err := c.reset(ctx); err != nil {
+				return err
+			}
+		}
+		if want.init != "" {
+			if err := execConn(ctx, c.Conn, want.init); err != nil {
+				return err
+			}
+			c.init = want.init
+		}
+	}
+
+	if c.searchPath != want.searchPath {
+		query := "RESET search_path"
+		if want.searchPath != "" {
+			query = "SET search_path TO " + want.searchPath
+		}
+		if err := execConn(ctx, c.Conn, query); err != nil {
+			return err
+		}
+		c.searchPath = want.searchPath
+	}
+
+	return nil
+}
+
+// reset resets the session or, if the database can't reset sessions,
+// returns driver.ErrBadConn, so database/sql closes the connection.
+func (c *trackedConn) reset(ctx context.Context) error {
+	queries := c.connector.getResetQueries()
+	if len(queries) == 0 {
+		return driver.ErrBadConn
+	}
+	for _, query := range queries {
+		if err := execConn(ctx, c.Conn, query); err != nil {
+			return err
+		}
+	}
+	c.init = ""
+	c.searchPath = ""
+	c.owner = nil
+	return nil
+}
+
+func (c *trackedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *trackedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := c.sync(ctx); err != nil {
+		return nil, err
+	}
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *trackedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *trackedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.sync(ctx); err != nil {
+		return nil, err
+	}
+
+	var tx driver.Tx
+	var err error
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = beginner.BeginTx(ctx, opts)
+	} else {
+		tx, err = c.Conn.Begin() //nolint:staticcheck
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.inTx = true
+	return &trackedTx{Tx: tx, conn: c}, nil
+}
+
+func (c *trackedConn) ExecContext(
+	ctx context.Context, query string, args []driver.NamedValue,
+) (driver.Result, error) {
+	if err := c.sync(ctx); err != nil {
+		return nil, err
+	}
+	if execer, ok := c.Conn.(driver.ExecerContext); ok {
+		return execer.ExecContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *trackedConn) QueryContext(
+	ctx context.Context, query string, args []driver.NamedValue,
+) (driver.Rows, error) {
+	if err := c.sync(ctx); err != nil {
+		return nil, err
+	}
+	if queryer, ok := c.Conn.(driver.QueryerContext); ok {
+		return queryer.QueryContext(ctx, query, args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c *trackedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession is called with the context of the next query before the connection
+// is reused, so it prepares the session for the query.
+func (c *trackedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		if err := resetter.ResetSession(ctx); err != nil {
+			return err
+		}
+	}
+	return c.sync(ctx)
+}
+
+func (c *trackedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+func (c *trackedConn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
+
+type trackedTx struct {
+	driver.Tx
+	conn *trackedConn
+}
+
+func (tx *trackedTx) Commit() error {
+	tx.conn.inTx = false
+	return tx.Tx.Commit()
+}
+
+func (tx *trackedTx) Rollback() error {
+	tx.conn.inTx = false
+	return tx.Tx.Rollback()
+}
+
+func execConn(ctx context.Context, conn driver.Conn, query string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, query, nil)
+		if !errors.Is(err, driver.ErrSkip) {
+			return err
+		}
+	}
+
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = conn.Prepare(query)
+	}
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	if execer, ok := stmt.(driver.StmtExecContext); ok {
+		_, err = execer.ExecContext(ctx, nil)
+	} else {
+		_, err = stmt.Exec(nil) //nolint:staticcheck
+	}
+	return err
+}
+
+//------------------------------------------------------------------------------
+
+var errNoConnector = errors.New(
+	"bun: WithConnectionInitSQL requires a connection pool opened with bun.NewConnector")
+
+// WithConnectionInitSQL returns a copy of the DB that executes the query on the connections
+// before they are used by the queries of the DB, for example,
+//
+//	db := db.WithConnectionInitSQL("SET search_path = tenant1, public")
+//
+// See WithConnectionInitFunc for details.
+func (db *DB) WithConnectionInitSQL(query string) *DB {
+	return db.WithConnectionInitFunc(func(context.Context) string {
+		return query
+	})
+}
+
+// WithConnectionInitFunc is like WithConnectionInitSQL, but the query is built by fn
+// from the context of the query, so it can depend on the request. Empty queries are not executed.
+//
+// The pool must be opened with NewConnector, which tracks the init query executed on each
+// connection. The init query is executed once on each connection as long as the connection
+// is used by the queries that expect the same init query. Before the connection is used
+// by other queries, including the queries of the original DB, it is reset, so the effects
+// of the init query don't leak to them. Queries fail with an error if the pool
+// is not opened with NewConnector.
+func (db *DB) WithConnectionInitFunc(fn func(ctx context.Context) string) *DB {
+	clone := db.clone()
+	clone.connInitFn = fn
+	return clone
+}
+
+// initTracking enables the session tracking if the pool is opened with NewConnector.
+func (db *DB) initTracking() {
+	if db.DB == nil {
+		// The DB is only used to format queries.
+		return
+	}
+	d, ok := db.DB.Driver().(*trackingDriver)
+	if !ok {
+		return
+	}
+	db.tracked = true
+	d.connector.setResetQueries(db.resetQueries())
+}
+
+// resetQueries returns the queries that reset the session settings,
+// or nil if the database can't reset them.
+func (db *DB) resetQueries() []string {
+	if db.dialect.Name() == dialect.PG {
+		return []string{"RESET ROLE", "RESET ALL"}
+	}
+	return nil
+}
+
+// HasSessionSetup reports whether the query results can depend on the session settings
+// made by RLSHook or WithConnectionInitFunc, which the caches can't include in the keys
+// and hooks can't reproduce on other connections.
+func (db *DB) HasSessionSetup(ctx context.Context) bool {
+	return db.connInitFn != nil || db.flags.Has(rlsHookFlag) || ctx.Value(rlsCtxKey{}) != nil
+}
+
+// connContext returns a context with the session state expected by the queries of the DB.
+func (db *DB) connContext(ctx context.Context) (context.Context, error) {
+	if !db.tracked {
+		if db.connInitFn != nil {
+			return ctx, errNoConnector
+		}
+		return ctx, nil
+	}
+
+	var state connState
+	if db.connInitFn != nil {
+		state.init = db.connInitFn(ctx)
+	}
+	if db.dialect.Name() == dialect.PG {
+		state.searchPath = db.searchPath(ctx)
+	}
+	if state == (connState{}) && ctx.Value(connStateKey{}) == nil {
+		return ctx, nil
+	}
+	return context.WithValue(ctx, connStateKey{}, &state), nil
+}
+
+// claimSession records that the query changes the session settings of the connection,
+// so the session is reset before the connection is used by other queries.
+// The returned context must be used for the queries executed on the connection.
+func (db *DB) claimSession(
+	ctx context.Context, conn *sql.Conn, owner interface{},
+) (context.Context, error) {
+	if !db.tracked {
+		db.sessions.setDirty(conn)
+		return ctx, nil
+	}
+
+	state := connState{owner: owner}
+	if s, ok := ctx.Value(connStateKey{}).(*connState); ok {
+		state.init = s.init
+		state.searchPath = s.searchPath
+	}
+	ctx = context.WithValue(ctx, connStateKey{}, &state)
+
+	err := conn.Raw(func(driverConn interface{}) error {
+		c, ok := driverConn.(*trackedConn)
+		if !ok {
+			return nil
+		}
+		// Reset the settings of the previous owner first.
+		if err := c.sync(ctx); err != nil {
+			return err
+		}
+		c.owner = owner
+		return nil
+	})
+	return ctx, err
+}
+
+// withPinnedConn calls fn with a connection checked out from the pool. The connection
+// is returned to the pool after fn returns or, if fn returns rows, after the rows are closed.
+func (db *DB) withPinnedConn(
+	ctx context.Context, returnsRows bool, fn func(conn *sql.Conn) error,
+) error {
+	conn, err := db.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	err = fn(conn)
+	_ = db.releaseConn(ctx, conn, returnsRows && err == nil)
+	return err
+}
+
+// releaseConn resets the session settings made by bun on the connection and returns it
+// to the pool. If the rows read from the connection are open, the connection is released
+// after the rows are closed and, if the settings were changed, the connection is closed,
+// because the settings can't be reset while the rows are open.
+func (db *DB) releaseConn(ctx context.Context, conn *sql.Conn, rowsOpen bool) error {
+	path, dirty := db.sessions.forgetConn(conn)
+	if path == "" && !dirty {
+		if rowsOpen {
+			// Blocks until the rows are closed.
+			go conn.Close()
+			return nil
+		}
+		return conn.Close()
+	}
+
+	if rowsOpen {
+		go discardConn(conn)
+		return nil
+	}
+	if err := db.resetConn(ctx, conn, dirty); err != nil {
+		discardConn(conn)
+		return nil
+	}
+	return conn.Close()
+}
+
+// resetConn resets the search path or, if the session is dirty, all session settings.
+func (db *DB) resetConn(ctx context.Context, conn *sql.Conn, dirty bool) error {
+	ctx = context.WithoutCancel(ctx)
+	if !dirty {
+		_, err := conn.ExecContext(ctx, "RESET search_path")
+		return err
+	}
+
+	queries := db.resetQueries()
+	if len(queries) == 0 {
+		return driver.ErrBadConn
+	}
+	for _, query := range queries {
+		if _, err := conn.ExecContext(ctx, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,11 +5,13 @@ import (
 	"crypto/rand"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
-	"sync/atomic"
+	"sync"
 
+	"github.com/uptrace/bun/dialect"
 	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
 	"github.com/uptrace/bun/schema"
@@ -17,8 +19,10 @@ import (
 
 const (
 	discardUnknownColumns internal.Flag = 1 << iota
+	rlsHookFlag
 )
 
+// DBStats contains the number of queries and errors, see Stats for more stats.
 type DBStats struct {
 	Queries uint32
 	Errors  uint32
@@ -32,18 +36,39 @@ func WithDiscardUnknownColumns() DBOption {
 	}
 }
 
+// WithMaxRetries retries queries that fail because the connection was lost up to n times
+// and logs a warning before each retry. Queries that fail with driver.ErrBadConn are retried,
+// because the driver did not send them. SELECT queries that fail with io.EOF or
+// io.ErrUnexpectedEOF are retried too, but other queries are not, because they could
+// be executed before the connection was lost. Queries executed inside transactions
+// are not retried.
+func WithMaxRetries(n int) DBOption {
+	return func(db *DB) {
+		db.Use(newConnRetryMiddleware(n))
+	}
+}
+
 type DB struct {
 	*sql.DB
 
 	dialect  schema.Dialect
 	features feature.Feature
 
-	queryHooks []QueryHook
+	queryHooks         []QueryHook
+	middlewares        []QueryMiddleware
+	builderMiddlewares []QueryBuilderMiddleware
 
 	fmter schema.Formatter
 	flags internal.Flag
 
-	stats DBStats
+	queryStats *queryStats
+	sessions   *sessions
+	modelCache *modelCache
+	connInitFn func(ctx context.Context) string
+	// redactionPolicy is set with WithRedactionPolicy or FieldRedactionHook.
+	redactionPolicy RedactionPolicy
+	// tracked reports whether the pool is opened with NewConnector.
+	tracked bool
 }
 
 func NewDB(sqldb *sql.DB, dialect schema.Dialect, opts ...DBOption) *DB {
@@ -54,7 +79,12 @@ func NewDB(sqldb *sql.DB, dialect schema.Dialect, opts ...DBOption) *DB {
 		dialect:  dialect,
 		features: dialect.Features(),
 		fmter:    schema.NewFormatter(dialect),
+
+		queryStats: new(queryStats),
+		sessions:   new(sessions),
+		modelCache: newModelCache(),
 	}
+	db.initTracking()
 
 	for _, opt := range opts {
 		opt(db)
@@ -73,8 +103,8 @@ func (db *DB) String() string {
 
 func (db *DB) DBStats() DBStats {
 	return DBStats{
-		Queries: atomic.LoadUint32(&db.stats.Queries),
-		Errors:  atomic.LoadUint32(&db.stats.Errors),
+		Queries: uint32(db.queryStats.queries.Load()),
+		Errors:  uint32(db.queryStats.errors.Load()),
 	}
 }
 
@@ -134,9 +164,57 @@ func (db *DB) NewDropColumn() *DropColumnQuery {
 	return NewDropColumnQuery(db)
 }
 
+func (db *DB) NewCreateSequence(name string) *CreateSequenceQuery {
+	return NewCreateSequenceQuery(db, name)
+}
+
+func (db *DB) NewAlterSequence(name string) *AlterSequenceQuery {
+	return NewAlterSequenceQuery(db, name)
+}
+
+func (db *DB) NewDropSequence(name string) *DropSequenceQuery {
+	return NewDropSequenceQuery(db, name)
+}
+
+func (db *DB) NewCreateExtension(name string) *CreateExtensionQuery {
+	return NewCreateExtensionQuery(db, name)
+}
+
+func (db *DB) NewDropExtension(name string) *DropExtensionQuery {
+	return NewDropExtensionQuery(db, name)
+}
+
+func (db *DB) NewCreateView(name string) *CreateViewQuery {
+	return NewCreateViewQuery(db, name)
+}
+
+func (db *DB) NewDropView(name string) *DropViewQuery {
+	return NewDropViewQuery(db, name)
+}
+
+func (db *DB) NewRefreshView(name string) *RefreshViewQuery {
+	return NewRefreshViewQuery(db, name)
+}
+
+func (db *DB) NewCreateTablePartition(parent, partition string) *CreateTablePartitionQuery {
+	return NewCreateTablePartitionQuery(db, parent, partition)
+}
+
+func (db *DB) NewAttachPartition(parent, partition string) *AttachPartitionQuery {
+	return NewAttachPartitionQuery(db, parent, partition)
+}
+
+func (db *DB) NewDetachPartition(parent, partition string) *DetachPartitionQuery {
+	return NewDetachPartitionQuery(db, parent, partition)
+}
+
 func (db *DB) ResetModel(ctx context.Context, models ...interface{}) error {
 	for _, model := range models {
-		if _, err := db.NewDropTable().Model(model).IfExists().Cascade().Exec(ctx); err != nil {
+		q := db.NewDropTable().Model(model).IfExists()
+		if db.HasFeature(feature.TableCascade) {
+			q = q.Cascade()
+		}
+		if _, err := q.Exec(ctx); err != nil {
 			return err
 		}
 		if _, err := db.NewCreateTable().Model(model).Exec(ctx); err != nil {
@@ -207,6 +285,12 @@ func (db *DB) clone() *DB {
 	l := len(clone.queryHooks)
 	clone.queryHooks = clone.queryHooks[:l:l]
 
+	l = len(clone.middlewares)
+	clone.middlewares = clone.middlewares[:l:l]
+
+	l = len(clone.builderMiddlewares)
+	clone.builderMiddlewares = clone.builderMiddlewares[:l:l]
+
 	return &clone
 }
 
@@ -244,9 +328,11 @@ func (db *DB) ExecContext(
 	ctx context.Context, query string, args ...interface{},
 ) (sql.Result, error) {
 	formattedQuery := db.format(query, args)
-	ctx, event := db.beforeQuery(ctx, nil, query, args, formattedQuery, nil)
-	res, err := db.DB.ExecContext(ctx, formattedQuery)
-	db.afterQuery(ctx, event, res, err)
+	formattedQuery = withQueryComment(ctx, formattedQuery)
+	ctx, event, start := db.beforeQuery(ctx, db.DB, nil, nil, query, args, formattedQuery, nil)
+	formattedQuery = event.query(formattedQuery)
+	res, err := db.runQuery(ctx, db.DB, formattedQuery, execQuery)
+	db.afterQuery(ctx, event, start, res, err)
 	return res, err
 }
 
@@ -258,9 +344,17 @@ func (db *DB) QueryContext(
 	ctx context.Context, query string, args ...interface{},
 ) (*sql.Rows, error) {
 	formattedQuery := db.format(query, args)
-	ctx, event := db.beforeQuery(ctx, nil, query, args, formattedQuery, nil)
-	rows, err := db.DB.QueryContext(ctx, formattedQuery)
-	db.afterQuery(ctx, event, nil, err)
+	formattedQuery = withQueryComment(ctx, formattedQuery)
+	ctx, event, start := db.beforeQuery(ctx, db.DB, nil, nil, query, args, formattedQuery, nil)
+	formattedQuery = event.query(formattedQuery)
+	var rows *sql.Rows
+	err := db.runQueryRows(ctx, db.DB, formattedQuery, func(
+		ctx context.Context, conn IConn, query string,
+	) (err error) {
+		rows, err = conn.QueryContext(ctx, query)
+		return err
+	})
+	db.afterQuery(ctx, event, start, nil, err)
 	return rows, err
 }
 
@@ -270,9 +364,20 @@ func (db *DB) QueryRow(query string, args ...interface{}) *sql.Row {
 
 func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
 	formattedQuery := db.format(query, args)
-	ctx, event := db.beforeQuery(ctx, nil, query, args, formattedQuery, nil)
-	row := db.DB.QueryRowContext(ctx, formattedQuery)
-	db.afterQuery(ctx, event, nil, row.Err())
+	formattedQuery = withQueryComment(ctx, formattedQuery)
+	ctx, event, start := db.beforeQuery(ctx, db.DB, nil, nil, query, args, formattedQuery, nil)
+	formattedQuery = event.query(formattedQuery)
+	var row *sql.Row
+	err := db.runQueryRows(ctx, db.DB, formattedQuery, func(
+		ctx context.Context, conn IConn, query string,
+	) error {
+		row = conn.QueryRowContext(ctx, query)
+		return row.Err()
+	})
+	if row == nil {
+		row = errRow(err)
+	}
+	db.afterQuery(ctx, event, start, nil, err)
 	return row
 }
 
@@ -288,7 +393,11 @@ type Conn struct {
 }
 
 func (db *DB) Conn(ctx context.Context) (Conn, error) {
-	conn, err := db.DB.Conn(ctx)
+	connCtx, err := db.connContext(ctx)
+	if err != nil {
+		return Conn{}, err
+	}
+	conn, err := db.DB.Conn(connCtx)
 	if err != nil {
 		return Conn{}, err
 	}
@@ -298,13 +407,32 @@ func (db *DB) Conn(ctx context.Context) (Conn, error) {
 	}, nil
 }
 
+// Close resets the session settings made by WithSearchPath and RLSHook
+// and returns the connection to the pool.
+func (c Conn) Close() error {
+	return c.db.releaseConn(context.Background(), c.Conn, false)
+}
+
+// Raw calls f with the driver connection, see sql.Conn.Raw. Unlike sql.Conn.Raw,
+// it passes the connection of the driver wrapped by NewConnector.
+func (c Conn) Raw(f func(driverConn interface{}) error) error {
+	return c.Conn.Raw(func(driverConn interface{}) error {
+		if tc, ok := driverConn.(*trackedConn); ok {
+			driverConn = tc.Conn
+		}
+		return f(driverConn)
+	})
+}
+
 func (c Conn) ExecContext(
 	ctx context.Context, query string, args ...interface{},
 ) (sql.Result, error) {
 	formattedQuery := c.db.format(query, args)
-	ctx, event := c.db.beforeQuery(ctx, nil, query, args, formattedQuery, nil)
-	res, err := c.Conn.ExecContext(ctx, formattedQuery)
-	c.db.afterQuery(ctx, event, res, err)
+	formattedQuery = withQueryComment(ctx, formattedQuery)
+	ctx, event, start := c.db.beforeQuery(ctx, c.Conn, nil, nil, query, args, formattedQuery, nil)
+	formattedQuery = event.query(formattedQuery)
+	res, err := c.db.runQuery(ctx, c.Conn, formattedQuery, execQuery)
+	c.db.afterQuery(ctx, event, start, res, err)
 	return res, err
 }
 
@@ -312,17 +440,36 @@ func (c Conn) QueryContext(
 	ctx context.Context, query string, args ...interface{},
 ) (*sql.Rows, error) {
 	formattedQuery := c.db.format(query, args)
-	ctx, event := c.db.beforeQuery(ctx, nil, query, args, formattedQuery, nil)
-	rows, err := c.Conn.QueryContext(ctx, formattedQuery)
-	c.db.afterQuery(ctx, event, nil, err)
+	formattedQuery = withQueryComment(ctx, formattedQuery)
+	ctx, event, start := c.db.beforeQuery(ctx, c.Conn, nil, nil, query, args, formattedQuery, nil)
+	formattedQuery = event.query(formattedQuery)
+	var rows *sql.Rows
+	err := c.db.runQueryRows(ctx, c.Conn, formattedQuery, func(
+		ctx context.Context, conn IConn, query string,
+	) (err error) {
+		rows, err = conn.QueryContext(ctx, query)
+		return err
+	})
+	c.db.afterQuery(ctx, event, start, nil, err)
 	return rows, err
 }
 
 func (c Conn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
 	formattedQuery := c.db.format(query, args)
-	ctx, event := c.db.beforeQuery(ctx, nil, query, args, formattedQuery, nil)
-	row := c.Conn.QueryRowContext(ctx, formattedQuery)
-	c.db.afterQuery(ctx, event, nil, row.Err())
+	formattedQuery = withQueryComment(ctx, formattedQuery)
+	ctx, event, start := c.db.beforeQuery(ctx, c.Conn, nil, nil, query, args, formattedQuery, nil)
+	formattedQuery = event.query(formattedQuery)
+	var row *sql.Row
+	err := c.db.runQueryRows(ctx, c.Conn, formattedQuery, func(
+		ctx context.Context, conn IConn, query string,
+	) error {
+		row = conn.QueryRowContext(ctx, query)
+		return row.Err()
+	})
+	if row == nil {
+		row = errRow(err)
+	}
+	c.db.afterQuery(ctx, event, start, nil, err)
 	return row
 }
 
@@ -386,6 +533,50 @@ func (c Conn) NewDropColumn() *DropColumnQuery {
 	return NewDropColumnQuery(c.db).Conn(c)
 }
 
+func (c Conn) NewCreateSequence(name string) *CreateSequenceQuery {
+	return NewCreateSequenceQuery(c.db, name).Conn(c)
+}
+
+func (c Conn) NewAlterSequence(name string) *AlterSequenceQuery {
+	return NewAlterSequenceQuery(c.db, name).Conn(c)
+}
+
+func (c Conn) NewDropSequence(name string) *DropSequenceQuery {
+	return NewDropSequenceQuery(c.db, name).Conn(c)
+}
+
+func (c Conn) NewCreateExtension(name string) *CreateExtensionQuery {
+	return NewCreateExtensionQuery(c.db, name).Conn(c)
+}
+
+func (c Conn) NewDropExtension(name string) *DropExtensionQuery {
+	return NewDropExtensionQuery(c.db, name).Conn(c)
+}
+
+func (c Conn) NewCreateView(name string) *CreateViewQuery {
+	return NewCreateViewQuery(c.db, name).Conn(c)
+}
+
+func (c Conn) NewDropView(name string) *DropViewQuery {
+	return NewDropViewQuery(c.db, name).Conn(c)
+}
+
+func (c Conn) NewRefreshView(name string) *RefreshViewQuery {
+	return NewRefreshViewQuery(c.db, name).Conn(c)
+}
+
+func (c Conn) NewCreateTablePartition(parent, partition string) *CreateTablePartitionQuery {
+	return NewCreateTablePartitionQuery(c.db, parent, partition).Conn(c)
+}
+
+func (c Conn) NewAttachPartition(parent, partition string) *AttachPartitionQuery {
+	return NewAttachPartitionQuery(c.db, parent, partition).Conn(c)
+}
+
+func (c Conn) NewDetachPartition(parent, partition string) *DetachPartitionQuery {
+	return NewDetachPartitionQuery(c.db, parent, partition).Conn(c)
+}
+
 // RunInTx runs the function in a transaction. If the function returns an error,
 // the transaction is rolled back. Otherwise, the transaction is committed.
 func (c Conn) RunInTx(
@@ -413,16 +604,17 @@ func (c Conn) RunInTx(
 }
 
 func (c Conn) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
-	ctx, event := c.db.beforeQuery(ctx, nil, "BEGIN", nil, "BEGIN", nil)
+	ctx, event, start := c.db.beforeQuery(ctx, c.Conn, nil, nil, "BEGIN", nil, "BEGIN", nil)
 	tx, err := c.Conn.BeginTx(ctx, opts)
-	c.db.afterQuery(ctx, event, nil, err)
+	c.db.afterQuery(ctx, event, start, nil, err)
 	if err != nil {
 		return Tx{}, err
 	}
 	return Tx{
-		ctx: ctx,
-		db:  c.db,
-		Tx:  tx,
+		ctx:       ctx,
+		db:        c.db,
+		Tx:        tx,
+		callbacks: new(txCallbacks),
 	}, nil
 }
 
@@ -452,6 +644,66 @@ type Tx struct {
 	// name is the name of a savepoint
 	name string
 	*sql.Tx
+
+	callbacks *txCallbacks
+}
+
+// txCallbacks are the OnCommit and OnRollback callbacks of a transaction or a savepoint.
+type txCallbacks struct {
+	mu         sync.Mutex
+	parent     *txCallbacks
+	onCommit   []func()
+	onRollback []func()
+}
+
+// OnCommit registers a function that is called after the transaction is committed.
+// Callbacks registered on a savepoint are called after the outermost transaction
+// is committed and are discarded if the savepoint is rolled back.
+func (tx Tx) OnCommit(fn func()) {
+	tx.callbacks.mu.Lock()
+	tx.callbacks.onCommit = append(tx.callbacks.onCommit, fn)
+	tx.callbacks.mu.Unlock()
+}
+
+// OnRollback registers a function that is called after the transaction
+// or the savepoint is rolled back, including when the commit fails.
+func (tx Tx) OnRollback(fn func()) {
+	tx.callbacks.mu.Lock()
+	tx.callbacks.onRollback = append(tx.callbacks.onRollback, fn)
+	tx.callbacks.mu.Unlock()
+}
+
+func (cb *txCallbacks) take() (onCommit, onRollback []func()) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	onCommit, onRollback = cb.onCommit, cb.onRollback
+	cb.onCommit, cb.onRollback = nil, nil
+	return onCommit, onRollback
+}
+
+func (cb *txCallbacks) committed() {
+	onCommit, _ := cb.take()
+	for _, fn := range onCommit {
+		fn()
+	}
+}
+
+func (cb *txCallbacks) rolledBack() {
+	_, onRollback := cb.take()
+	for _, fn := range onRollback {
+		fn()
+	}
+}
+
+// released moves the callbacks of a released savepoint to the parent transaction.
+func (cb *txCallbacks) released() {
+	onCommit, onRollback := cb.take()
+
+	cb.parent.mu.Lock()
+	cb.parent.onCommit = append(cb.parent.onCommit, onCommit...)
+	cb.parent.onRollback = append(cb.parent.onRollback, onRollback...)
+	cb.parent.mu.Unlock()
 }
 
 // RunInTx runs the function in a transaction. If the function returns an error,
@@ -485,19 +737,38 @@ func (db *DB) Begin() (Tx, error) {
 }
 
 func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
-	ctx, event := db.beforeQuery(ctx, nil, "BEGIN", nil, "BEGIN", nil)
-	tx, err := db.DB.BeginTx(ctx, opts)
-	db.afterQuery(ctx, event, nil, err)
+	ctx, event, start := db.beforeQuery(ctx, db.DB, nil, nil, "BEGIN", nil, "BEGIN", nil)
+	tx, err := db.beginTx(ctx, opts)
+	db.afterQuery(ctx, event, start, nil, err)
 	if err != nil {
 		return Tx{}, err
 	}
 	return Tx{
-		ctx: ctx,
-		db:  db,
-		Tx:  tx,
+		ctx:       ctx,
+		db:        db,
+		Tx:        tx,
+		callbacks: new(txCallbacks),
 	}, nil
 }
 
+// beginTx starts a transaction on a connection prepared for the session state
+// expected by the context, see connContext.
+func (db *DB) beginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	connCtx, err := db.connContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := db.DB.BeginTx(connCtx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if db.tracked && db.dialect.Name() == dialect.PG {
+		// The transaction keeps the search path set on the connection.
+		db.sessions.setTx(tx, db.searchPath(ctx))
+	}
+	return tx, nil
+}
+
 func (tx Tx) Commit() error {
 	if tx.name == "" {
 		return tx.commitTX()
@@ -506,18 +777,28 @@ func (tx Tx) Commit() error {
 }
 
 func (tx Tx) commitTX() error {
-	ctx, event := tx.db.beforeQuery(tx.ctx, nil, "COMMIT", nil, "COMMIT", nil)
+	ctx, event, start := tx.db.beforeQuery(tx.ctx, tx.Tx, &tx, nil, "COMMIT", nil, "COMMIT", nil)
 	err := tx.Tx.Commit()
-	tx.db.afterQuery(ctx, event, nil, err)
+	tx.db.sessions.setTx(tx.Tx, "")
+	tx.db.afterQuery(ctx, event, start, nil, err)
+	if err == nil {
+		tx.callbacks.committed()
+	} else if !errors.Is(err, sql.ErrTxDone) {
+		tx.callbacks.rolledBack()
+	}
 	return err
 }
 
 func (tx Tx) commitSP() error {
 	if tx.Dialect().Features().Has(feature.MSSavepoint) {
+		tx.callbacks.released()
 		return nil
 	}
 	query := "RELEASE SAVEPOINT " + tx.name
 	_, err := tx.ExecContext(tx.ctx, query)
+	if err == nil {
+		tx.callbacks.released()
+	}
 	return err
 }
 
@@ -529,9 +810,13 @@ func (tx Tx) Rollback() error {
 }
 
 func (tx Tx) rollbackTX() error {
-	ctx, event := tx.db.beforeQuery(tx.ctx, nil, "ROLLBACK", nil, "ROLLBACK", nil)
+	ctx, event, start := tx.db.beforeQuery(tx.ctx, tx.Tx, &tx, nil, "ROLLBACK", nil, "ROLLBACK", nil)
 	err := tx.Tx.Rollback()
-	tx.db.afterQuery(ctx, event, nil, err)
+	tx.db.sessions.setTx(tx.Tx, "")
+	tx.db.afterQuery(ctx, event, start, nil, err)
+	if err == nil {
+		tx.callbacks.rolledBack()
+	}
 	return err
 }
 
@@ -541,6 +826,9 @@ func (tx Tx) rollbackSP() error {
 		query = "ROLLBACK TRANSACTION " + tx.name
 	}
 	_, err := tx.ExecContext(tx.ctx, query)
+	if err == nil {
+		tx.callbacks.rolledBack()
+	}
 	return err
 }
 
@@ -552,9 +840,11 @@ func (tx Tx) ExecContext(
 	ctx context.Context, query string, args ...interface{},
 ) (sql.Result, error) {
 	formattedQuery := tx.db.format(query, args)
-	ctx, event := tx.db.beforeQuery(ctx, nil, query, args, formattedQuery, nil)
-	res, err := tx.Tx.ExecContext(ctx, formattedQuery)
-	tx.db.afterQuery(ctx, event, res, err)
+	formattedQuery = withQueryComment(ctx, formattedQuery)
+	ctx, event, start := tx.db.beforeQuery(ctx, tx.Tx, &tx, nil, query, args, formattedQuery, nil)
+	formattedQuery = event.query(formattedQuery)
+	res, err := tx.db.runQuery(ctx, tx.Tx, formattedQuery, execQuery)
+	tx.db.afterQuery(ctx, event, start, res, err)
 	return res, err
 }
 
@@ -566,9 +856,17 @@ func (tx Tx) QueryContext(
 	ctx context.Context, query string, args ...interface{},
 ) (*sql.Rows, error) {
 	formattedQuery := tx.db.format(query, args)
-	ctx, event := tx.db.beforeQuery(ctx, nil, query, args, formattedQuery, nil)
-	rows, err := tx.Tx.QueryContext(ctx, formattedQuery)
-	tx.db.afterQuery(ctx, event, nil, err)
+	formattedQuery = withQueryComment(ctx, formattedQuery)
+	ctx, event, start := tx.db.beforeQuery(ctx, tx.Tx, &tx, nil, query, args, formattedQuery, nil)
+	formattedQuery = event.query(formattedQuery)
+	var rows *sql.Rows
+	err := tx.db.runQueryRows(ctx, tx.Tx, formattedQuery, func(
+		ctx context.Context, conn IConn, query string,
+	) (err error) {
+		rows, err = conn.QueryContext(ctx, query)
+		return err
+	})
+	tx.db.afterQuery(ctx, event, start, nil, err)
 	return rows, err
 }
 
@@ -578,9 +876,20 @@ func (tx Tx) QueryRow(query string, args ...interface{}) *sql.Row {
 
 func (tx Tx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
 	formattedQuery := tx.db.format(query, args)
-	ctx, event := tx.db.beforeQuery(ctx, nil, query, args, formattedQuery, nil)
-	row := tx.Tx.QueryRowContext(ctx, formattedQuery)
-	tx.db.afterQuery(ctx, event, nil, row.Err())
+	formattedQuery = withQueryComment(ctx, formattedQuery)
+	ctx, event, start := tx.db.beforeQuery(ctx, tx.Tx, &tx, nil, query, args, formattedQuery, nil)
+	formattedQuery = event.query(formattedQuery)
+	var row *sql.Row
+	err := tx.db.runQueryRows(ctx, tx.Tx, formattedQuery, func(
+		ctx context.Context, conn IConn, query string,
+	) error {
+		row = conn.QueryRowContext(ctx, query)
+		return row.Err()
+	})
+	if row == nil {
+		row = errRow(err)
+	}
+	tx.db.afterQuery(ctx, event, start, nil, err)
 	return row
 }
 
@@ -609,10 +918,11 @@ func (tx Tx) BeginTx(ctx context.Context, _ *sql.TxOptions) (Tx, error) {
 		return Tx{}, err
 	}
 	return Tx{
-		ctx:  ctx,
-		db:   tx.db,
-		Tx:   tx.Tx,
-		name: qName,
+		ctx:       ctx,
+		db:        tx.db,
+		Tx:        tx.Tx,
+		name:      qName,
+		callbacks: &txCallbacks{parent: tx.callbacks},
 	}, nil
 }
 
@@ -700,6 +1010,50 @@ func (tx Tx) NewDropColumn() *DropColumnQuery {
 	return NewDropColumnQuery(tx.db).Conn(tx)
 }
 
+func (tx Tx) NewCreateSequence(name string) *CreateSequenceQuery {
+	return NewCreateSequenceQuery(tx.db, name).Conn(tx)
+}
+
+func (tx Tx) NewAlterSequence(name string) *AlterSequenceQuery {
+	return NewAlterSequenceQuery(tx.db, name).Conn(tx)
+}
+
+func (tx Tx) NewDropSequence(name string) *DropSequenceQuery {
+	return NewDropSequenceQuery(tx.db, name).Conn(tx)
+}
+
+func (tx Tx) NewCreateExtension(name string) *CreateExtensionQuery {
+	return NewCreateExtensionQuery(tx.db, name).Conn(tx)
+}
+
+func (tx Tx) NewDropExtension(name string) *DropExtensionQuery {
+	return NewDropExtensionQuery(tx.db, name).Conn(tx)
+}
+
+func (tx Tx) NewCreateView(name string) *CreateViewQuery {
+	return NewCreateViewQuery(tx.db, name).Conn(tx)
+}
+
+func (tx Tx) NewDropView(name string) *DropViewQuery {
+	return NewDropViewQuery(tx.db, name).Conn(tx)
+}
+
+func (tx Tx) NewRefreshView(name string) *RefreshViewQuery {
+	return NewRefreshViewQuery(tx.db, name).Conn(tx)
+}
+
+func (tx Tx) NewCreateTablePartition(parent, partition string) *CreateTablePartitionQuery {
+	return NewCreateTablePartitionQuery(tx.db, parent, partition).Conn(tx)
+}
+
+func (tx Tx) NewAttachPartition(parent, partition string) *AttachPartitionQuery {
+	return NewAttachPartitionQuery(tx.db, parent, partition).Conn(tx)
+}
+
+func (tx Tx) NewDetachPartition(parent, partition string) *DetachPartitionQuery {
+	return NewDetachPartitionQuery(tx.db, parent, partition).Conn(tx)
+}
+
 //------------------------------------------------------------------------------
 
 func (db *DB) makeQueryBytes() []byte {
@@ -0,0 +1,22 @@
+package bun
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun/schema"
+)
+
+func TestNewDBWithoutConnection(t *testing.T) {
+	type Model struct {
+		ID   int64 `bun:",pk"`
+		Name string
+	}
+
+	// A DB without *sql.DB is used to build queries without a connection.
+	db := NewDB(nil, schema.NewNopFormatter().Dialect())
+
+	query := db.NewSelect().Model((*Model)(nil)).Where("id = ?", 1).String()
+	require.Equal(t, `SELECT "model"."id", "model"."name" FROM "models" AS "model" WHERE (id = ?)`, query)
+}
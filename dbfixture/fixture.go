@@ -17,6 +17,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/schema"
 )
 
@@ -278,11 +279,13 @@ func (f *Fixture) dropTable(ctx context.Context, table *schema.Table) error {
 	}
 	f.seenTables[table.Name] = struct{}{}
 
-	if _, err := f.db.NewDropTable().
+	q := f.db.NewDropTable().
 		Model(table.ZeroIface).
-		IfExists().
-		Cascade().
-		Exec(ctx); err != nil {
+		IfExists()
+	if f.db.Dialect().Features().Has(feature.TableCascade) {
+		q = q.Cascade()
+	}
+	if _, err := q.Exec(ctx); err != nil {
 		return err
 	}
 
@@ -31,5 +31,16 @@ const (
 	UpdateFromTable
 	MSSavepoint
 	GeneratedIdentity
-	CompositeIn // ... WHERE (A,B) IN ((N, NN), (N, NN)...)
+	CompositeIn       // ... WHERE (A,B) IN ((N, NN), (N, NN)...)
+	EnumType          // CREATE TYPE ... AS ENUM
+	IndexConcurrently // CREATE INDEX CONCURRENTLY ...
+	ExpressionIndex   // CREATE INDEX ... ((expr))
+	UpdateOrderLimit  // UPDATE ... ORDER BY ... LIMIT ...
+	DeleteOrderLimit  // DELETE ... ORDER BY ... LIMIT ...
+	Sequence          // CREATE SEQUENCE ...
+	Extension         // CREATE EXTENSION ...
+	MaterializedView  // CREATE MATERIALIZED VIEW ...
+	TablePartition    // CREATE TABLE ... PARTITION OF ...
+	NullsOrder        // ORDER BY ... NULLS FIRST/LAST
+	SkipLocked        // SELECT ... FOR UPDATE SKIP LOCKED
 )
@@ -9,8 +9,6 @@ import (
 	"strings"
 	"time"
 
-	"golang.org/x/mod/semver"
-
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect"
 	"github.com/uptrace/bun/dialect/feature"
@@ -59,7 +57,23 @@ func (d *Dialect) Init(db *sql.DB) {
 		return
 	}
 
-	version = semver.MajorMinor("v" + cleanupVersion(version))
+	d.setVersion(version)
+}
+
+// setVersion disables the features that the server doesn't support using the version
+// returned by @@VERSION, for example, "Microsoft SQL Server 2019 (RTM-CU4) - 15.0.4033.1 (X64)".
+func (d *Dialect) setVersion(version string) {
+	major, _, _ := strings.Cut(cleanupVersion(version), ".")
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		log.Printf("can't parse MSSQL version: %q", version)
+		return
+	}
+
+	// OFFSET ... FETCH is supported since SQL Server 2012, which is version 11.
+	if n < 11 {
+		d.features = d.features.Remove(feature.OffsetFetch)
+	}
 }
 
 func cleanupVersion(v string) string {
@@ -0,0 +1,67 @@
+package mssqldialect
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
+)
+
+func TestSetVersion(t *testing.T) {
+	d := New()
+	d.setVersion("Microsoft SQL Server 2019 (RTM-CU4) (KB4548597) - 15.0.4033.1 (X64)")
+	require.True(t, d.Features().Has(feature.OffsetFetch))
+
+	d.setVersion("Microsoft SQL Server 2008 R2 (SP2) - 10.50.4000.0 (X64)")
+	require.False(t, d.Features().Has(feature.OffsetFetch))
+}
+
+// noConnector is a connector that fails to connect, so Init keeps the default features.
+type noConnector struct{}
+
+func (noConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, errors.New("no connection")
+}
+
+func (noConnector) Driver() driver.Driver {
+	return nil
+}
+
+func TestLimitOffset(t *testing.T) {
+	type Model struct {
+		ID int64 `bun:",pk"`
+	}
+
+	d := New()
+	db := bun.NewDB(sql.OpenDB(noConnector{}), d)
+
+	query := func(limit, offset int) (string, error) {
+		b, err := db.NewSelect().
+			Model((*Model)(nil)).
+			Order("id").
+			Limit(limit).
+			Offset(offset).
+			AppendQuery(db.Formatter(), nil)
+		return string(b), err
+	}
+
+	q, err := query(10, 20)
+	require.NoError(t, err)
+	require.Equal(t,
+		`SELECT "model"."id" FROM "models" AS "model" ORDER BY "id" OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY`, q)
+
+	d.setVersion("Microsoft SQL Server 2008 R2 (SP2) - 10.50.4000.0 (X64)")
+
+	q, err = query(10, 0)
+	require.NoError(t, err)
+	require.Equal(t, `SELECT TOP (10) "model"."id" FROM "models" AS "model" ORDER BY "id"`, q)
+
+	_, err = query(10, 20)
+	require.EqualError(t, err, "bun: OFFSET requires SQL Server 2012 or later")
+}
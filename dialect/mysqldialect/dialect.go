@@ -32,6 +32,11 @@ type Dialect struct {
 
 	tables   *schema.Tables
 	features feature.Feature
+
+	// version is the server version discovered by Init, for example, v8.0.35.
+	version string
+	// noJSON disables JSON helpers on servers without JSON functions.
+	noJSON bool
 }
 
 func New() *Dialect {
@@ -46,7 +51,9 @@ func New() *Dialect {
 		feature.InsertIgnore |
 		feature.InsertOnDuplicateKey |
 		feature.SelectExists |
-		feature.CompositeIn
+		feature.CompositeIn |
+		feature.UpdateOrderLimit |
+		feature.DeleteOrderLimit
 	return d
 }
 
@@ -59,16 +66,29 @@ func (d *Dialect) Init(db *sql.DB) {
 
 	if strings.Contains(version, "MariaDB") {
 		version = semver.MajorMinor("v" + cleanupVersion(version))
+		d.version = version
+		d.noJSON = semver.Compare(version, "v10.2") < 0
 		if semver.Compare(version, "v10.5.0") >= 0 {
 			d.features |= feature.InsertReturning
 		}
+		if semver.Compare(version, "v10.6") >= 0 {
+			d.features |= feature.SkipLocked
+		}
 		return
 	}
 
 	version = "v" + cleanupVersion(version)
+	d.version = version
+	d.noJSON = semver.Compare(version, "v5.7") < 0
 	if semver.Compare(version, "v8.0") >= 0 {
 		d.features |= feature.CTE | feature.WithValues
 	}
+	if semver.Compare(version, "v8.0.1") >= 0 {
+		d.features |= feature.SkipLocked
+	}
+	if semver.Compare(version, "v8.0.13") >= 0 {
+		d.features |= feature.ExpressionIndex
+	}
 	if semver.Compare(version, "v8.0.16") >= 0 {
 		d.features |= feature.DeleteTableAlias
 	}
@@ -81,6 +101,12 @@ func cleanupVersion(s string) string {
 	return s
 }
 
+// ServerVersion returns the server version discovered by Init, for example, v8.0.35,
+// or an empty string if the version is unknown.
+func (d *Dialect) ServerVersion() string {
+	return d.version
+}
+
 func (d *Dialect) Name() dialect.Name {
 	return dialect.MySQL
 }
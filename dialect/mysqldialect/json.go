@@ -0,0 +1,86 @@
+package mysqldialect
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/uptrace/bun/schema"
+)
+
+// JSONColumn builds expressions that use MySQL JSON functions on a JSON column.
+// The expressions can be used as arguments in Where, ColumnExpr, and other methods,
+// for example:
+//
+//	db.NewSelect().
+//		Model(&items).
+//		Where("? = ?", mysqldialect.JSON("attrs").Extract("$.size.width"), "10").
+//		Where("?", mysqldialect.JSON("attrs").Contains("red", "$.colors"))
+//
+// JSON functions require MySQL 5.7 or MariaDB 10.2. On older servers, the expressions
+// are formatted as errors.
+type JSONColumn struct {
+	col string
+}
+
+// JSON returns the JSONColumn for the column, which can be qualified with the table alias,
+// for example, item.attrs.
+func JSON(col string) JSONColumn {
+	return JSONColumn{col: col}
+}
+
+// Extract returns `JSON_UNQUOTE(JSON_EXTRACT(col, path))` expression that selects
+// the value at the path. Strings are unquoted, so they can be compared with Go strings.
+func (c JSONColumn) Extract(path string) schema.QueryWithArgs {
+	return schema.SafeQuery("JSON_UNQUOTE(JSON_EXTRACT(?, ?))", []interface{}{c.ident(), path})
+}
+
+// Contains returns `JSON_CONTAINS(col, val, path)` expression that checks that the value
+// at the path contains val, which is encoded as JSON. With an empty path,
+// the whole document is checked.
+func (c JSONColumn) Contains(val interface{}, path string) schema.QueryWithArgs {
+	if path == "" {
+		return schema.SafeQuery("JSON_CONTAINS(?, ?)", []interface{}{c.ident(), jsonValue{val}})
+	}
+	return schema.SafeQuery("JSON_CONTAINS(?, ?, ?)", []interface{}{c.ident(), jsonValue{val}, path})
+}
+
+// Search returns `JSON_SEARCH(col, mode, val, NULL, path)` expression that returns
+// the path to the string val or NULL. The mode is either "one" or "all", and val can
+// contain the % and _ wildcards. With an empty path, the whole document is searched.
+func (c JSONColumn) Search(mode, val, path string) schema.QueryWithArgs {
+	if path == "" {
+		return schema.SafeQuery("JSON_SEARCH(?, ?, ?)", []interface{}{c.ident(), mode, val})
+	}
+	return schema.SafeQuery("JSON_SEARCH(?, ?, ?, NULL, ?)", []interface{}{c.ident(), mode, val, path})
+}
+
+func (c JSONColumn) ident() jsonIdent {
+	return jsonIdent(c.col)
+}
+
+// jsonIdent is the column identifier that checks that the server supports JSON functions.
+type jsonIdent string
+
+var _ schema.QueryAppender = jsonIdent("")
+
+func (col jsonIdent) AppendQuery(fmter schema.Formatter, b []byte) ([]byte, error) {
+	if d, ok := fmter.Dialect().(*Dialect); ok && d.noJSON {
+		return nil, fmt.Errorf("mysqldialect: JSON functions require MySQL 5.7+, got %s", d.version)
+	}
+	return fmter.AppendIdent(b, string(col)), nil
+}
+
+// jsonValue is the value encoded as a JSON document.
+type jsonValue struct {
+	v interface{}
+}
+
+var _ schema.QueryAppender = jsonValue{}
+
+func (v jsonValue) AppendQuery(fmter schema.Formatter, b []byte) ([]byte, error) {
+	data, err := json.Marshal(v.v)
+	if err != nil {
+		return nil, err
+	}
+	return fmter.Dialect().AppendString(b, string(data)), nil
+}
@@ -0,0 +1,41 @@
+package mysqldialect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun/schema"
+)
+
+func TestJSON(t *testing.T) {
+	fmter := schema.NewFormatter(New())
+
+	tests := []struct {
+		query schema.QueryWithArgs
+		want  string
+	}{
+		{JSON("attrs").Extract("$.size.width"), "JSON_UNQUOTE(JSON_EXTRACT(`attrs`, '$.size.width'))"},
+		{JSON("item.attrs").Extract("$.name"), "JSON_UNQUOTE(JSON_EXTRACT(`item`.`attrs`, '$.name'))"},
+		{JSON("attrs").Contains("red", "$.colors"), "JSON_CONTAINS(`attrs`, '\"red\"', '$.colors')"},
+		{JSON("attrs").Contains(map[string]int{"width": 10}, ""), "JSON_CONTAINS(`attrs`, '{\"width\":10}')"},
+		{JSON("attrs").Search("one", "it's", "$.tags"), "JSON_SEARCH(`attrs`, 'one', 'it''s', NULL, '$.tags')"},
+		{JSON("attrs").Search("all", "r%", ""), "JSON_SEARCH(`attrs`, 'all', 'r%')"},
+	}
+
+	for _, test := range tests {
+		b, err := test.query.AppendQuery(fmter, nil)
+		require.NoError(t, err)
+		require.Equal(t, test.want, string(b))
+	}
+}
+
+func TestJSONUnsupported(t *testing.T) {
+	d := New()
+	d.version = "v5.6.51"
+	d.noJSON = true
+
+	b, err := JSON("attrs").Extract("$.name").AppendQuery(schema.NewFormatter(d), nil)
+	require.NoError(t, err)
+	require.Contains(t, string(b), "mysqldialect: JSON functions require MySQL 5.7+, got v5.6.51")
+}
@@ -0,0 +1,178 @@
+package pgdialect
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// autoExplainInterval is the minimum interval between plans of the same query.
+const autoExplainInterval = time.Second
+
+// autoExplainConcurrency is the maximum number of queries explained at the same time.
+// Slow queries are not explained while the limit is reached.
+const autoExplainConcurrency = 4
+
+type autoExplainHook struct {
+	threshold time.Duration
+	logger    *slog.Logger
+
+	sem chan struct{}
+
+	mu   sync.Mutex
+	last map[string]time.Time // fingerprint -> time of the last EXPLAIN
+}
+
+var _ bun.QueryHook = (*autoExplainHook)(nil)
+
+// NewAutoExplainHook returns a query hook that logs the plans of the queries that run
+// longer than threshold, like the auto_explain extension but on the client:
+//
+//	db.AddQueryHook(pgdialect.NewAutoExplainHook(time.Second, slog.Default()))
+//
+// The hook executes EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) for SELECT queries in
+// the background on a separate connection and rolls back its transaction. Other queries
+// are explained with EXPLAIN (FORMAT JSON) without executing them again, because
+// their side effects, for example, nextval or triggers, are not rolled back.
+// The queries executed in a transaction are explained outside of it, so they can't see
+// the uncommitted changes. The search path set with bun.WithSearchPath is used for
+// the EXPLAIN too. The queries that depend on the session settings made by bun.RLSHook
+// or DB.WithConnectionInitFunc are not explained, see DB.HasSessionSetup. The same query (by fingerprint) is explained at most once a second
+// and at most 4 queries are explained at the same time.
+func NewAutoExplainHook(threshold time.Duration, logger *slog.Logger) bun.QueryHook {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &autoExplainHook{
+		threshold: threshold,
+		logger:    logger,
+		sem:       make(chan struct{}, autoExplainConcurrency),
+		last:      make(map[string]time.Time),
+	}
+}
+
+func (h *autoExplainHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *autoExplainHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	if event.Err != nil || event.DB == nil || event.DB.HasSessionSetup(ctx) {
+		return
+	}
+
+	now := time.Now()
+	duration := now.Sub(event.StartTime)
+	if duration < h.threshold {
+		return
+	}
+
+	op := event.Operation()
+	switch op {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "MERGE", "WITH":
+	default:
+		return
+	}
+
+	if !h.allow(event.Fingerprint(), now) {
+		return
+	}
+
+	select {
+	case h.sem <- struct{}{}:
+	default:
+		return
+	}
+
+	searchPath := bun.SearchPathFromContext(ctx)
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer func() { <-h.sem }()
+		h.explain(ctx, event.DB, event.Query, searchPath, op == "SELECT", duration)
+	}()
+}
+
+// allow reports whether the query can be explained and records the time if it can.
+func (h *autoExplainHook) allow(fingerprint string, now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if last, ok := h.last[fingerprint]; ok && now.Sub(last) < autoExplainInterval {
+		return false
+	}
+
+	if len(h.last) >= 1000 {
+		for key, last := range h.last {
+			if now.Sub(last) >= autoExplainInterval {
+				delete(h.last, key)
+			}
+		}
+	}
+	h.last[fingerprint] = now
+	return true
+}
+
+func (h *autoExplainHook) explain(
+	ctx context.Context,
+	db *bun.DB,
+	query string,
+	searchPath []string,
+	analyze bool,
+	duration time.Duration,
+) {
+	plan, err := explainQuery(ctx, db, query, searchPath, analyze)
+	if err != nil {
+		h.logger.LogAttrs(ctx, slog.LevelWarn, "pgdialect: can't explain slow query",
+			slog.String("query", query),
+			slog.Any("error", err),
+		)
+		return
+	}
+
+	h.logger.LogAttrs(ctx, slog.LevelWarn, "slow query plan",
+		slog.String("query", query),
+		slog.Duration("duration", duration),
+		slog.Any("plan", plan),
+	)
+}
+
+// explainQuery uses the underlying sql.DB to not call the query hooks again,
+// so it sets the search path itself.
+func explainQuery(
+	ctx context.Context, db *bun.DB, query string, searchPath []string, analyze bool,
+) (json.RawMessage, error) {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if len(searchPath) > 0 {
+		b := []byte("SET LOCAL search_path TO ")
+		for i, schema := range searchPath {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b = db.Formatter().AppendIdent(b, schema)
+		}
+		if _, err := tx.ExecContext(ctx, string(b)); err != nil {
+			return nil, err
+		}
+	}
+
+	explain := "EXPLAIN (FORMAT JSON) "
+	if analyze {
+		explain = "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "
+	}
+
+	var plan []byte
+	if err := tx.QueryRowContext(ctx, explain+query).Scan(&plan); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(plan), nil
+}
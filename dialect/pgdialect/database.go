@@ -0,0 +1,64 @@
+package pgdialect
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// templateLocks serializes clones of the same template in the process, because
+// PostgreSQL refuses to copy a template that is accessed by other sessions.
+var templateLocks sync.Map // map[string]*sync.Mutex
+
+// CloneDatabase creates the newDB database as a copy of the templateDB database
+// using CREATE DATABASE ... TEMPLATE. The template must not have open connections.
+func CloneDatabase(ctx context.Context, adminDB *bun.DB, templateDB, newDB string) error {
+	mu, _ := templateLocks.LoadOrStore(templateDB, new(sync.Mutex))
+	mu.(*sync.Mutex).Lock()
+	defer mu.(*sync.Mutex).Unlock()
+
+	const maxRetries = 5
+	for i := 0; ; i++ {
+		_, err := adminDB.ExecContext(ctx, "CREATE DATABASE ? TEMPLATE ?",
+			bun.Ident(newDB), bun.Ident(templateDB))
+		// Other processes can access the template at the same time (object_in_use).
+		if err == nil || i == maxRetries || sqlState(err) != "55006" {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(i+1) * 50 * time.Millisecond):
+		}
+	}
+}
+
+// DropDatabase terminates connections to the database and drops it if it exists.
+func DropDatabase(ctx context.Context, adminDB *bun.DB, name string) error {
+	if _, err := adminDB.ExecContext(ctx, `
+		SELECT pg_terminate_backend(pid) FROM pg_stat_activity
+		WHERE datname = ? AND pid <> pg_backend_pid()
+	`, name); err != nil {
+		return err
+	}
+
+	_, err := adminDB.ExecContext(ctx, "DROP DATABASE IF EXISTS ?", bun.Ident(name))
+	return err
+}
+
+// sqlState returns the SQLSTATE code of the error returned by pgdriver or pgx.
+func sqlState(err error) string {
+	var pgdriverErr interface{ Field(byte) string }
+	if errors.As(err, &pgdriverErr) {
+		return pgdriverErr.Field('C')
+	}
+	var pgxErr interface{ SQLState() string }
+	if errors.As(err, &pgxErr) {
+		return pgxErr.SQLState()
+	}
+	return ""
+}
@@ -48,7 +48,16 @@ func New() *Dialect {
 		feature.InsertOnConflict |
 		feature.SelectExists |
 		feature.GeneratedIdentity |
-		feature.CompositeIn
+		feature.CompositeIn |
+		feature.EnumType |
+		feature.IndexConcurrently |
+		feature.ExpressionIndex |
+		feature.Sequence |
+		feature.Extension |
+		feature.MaterializedView |
+		feature.TablePartition |
+		feature.NullsOrder |
+		feature.SkipLocked
 	return d
 }
 
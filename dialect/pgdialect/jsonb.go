@@ -0,0 +1,45 @@
+package pgdialect
+
+import (
+	"strings"
+
+	"github.com/uptrace/bun/schema"
+)
+
+// JSONBContainsAll returns `col ?& ARRAY[...]` expression that checks that the JSONB column
+// contains all the keys. PostgreSQL can use a GIN index on the column, for example:
+//
+//	db.NewSelect().Model(&items).Where("?", pgdialect.JSONBContainsAll("tags", keys))
+func JSONBContainsAll(col string, keys []string) schema.QueryWithArgs {
+	return jsonbContains(col, `\?&`, keys)
+}
+
+// JSONBContainsAny returns `col ?| ARRAY[...]` expression that checks that the JSONB column
+// contains any of the keys.
+func JSONBContainsAny(col string, keys []string) schema.QueryWithArgs {
+	return jsonbContains(col, `\?|`, keys)
+}
+
+func jsonbContains(col, op string, keys []string) schema.QueryWithArgs {
+	var b strings.Builder
+	b.WriteString("? ")
+	b.WriteString(op) // escaped so ? is not treated as a placeholder
+	b.WriteString(" ARRAY[")
+
+	args := make([]interface{}, 0, len(keys)+1)
+	args = append(args, schema.Ident(col))
+	for i, key := range keys {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('?')
+		args = append(args, key)
+	}
+
+	b.WriteString("]")
+	if len(keys) == 0 {
+		b.WriteString("::text[]")
+	}
+
+	return schema.SafeQuery(b.String(), args)
+}
@@ -0,0 +1,29 @@
+package pgdialect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun/schema"
+)
+
+func TestJSONBContains(t *testing.T) {
+	fmter := schema.NewFormatter(New())
+
+	tests := []struct {
+		query schema.QueryWithArgs
+		want  string
+	}{
+		{JSONBContainsAll("tags", []string{"a", "b"}), `"tags" ?& ARRAY['a', 'b']`},
+		{JSONBContainsAny("tags", []string{"a", "b"}), `"tags" ?| ARRAY['a', 'b']`},
+		{JSONBContainsAny("item.tags", []string{"it's"}), `"item"."tags" ?| ARRAY['it''s']`},
+		{JSONBContainsAll("tags", nil), `"tags" ?& ARRAY[]::text[]`},
+	}
+
+	for _, test := range tests {
+		b, err := test.query.AppendQuery(fmter, nil)
+		require.NoError(t, err)
+		require.Equal(t, test.want, string(b))
+	}
+}
@@ -0,0 +1,71 @@
+// Package pgtest helps tests that need a PostgreSQL database of their own.
+package pgtest
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+)
+
+type databaseConnector interface {
+	ConnectorForDatabase(database string) (driver.Connector, error)
+}
+
+// WithTestDB clones the template database into a new database with a random name
+// and returns a DB connected to it. The DB is closed and the database is dropped
+// when the test finishes. The driver of the adminDB must be able to connect to
+// other databases, which is supported by pgdriver.
+func WithTestDB(tb testing.TB, adminDB *bun.DB, template string) *bun.DB {
+	tb.Helper()
+
+	drv, ok := adminDB.DB.Driver().(databaseConnector)
+	if !ok {
+		tb.Fatalf("pgtest: %T can't connect to other databases", adminDB.DB.Driver())
+	}
+
+	name, err := testDBName(template)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if err := pgdialect.CloneDatabase(ctx, adminDB, template, name); err != nil {
+		tb.Fatalf("pgtest: cloning %s failed: %s", template, err)
+	}
+
+	connector, err := drv.ConnectorForDatabase(name)
+	if err != nil {
+		_ = pgdialect.DropDatabase(ctx, adminDB, name)
+		tb.Fatal(err)
+	}
+
+	db := bun.NewDB(sql.OpenDB(connector), pgdialect.New())
+	tb.Cleanup(func() {
+		if err := errors.Join(db.Close(), pgdialect.DropDatabase(ctx, adminDB, name)); err != nil {
+			tb.Errorf("pgtest: dropping %s failed: %s", name, err)
+		}
+	})
+	return db
+}
+
+func testDBName(template string) (string, error) {
+	var b [6]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	// PostgreSQL truncates identifiers to 63 bytes.
+	const maxPrefixLen = 63 - len("_test_") - 2*len(b)
+	if len(template) > maxPrefixLen {
+		template = template[:maxPrefixLen]
+	}
+	return fmt.Sprintf("%s_test_%s", template, hex.EncodeToString(b[:])), nil
+}
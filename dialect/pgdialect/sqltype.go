@@ -68,6 +68,10 @@ func fieldSQLType(field *schema.Field) string {
 		}
 	}
 
+	if field.Enum != nil {
+		return sqltype.VarChar
+	}
+
 	if field.DiscoveredSQLType == sqltype.Blob {
 		return pgTypeBytea
 	}
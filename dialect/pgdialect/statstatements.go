@@ -0,0 +1,104 @@
+package pgdialect
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// PGStatStatement is the statistics of a normalized query collected by
+// the pg_stat_statements extension.
+type PGStatStatement struct {
+	Query      string
+	Calls      int64
+	MeanTime   time.Duration
+	TotalTime  time.Duration
+	StddevTime time.Duration
+	Rows       int64
+	// HitRate is the fraction of the shared blocks found in the buffer cache
+	// or 0 if the query did not read any shared blocks.
+	HitRate float64
+}
+
+// statStatementRow is scanned from pg_stat_statements, which reports times in milliseconds.
+type statStatementRow struct {
+	Query      string
+	Calls      int64
+	MeanTime   float64
+	TotalTime  float64
+	StddevTime float64
+	Rows       int64
+	HitRate    float64
+}
+
+var errNoStatStatements = errors.New("pgdialect: pg_stat_statements extension is not installed " +
+	"(add it to shared_preload_libraries and run CREATE EXTENSION pg_stat_statements)")
+
+// TopSlowQueries returns the limit queries of the current database with
+// the highest mean execution time reported by pg_stat_statements.
+// PostgreSQL 13 or later is required.
+func TopSlowQueries(ctx context.Context, db *bun.DB, limit int) ([]PGStatStatement, error) {
+	if err := checkStatStatements(ctx, db); err != nil {
+		return nil, err
+	}
+
+	var rows []statStatementRow
+	if err := db.NewRaw(`
+		SELECT
+			query,
+			calls,
+			mean_exec_time AS mean_time,
+			total_exec_time AS total_time,
+			stddev_exec_time AS stddev_time,
+			rows,
+			COALESCE(shared_blks_hit::float8 / NULLIF(shared_blks_hit + shared_blks_read, 0), 0) AS hit_rate
+		FROM pg_stat_statements
+		WHERE dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+		ORDER BY mean_exec_time DESC
+		LIMIT ?
+	`, limit).Scan(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	stmts := make([]PGStatStatement, len(rows))
+	for i, row := range rows {
+		stmts[i] = PGStatStatement{
+			Query:      row.Query,
+			Calls:      row.Calls,
+			MeanTime:   millisToDuration(row.MeanTime),
+			TotalTime:  millisToDuration(row.TotalTime),
+			StddevTime: millisToDuration(row.StddevTime),
+			Rows:       row.Rows,
+			HitRate:    row.HitRate,
+		}
+	}
+	return stmts, nil
+}
+
+// ResetStatStatements discards the statistics collected by pg_stat_statements.
+func ResetStatStatements(ctx context.Context, db *bun.DB) error {
+	if err := checkStatStatements(ctx, db); err != nil {
+		return err
+	}
+	_, err := db.ExecContext(ctx, "SELECT pg_stat_statements_reset()")
+	return err
+}
+
+func checkStatStatements(ctx context.Context, db *bun.DB) error {
+	var exists bool
+	if err := db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements')
+	`).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return errNoStatStatements
+	}
+	return nil
+}
+
+func millisToDuration(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
@@ -40,7 +40,9 @@ func New() *Dialect {
 		feature.TableNotExists |
 		feature.SelectExists |
 		feature.AutoIncrement |
-		feature.CompositeIn
+		feature.CompositeIn |
+		feature.ExpressionIndex |
+		feature.NullsOrder
 	return d
 }
 
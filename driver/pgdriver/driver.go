@@ -64,6 +64,18 @@ func (d Driver) Open(name string) (driver.Conn, error) {
 	return connector.Connect(context.TODO())
 }
 
+// ConnectorForDatabase returns a connector that uses the same config as the driver's
+// connector, but connects to the database. It is used by pgtest.WithTestDB.
+func (d Driver) ConnectorForDatabase(database string) (driver.Connector, error) {
+	if d.connector == nil {
+		return nil, errors.New("pgdriver: driver has no connector")
+	}
+
+	cfg := *d.connector.cfg
+	cfg.Database = database
+	return &Connector{cfg: &cfg}, nil
+}
+
 //------------------------------------------------------------------------------
 
 type Connector struct {
@@ -198,6 +198,14 @@ func (ln *Listener) Receive(ctx context.Context) (channel string, payload string
 func (ln *Listener) ReceiveTimeout(
 	ctx context.Context, timeout time.Duration,
 ) (channel, payload string, err error) {
+	n, err := ln.receive(ctx, timeout)
+	if err != nil {
+		return "", "", err
+	}
+	return n.Channel, n.Payload, nil
+}
+
+func (ln *Listener) receive(ctx context.Context, timeout time.Duration) (Notification, error) {
 	var cn *Conn
 
 	if err := ln.withLock(func() error {
@@ -205,17 +213,17 @@ func (ln *Listener) ReceiveTimeout(
 		cn, err = ln.conn(ctx)
 		return err
 	}); err != nil {
-		return "", "", err
+		return Notification{}, err
 	}
 
 	rd := cn.reader(ctx, timeout)
-	channel, payload, err = readNotification(ctx, rd)
+	n, err := readNotification(ctx, rd)
 	if err != nil {
 		ln.checkConn(ctx, cn, err, timeout > 0)
-		return "", "", err
+		return Notification{}, err
 	}
 
-	return channel, payload, nil
+	return n, nil
 }
 
 // Channel returns a channel for concurrently receiving notifications.
@@ -233,6 +241,8 @@ func (ln *Listener) Channel(opts ...ChannelOption) <-chan Notification {
 type Notification struct {
 	Channel string
 	Payload string
+	// PID is the process ID of the backend that sent the notification.
+	PID int
 }
 
 type ChannelOption func(c *channel)
@@ -279,15 +289,19 @@ func newChannel(ln *Listener, opts []ChannelOption) *channel {
 func (c *channel) startReceive() {
 	var errCount int
 	for {
-		channel, payload, err := c.ln.Receive(c.ctx)
+		n, err := c.ln.receive(c.ctx, 0)
 		if err != nil {
 			if err == errListenerClosed {
 				close(c.ch)
 				return
 			}
 
+			// The first error is retried immediately, which reconnects the listener.
 			if errCount > 0 {
-				time.Sleep(500 * time.Millisecond)
+				select {
+				case <-time.After(receiveBackoff(errCount)):
+				case <-c.ln.exit:
+				}
 			}
 			errCount++
 
@@ -302,12 +316,12 @@ func (c *channel) startReceive() {
 		default:
 		}
 
-		switch channel {
+		switch n.Channel {
 		case pingChannel:
 			// ignore
 		default:
 			select {
-			case c.ch <- Notification{channel, payload}:
+			case c.ch <- n:
 			default:
 				Logger.Printf(c.ctx, "pgdriver: Listener buffer is full (message is dropped)")
 			}
@@ -315,6 +329,19 @@ func (c *channel) startReceive() {
 	}
 }
 
+// receiveBackoff returns the exponential backoff after errCount consecutive errors.
+func receiveBackoff(errCount int) time.Duration {
+	const maxBackoff = 5 * time.Second
+	if errCount > 6 {
+		return maxBackoff
+	}
+	d := 100 * time.Millisecond << (errCount - 1)
+	if d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
+
 func (c *channel) startPing() {
 	timer := time.NewTimer(time.Minute)
 	timer.Stop()
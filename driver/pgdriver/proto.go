@@ -664,39 +664,44 @@ func readRowDescription(rd *reader) (*rowDescription, error) {
 
 //------------------------------------------------------------------------------
 
-func readNotification(ctx context.Context, rd *reader) (channel, payload string, err error) {
+func readNotification(ctx context.Context, rd *reader) (Notification, error) {
 	for {
 		c, msgLen, err := readMessageType(rd)
 		if err != nil {
-			return "", "", err
+			return Notification{}, err
 		}
 
 		switch c {
 		case commandCompleteMsg, readyForQueryMsg, noticeResponseMsg:
 			if err := rd.Discard(msgLen); err != nil {
-				return "", "", err
+				return Notification{}, err
 			}
 		case errorResponseMsg:
 			e, err := readError(rd)
 			if err != nil {
-				return "", "", err
+				return Notification{}, err
 			}
-			return "", "", e
+			return Notification{}, e
 		case notificationResponseMsg:
-			if err := rd.Discard(4); err != nil {
-				return "", "", err
+			pid, err := readInt32(rd)
+			if err != nil {
+				return Notification{}, err
 			}
-			channel, err = readString(rd)
+			channel, err := readString(rd)
 			if err != nil {
-				return "", "", err
+				return Notification{}, err
 			}
-			payload, err = readString(rd)
+			payload, err := readString(rd)
 			if err != nil {
-				return "", "", err
+				return Notification{}, err
 			}
-			return channel, payload, nil
+			return Notification{
+				Channel: channel,
+				Payload: payload,
+				PID:     int(pid),
+			}, nil
 		default:
-			return "", "", fmt.Errorf("pgdriver: readNotification: unexpected message %q", c)
+			return Notification{}, fmt.Errorf("pgdriver: readNotification: unexpected message %q", c)
 		}
 	}
 }
@@ -0,0 +1,58 @@
+package bun
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/uptrace/bun/schema"
+)
+
+type encryptionKeyCtxKey struct{}
+
+var encryptedFieldType = reflect.TypeOf((*EncryptedField)(nil)).Elem()
+
+// NewEncryptedField returns an EncryptedField that uses the key and the algorithm.
+// See schema.NewEncryptedField.
+func NewEncryptedField(key []byte, algo string) *EncryptedField {
+	return schema.NewEncryptedField(key, algo)
+}
+
+// WithEncryptionKey returns a context with the key that is used to encrypt and decrypt
+// EncryptedField values that don't have a key. It allows to use a different key per request,
+// for example, after the key is rotated.
+func (db *DB) WithEncryptionKey(ctx context.Context, key []byte) context.Context {
+	return context.WithValue(ctx, encryptionKeyCtxKey{}, key)
+}
+
+func encryptionKey(ctx context.Context) []byte {
+	key, _ := ctx.Value(encryptionKeyCtxKey{}).([]byte)
+	return key
+}
+
+// decryptFields decrypts EncryptedField values that were scanned without a key
+// using the key from the context.
+func decryptFields(ctx context.Context, table *schema.Table, strct reflect.Value) error {
+	if !table.HasEncryptedFields() {
+		return nil
+	}
+
+	key := encryptionKey(ctx)
+	for _, field := range table.Fields {
+		if field.IndirectType != encryptedFieldType {
+			continue
+		}
+
+		fv := field.Value(strct)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+
+		if err := fv.Addr().Interface().(*EncryptedField).Decrypt(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -57,13 +57,24 @@ func newDBCommand(migrator *migrate.Migrator) *cli.Command {
 			{
 				Name:  "migrate",
 				Usage: "migrate database",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "skip checksum verification of applied migrations",
+					},
+				},
 				Action: func(c *cli.Context) error {
 					if err := migrator.Lock(c.Context); err != nil {
 						return err
 					}
 					defer migrator.Unlock(c.Context) //nolint:errcheck
 
-					group, err := migrator.Migrate(c.Context)
+					var opts []migrate.MigrationOption
+					if c.Bool("force") {
+						opts = append(opts, migrate.WithChecksumSkip())
+					}
+
+					group, err := migrator.Migrate(c.Context, opts...)
 					if err != nil {
 						return err
 					}
@@ -96,6 +107,18 @@ func newDBCommand(migrator *migrate.Migrator) *cli.Command {
 					return nil
 				},
 			},
+			{
+				Name:  "plan",
+				Usage: "print migrations that would be applied and rolled back",
+				Action: func(c *cli.Context) error {
+					plan, err := migrator.Plan(c.Context)
+					if err != nil {
+						return err
+					}
+					fmt.Print(plan)
+					return nil
+				},
+			},
 			{
 				Name:  "lock",
 				Usage: "lock migrations",
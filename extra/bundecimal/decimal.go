@@ -0,0 +1,68 @@
+// Package bundecimal registers decimal.Decimal from github.com/shopspring/decimal
+// with the bun type registry, so the values are stored and read without
+// losing precision:
+//
+//	import _ "github.com/uptrace/bun/extra/bundecimal"
+//
+//	type Account struct {
+//		ID      int64
+//		Balance decimal.Decimal `bun:"type:numeric(19,4)"`
+//	}
+package bundecimal
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/schema"
+)
+
+func init() {
+	schema.RegisterType[decimal.Decimal](AppendValue, Scan)
+}
+
+// AppendValue appends the decimal as a numeric literal in the fixed-point notation,
+// for example, 0.0000001 instead of 1e-7.
+func AppendValue(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
+	d, ok := v.Interface().(decimal.Decimal)
+	if !ok {
+		return dialect.AppendError(b, fmt.Errorf("bundecimal: Append(unsupported %s)", v.Type()))
+	}
+	return append(b, d.String()...)
+}
+
+// Scan scans the numeric value returned by the database into the decimal.
+func Scan(dest reflect.Value, src interface{}) error {
+	var d decimal.Decimal
+	switch src := src.(type) {
+	case nil:
+		// zero value
+	case string:
+		var err error
+		d, err = decimal.NewFromString(src)
+		if err != nil {
+			return err
+		}
+	case []byte:
+		var err error
+		d, err = decimal.NewFromString(string(src))
+		if err != nil {
+			return err
+		}
+	case int64:
+		d = decimal.NewFromInt(src)
+	case float64:
+		d = decimal.NewFromFloat(src)
+	default:
+		return fmt.Errorf("bundecimal: can't scan %T into decimal.Decimal", src)
+	}
+
+	if !dest.CanSet() {
+		return fmt.Errorf("bundecimal: Scan(non-settable %s)", dest.Type())
+	}
+	dest.Set(reflect.ValueOf(d))
+	return nil
+}
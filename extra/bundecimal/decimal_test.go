@@ -0,0 +1,46 @@
+package bundecimal_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun/extra/bundecimal"
+	"github.com/uptrace/bun/schema"
+)
+
+func TestAppendValue(t *testing.T) {
+	fmter := schema.NewNopFormatter()
+
+	for _, s := range []string{"0", "0.3", "-12345.6789", "0.0000001", "123456789012345678901234567890"} {
+		d := decimal.RequireFromString(s)
+		b := bundecimal.AppendValue(fmter, nil, reflect.ValueOf(d))
+		require.Equal(t, s, string(b))
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		src    interface{}
+		wanted string
+	}{
+		{nil, "0"},
+		{"0.3000", "0.3"},
+		{[]byte("-1.25"), "-1.25"},
+		{int64(42), "42"},
+		{float64(0.5), "0.5"},
+	}
+
+	for _, test := range tests {
+		var d decimal.Decimal
+		err := bundecimal.Scan(reflect.ValueOf(&d).Elem(), test.src)
+		require.NoError(t, err)
+		require.True(t, decimal.RequireFromString(test.wanted).Equal(d), "got %s", d)
+	}
+
+	var d decimal.Decimal
+	err := bundecimal.Scan(reflect.ValueOf(&d).Elem(), "abc")
+	require.Error(t, err)
+}
@@ -33,6 +33,23 @@ func WithFormattedQueries(format bool) Option {
 	}
 }
 
+// WithOTelSensitive configures whether the statement attribute may contain sensitive data,
+// for example, query arguments. When disabled, the statement is normalized with bun.Fingerprint,
+// which replaces literal values with ? placeholders. By default, it is enabled only
+// with WithFormattedQueries.
+func WithOTelSensitive(sensitive bool) Option {
+	return func(h *QueryHook) {
+		h.sensitive = &sensitive
+	}
+}
+
+// WithTreatNoRowsAsError configures whether sql.ErrNoRows sets the span error status.
+func WithTreatNoRowsAsError(treat bool) Option {
+	return func(h *QueryHook) {
+		h.noRowsAsError = treat
+	}
+}
+
 // WithTracerProvider returns an Option to use the TracerProvider when
 // creating a Tracer.
 func WithTracerProvider(tp trace.TracerProvider) Option {
@@ -3,6 +3,7 @@ package bunotel
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"runtime"
 	"strings"
 	"time"
@@ -23,6 +24,8 @@ import (
 type QueryHook struct {
 	attrs          []attribute.KeyValue
 	formatQueries  bool
+	sensitive      *bool
+	noRowsAsError  bool
 	tracer         trace.Tracer
 	meter          metric.Meter
 	queryHistogram metric.Int64Histogram
@@ -68,15 +71,20 @@ func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
 	operation := event.Operation()
 	dbOperation := semconv.DBOperationKey.String(operation)
 
-	labels := make([]attribute.KeyValue, 0, len(h.attrs)+2)
-	labels = append(labels, h.attrs...)
-	labels = append(labels, dbOperation)
+	var dbTable attribute.KeyValue
 	if event.IQuery != nil {
 		if tableName := event.IQuery.GetTableName(); tableName != "" {
-			labels = append(labels, semconv.DBSQLTableKey.String(tableName))
+			dbTable = semconv.DBSQLTableKey.String(tableName)
 		}
 	}
 
+	labels := make([]attribute.KeyValue, 0, len(h.attrs)+2)
+	labels = append(labels, h.attrs...)
+	labels = append(labels, dbOperation)
+	if dbTable.Valid() {
+		labels = append(labels, dbTable)
+	}
+
 	dur := time.Since(event.StartTime)
 	h.queryHistogram.Record(ctx, dur.Milliseconds(), metric.WithAttributes(labels...))
 
@@ -101,17 +109,22 @@ func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
 		semconv.CodeLineNumberKey.Int(line),
 	)
 
+	if dbTable.Valid() {
+		attrs = append(attrs, dbTable)
+	}
 	if sys := dbSystem(event.DB); sys.Valid() {
 		attrs = append(attrs, sys)
 	}
 	if event.Result != nil {
-		if n, _ := event.Result.RowsAffected(); n > 0 {
+		if n, err := event.Result.RowsAffected(); err == nil && n >= 0 {
 			attrs = append(attrs, attribute.Int64("db.rows_affected", n))
 		}
 	}
 
-	switch event.Err {
-	case nil, sql.ErrNoRows, sql.ErrTxDone:
+	switch {
+	case event.Err == nil, event.Err == sql.ErrTxDone:
+		// ignore
+	case errors.Is(event.Err, sql.ErrNoRows) && !h.noRowsAsError:
 		// ignore
 	default:
 		span.RecordError(event.Err)
@@ -159,6 +172,9 @@ func (h *QueryHook) eventQuery(event *bun.QueryEvent) string {
 		query = unformattedQuery(event)
 	}
 
+	if !h.isSensitive() {
+		query = bun.Fingerprint(query)
+	}
 	if len(query) > hardQueryLimit {
 		query = query[:hardQueryLimit]
 	}
@@ -166,6 +182,13 @@ func (h *QueryHook) eventQuery(event *bun.QueryEvent) string {
 	return query
 }
 
+func (h *QueryHook) isSensitive() bool {
+	if h.sensitive != nil {
+		return *h.sensitive
+	}
+	return h.formatQueries
+}
+
 func unformattedQuery(event *bun.QueryEvent) string {
 	if event.IQuery != nil {
 		if b, err := event.IQuery.AppendQuery(schema.NewNopFormatter(), nil); err == nil {
@@ -176,6 +199,9 @@ func unformattedQuery(event *bun.QueryEvent) string {
 }
 
 func dbSystem(db *bun.DB) attribute.KeyValue {
+	if db == nil {
+		return attribute.KeyValue{}
+	}
 	switch db.Dialect().Name() {
 	case dialect.PG:
 		return semconv.DBSystemPostgreSQL
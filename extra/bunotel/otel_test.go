@@ -0,0 +1,127 @@
+package bunotel
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+type testQuery struct {
+	query string
+	table string
+}
+
+var _ schema.Query = (*testQuery)(nil)
+
+func (q *testQuery) AppendQuery(fmter schema.Formatter, b []byte) ([]byte, error) {
+	return append(b, q.query...), nil
+}
+
+func (q *testQuery) Operation() string    { return "SELECT" }
+func (q *testQuery) GetModel() bun.Model  { return nil }
+func (q *testQuery) GetTableName() string { return q.table }
+
+func recordSpan(t *testing.T, event *bun.QueryEvent, opts ...Option) sdktrace.ReadOnlySpan {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	h := NewQueryHook(append(opts, WithTracerProvider(tp))...)
+	ctx := h.BeforeQuery(context.Background(), event)
+	h.AfterQuery(ctx, event)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, wanted 1", len(spans))
+	}
+	return spans[0]
+}
+
+func spanAttrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func newEvent(err error) *bun.QueryEvent {
+	return &bun.QueryEvent{
+		IQuery: &testQuery{
+			query: `SELECT "id" FROM "users" WHERE "name" = 'john' AND "age" > 18 AND "id" = ?`,
+			table: "users",
+		},
+		Query:     `SELECT "id" FROM "users" WHERE "name" = 'john' AND "age" > 18 AND "id" = 42`,
+		StartTime: time.Now(),
+		Result:    driver.RowsAffected(3),
+		Err:       err,
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	span := recordSpan(t, newEvent(nil))
+	attrs := spanAttrs(span)
+
+	if got := attrs["db.operation"].AsString(); got != "SELECT" {
+		t.Errorf("db.operation = %q", got)
+	}
+	if got := attrs["db.sql.table"].AsString(); got != "users" {
+		t.Errorf("db.sql.table = %q", got)
+	}
+	if got := attrs["db.rows_affected"].AsInt64(); got != 3 {
+		t.Errorf("db.rows_affected = %d", got)
+	}
+
+	wanted := `select "id" from "users" where "name" = ? and "age" > ? and "id" = ?`
+	if got := attrs["db.statement"].AsString(); got != wanted {
+		t.Errorf("db.statement = %q, wanted %q", got, wanted)
+	}
+}
+
+func TestSensitive(t *testing.T) {
+	tests := []struct {
+		opts   []Option
+		wanted string
+	}{
+		{
+			opts:   []Option{WithFormattedQueries(true)},
+			wanted: `SELECT "id" FROM "users" WHERE "name" = 'john' AND "age" > 18 AND "id" = 42`,
+		},
+		{
+			opts:   []Option{WithFormattedQueries(true), WithOTelSensitive(false)},
+			wanted: `select "id" from "users" where "name" = ? and "age" > ? and "id" = ?`,
+		},
+		{
+			opts:   []Option{WithOTelSensitive(true)},
+			wanted: `SELECT "id" FROM "users" WHERE "name" = 'john' AND "age" > 18 AND "id" = ?`,
+		},
+	}
+
+	for _, test := range tests {
+		attrs := spanAttrs(recordSpan(t, newEvent(nil), test.opts...))
+		if got := attrs["db.statement"].AsString(); got != test.wanted {
+			t.Errorf("db.statement = %q, wanted %q", got, test.wanted)
+		}
+	}
+}
+
+func TestTreatNoRowsAsError(t *testing.T) {
+	span := recordSpan(t, newEvent(sql.ErrNoRows))
+	if span.Status().Code != codes.Unset {
+		t.Errorf("status = %s, wanted Unset", span.Status().Code)
+	}
+
+	span = recordSpan(t, newEvent(sql.ErrNoRows), WithTreatNoRowsAsError(true))
+	if span.Status().Code != codes.Error {
+		t.Errorf("status = %s, wanted Error", span.Status().Code)
+	}
+}
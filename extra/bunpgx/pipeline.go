@@ -0,0 +1,281 @@
+// Package bunpgx sends bun queries to PostgreSQL using pgx features
+// that database/sql doesn't expose.
+package bunpgx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/schema"
+)
+
+// ErrPipelineSkipped is the error of the queries that were not executed,
+// because a query queued before them failed.
+var ErrPipelineSkipped = errors.New("bunpgx: query skipped after a failed pipeline query")
+
+// Pipeline sends queued queries to PostgreSQL in a single round-trip
+// using the pipelining of the extended query protocol:
+//
+//	results, err := bunpgx.NewPipeline(conn).
+//		Queue(db.NewSelect().Model(&users)).
+//		Queue(db.NewUpdate().Model(&user).WherePK()).
+//		Send(ctx)
+//
+// The rows returned by the queries are scanned into the query models.
+// Relations that require separate queries are not loaded and model hooks are not called.
+//
+// PostgreSQL executes the pipeline in an implicit transaction unless it is sent
+// inside a transaction. When a query fails, the queries queued after it are skipped
+// and the changes made by the pipeline are rolled back, but the rows read by
+// the preceding queries are still scanned and returned.
+type Pipeline struct {
+	conn    *pgx.Conn
+	queries []bun.Query
+}
+
+func NewPipeline(conn *pgx.Conn) *Pipeline {
+	return &Pipeline{conn: conn}
+}
+
+// Queue adds the query to the pipeline.
+func (p *Pipeline) Queue(q bun.Query) *Pipeline {
+	p.queries = append(p.queries, q)
+	return p
+}
+
+// Len returns the number of queued queries.
+func (p *Pipeline) Len() int {
+	return len(p.queries)
+}
+
+// PipelineResult is the result of a pipeline query.
+type PipelineResult struct {
+	// Model is the query model that the returned rows were scanned into.
+	Model bun.Model
+	// RowsAffected is the number of rows affected or returned by the query.
+	RowsAffected int64
+	Err          error
+}
+
+// Send executes the queued queries and resets the pipeline. It returns a result
+// for each queued query and the first error. The results of the queries
+// that succeeded are returned even if a later query fails.
+func (p *Pipeline) Send(ctx context.Context) ([]PipelineResult, error) {
+	queries := p.queries
+	p.queries = nil
+
+	results := make([]PipelineResult, len(queries))
+	if len(queries) == 0 {
+		return results, nil
+	}
+
+	batch := new(pgconn.Batch)
+	for i, q := range queries {
+		results[i].Model = q.GetModel()
+
+		query, err := q.AppendQuery(pipelineFormatter(q), nil)
+		if err != nil {
+			return results, fmt.Errorf("bunpgx: query #%d: %w", i, err)
+		}
+		// The queries have the arguments inlined and use the text result format.
+		batch.ExecParams(string(query), nil, nil, nil, nil)
+	}
+
+	mrr := p.conn.PgConn().ExecBatch(ctx, batch)
+
+	var firstErr error
+	for i := range queries {
+		if firstErr != nil {
+			results[i].Err = ErrPipelineSkipped
+			continue
+		}
+		if !mrr.NextResult() {
+			firstErr = mrr.Close()
+			if firstErr == nil {
+				firstErr = io.ErrUnexpectedEOF
+			}
+			results[i].Err = firstErr
+			continue
+		}
+
+		res := &results[i]
+		res.RowsAffected, res.Err = readPipelineResult(ctx, mrr.ResultReader(), res.Model)
+		if res.Err != nil {
+			firstErr = res.Err
+		}
+	}
+
+	if err := mrr.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return results, firstErr
+}
+
+func pipelineFormatter(q bun.Query) schema.Formatter {
+	if q, ok := q.(interface{ DB() *bun.DB }); ok {
+		return q.DB().Formatter()
+	}
+	return schema.NewFormatter(pgdialect.New())
+}
+
+func readPipelineResult(
+	ctx context.Context, rr *pgconn.ResultReader, model bun.Model,
+) (int64, error) {
+	fields := rr.FieldDescriptions()
+	res := &pipelineRows{
+		columns: make([]string, len(fields)),
+	}
+	for i := range fields {
+		res.columns[i] = string(fields[i].Name)
+	}
+
+	for rr.NextRow() {
+		values := rr.Values()
+		row := make([]driver.Value, len(values))
+		for i, b := range values {
+			v, err := pipelineValue(fields[i].DataTypeOID, b)
+			if err != nil {
+				_, _ = rr.Close()
+				return 0, err
+			}
+			row[i] = v
+		}
+		res.rows = append(res.rows, row)
+	}
+
+	tag, err := rr.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(fields) == 0 || model == nil {
+		return tag.RowsAffected(), nil
+	}
+
+	rows, err := pipelineRowsDB().QueryContext(ctx, "", res)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if _, err := model.ScanRows(ctx, rows); err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Type OIDs of the values that are not returned as strings.
+const (
+	pgBoolOID  = 16
+	pgByteaOID = 17
+)
+
+// pipelineValue converts the value in the text format to the value that
+// bun scanners expect.
+func pipelineValue(oid uint32, b []byte) (driver.Value, error) {
+	if b == nil {
+		return nil, nil
+	}
+
+	switch oid {
+	case pgBoolOID:
+		return len(b) == 1 && b[0] == 't', nil
+	case pgByteaOID:
+		if !strings.HasPrefix(string(b), `\x`) {
+			return nil, fmt.Errorf("bunpgx: unsupported bytea format: %q", b)
+		}
+		return hex.DecodeString(string(b[2:]))
+	default:
+		return string(b), nil
+	}
+}
+
+//------------------------------------------------------------------------------
+
+var pipelineRowsDBOnce struct {
+	sync.Once
+	db *sql.DB
+}
+
+// pipelineRowsDB returns a database that returns the rows passed as the query argument,
+// so models can scan the rows read by the pipeline.
+func pipelineRowsDB() *sql.DB {
+	pipelineRowsDBOnce.Do(func() {
+		pipelineRowsDBOnce.db = sql.OpenDB(pipelineConnector{})
+	})
+	return pipelineRowsDBOnce.db
+}
+
+type pipelineConnector struct{}
+
+func (pipelineConnector) Connect(context.Context) (driver.Conn, error) {
+	return pipelineConn{}, nil
+}
+
+func (pipelineConnector) Driver() driver.Driver {
+	return pipelineDriver{}
+}
+
+type pipelineDriver struct{}
+
+func (pipelineDriver) Open(string) (driver.Conn, error) {
+	return pipelineConn{}, nil
+}
+
+var errPipelineConn = errors.New("bunpgx: pipeline conn only supports queries")
+
+type pipelineConn struct{}
+
+var (
+	_ driver.QueryerContext    = pipelineConn{}
+	_ driver.NamedValueChecker = pipelineConn{}
+)
+
+func (pipelineConn) Prepare(string) (driver.Stmt, error) { return nil, errPipelineConn }
+func (pipelineConn) Close() error                        { return nil }
+func (pipelineConn) Begin() (driver.Tx, error)           { return nil, errPipelineConn }
+
+// CheckNamedValue passes the rows to QueryContext as is.
+func (pipelineConn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (pipelineConn) QueryContext(
+	ctx context.Context, query string, args []driver.NamedValue,
+) (driver.Rows, error) {
+	if len(args) != 1 {
+		return nil, errPipelineConn
+	}
+	rows, ok := args[0].Value.(*pipelineRows)
+	if !ok {
+		return nil, errPipelineConn
+	}
+	return &pipelineRows{columns: rows.columns, rows: rows.rows}, nil
+}
+
+type pipelineRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *pipelineRows) Columns() []string { return r.columns }
+func (r *pipelineRows) Close() error      { return nil }
+
+func (r *pipelineRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,238 @@
+package bunpostgis
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	pointType   = 1
+	polygonType = 3
+
+	// ewkbSRIDFlag is set in the EWKB geometry type when the SRID follows the type.
+	ewkbSRIDFlag = 0x20000000
+	ewkbZFlag    = 0x80000000
+	ewkbMFlag    = 0x40000000
+)
+
+var typeNames = map[uint32]string{
+	pointType:   "POINT",
+	polygonType: "POLYGON",
+}
+
+// parseGeometry parses the geometry of the type from hex or binary EWKB or (E)WKT.
+// It returns nil for NULL.
+func parseGeometry(src interface{}, typ uint32) (interface{}, error) {
+	var s string
+	switch src := src.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		s = src
+	case []byte:
+		if len(src) > 0 && (src[0] == 0 || src[0] == 1) {
+			return parseWKB(src, typ)
+		}
+		s = string(src)
+	default:
+		return nil, fmt.Errorf("bunpostgis: can't scan %T", src)
+	}
+
+	if isHex(s) {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		return parseWKB(b, typ)
+	}
+	return parseWKT(s, typ)
+}
+
+func isHex(s string) bool {
+	if s == "" || len(s)%2 != 0 {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+//------------------------------------------------------------------------------
+
+type wkbReader struct {
+	b     []byte
+	order binary.ByteOrder
+	err   error
+}
+
+func (r *wkbReader) uint32() uint32 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.b) < 4 {
+		r.err = errors.New("bunpostgis: unexpected end of WKB")
+		return 0
+	}
+	n := r.order.Uint32(r.b)
+	r.b = r.b[4:]
+	return n
+}
+
+// count reads the number of the elements with at least size bytes each.
+func (r *wkbReader) count(size int) int {
+	n := r.uint32()
+	if r.err == nil && int64(n)*int64(size) > int64(len(r.b)) {
+		r.err = errors.New("bunpostgis: unexpected end of WKB")
+		return 0
+	}
+	return int(n)
+}
+
+func (r *wkbReader) float64() float64 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.b) < 8 {
+		r.err = errors.New("bunpostgis: unexpected end of WKB")
+		return 0
+	}
+	n := math.Float64frombits(r.order.Uint64(r.b))
+	r.b = r.b[8:]
+	return n
+}
+
+func (r *wkbReader) point() Point {
+	return Point{Lng: r.float64(), Lat: r.float64()}
+}
+
+func parseWKB(b []byte, typ uint32) (interface{}, error) {
+	if len(b) == 0 {
+		return nil, errors.New("bunpostgis: empty WKB")
+	}
+
+	r := &wkbReader{b: b[1:]}
+	switch b[0] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return nil, fmt.Errorf("bunpostgis: invalid WKB byte order: %d", b[0])
+	}
+
+	gotType := r.uint32()
+	if gotType&(ewkbZFlag|ewkbMFlag) != 0 {
+		return nil, errors.New("bunpostgis: only 2D geometries are supported")
+	}
+	if gotType&ewkbSRIDFlag != 0 {
+		_ = r.uint32() // SRID
+	}
+	if gotType &= 0xffff; gotType != typ {
+		return nil, fmt.Errorf("bunpostgis: can't scan WKB geometry type %d into %s",
+			gotType, typeNames[typ])
+	}
+
+	var g interface{}
+	switch typ {
+	case pointType:
+		g = r.point()
+	case polygonType:
+		polygon := make(Polygon, r.count(4))
+		for i := range polygon {
+			ring := make([]Point, r.count(16))
+			for j := range ring {
+				ring[j] = r.point()
+			}
+			polygon[i] = ring
+		}
+		g = polygon
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return g, nil
+}
+
+//------------------------------------------------------------------------------
+
+func parseWKT(s string, typ uint32) (interface{}, error) {
+	if strings.HasPrefix(s, "SRID=") {
+		i := strings.IndexByte(s, ';')
+		if i == -1 {
+			return nil, fmt.Errorf("bunpostgis: invalid EWKT: %q", s)
+		}
+		s = s[i+1:]
+	}
+
+	name := typeNames[typ]
+	body, ok := cutPrefixFold(strings.TrimSpace(s), name)
+	if !ok {
+		return nil, fmt.Errorf("bunpostgis: can't scan %q into %s", s, name)
+	}
+	body = strings.TrimSpace(body)
+	if !strings.HasPrefix(body, "(") || !strings.HasSuffix(body, ")") {
+		return nil, fmt.Errorf("bunpostgis: invalid WKT: %q", s)
+	}
+	body = body[1 : len(body)-1]
+
+	switch typ {
+	case pointType:
+		return parseWKTPoint(body)
+	default:
+		var polygon Polygon
+		for body = strings.TrimSpace(body); body != ""; {
+			if body[0] != '(' {
+				return nil, fmt.Errorf("bunpostgis: invalid WKT: %q", s)
+			}
+			end := strings.IndexByte(body, ')')
+			if end == -1 {
+				return nil, fmt.Errorf("bunpostgis: invalid WKT: %q", s)
+			}
+
+			var ring []Point
+			for _, coords := range strings.Split(body[1:end], ",") {
+				point, err := parseWKTPoint(coords)
+				if err != nil {
+					return nil, err
+				}
+				ring = append(ring, point)
+			}
+			polygon = append(polygon, ring)
+
+			body = strings.TrimSpace(body[end+1:])
+			body = strings.TrimSpace(strings.TrimPrefix(body, ","))
+		}
+		return polygon, nil
+	}
+}
+
+func parseWKTPoint(s string) (Point, error) {
+	coords := strings.Fields(s)
+	if len(coords) != 2 {
+		return Point{}, fmt.Errorf("bunpostgis: invalid WKT point: %q", s)
+	}
+	lng, err := strconv.ParseFloat(coords[0], 64)
+	if err != nil {
+		return Point{}, err
+	}
+	lat, err := strconv.ParseFloat(coords[1], 64)
+	if err != nil {
+		return Point{}, err
+	}
+	return Point{Lng: lng, Lat: lat}, nil
+}
+
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
@@ -0,0 +1,26 @@
+module github.com/uptrace/bun/extra/bunpostgis
+
+go 1.21
+
+toolchain go1.22.1
+
+replace github.com/uptrace/bun => ../..
+
+replace github.com/uptrace/bun/dialect/pgdialect => ../../dialect/pgdialect
+
+require (
+	github.com/stretchr/testify v1.8.1
+	github.com/uptrace/bun v1.2.1
+	github.com/uptrace/bun/dialect/pgdialect v1.2.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/tmthrgd/go-hex v0.0.0-20190904060850-447a3041c3bc // indirect
+	github.com/vmihailenco/msgpack/v5 v5.4.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/sys v0.18.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,153 @@
+// Package bunpostgis provides PostGIS geometry types and spatial query helpers.
+// Point and Polygon are written as EWKT with the WGS 84 SRID and read from EWKB,
+// which PostGIS returns for geometry and geography columns, or from (E)WKT:
+//
+//	type Place struct {
+//		ID       int64
+//		Location bunpostgis.Point `bun:"type:geometry(POINT,4326)"`
+//	}
+//
+//	err := db.NewSelect().
+//		Model(&places).
+//		Where("?", bunpostgis.STDWithin("location", center, 1000)).
+//		OrderExpr("?", bunpostgis.STDistance("location", center)).
+//		Scan(ctx)
+package bunpostgis
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strconv"
+
+	"github.com/uptrace/bun/schema"
+)
+
+// SRID is the spatial reference system of the values, WGS 84 longitude and latitude.
+const SRID = 4326
+
+// Point is a geographic point. Use the `type:geometry(POINT,4326)` or
+// `type:geography(POINT,4326)` tag option to create the column.
+type Point struct {
+	Lng float64
+	Lat float64
+}
+
+var (
+	_ driver.Valuer = Point{}
+	_ sql.Scanner   = (*Point)(nil)
+)
+
+// String returns the point as WKT, for example, POINT(30.5 50.45).
+func (p Point) String() string {
+	return string(p.appendWKT(nil))
+}
+
+func (p Point) appendWKT(b []byte) []byte {
+	b = append(b, "POINT("...)
+	b = p.appendCoords(b)
+	return append(b, ')')
+}
+
+func (p Point) appendCoords(b []byte) []byte {
+	b = strconv.AppendFloat(b, p.Lng, 'f', -1, 64)
+	b = append(b, ' ')
+	return strconv.AppendFloat(b, p.Lat, 'f', -1, 64)
+}
+
+// Value returns the point as EWKT, for example, SRID=4326;POINT(30.5 50.45).
+func (p Point) Value() (driver.Value, error) {
+	return string(p.appendWKT(appendSRID(nil))), nil
+}
+
+// Scan scans EWKB or (E)WKT of a point.
+func (p *Point) Scan(src interface{}) error {
+	g, err := parseGeometry(src, pointType)
+	if err != nil {
+		return err
+	}
+	if g == nil {
+		*p = Point{}
+		return nil
+	}
+	*p = g.(Point)
+	return nil
+}
+
+// Polygon is a polygon with the exterior ring followed by the interior rings (holes).
+// The rings must be closed, that is, the first and the last points must be equal.
+// Use the `type:geometry(POLYGON,4326)` tag option to create the column.
+type Polygon [][]Point
+
+var (
+	_ driver.Valuer = Polygon{}
+	_ sql.Scanner   = (*Polygon)(nil)
+)
+
+// String returns the polygon as WKT, for example, POLYGON((0 0, 1 0, 1 1, 0 0)).
+func (p Polygon) String() string {
+	return string(p.appendWKT(nil))
+}
+
+func (p Polygon) appendWKT(b []byte) []byte {
+	b = append(b, "POLYGON("...)
+	for i, ring := range p {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = append(b, '(')
+		for j, point := range ring {
+			if j > 0 {
+				b = append(b, ", "...)
+			}
+			b = point.appendCoords(b)
+		}
+		b = append(b, ')')
+	}
+	return append(b, ')')
+}
+
+// Value returns the polygon as EWKT or nil if the polygon is empty.
+func (p Polygon) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	return string(p.appendWKT(appendSRID(nil))), nil
+}
+
+// Scan scans EWKB or (E)WKT of a polygon.
+func (p *Polygon) Scan(src interface{}) error {
+	g, err := parseGeometry(src, polygonType)
+	if err != nil {
+		return err
+	}
+	if g == nil {
+		*p = nil
+		return nil
+	}
+	*p = g.(Polygon)
+	return nil
+}
+
+func appendSRID(b []byte) []byte {
+	b = append(b, "SRID="...)
+	b = strconv.AppendInt(b, SRID, 10)
+	return append(b, ';')
+}
+
+//------------------------------------------------------------------------------
+
+// STDistance returns `ST_Distance(col::geography, point::geography)` expression that
+// computes the distance between the column and the point in meters.
+func STDistance(col string, p Point) schema.QueryWithArgs {
+	return schema.SafeQuery("ST_Distance(?::geography, ?::geography)",
+		[]interface{}{schema.Ident(col), p})
+}
+
+// STDWithin returns `ST_DWithin(col::geography, point::geography, meters)` expression
+// that checks that the column is within the distance from the point in meters.
+// To use an index, create it on the `(col::geography)` expression or
+// use a geography column.
+func STDWithin(col string, p Point, meters float64) schema.QueryWithArgs {
+	return schema.SafeQuery("ST_DWithin(?::geography, ?::geography, ?)",
+		[]interface{}{schema.Ident(col), p, meters})
+}
@@ -0,0 +1,107 @@
+package bunpostgis_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/extra/bunpostgis"
+	"github.com/uptrace/bun/schema"
+)
+
+func TestPointValue(t *testing.T) {
+	v, err := bunpostgis.Point{Lng: 30.5, Lat: -50.45}.Value()
+	require.NoError(t, err)
+	require.Equal(t, "SRID=4326;POINT(30.5 -50.45)", v)
+
+	v, err = bunpostgis.Polygon{
+		{{0, 0}, {1, 0}, {1, 1}, {0, 0}},
+		{{0.2, 0.2}, {0.5, 0.2}, {0.5, 0.5}, {0.2, 0.2}},
+	}.Value()
+	require.NoError(t, err)
+	require.Equal(t,
+		"SRID=4326;POLYGON((0 0, 1 0, 1 1, 0 0), (0.2 0.2, 0.5 0.2, 0.5 0.5, 0.2 0.2))", v)
+}
+
+func TestPointScan(t *testing.T) {
+	tests := []struct {
+		src    interface{}
+		wanted bunpostgis.Point
+	}{
+		{nil, bunpostgis.Point{}},
+		// SELECT 'SRID=4326;POINT(30.5 50.45)'::geometry
+		{"0101000020E61000000000000000803E409A99999999394940", bunpostgis.Point{Lng: 30.5, Lat: 50.45}},
+		// SELECT ST_AsBinary('POINT(1 2)'::geometry), big-endian.
+		{[]byte{
+			0x00, 0x00, 0x00, 0x00, 0x01,
+			0x3f, 0xf0, 0, 0, 0, 0, 0, 0,
+			0x40, 0x00, 0, 0, 0, 0, 0, 0,
+		}, bunpostgis.Point{Lng: 1, Lat: 2}},
+		{"SRID=4326;POINT(-1.5 2)", bunpostgis.Point{Lng: -1.5, Lat: 2}},
+		{[]byte("point (1 2)"), bunpostgis.Point{Lng: 1, Lat: 2}},
+	}
+
+	for _, test := range tests {
+		p := bunpostgis.Point{Lng: 9, Lat: 9}
+		err := p.Scan(test.src)
+		require.NoError(t, err)
+		require.Equal(t, test.wanted, p)
+	}
+
+	var p bunpostgis.Point
+	require.Error(t, p.Scan("POLYGON((0 0, 1 0, 1 1, 0 0))"))
+	require.Error(t, p.Scan("0101000020E6100000000000000080"))
+}
+
+func TestPolygonScan(t *testing.T) {
+	wanted := bunpostgis.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}
+
+	// SELECT 'SRID=4326;POLYGON((0 0, 1 0, 1 1, 0 0))'::geometry
+	var p bunpostgis.Polygon
+	err := p.Scan("0103000020E61000000100000004000000" +
+		"00000000000000000000000000000000" +
+		"000000000000F03F0000000000000000" +
+		"000000000000F03F000000000000F03F" +
+		"00000000000000000000000000000000")
+	require.NoError(t, err)
+	require.Equal(t, wanted, p)
+
+	p = nil
+	require.NoError(t, p.Scan("POLYGON((0 0,1 0,1 1,0 0))"))
+	require.Equal(t, wanted, p)
+
+	require.NoError(t, p.Scan(nil))
+	require.Nil(t, p)
+}
+
+func TestQueries(t *testing.T) {
+	type Place struct {
+		ID       int64            `bun:",pk,autoincrement"`
+		Location bunpostgis.Point `bun:"type:geometry(POINT,4326)"`
+	}
+
+	db := bun.NewDB(nil, pgdialect.New())
+	center := bunpostgis.Point{Lng: 30.5, Lat: 50.45}
+
+	query := db.NewCreateTable().Model((*Place)(nil)).String()
+	require.Equal(t,
+		`CREATE TABLE "places" ("id" BIGSERIAL NOT NULL, "location" geometry(POINT,4326), PRIMARY KEY ("id"))`,
+		query)
+
+	query = db.NewSelect().
+		Model((*Place)(nil)).
+		Where("?", bunpostgis.STDWithin("place.location", center, 1000)).
+		OrderExpr("?", bunpostgis.STDistance("place.location", center)).
+		String()
+	require.Equal(t, `SELECT "place"."id", "place"."location" FROM "places" AS "place" `+
+		`WHERE (ST_DWithin("place"."location"::geography, 'SRID=4326;POINT(30.5 50.45)'::geography, 1000)) `+
+		`ORDER BY ST_Distance("place"."location"::geography, 'SRID=4326;POINT(30.5 50.45)'::geography)`,
+		query)
+
+	fmter := schema.NewFormatter(pgdialect.New())
+	b, err := bunpostgis.STDistance("location", center).AppendQuery(fmter, nil)
+	require.NoError(t, err)
+	require.Equal(t, `ST_Distance("location"::geography, 'SRID=4326;POINT(30.5 50.45)'::geography)`, string(b))
+}
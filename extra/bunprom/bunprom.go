@@ -0,0 +1,213 @@
+package bunprom
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/uptrace/bun"
+)
+
+type Option func(h *QueryHook)
+
+// WithDBName adds a db_name label to the metrics, so multiple databases
+// can share a registry. All hooks that share a registry must use the option.
+func WithDBName(name string) Option {
+	return func(h *QueryHook) {
+		h.constLabels = prometheus.Labels{"db_name": name}
+	}
+}
+
+// WithDurationBuckets configures the buckets of the query duration histogram.
+// The default is prometheus.DefBuckets.
+func WithDurationBuckets(buckets []float64) Option {
+	return func(h *QueryHook) {
+		h.buckets = buckets
+	}
+}
+
+// QueryHook records Prometheus metrics for queries and, once added to a DB,
+// for the connection pool.
+type QueryHook struct {
+	registerer  prometheus.Registerer
+	constLabels prometheus.Labels
+	buckets     []float64
+
+	duration *prometheus.HistogramVec
+	queries  *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	inFlight prometheus.Gauge
+}
+
+var _ bun.QueryHook = (*QueryHook)(nil)
+
+// NewQueryHook returns a hook that registers the metrics with the registerer.
+// If the registerer is nil, prometheus.DefaultRegisterer is used.
+// Metrics that are already registered are reused.
+func NewQueryHook(registerer prometheus.Registerer, opts ...Option) *QueryHook {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	h := &QueryHook{
+		registerer: registerer,
+		buckets:    prometheus.DefBuckets,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	labels := []string{"operation", "table", "status"}
+
+	h.duration = register(registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:        "db_sql_query_duration_seconds",
+		Help:        "Duration of queries.",
+		ConstLabels: h.constLabels,
+		Buckets:     h.buckets,
+	}, labels))
+	h.queries = register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:        "db_sql_queries_total",
+		Help:        "Number of queries.",
+		ConstLabels: h.constLabels,
+	}, labels))
+	h.errors = register(registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:        "db_sql_errors_total",
+		Help:        "Number of failed queries.",
+		ConstLabels: h.constLabels,
+	}, labels[:2]))
+	h.inFlight = register(registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        "db_sql_queries_in_flight",
+		Help:        "Number of queries that are being executed.",
+		ConstLabels: h.constLabels,
+	}))
+
+	return h
+}
+
+// Init registers the connection pool metrics of the db. It is called by DB.AddQueryHook.
+func (h *QueryHook) Init(db *bun.DB) {
+	register[prometheus.Collector](h.registerer, newStatsCollector(db.DB, h.constLabels))
+}
+
+func (h *QueryHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	h.inFlight.Inc()
+	return ctx
+}
+
+func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	h.inFlight.Dec()
+
+	var table string
+	if event.IQuery != nil {
+		table = event.IQuery.GetTableName()
+	}
+	operation := event.Operation()
+
+	status := "ok"
+	switch {
+	case event.Err == nil, errors.Is(event.Err, sql.ErrNoRows):
+	default:
+		status = "error"
+		h.errors.WithLabelValues(operation, table).Inc()
+	}
+
+	h.queries.WithLabelValues(operation, table, status).Inc()
+	h.duration.WithLabelValues(operation, table, status).
+		Observe(time.Since(event.StartTime).Seconds())
+}
+
+func register[T prometheus.Collector](registerer prometheus.Registerer, c T) T {
+	if err := registerer.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return c
+}
+
+//------------------------------------------------------------------------------
+
+// statsCollector reports sql.DBStats when the metrics are collected.
+type statsCollector struct {
+	db *sql.DB
+
+	maxOpen           *prometheus.Desc
+	open              *prometheus.Desc
+	inUse             *prometheus.Desc
+	idle              *prometheus.Desc
+	waitCount         *prometheus.Desc
+	waitDuration      *prometheus.Desc
+	maxIdleClosed     *prometheus.Desc
+	maxIdleTimeClosed *prometheus.Desc
+	maxLifetimeClosed *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*statsCollector)(nil)
+
+func newStatsCollector(db *sql.DB, constLabels prometheus.Labels) *statsCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(name, help, nil, constLabels)
+	}
+	return &statsCollector{
+		db: db,
+
+		maxOpen: desc("db_sql_connections_max_open",
+			"Maximum number of open connections."),
+		open: desc("db_sql_connections_open",
+			"Number of open connections."),
+		inUse: desc("db_sql_connections_in_use",
+			"Number of connections in use."),
+		idle: desc("db_sql_connections_idle",
+			"Number of idle connections."),
+		waitCount: desc("db_sql_connections_wait_total",
+			"Number of connections waited for."),
+		waitDuration: desc("db_sql_connections_wait_duration_seconds_total",
+			"Time blocked waiting for a new connection."),
+		maxIdleClosed: desc("db_sql_connections_closed_max_idle_total",
+			"Number of connections closed due to SetMaxIdleConns."),
+		maxIdleTimeClosed: desc("db_sql_connections_closed_max_idle_time_total",
+			"Number of connections closed due to SetConnMaxIdleTime."),
+		maxLifetimeClosed: desc("db_sql_connections_closed_max_lifetime_total",
+			"Number of connections closed due to SetConnMaxLifetime."),
+	}
+}
+
+func (c *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxOpen
+	ch <- c.open
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+	ch <- c.maxIdleClosed
+	ch <- c.maxIdleTimeClosed
+	ch <- c.maxLifetimeClosed
+}
+
+func (c *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.db.Stats()
+
+	gauge := func(desc *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v)
+	}
+	counter := func(desc *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, v)
+	}
+
+	gauge(c.maxOpen, float64(stats.MaxOpenConnections))
+	gauge(c.open, float64(stats.OpenConnections))
+	gauge(c.inUse, float64(stats.InUse))
+	gauge(c.idle, float64(stats.Idle))
+	counter(c.waitCount, float64(stats.WaitCount))
+	counter(c.waitDuration, stats.WaitDuration.Seconds())
+	counter(c.maxIdleClosed, float64(stats.MaxIdleClosed))
+	counter(c.maxIdleTimeClosed, float64(stats.MaxIdleTimeClosed))
+	counter(c.maxLifetimeClosed, float64(stats.MaxLifetimeClosed))
+}
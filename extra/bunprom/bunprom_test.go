@@ -0,0 +1,105 @@
+package bunprom
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/driver/sqliteshim"
+)
+
+type Model struct {
+	ID   int64 `bun:",pk,autoincrement"`
+	Name string
+}
+
+func newDB(t *testing.T) *bun.DB {
+	sqldb, err := sql.Open(sqliteshim.ShimName, "file::memory:?cache=shared")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqldb.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqldb.Close() })
+
+	return bun.NewDB(sqldb, sqlitedialect.New())
+}
+
+func TestQueryHook(t *testing.T) {
+	ctx := context.Background()
+	db := newDB(t)
+
+	registry := prometheus.NewPedanticRegistry()
+	h := NewQueryHook(registry)
+	db.AddQueryHook(h)
+
+	if _, err := db.NewCreateTable().Model((*Model)(nil)).Exec(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := db.NewInsert().Model(&Model{Name: "foo"}).Exec(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.NewSelect().Model(new(Model)).Where("id = 100").Scan(ctx); err != sql.ErrNoRows {
+		t.Fatalf("got %v, wanted sql.ErrNoRows", err)
+	}
+	if err := db.NewSelect().Model(new(Model)).Where("missing = 1").Scan(ctx); err == nil {
+		t.Fatal("wanted an error")
+	}
+
+	for _, test := range []struct {
+		labels []string
+		wanted float64
+	}{
+		{[]string{"CREATE TABLE", "models", "ok"}, 1},
+		{[]string{"INSERT", "models", "ok"}, 3},
+		{[]string{"SELECT", "models", "ok"}, 1},
+		{[]string{"SELECT", "models", "error"}, 1},
+	} {
+		got := testutil.ToFloat64(h.queries.WithLabelValues(test.labels...))
+		if got != test.wanted {
+			t.Errorf("db_sql_queries_total%v = %v, wanted %v", test.labels, got, test.wanted)
+		}
+	}
+
+	if got := testutil.ToFloat64(h.errors.WithLabelValues("SELECT", "models")); got != 1 {
+		t.Errorf("db_sql_errors_total = %v, wanted 1", got)
+	}
+	if got := testutil.ToFloat64(h.inFlight); got != 0 {
+		t.Errorf("db_sql_queries_in_flight = %v, wanted 0", got)
+	}
+	if n := testutil.CollectAndCount(registry, "db_sql_query_duration_seconds"); n != 4 {
+		t.Errorf("got %d histograms, wanted 4", n)
+	}
+
+	err := testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP db_sql_connections_max_open Maximum number of open connections.
+# TYPE db_sql_connections_max_open gauge
+db_sql_connections_max_open 1
+`), "db_sql_connections_max_open")
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestQueryHookSharedRegistry(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	h1 := NewQueryHook(registry, WithDBName("main"))
+	h2 := NewQueryHook(registry, WithDBName("main"))
+	if h1.queries != h2.queries {
+		t.Error("wanted metrics to be reused")
+	}
+
+	NewQueryHook(registry, WithDBName("main")).Init(newDB(t))
+	NewQueryHook(registry, WithDBName("other")).Init(newDB(t))
+	if n := testutil.CollectAndCount(registry, "db_sql_connections_open"); n != 2 {
+		t.Errorf("got %d metrics, wanted 2", n)
+	}
+}
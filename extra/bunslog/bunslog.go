@@ -8,6 +8,8 @@ import (
 	"database/sql"
 	"errors"
 	"log/slog"
+	"runtime"
+	"strings"
 	"time"
 
 	"github.com/uptrace/bun"
@@ -58,6 +60,22 @@ func WithLogFormat(f logFormat) Option {
 	}
 }
 
+// WithMinDuration sets the minimum duration of logged queries.
+// Queries that fail are logged regardless of the duration.
+func WithMinDuration(d time.Duration) Option {
+	return func(h *QueryHook) {
+		h.minDuration = d
+	}
+}
+
+// WithLogValues enables logging of the query arguments as the "args" attribute
+// when they are available, that is, for queries executed with DB.ExecContext and friends.
+func WithLogValues(on bool) Option {
+	return func(h *QueryHook) {
+		h.logValues = on
+	}
+}
+
 type logFormat func(event *bun.QueryEvent) []slog.Attr
 
 // QueryHook is a hook for Bun that enables logging with slog.
@@ -68,6 +86,8 @@ type QueryHook struct {
 	slowQueryLogLevel  slog.Level
 	errorLogLevel      slog.Level
 	slowQueryThreshold time.Duration
+	minDuration        time.Duration
+	logValues          bool
 	logFormat          func(event *bun.QueryEvent) []slog.Attr
 	now                func() time.Time
 }
@@ -95,6 +115,7 @@ func NewQueryHook(opts ...Option) *QueryHook {
 				slog.String("operation", event.Operation()),
 				slog.String("query", event.Query),
 				slog.String("duration", duration.String()),
+				slog.Int64("rows_affected", rowsAffected(event)),
 			}
 		}
 	}
@@ -109,6 +130,7 @@ func (h *QueryHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) cont
 
 // AfterQuery is called after a query is executed.
 // It logs the query based on its duration and whether it resulted in an error.
+// The source of the record is the caller that executed the query.
 func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
 	level := h.queryLogLevel
 	duration := h.now().Sub(event.StartTime)
@@ -116,17 +138,65 @@ func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
 		level = h.slowQueryLogLevel
 	}
 
-	if event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows) {
+	isErr := event.Err != nil && !errors.Is(event.Err, sql.ErrNoRows)
+	if isErr {
 		level = h.errorLogLevel
+	} else if duration < h.minDuration {
+		return
 	}
 
-	attrs := h.logFormat(event)
-	if h.logger != nil {
-		h.logger.LogAttrs(ctx, level, "", attrs...)
+	logger := h.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if !logger.Enabled(ctx, level) {
 		return
 	}
 
-	slog.LogAttrs(ctx, level, "", attrs...)
+	attrs := h.logFormat(event)
+	if h.logValues && len(event.QueryArgs) > 0 {
+		attrs = append(attrs, slog.Any("args", event.QueryArgs))
+	}
+
+	r := slog.NewRecord(h.now(), level, "", callerPC())
+	r.AddAttrs(attrs...)
+	_ = logger.Handler().Handle(ctx, r)
+}
+
+func rowsAffected(event *bun.QueryEvent) int64 {
+	if event.Result == nil {
+		return 0
+	}
+	n, err := event.Result.RowsAffected()
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// callerPC returns the program counter of the first caller outside of Bun packages.
+func callerPC() uintptr {
+	var pcs [32]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isBunFrame(frame) {
+			return frame.PC
+		}
+		if !more {
+			return 0
+		}
+	}
+}
+
+func isBunFrame(frame runtime.Frame) bool {
+	const bunPkg = "github.com/uptrace/bun"
+	if !strings.HasPrefix(frame.Function, bunPkg) || strings.HasSuffix(frame.File, "_test.go") {
+		return false
+	}
+	rest := frame.Function[len(bunPkg):]
+	return rest == "" || rest[0] == '.' || rest[0] == '/'
 }
 
 var (
@@ -3,9 +3,11 @@ package bunslog
 import (
 	"bytes"
 	"context"
+	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"log/slog"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
@@ -199,3 +201,71 @@ func TestAfterQuery(t *testing.T) {
 		}
 	})
 }
+
+func TestQueryHookOptions(t *testing.T) {
+	now := time.Date(2006, 1, 2, 15, 4, 5, 0, time.Local)
+
+	type record struct {
+		Source struct {
+			File string `json:"file"`
+		} `json:"source"`
+		Query        string `json:"query"`
+		RowsAffected int64  `json:"rows_affected"`
+		Args         []int  `json:"args"`
+	}
+
+	log := func(opts []Option, event *bun.QueryEvent) (record, bool) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+			Level:     slog.LevelDebug,
+			AddSource: true,
+		}))
+		hook := NewQueryHook(append(opts, WithLogger(logger))...)
+		hook.now = func() time.Time { return now }
+		hook.AfterQuery(context.Background(), event)
+
+		var rec record
+		if buf.Len() == 0 {
+			return rec, false
+		}
+		if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+			t.Fatalf("failed to unmarshal JSON: %v", err)
+		}
+		return rec, true
+	}
+
+	event := &bun.QueryEvent{
+		Query:     "DELETE FROM users WHERE id = 1",
+		QueryArgs: []interface{}{1},
+		StartTime: now.Add(-time.Second),
+		Result:    driver.RowsAffected(3),
+	}
+
+	rec, ok := log(nil, event)
+	if !ok {
+		t.Fatal("query is not logged")
+	}
+	if rec.RowsAffected != 3 {
+		t.Errorf("got rows_affected=%d, wanted 3", rec.RowsAffected)
+	}
+	if rec.Args != nil {
+		t.Errorf("got args=%v, wanted none", rec.Args)
+	}
+	if filepath.Base(rec.Source.File) != "bunslog_test.go" {
+		t.Errorf("got source %q, wanted the caller", rec.Source.File)
+	}
+
+	rec, _ = log([]Option{WithLogValues(true)}, event)
+	if !reflect.DeepEqual(rec.Args, []int{1}) {
+		t.Errorf("got args=%v, wanted [1]", rec.Args)
+	}
+
+	if _, ok := log([]Option{WithMinDuration(2 * time.Second)}, event); ok {
+		t.Error("fast query is logged")
+	}
+
+	event.Err = errors.New("unexpected error")
+	if _, ok := log([]Option{WithMinDuration(2 * time.Second)}, event); !ok {
+		t.Error("failed query is not logged")
+	}
+}
@@ -0,0 +1,96 @@
+package bunslog
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+// SlowQueryOption is a function that configures a SlowQueryHook.
+type SlowQueryOption func(*SlowQueryHook)
+
+// WithTestLogging enables logging when the binary is a test binary.
+func WithTestLogging() SlowQueryOption {
+	return func(h *SlowQueryHook) {
+		h.testLogging = true
+	}
+}
+
+// WithFormatQuery sets a function that formats the logged query,
+// for example, to redact or truncate long queries.
+func WithFormatQuery(fn func(query string) string) SlowQueryOption {
+	return func(h *SlowQueryHook) {
+		h.formatQuery = fn
+	}
+}
+
+// SlowQueryHook logs queries that take longer than a threshold at the WARN level.
+// It implements bun.QueryHook interface.
+type SlowQueryHook struct {
+	threshold   time.Duration
+	logger      *slog.Logger
+	testLogging bool
+	formatQuery func(query string) string
+	now         func() time.Time
+}
+
+var _ bun.QueryHook = (*SlowQueryHook)(nil)
+
+// NewSlowQueryHook returns a hook that logs queries that take longer than the threshold.
+// If the logger is nil, slog.Default is used. The hook doesn't log in tests
+// unless WithTestLogging is used.
+func NewSlowQueryHook(threshold time.Duration, logger *slog.Logger, opts ...SlowQueryOption) *SlowQueryHook {
+	h := &SlowQueryHook{
+		threshold: threshold,
+		logger:    logger,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// BeforeQuery is called before a query is executed.
+func (h *SlowQueryHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+// AfterQuery logs the query if it took longer than the threshold.
+func (h *SlowQueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	duration := h.now().Sub(event.StartTime)
+	if duration < h.threshold {
+		return
+	}
+	if testing.Testing() && !h.testLogging {
+		return
+	}
+
+	query := event.Query
+	if h.formatQuery != nil {
+		query = h.formatQuery(query)
+	}
+
+	attrs := []slog.Attr{
+		slog.String("query", query),
+		slog.Duration("duration", duration),
+		slog.String("operation", event.Operation()),
+	}
+	if event.IQuery != nil {
+		if table := event.IQuery.GetTableName(); table != "" {
+			attrs = append(attrs, slog.String("table", table))
+		}
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.Any("error", event.Err))
+	}
+
+	logger := h.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.LogAttrs(ctx, slog.LevelWarn, "slow query", attrs...)
+}
@@ -0,0 +1,105 @@
+package bunslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+func TestSlowQueryHook(t *testing.T) {
+	now := time.Date(2006, 1, 2, 15, 4, 5, 0, time.Local)
+
+	newEvent := func(duration time.Duration, err error) *bun.QueryEvent {
+		return &bun.QueryEvent{
+			Query:     "SELECT * FROM users WHERE name = 'john'",
+			StartTime: now.Add(-duration),
+			Err:       err,
+		}
+	}
+
+	type record struct {
+		Level     string `json:"level"`
+		Msg       string `json:"msg"`
+		Query     string `json:"query"`
+		Duration  int64  `json:"duration"`
+		Operation string `json:"operation"`
+		Error     string `json:"error"`
+	}
+
+	testCases := []struct {
+		name   string
+		opts   []SlowQueryOption
+		event  *bun.QueryEvent
+		expect *record
+	}{
+		{
+			name:  "fast query is not logged",
+			opts:  []SlowQueryOption{WithTestLogging()},
+			event: newEvent(time.Second, nil),
+		},
+		{
+			name:  "slow query is not logged in tests",
+			event: newEvent(3*time.Second, nil),
+		},
+		{
+			name:  "slow query",
+			opts:  []SlowQueryOption{WithTestLogging()},
+			event: newEvent(3*time.Second, nil),
+			expect: &record{
+				Level:     "WARN",
+				Msg:       "slow query",
+				Query:     "SELECT * FROM users WHERE name = 'john'",
+				Duration:  int64(3 * time.Second),
+				Operation: "SELECT",
+			},
+		},
+		{
+			name: "slow query with error and formatted query",
+			opts: []SlowQueryOption{
+				WithTestLogging(),
+				WithFormatQuery(func(query string) string { return query[:13] + "..." }),
+			},
+			event: newEvent(5*time.Second, errors.New("canceled")),
+			expect: &record{
+				Level:     "WARN",
+				Msg:       "slow query",
+				Query:     "SELECT * FROM...",
+				Duration:  int64(5 * time.Second),
+				Operation: "SELECT",
+				Error:     "canceled",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+			hook := NewSlowQueryHook(2*time.Second, logger, tc.opts...)
+			hook.now = func() time.Time { return now }
+			hook.AfterQuery(context.Background(), tc.event)
+
+			if tc.expect == nil {
+				if buf.Len() > 0 {
+					t.Errorf("unexpected log: %s", buf.String())
+				}
+				return
+			}
+
+			var got record
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("failed to unmarshal %q: %s", buf.String(), err)
+			}
+			if got != *tc.expect {
+				t.Errorf("got %+v, wanted %+v", got, *tc.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package bun
+
+import (
+	"strings"
+)
+
+// Fingerprint normalizes the query so queries that differ only in values produce
+// the same string, for example, to be used as a metric label:
+//
+//   - literal values and placeholders ($1 and ?) are replaced with ?,
+//   - IN lists and multi-row VALUES are collapsed to a single element,
+//   - comments are removed, whitespace is collapsed, and unquoted words are lowercased.
+//
+// Quoted identifiers are kept as is.
+func Fingerprint(query string) string {
+	tokens := tokenizeFingerprint(query)
+	tokens = collapseFingerprintLists(tokens)
+
+	var b strings.Builder
+	b.Grow(len(query))
+
+	for i, tok := range tokens {
+		if i > 0 && needsSpace(tokens[i-1], tok) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(tok)
+	}
+	return b.String()
+}
+
+func needsSpace(prev, tok string) bool {
+	switch {
+	case prev == "(" || prev == ".":
+		return false
+	case tok == ")" || tok == "," || tok == "." || tok == ";":
+		return false
+	case tok == "(":
+		// Function calls: count(*), but IN (?) and "table" (columns).
+		return !isFingerprintWord(prev) || isFingerprintKeyword(prev)
+	}
+	return true
+}
+
+// isFingerprintWord reports whether the token is an unquoted word.
+func isFingerprintWord(tok string) bool {
+	return isFingerprintIdentByte(tok[0])
+}
+
+func isFingerprintKeyword(tok string) bool {
+	switch tok {
+	case "in", "values", "as", "on", "and", "or", "not", "exists", "from", "join",
+		"where", "select", "using", "into", "over", "filter", "within":
+		return true
+	}
+	return false
+}
+
+// tokenizeFingerprint splits the query into normalized tokens.
+func tokenizeFingerprint(query string) []string {
+	tokens := make([]string, 0, len(query)/4)
+
+	for i := 0; i < len(query); {
+		c := query[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end == -1 {
+				return tokens
+			}
+			i += end + 1
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end == -1 {
+				return tokens
+			}
+			i += end + 4
+		case c == '\'':
+			i = skipStringLiteral(query, i)
+			tokens = appendLiteral(tokens)
+		case c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			end := strings.IndexByte(query[i+1:], closing)
+			if end == -1 {
+				tokens = append(tokens, query[i:])
+				return tokens
+			}
+			tokens = append(tokens, query[i:i+end+2])
+			i += end + 2
+		case c == '?':
+			tokens = append(tokens, "?")
+			i++
+		case c == '$' && i+1 < len(query) && isFingerprintDigit(query[i+1]):
+			i++
+			for i < len(query) && isFingerprintDigit(query[i]) {
+				i++
+			}
+			tokens = append(tokens, "?")
+		case isFingerprintDigit(c) ||
+			(c == '.' && i+1 < len(query) && isFingerprintDigit(query[i+1])):
+			for i < len(query) && (isFingerprintDigit(query[i]) || query[i] == '.' ||
+				query[i] == 'e' || query[i] == 'E') {
+				i++
+			}
+			tokens = appendLiteral(tokens)
+		case isFingerprintIdentByte(c):
+			start := i
+			for i < len(query) && (isFingerprintIdentByte(query[i]) || isFingerprintDigit(query[i])) {
+				i++
+			}
+			word := strings.ToLower(query[start:i])
+
+			// Prefixed string literals, e.g. E'\n' or X'00'.
+			if len(word) == 1 && i < len(query) && query[i] == '\'' && strings.Contains("ebnx", word) {
+				i = skipStringLiteral(query, i)
+				tokens = appendLiteral(tokens)
+				continue
+			}
+
+			switch word {
+			case "true", "false", "null":
+				tokens = appendLiteral(tokens)
+			default:
+				tokens = append(tokens, word)
+			}
+		default:
+			// Operators: keep multi-character operators together.
+			start := i
+			i++
+			if isFingerprintOperator(c) {
+				for i < len(query) && isFingerprintOperator(query[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, query[start:i])
+		}
+	}
+
+	return tokens
+}
+
+// appendLiteral appends a ? placeholder. A minus sign before a number is folded
+// into the placeholder when it can't be a binary operator.
+func appendLiteral(tokens []string) []string {
+	if n := len(tokens); n >= 2 && tokens[n-1] == "-" {
+		switch prev := tokens[n-2]; prev {
+		case "(", ",", "=", "<", ">", "<=", ">=", "<>", "!=":
+			tokens = tokens[:n-1]
+		default:
+			if isFingerprintKeyword(prev) {
+				tokens = tokens[:n-1]
+			}
+		}
+	}
+	return append(tokens, "?")
+}
+
+func skipStringLiteral(query string, i int) int {
+	for i++; i < len(query); i++ {
+		if query[i] != '\'' {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == '\'' {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(query)
+}
+
+// collapseFingerprintLists replaces IN (?, ?, ...) with IN (?) and
+// VALUES (...), (...) with VALUES (...).
+func collapseFingerprintLists(tokens []string) []string {
+	out := tokens[:0]
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		out = append(out, tok)
+
+		switch tok {
+		case "in":
+			if end := placeholderListEnd(tokens, i+1); end != -1 {
+				out = append(out, "(", "?", ")")
+				i = end
+			}
+		case "values":
+			end := groupEnd(tokens, i+1)
+			if end == -1 {
+				continue
+			}
+			out = append(out, tokens[i+1:end+1]...)
+			i = end
+			// Skip the remaining rows.
+			for i+2 < len(tokens) && tokens[i+1] == "," && tokens[i+2] == "(" {
+				next := groupEnd(tokens, i+2)
+				if next == -1 {
+					break
+				}
+				i = next
+			}
+		}
+	}
+
+	return out
+}
+
+// placeholderListEnd returns the index of ")" if tokens[start:] is (?, ?, ...).
+func placeholderListEnd(tokens []string, start int) int {
+	if start >= len(tokens) || tokens[start] != "(" {
+		return -1
+	}
+	for i := start + 1; i < len(tokens); i += 2 {
+		if tokens[i] != "?" || i+1 >= len(tokens) {
+			return -1
+		}
+		switch tokens[i+1] {
+		case ")":
+			return i + 1
+		case ",":
+		default:
+			return -1
+		}
+	}
+	return -1
+}
+
+// groupEnd returns the index of the ")" that closes tokens[start] == "(".
+func groupEnd(tokens []string, start int) int {
+	if start >= len(tokens) || tokens[start] != "(" {
+		return -1
+	}
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isFingerprintDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isFingerprintIdentByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isFingerprintOperator(c byte) bool {
+	return strings.IndexByte("<>=!|&~+-*/%^@#:", c) != -1
+}
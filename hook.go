@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"strings"
-	"sync/atomic"
 	"time"
 	"unicode"
 
@@ -21,11 +20,60 @@ type QueryEvent struct {
 	QueryArgs     []interface{}
 	Model         Model
 
+	// Columns are the result columns of queries that return rows.
+	// They are set before the rows are scanned.
+	Columns []string
+	// RowsAffected is the number of affected or scanned rows.
+	RowsAffected int64
+
+	// QueryModified reports whether a BeforeQuery hook changed Query,
+	// in which case the changed query is executed. Use SetQuery or AddComment to set it.
+	QueryModified bool
+
+	// Tx is the transaction the query runs in, or nil.
+	Tx *Tx
+
 	StartTime time.Time
 	Result    sql.Result
 	Err       error
 
 	Stash map[interface{}]interface{}
+
+	conn        IConn
+	fingerprint string
+}
+
+// Fingerprint returns the normalized query, see the Fingerprint function.
+// It is computed on the first call.
+func (e *QueryEvent) Fingerprint() string {
+	if e.fingerprint == "" {
+		e.fingerprint = Fingerprint(e.Query)
+	}
+	return e.fingerprint
+}
+
+// SetQuery replaces the query that is executed after BeforeQuery hooks.
+// Hooks must not build the new query from untrusted input.
+func (e *QueryEvent) SetQuery(query string) {
+	e.Query = query
+	e.QueryModified = true
+	e.fingerprint = ""
+}
+
+// AddComment prepends a comment to the query, for example, to tag queries
+// with the request ID. Comment delimiters in the text are escaped, so the
+// comment can't change the rest of the query.
+func (e *QueryEvent) AddComment(comment string) {
+	comment = strings.ReplaceAll(comment, "/*", "/ *")
+	comment = strings.ReplaceAll(comment, "*/", "* /")
+	e.SetQuery("/* " + comment + " */ " + e.Query)
+}
+
+func (e *QueryEvent) query(query string) string {
+	if e != nil && e.QueryModified {
+		return e.Query
+	}
+	return query
 }
 
 func (e *QueryEvent) Operation() string {
@@ -37,6 +85,13 @@ func (e *QueryEvent) Operation() string {
 
 func queryOperation(query string) string {
 	queryOp := strings.TrimLeftFunc(query, unicode.IsSpace)
+	for strings.HasPrefix(queryOp, "/*") {
+		idx := strings.Index(queryOp, "*/")
+		if idx == -1 {
+			break
+		}
+		queryOp = strings.TrimLeftFunc(queryOp[idx+2:], unicode.IsSpace)
+	}
 
 	if idx := strings.IndexByte(queryOp, ' '); idx > 0 {
 		queryOp = queryOp[:idx]
@@ -54,16 +109,17 @@ type QueryHook interface {
 
 func (db *DB) beforeQuery(
 	ctx context.Context,
+	conn IConn,
+	tx *Tx,
 	iquery Query,
 	queryTemplate string,
 	queryArgs []interface{},
 	query string,
 	model Model,
-) (context.Context, *QueryEvent) {
-	atomic.AddUint32(&db.stats.Queries, 1)
-
+) (context.Context, *QueryEvent, time.Time) {
+	start := time.Now()
 	if len(db.queryHooks) == 0 {
-		return ctx, nil
+		return ctx, nil, start
 	}
 
 	event := &QueryEvent{
@@ -76,28 +132,28 @@ func (db *DB) beforeQuery(
 		QueryTemplate: queryTemplate,
 		QueryArgs:     queryArgs,
 
-		StartTime: time.Now(),
+		Tx: tx,
+
+		StartTime: start,
+
+		conn: conn,
 	}
 
 	for _, hook := range db.queryHooks {
 		ctx = hook.BeforeQuery(ctx, event)
 	}
 
-	return ctx, event
+	return ctx, event, start
 }
 
 func (db *DB) afterQuery(
 	ctx context.Context,
 	event *QueryEvent,
+	start time.Time,
 	res sql.Result,
 	err error,
 ) {
-	switch err {
-	case nil, sql.ErrNoRows:
-		// nothing
-	default:
-		atomic.AddUint32(&db.stats.Errors, 1)
-	}
+	db.queryStats.record(time.Since(start), err)
 
 	if event == nil {
 		return
@@ -105,6 +161,11 @@ func (db *DB) afterQuery(
 
 	event.Result = res
 	event.Err = err
+	if res != nil {
+		if n, err := res.RowsAffected(); err == nil {
+			event.RowsAffected = n
+		}
+	}
 
 	db.afterQueryFromIndex(ctx, event, len(db.queryHooks)-1)
 }
@@ -114,3 +175,131 @@ func (db *DB) afterQueryFromIndex(ctx context.Context, event *QueryEvent, hookIn
 		db.queryHooks[hookIndex].AfterQuery(ctx, event)
 	}
 }
+
+//------------------------------------------------------------------------------
+
+// QueryFunc executes the query on the connection.
+type QueryFunc func(ctx context.Context, conn IConn, query string) (sql.Result, error)
+
+// QueryMiddleware wraps query execution, for example, to retry failed queries.
+// Middlewares run between BeforeQuery and AfterQuery hooks and apply to queries
+// executed by the query builders, ExecContext, QueryContext, and QueryRowContext.
+// For the queries that return rows, for example, SelectQuery.Rows, the result is nil
+// and the rows are returned to the caller.
+type QueryMiddleware func(next QueryFunc) QueryFunc
+
+// Use adds middlewares that wrap query execution. The first middleware is the outermost.
+func (db *DB) Use(mws ...QueryMiddleware) {
+	db.middlewares = append(db.middlewares, mws...)
+}
+
+// QueryHandler builds and executes the query passed to it.
+type QueryHandler func(ctx context.Context, q Query) error
+
+// QueryBuilderMiddleware wraps QueryHandler, so policies can inspect or change the queries
+// before they are formatted and executed, for example, to reject SELECT queries without
+// WHERE conditions or to add a default LIMIT. Middlewares change the query in place
+// and pass it to the next handler. Unlike QueryMiddleware, they apply only to the queries
+// executed by the query builders.
+type QueryBuilderMiddleware func(next QueryHandler) QueryHandler
+
+// UseQueryBuilder adds middlewares that wrap the query builders.
+// The first middleware is the outermost.
+func (db *DB) UseQueryBuilder(mws ...QueryBuilderMiddleware) {
+	db.builderMiddlewares = append(db.builderMiddlewares, mws...)
+}
+
+// handleQuery runs fn, which formats and executes the query, with the query builder middlewares.
+func (db *DB) handleQuery(
+	ctx context.Context, q Query, fn func(context.Context) (sql.Result, error),
+) (sql.Result, error) {
+	if _, ok := q.(*SelectQuery); !ok {
+		exec := fn
+		fn = func(ctx context.Context) (sql.Result, error) {
+			// Evict even if the query fails, because it could change some rows.
+			defer db.evictModelCache(q)
+			return exec(ctx)
+		}
+	}
+
+	if len(db.builderMiddlewares) == 0 {
+		return fn(ctx)
+	}
+
+	var res sql.Result
+	handler := QueryHandler(func(ctx context.Context, _ Query) (err error) {
+		res, err = fn(ctx)
+		return err
+	})
+	for i := len(db.builderMiddlewares) - 1; i >= 0; i-- {
+		handler = db.builderMiddlewares[i](handler)
+	}
+	err := handler(ctx, q)
+	return res, err
+}
+
+func (db *DB) runQuery(ctx context.Context, conn IConn, query string, fn QueryFunc) (sql.Result, error) {
+	for i := len(db.middlewares) - 1; i >= 0; i-- {
+		fn = db.middlewares[i](fn)
+	}
+	return db.runWithConn(ctx, conn, query, fn)
+}
+
+// runQueryRows is like runQuery, but for the queries that return rows,
+// which are read after fn returns.
+func (db *DB) runQueryRows(
+	ctx context.Context, conn IConn, query string, fn func(context.Context, IConn, string) error,
+) error {
+	next := QueryFunc(func(ctx context.Context, conn IConn, query string) (sql.Result, error) {
+		return nil, fn(ctx, conn, query)
+	})
+	for i := len(db.middlewares) - 1; i >= 0; i-- {
+		next = db.middlewares[i](next)
+	}
+	return db.withConn(ctx, conn, true, func(ctx context.Context, conn IConn) error {
+		_, err := next(ctx, conn, query)
+		return err
+	})
+}
+
+// runWithConn executes fn on the connection prepared by withConn.
+func (db *DB) runWithConn(
+	ctx context.Context, conn IConn, query string, fn QueryFunc,
+) (res sql.Result, err error) {
+	if c, ok := conn.(*cacheConn); ok {
+		if c.cache.result != nil {
+			// The rows are read from the cache.
+			return fn(ctx, conn, query)
+		}
+		err = db.withConn(ctx, c.IConn, false, func(ctx context.Context, conn IConn) error {
+			res, err = fn(ctx, &cacheConn{IConn: conn, cache: c.cache}, query)
+			return err
+		})
+		return res, err
+	}
+
+	err = db.withConn(ctx, conn, false, func(ctx context.Context, conn IConn) error {
+		res, err = fn(ctx, conn, query)
+		return err
+	})
+	return res, err
+}
+
+// withConn calls fn with the connection prepared by WithConnectionInitFunc,
+// WithSearchPath, and RLSHook.
+// returnsRows reports whether fn returns rows that are read after it returns.
+func (db *DB) withConn(
+	ctx context.Context, conn IConn, returnsRows bool, fn func(context.Context, IConn) error,
+) error {
+	ctx, err := db.connContext(ctx)
+	if err != nil {
+		return err
+	}
+	return db.withSearchPath(ctx, conn, returnsRows, func(ctx context.Context, conn IConn) error {
+		return db.withRLS(ctx, conn, returnsRows, fn)
+	})
+}
+
+func execQuery(ctx context.Context, conn IConn, query string) (sql.Result, error) {
+	return conn.ExecContext(ctx, query)
+}
@@ -0,0 +1,253 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by queries rejected by CircuitBreakerHook.
+var ErrCircuitOpen = errors.New("bun: circuit breaker is open")
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+const circuitBuckets = 10
+
+type circuitBucket struct {
+	start    time.Time
+	total    int
+	failures int
+}
+
+// CircuitBreakerHook stops issuing queries when the error rate over the sliding window
+// reaches the threshold:
+//
+//	db.AddQueryHook(bun.NewCircuitBreakerHook(0.5, 10*time.Second, 30*time.Second))
+//
+// While the circuit is open, queries fail with ErrCircuitOpen without touching the
+// database. After resetTimeout the circuit becomes half-open and lets one probe query
+// through: the circuit closes if the probe succeeds and opens again otherwise.
+//
+// The hook rejects the queries that run through QueryMiddleware, including QueryContext
+// and SelectQuery.Rows. Only connection errors, that is driver.ErrBadConn, io.EOF and
+// net.Error including timeouts, count as failures. Other query errors don't. Queries
+// canceled with their context count as neither successes nor failures; when a probe
+// is canceled, the next query becomes the probe.
+type CircuitBreakerHook struct {
+	errorRate    float64
+	window       time.Duration
+	resetTimeout time.Duration
+	minRequests  int
+	now          func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	openedAt time.Time
+	probing  bool
+	buckets  [circuitBuckets]circuitBucket
+}
+
+var _ QueryHook = (*CircuitBreakerHook)(nil)
+
+type CircuitBreakerOption func(h *CircuitBreakerHook)
+
+// WithCircuitBreakerMinRequests sets the number of queries in the window required
+// to open the circuit. The default is 10.
+func WithCircuitBreakerMinRequests(n int) CircuitBreakerOption {
+	return func(h *CircuitBreakerHook) {
+		h.minRequests = n
+	}
+}
+
+// WithCircuitBreakerClock replaces time.Now, which is useful in tests.
+func WithCircuitBreakerClock(now func() time.Time) CircuitBreakerOption {
+	return func(h *CircuitBreakerHook) {
+		h.now = now
+	}
+}
+
+func NewCircuitBreakerHook(
+	errorRate float64, window, resetTimeout time.Duration, opts ...CircuitBreakerOption,
+) *CircuitBreakerHook {
+	h := &CircuitBreakerHook{
+		errorRate:    errorRate,
+		window:       window,
+		resetTimeout: resetTimeout,
+		minRequests:  10,
+		now:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Init implements the queryHookIniter interface by adding the circuit breaker middleware to the db.
+func (h *CircuitBreakerHook) Init(db *DB) {
+	db.Use(h.Middleware)
+}
+
+func (h *CircuitBreakerHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *CircuitBreakerHook) AfterQuery(ctx context.Context, event *QueryEvent) {}
+
+// State returns the current state of the circuit.
+func (h *CircuitBreakerHook) State() CircuitState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.state == CircuitOpen && h.now().Sub(h.openedAt) >= h.resetTimeout {
+		return CircuitHalfOpen
+	}
+	return h.state
+}
+
+// Middleware is the QueryMiddleware that rejects queries while the circuit is open.
+// It can be used with DB.Use directly instead of adding the hook.
+func (h *CircuitBreakerHook) Middleware(next QueryFunc) QueryFunc {
+	return func(ctx context.Context, conn IConn, query string) (sql.Result, error) {
+		probe, err := h.allow()
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := next(ctx, conn, query)
+		if isCanceledQuery(ctx, err) {
+			h.cancel(probe)
+			return res, err
+		}
+		h.record(probe, isCircuitFailure(err))
+		return res, err
+	}
+}
+
+func (h *CircuitBreakerHook) allow() (probe bool, _ error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch h.state {
+	case CircuitOpen:
+		if h.now().Sub(h.openedAt) < h.resetTimeout {
+			return false, ErrCircuitOpen
+		}
+		h.state = CircuitHalfOpen
+		fallthrough
+	case CircuitHalfOpen:
+		if h.probing {
+			return false, ErrCircuitOpen
+		}
+		h.probing = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// cancel forgets the canceled query, which is neither a success nor a failure.
+// The circuit stays half-open after a canceled probe, so the next query is a probe.
+func (h *CircuitBreakerHook) cancel(probe bool) {
+	if !probe {
+		return
+	}
+	h.mu.Lock()
+	h.probing = false
+	h.mu.Unlock()
+}
+
+func (h *CircuitBreakerHook) record(probe, failed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+
+	if probe {
+		h.probing = false
+		if failed {
+			h.state = CircuitOpen
+			h.openedAt = now
+		} else {
+			h.state = CircuitClosed
+			h.buckets = [circuitBuckets]circuitBucket{}
+		}
+		return
+	}
+	if h.state != CircuitClosed {
+		// The query started before the circuit opened.
+		return
+	}
+
+	size := h.window / circuitBuckets
+	if size <= 0 {
+		size = 1
+	}
+	start := now.Truncate(size)
+	b := &h.buckets[(start.UnixNano()/int64(size))%circuitBuckets]
+	if !b.start.Equal(start) {
+		*b = circuitBucket{start: start}
+	}
+	b.total++
+	if failed {
+		b.failures++
+	}
+
+	if !failed {
+		return
+	}
+
+	var total, failures int
+	for i := range h.buckets {
+		b := &h.buckets[i]
+		if now.Sub(b.start) < h.window {
+			total += b.total
+			failures += b.failures
+		}
+	}
+	if total >= h.minRequests && float64(failures) >= h.errorRate*float64(total) {
+		h.state = CircuitOpen
+		h.openedAt = now
+	}
+}
+
+// isCanceledQuery reports whether the query failed because its context was canceled.
+func isCanceledQuery(ctx context.Context, err error) bool {
+	return err != nil && (ctx.Err() != nil || errors.Is(err, context.Canceled))
+}
+
+// isCircuitFailure reports whether the error means that the database is unavailable.
+// Query errors, for example, syntax errors or constraint violations, are not failures.
+func isCircuitFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,173 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun/internal"
+)
+
+// RetryHook retries queries that fail with transient errors, for example, deadlocks,
+// using exponential backoff with jitter:
+//
+//	db.AddQueryHook(bun.NewRetryHook(3, nil))
+//
+// The hook retries the queries that run through QueryMiddleware, including QueryContext,
+// QueryRowContext, and SelectQuery.Rows. Only the errors returned before the rows are read
+// can be retried. Queries executed inside a transaction are not retried, because the database
+// aborts the whole transaction on such errors and the transaction must be retried instead.
+// Query hooks are called once per query and observe the result of the last attempt.
+type RetryHook struct {
+	maxAttempts int
+	shouldRetry func(error) bool
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	onRetry     func(err error)
+
+	// shouldRetryQuery is used instead of shouldRetry when the decision depends on the query.
+	shouldRetryQuery func(query string, err error) bool
+}
+
+var _ QueryHook = (*RetryHook)(nil)
+
+type RetryOption func(h *RetryHook)
+
+// WithRetryBackoff configures the backoff before the second attempt and
+// the maximum backoff. The defaults are 10ms and 1s.
+func WithRetryBackoff(min, max time.Duration) RetryOption {
+	return func(h *RetryHook) {
+		h.minBackoff = min
+		h.maxBackoff = max
+	}
+}
+
+// NewRetryHook returns a hook that executes queries at most maxAttempts times.
+// If shouldRetry is nil, IsTransientError is used.
+func NewRetryHook(maxAttempts int, shouldRetry func(error) bool, opts ...RetryOption) *RetryHook {
+	if shouldRetry == nil {
+		shouldRetry = IsTransientError
+	}
+	h := &RetryHook{
+		maxAttempts: maxAttempts,
+		shouldRetry: shouldRetry,
+		minBackoff:  10 * time.Millisecond,
+		maxBackoff:  time.Second,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Init implements the queryHookIniter interface by adding the retry middleware to the db.
+func (h *RetryHook) Init(db *DB) {
+	db.Use(h.Middleware)
+}
+
+func (h *RetryHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *RetryHook) AfterQuery(ctx context.Context, event *QueryEvent) {}
+
+// Middleware is the QueryMiddleware that retries queries. It can be used
+// with DB.Use directly instead of adding the hook.
+func (h *RetryHook) Middleware(next QueryFunc) QueryFunc {
+	return func(ctx context.Context, conn IConn, query string) (sql.Result, error) {
+		if _, ok := conn.(*sql.Tx); ok {
+			return next(ctx, conn, query)
+		}
+
+		for attempt := 1; ; attempt++ {
+			res, err := next(ctx, conn, query)
+			if err == nil || attempt >= h.maxAttempts || !h.canRetry(query, err) {
+				return res, err
+			}
+			if h.onRetry != nil {
+				h.onRetry(err)
+			}
+
+			timer := time.NewTimer(h.backoff(attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return res, err
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+func (h *RetryHook) canRetry(query string, err error) bool {
+	if h.shouldRetryQuery != nil {
+		return h.shouldRetryQuery(query, err)
+	}
+	return h.shouldRetry(err)
+}
+
+func (h *RetryHook) backoff(attempt int) time.Duration {
+	d := h.minBackoff << (attempt - 1)
+	if d <= 0 || d > h.maxBackoff {
+		d = h.maxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Use the equal jitter: half of the backoff is fixed and the rest is random.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// IsTransientError reports whether the error is a deadlock or a serialization failure
+// and the query can be retried. It recognizes PostgreSQL SQLSTATE codes 40P01 and 40001
+// and MySQL error 1213.
+func IsTransientError(err error) bool {
+	switch sqlState(err) {
+	case "40P01", "40001":
+		return true
+	}
+
+	// go-sql-driver/mysql formats errors as "Error 1213 (40001): Deadlock found...".
+	return strings.Contains(err.Error(), "Error 1213")
+}
+
+// newConnRetryMiddleware returns the middleware used by WithMaxRetries.
+func newConnRetryMiddleware(maxRetries int) QueryMiddleware {
+	h := NewRetryHook(maxRetries+1, nil)
+	h.shouldRetryQuery = isRetryableConnError
+	h.onRetry = func(err error) {
+		internal.Warn.Printf("retrying query after connection loss: %s", err)
+	}
+	return h.Middleware
+}
+
+// isRetryableConnError reports whether the query that failed because the connection
+// was lost can be executed again. Drivers return driver.ErrBadConn only when the query
+// was not sent, so any query can be retried. After other errors the query could be
+// executed, so only SELECT queries are retried.
+func isRetryableConnError(query string, err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return false
+	}
+	return strings.ToUpper(queryOperation(query)) == "SELECT"
+}
+
+func sqlState(err error) string {
+	var fieldErr interface{ Field(byte) string }
+	if errors.As(err, &fieldErr) {
+		return fieldErr.Field('C')
+	}
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		return stateErr.SQLState()
+	}
+	return ""
+}
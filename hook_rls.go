@@ -0,0 +1,172 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// RLSHook is a query hook that prepares the connection for row-level security policies,
+// for example, by setting the current tenant before each query:
+//
+//	db.AddQueryHook(bun.NewRLSHook(func(ctx context.Context, conn bun.IConn) error {
+//		_, err := conn.ExecContext(ctx,
+//			"SELECT set_config('app.current_tenant', $1, $2)",
+//			tenantFromContext(ctx), bun.RLSLocal(ctx))
+//		return err
+//	}))
+//
+// The setup function receives the database/sql transaction or connection the query runs on,
+// so queries made by the function don't invoke query hooks and use driver placeholders.
+// Inside a transaction the function is called once before the first query and RLSLocal
+// reports true, so the settings can be made with set_config(..., true) or SET LOCAL
+// and are reset when the transaction ends.
+//
+// Other queries are executed on a connection checked out from the pool and the function
+// is called before each query. The settings must apply to the session, for example,
+// set_config(..., false) or SET, and bun resets them with RESET ROLE and RESET ALL before
+// the connection is used by other queries. On databases that can't reset sessions,
+// the connection is closed instead. Queries that return *sql.Rows keep the connection
+// until the rows are closed.
+//
+// If the setup function fails, the query is not executed and returns the error.
+type RLSHook struct {
+	setupFn func(ctx context.Context, conn IConn) error
+
+	mu  sync.Mutex
+	txs map[*sql.Tx]struct{}
+}
+
+var _ QueryHook = (*RLSHook)(nil)
+
+func NewRLSHook(setupFn func(ctx context.Context, conn IConn) error) *RLSHook {
+	return &RLSHook{
+		setupFn: setupFn,
+		txs:     make(map[*sql.Tx]struct{}),
+	}
+}
+
+type rlsCtxKey struct{}
+
+type rlsSetup struct {
+	hook *RLSHook
+	tx   *Tx
+}
+
+// Init records that the DB uses the hook, so the caches don't share the results
+// of the queries that depend on the RLS settings.
+func (h *RLSHook) Init(db *DB) {
+	db.flags = db.flags.Set(rlsHookFlag)
+}
+
+func (h *RLSHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	if isTxControlQuery(event.Query) {
+		return ctx
+	}
+	return context.WithValue(ctx, rlsCtxKey{}, &rlsSetup{hook: h, tx: event.Tx})
+}
+
+func (h *RLSHook) AfterQuery(ctx context.Context, event *QueryEvent) {}
+
+// setupTx calls the setup function unless it was already called in the transaction.
+func (h *RLSHook) setupTx(ctx context.Context, tx *sql.Tx, btx *Tx) error {
+	if btx == nil {
+		// The transaction is not managed by bun, so its end can't be tracked.
+		return h.setup(ctx, tx, true)
+	}
+
+	h.mu.Lock()
+	_, done := h.txs[tx]
+	h.mu.Unlock()
+	if done {
+		return nil
+	}
+
+	if err := h.setup(ctx, tx, true); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	h.txs[tx] = struct{}{}
+	h.mu.Unlock()
+
+	// The settings are reset when the transaction or the savepoint is rolled back.
+	forget := func() {
+		h.mu.Lock()
+		delete(h.txs, tx)
+		h.mu.Unlock()
+	}
+	btx.OnCommit(forget)
+	btx.OnRollback(forget)
+	return nil
+}
+
+type rlsLocalKey struct{}
+
+// RLSLocal reports whether the RLSHook setup function is called inside a transaction,
+// so the settings can be local to the transaction.
+func RLSLocal(ctx context.Context) bool {
+	local, _ := ctx.Value(rlsLocalKey{}).(bool)
+	return local
+}
+
+func (h *RLSHook) setup(ctx context.Context, conn IConn, local bool) error {
+	if local {
+		ctx = context.WithValue(ctx, rlsLocalKey{}, true)
+	}
+	if err := h.setupFn(ctx, conn); err != nil {
+		return fmt.Errorf("bun: RLS setup failed: %w", err)
+	}
+	return nil
+}
+
+// withRLS calls fn with a connection prepared by the RLSHook from the context.
+func (db *DB) withRLS(
+	ctx context.Context, conn IConn, returnsRows bool, fn func(context.Context, IConn) error,
+) error {
+	setup, _ := ctx.Value(rlsCtxKey{}).(*rlsSetup)
+	if setup == nil {
+		return fn(ctx, conn)
+	}
+
+	switch c := conn.(type) {
+	case *sql.Tx:
+		if err := setup.hook.setupTx(ctx, c, setup.tx); err != nil {
+			return err
+		}
+		return fn(ctx, conn)
+	case *sql.DB:
+		return db.withPinnedConn(ctx, returnsRows, func(conn *sql.Conn) error {
+			return db.withRLSConn(ctx, setup, conn, fn)
+		})
+	case *sql.Conn:
+		return db.withRLSConn(ctx, setup, c, fn)
+	default:
+		return fn(ctx, conn)
+	}
+}
+
+// withRLSConn calls the setup function and fn on the connection outside transactions.
+func (db *DB) withRLSConn(
+	ctx context.Context, setup *rlsSetup, conn *sql.Conn, fn func(context.Context, IConn) error,
+) error {
+	// The settings are reset even if the setup function fails halfway.
+	ctx, err := db.claimSession(ctx, conn, setup)
+	if err != nil {
+		return err
+	}
+	if err := setup.hook.setup(ctx, conn, false); err != nil {
+		return err
+	}
+	return fn(ctx, conn)
+}
+
+func isTxControlQuery(query string) bool {
+	switch strings.ToUpper(queryOperation(query)) {
+	case "BEGIN", "COMMIT", "ROLLBACK", "SAVEPOINT", "RELEASE":
+		return true
+	}
+	return false
+}
@@ -8,6 +8,9 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/mssqldialect"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/extra/bunpgx"
 )
 
 type Bench struct {
@@ -70,6 +73,71 @@ func benchmarkSelectError(b *testing.B, db *bun.DB) {
 	})
 }
 
+func BenchmarkPipeline(b *testing.B) {
+	const numQueries = 10
+
+	db := pg(b)
+	require.NoError(b, resetBenchSchema(b, db))
+	conn := pgxConn(b)
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for j := 0; j < numQueries; j++ {
+				bench := new(Bench)
+				q := db.NewSelect().Model(bench).Where("id = ?", j+1)
+				if _, err := bunpgx.NewPipeline(conn).Queue(q).Send(ctx); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("pipeline", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			p := bunpgx.NewPipeline(conn)
+			for j := 0; j < numQueries; j++ {
+				bench := new(Bench)
+				p.Queue(db.NewSelect().Model(bench).Where("id = ?", j+1))
+			}
+			if _, err := p.Send(ctx); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkWherePKComposite(b *testing.B) {
+	type UserRole struct {
+		UserID int64 `bun:",pk"`
+		RoleID int64 `bun:",pk"`
+	}
+
+	models := make([]UserRole, 1000)
+	for i := range models {
+		models[i] = UserRole{UserID: int64(i + 1), RoleID: int64(i%10 + 1)}
+	}
+
+	sqldb := sqlite(b).DB
+	for _, db := range []*bun.DB{
+		bun.NewDB(sqldb, sqlitedialect.New()), // (user_id, role_id) IN (...)
+		bun.NewDB(sqldb, mssqldialect.New()),  // (user_id = ? AND role_id = ?) OR ...
+	} {
+		b.Run(db.Dialect().Name().String(), func(b *testing.B) {
+			b.ReportAllocs()
+
+			var size int
+			for i := 0; i < b.N; i++ {
+				query, err := db.NewSelect().Model(&models).WherePKComposite().AppendQuery(db.Formatter(), nil)
+				if err != nil {
+					b.Fatal(err)
+				}
+				size = len(query)
+			}
+			b.ReportMetric(float64(size), "query_bytes")
+		})
+	}
+}
+
 func benchEachDB(b *testing.B, f func(b *testing.B, db *bun.DB)) {
 	for name, newDB := range allDBs {
 		b.Run(name, func(b *testing.B) {
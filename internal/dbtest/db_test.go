@@ -6,11 +6,14 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/netip"
 	"os"
 	"path/filepath"
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -24,10 +27,15 @@ import (
 	"github.com/uptrace/bun/driver/pgdriver"
 	"github.com/uptrace/bun/driver/sqliteshim"
 	"github.com/uptrace/bun/extra/bundebug"
+	_ "github.com/uptrace/bun/extra/bundecimal"
+	"github.com/uptrace/bun/migrate/sqlschema"
+	"github.com/uptrace/bun/schema"
 
 	_ "github.com/denisenkom/go-mssqldb"
 	_ "github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
 	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 )
 
@@ -258,6 +266,7 @@ func TestDB(t *testing.T) {
 		{testFKViolation},
 		{testWithForeignKeysAndRules},
 		{testWithForeignKeys},
+		{testWithForeignKeysCascade},
 		{testInterfaceAny},
 		{testInterfaceJSON},
 		{testScanRawMessage},
@@ -270,13 +279,58 @@ func TestDB(t *testing.T) {
 		{testMultiUpdate},
 		{testUpdateWithSkipupdateTag},
 		{testScanAndCount},
+		{testScanAndCountWithCount},
 		{testEmbedModelValue},
 		{testEmbedModelPointer},
 		{testJSONMarshaler},
 		{testNilDriverValue},
 		{testRunInTxAndSavepoint},
+		{testTxCallbacks},
+		{testTypedSelect},
+		{testPager},
+		{testQueryBuilderPaginate},
+		{testWhereMap},
+		{testOrderByRandom},
+		{testPluck},
+		{testFind},
+		{testTemporalTable},
+		{testQueryComment},
+		{testInsertOmitZero},
+		{testCacheHook},
+		{testStats},
+		{testTableSample},
+		{testInsertRow},
+		{testDropTableCascade},
+		{testSearchPath},
+		{testOutbox},
+		{testRegisterType},
+		{testDecimal},
+		{testUUIDGenerate},
+		{testULID},
+		{testWhereIn},
+		{testExplain},
+		{testPreload},
+		{testBelongsToChain},
+		{testFieldRedaction},
+		{testLazyColumns},
+		{testModelCache},
+		{testDeleteChunk},
+		{testUpsertReturnAction},
+		{testUpsertReturnInserted},
+		{testMySQLJSON},
 		{testDriverValuerReturnsItself},
 		{testNoPanicWhenReturningNullColumns},
+		{testPolymorphicHasMany},
+		{testSelfReferentialRelation},
+		{testGeneratedColumn},
+		{testEncryptedField},
+		{testTableNameOverride},
+		{testScanChan},
+		{testScanChanLarge},
+		{testUpdateMap},
+		{testCreatePartialIndex},
+		{testCreateExpressionIndex},
+		{testTableComment},
 	}
 
 	testEachDB(t, func(t *testing.T, dbName string, db *bun.DB) {
@@ -975,6 +1029,60 @@ func testWithForeignKeysAndRules(t *testing.T, db *bun.DB) {
 	require.Equal(t, 0, n)
 }
 
+func testWithForeignKeysCascade(t *testing.T, db *bun.DB) {
+	type Author struct {
+		ID   int64 `bun:",pk,autoincrement"`
+		Name string
+	}
+	type Book struct {
+		ID       int64 `bun:",pk,autoincrement"`
+		AuthorID int64
+		Author   *Author `bun:"rel:belongs-to,fk:on_delete:cascade,fk:name"`
+	}
+
+	switch db.Dialect().Name() {
+	case dialect.SQLite:
+		_, err := db.Exec("PRAGMA foreign_keys = ON;")
+		require.NoError(t, err)
+	case dialect.MSSQL:
+		t.Skip("sqlschema does not support MSSQL")
+	}
+
+	_, err := db.NewDropTable().Model((*Book)(nil)).IfExists().WithForeignKeys().Exec(ctx)
+	require.NoError(t, err)
+	mustResetModel(t, ctx, db, (*Author)(nil))
+	_, err = db.NewCreateTable().Model((*Book)(nil)).WithForeignKeys().Exec(ctx)
+	require.NoError(t, err)
+
+	table, err := sqlschema.InspectTable(ctx, db, "", "books")
+	require.NoError(t, err)
+	require.Len(t, table.ForeignKeys, 1)
+	fk := table.ForeignKeys[0]
+	require.Equal(t, []string{"author_id"}, fk.Columns)
+	require.Equal(t, "CASCADE", fk.OnDelete)
+	if db.Dialect().Name() != dialect.SQLite {
+		require.Equal(t, "fk_books_author_id", fk.Name)
+	}
+
+	author := &Author{Name: "author"}
+	_, err = db.NewInsert().Model(author).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewInsert().Model(&Book{AuthorID: author.ID}).Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = db.NewDelete().Model(author).WherePK().Exec(ctx)
+	require.NoError(t, err)
+
+	n, err := db.NewSelect().Model((*Book)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	_, err = db.NewDropTable().Model((*Book)(nil)).WithForeignKeys().Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewDropTable().Model((*Book)(nil)).IfExists().WithForeignKeys().Exec(ctx)
+	require.NoError(t, err)
+}
+
 func testWithForeignKeys(t *testing.T, db *bun.DB) {
 	type User struct {
 		ID   int    `bun:",pk,autoincrement"`
@@ -1331,6 +1439,64 @@ func testScanAndCount(t *testing.T, db *bun.DB) {
 	})
 }
 
+func testScanAndCountWithCount(t *testing.T, db *bun.DB) {
+	type Order struct {
+		ID     int64 `bun:",pk,autoincrement"`
+		UserID int64
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Order)(nil))
+
+	src := []Order{{UserID: 1}, {UserID: 1}, {UserID: 1}, {UserID: 2}}
+	_, err := db.NewInsert().Model(&src).Exec(ctx)
+	require.NoError(t, err)
+
+	// ORDER BY and LIMIT of the count query are ignored.
+	countQuery := db.NewSelect().
+		Model((*Order)(nil)).
+		Column("user_id").
+		Distinct().
+		Order("user_id").
+		Limit(1)
+
+	var orders []Order
+	count, err := db.NewSelect().
+		Model(&orders).
+		Order("user_id", "id").
+		Limit(2).
+		WithCount(countQuery).
+		ScanAndCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Len(t, orders, 2)
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var orders []Order
+		count, err := tx.NewSelect().
+			Model(&orders).
+			Order("user_id", "id").
+			Limit(2).
+			WithCount(countQuery).
+			ScanAndCount(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+		return err
+	})
+	require.NoError(t, err)
+
+	orders = nil
+	count, err = db.NewSelect().
+		Model(&orders).
+		Order("user_id", "id").
+		Limit(2).
+		WithCount(nil).
+		ScanAndCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+	require.Len(t, orders, 2)
+}
+
 func testEmbedModelValue(t *testing.T, db *bun.DB) {
 	type DoubleEmbed struct {
 		A string
@@ -1625,6 +1791,233 @@ func testNoPanicWhenReturningNullColumns(t *testing.T, db *bun.DB) {
 	})
 }
 
+func testPolymorphicHasMany(t *testing.T, db *bun.DB) {
+	type Comment struct {
+		bun.BaseModel `bun:"table:poly_comments"`
+
+		ID              int64 `bun:",pk,autoincrement"`
+		Text            string
+		CommentableType string
+		CommentableID   int64
+	}
+	type Post struct {
+		ID       int64      `bun:",pk"`
+		Comments []*Comment `bun:"rel:has-many,polymorphic_prefix:commentable"`
+	}
+	type Video struct {
+		ID       int64      `bun:",pk"`
+		Comments []*Comment `bun:"rel:has-many,polymorphic_prefix:commentable"`
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Comment)(nil), (*Post)(nil), (*Video)(nil))
+
+	_, err := db.NewInsert().Model(&Post{ID: 1}).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewInsert().Model(&Video{ID: 1}).Exec(ctx)
+	require.NoError(t, err)
+
+	comments := []*Comment{
+		{Text: "post comment", CommentableType: "posts", CommentableID: 1},
+		{Text: "video comment 1", CommentableType: "videos", CommentableID: 1},
+		{Text: "video comment 2", CommentableType: "videos", CommentableID: 1},
+	}
+	_, err = db.NewInsert().Model(&comments).Exec(ctx)
+	require.NoError(t, err)
+
+	post := new(Post)
+	err = db.NewSelect().
+		Model(post).
+		Relation("Comments").
+		Where("id = ?", 1).
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, post.Comments, 1)
+	require.Equal(t, "post comment", post.Comments[0].Text)
+
+	video := new(Video)
+	err = db.NewSelect().
+		Model(video).
+		Relation("Comments", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("text")
+		}).
+		Where("id = ?", 1).
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, video.Comments, 2)
+	require.Equal(t, "video comment 1", video.Comments[0].Text)
+	require.Equal(t, "video comment 2", video.Comments[1].Text)
+}
+
+func testSelfReferentialRelation(t *testing.T, db *bun.DB) {
+	type Category struct {
+		ID       int64 `bun:",pk"`
+		Name     string
+		ParentID int64
+		Children []*Category `bun:"rel:has-many,join:id=parent_id"`
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Category)(nil))
+
+	categories := []Category{
+		{ID: 1, Name: "root"},
+		{ID: 2, Name: "books", ParentID: 1},
+		{ID: 3, Name: "music", ParentID: 1},
+		{ID: 4, Name: "fiction", ParentID: 2},
+	}
+	_, err := db.NewInsert().Model(&categories).Exec(ctx)
+	require.NoError(t, err)
+
+	childNames := func(cats []*Category) []string {
+		var names []string
+		for _, cat := range cats {
+			names = append(names, cat.Name)
+		}
+		return names
+	}
+
+	root := new(Category)
+	err = db.NewSelect().
+		Model(root).
+		Relation("Children", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("id")
+		}).
+		Relation("Children.Children").
+		Where("id = ?", 1).
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"books", "music"}, childNames(root.Children))
+	require.Equal(t, []string{"fiction"}, childNames(root.Children[0].Children))
+	require.Empty(t, root.Children[1].Children)
+
+	if !db.Dialect().Features().Has(feature.CTE) {
+		return
+	}
+
+	root = new(Category)
+	err = db.NewSelect().
+		Model(root).
+		WithRecursiveRelation("Children", 10).
+		Where("id = ?", 1).
+		Scan(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"books", "music"}, childNames(root.Children))
+	for _, child := range root.Children {
+		if child.Name == "books" {
+			require.Equal(t, []string{"fiction"}, childNames(child.Children))
+		} else {
+			require.Empty(t, child.Children)
+		}
+	}
+
+	root = new(Category)
+	err = db.NewSelect().
+		Model(root).
+		WithRecursiveRelation("Children", 1).
+		Where("id = ?", 1).
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, root.Children, 2)
+	for _, child := range root.Children {
+		require.Empty(t, child.Children)
+	}
+}
+
+func testGeneratedColumn(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID         int64 `bun:",pk"`
+		Name       string
+		SearchName string `bun:",generated:'lower(name)'"`
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	_, err := db.NewInsert().Model(&Model{ID: 1, Name: "Hello World"}).Exec(ctx)
+	require.NoError(t, err)
+
+	model := new(Model)
+	err = db.NewSelect().Model(model).Where("id = ?", 1).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "hello world", model.SearchName)
+
+	model.Name = "Goodbye"
+	_, err = db.NewUpdate().Model(model).WherePK().Exec(ctx)
+	require.NoError(t, err)
+
+	err = db.NewSelect().Model(model).WherePK().Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "goodbye", model.SearchName)
+}
+
+func testEncryptedField(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID     int64 `bun:",pk"`
+		SSN    bun.EncryptedField
+		Note   *bun.EncryptedField
+		Public string
+	}
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	ctx := db.WithEncryptionKey(context.Background(), key)
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	models := []Model{
+		{ID: 1, SSN: bun.EncryptedField{Plaintext: "123-45-6789"}, Note: &bun.EncryptedField{Plaintext: "note"}},
+		{ID: 2, SSN: bun.EncryptedField{Plaintext: "987-65-4321"}},
+	}
+	_, err := db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+
+	var raw []byte
+	err = db.NewSelect().Model((*Model)(nil)).Column("ssn").Where("id = 1").Scan(ctx, &raw)
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "123-45-6789")
+
+	var got []Model
+	err = db.NewSelect().Model(&got).Order("id").Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, "123-45-6789", got[0].SSN.Plaintext)
+	require.Equal(t, "note", got[0].Note.Plaintext)
+	require.Equal(t, "987-65-4321", got[1].SSN.Plaintext)
+	require.Nil(t, got[1].Note)
+
+	model := &Model{SSN: *bun.NewEncryptedField(key, schema.AESGCM)}
+	err = db.NewSelect().Model(model).Where("id = 2").Scan(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "987-65-4321", model.SSN.Plaintext)
+
+	err = db.NewSelect().Model(new(Model)).Where("id = 2").Scan(context.Background())
+	require.Error(t, err)
+
+	wrongKey := db.WithEncryptionKey(context.Background(), []byte("fedcba9876543210fedcba9876543210"))
+	err = db.NewSelect().Model(new(Model)).Where("id = 2").Scan(wrongKey)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "decryption failed")
+
+	// The key from the context is not stored in the field, so the model
+	// loaded with the old key is saved with the new one.
+	newKey := db.WithEncryptionKey(context.Background(), []byte("fedcba9876543210fedcba9876543210"))
+
+	model = new(Model)
+	err = db.NewSelect().Model(model).Where("id = 1").Scan(ctx)
+	require.NoError(t, err)
+
+	_, err = db.NewUpdate().Model(model).WherePK().Exec(newKey)
+	require.NoError(t, err)
+
+	model = new(Model)
+	err = db.NewSelect().Model(model).Where("id = 1").Scan(newKey)
+	require.NoError(t, err)
+	require.Equal(t, "123-45-6789", model.SSN.Plaintext)
+	require.Equal(t, "note", model.Note.Plaintext)
+
+	err = db.NewSelect().Model(new(Model)).Where("id = 1").Scan(ctx)
+	require.Error(t, err)
+}
+
 func mustResetModel(tb testing.TB, ctx context.Context, db *bun.DB, models ...interface{}) {
 	err := db.ResetModel(ctx, models...)
 	require.NoError(tb, err, "must reset model")
@@ -1640,3 +2033,2587 @@ func mustDropTableOnCleanup(tb testing.TB, ctx context.Context, db *bun.DB, mode
 		}
 	})
 }
+
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func TestConnectionInitSQL(t *testing.T) {
+	if !sqliteshim.HasDriver() {
+		t.Skip("sqlite driver is not available")
+	}
+
+	dsn := filepath.Join(t.TempDir(), "sqlite.db")
+
+	// The pool must be opened with bun.NewConnector.
+	sqldb, err := sql.Open(sqliteshim.ShimName, dsn)
+	require.NoError(t, err)
+	defer sqldb.Close()
+	_, err = bun.NewDB(sqldb, sqlitedialect.New()).
+		WithConnectionInitSQL("SELECT 1").
+		NewSelect().ColumnExpr("1").Exec(ctx)
+	require.Error(t, err)
+
+	sqldb = sql.OpenDB(bun.NewConnector(dsnConnector{driver: sqliteshim.Driver(), dsn: dsn}))
+	defer sqldb.Close()
+	sqldb.SetMaxOpenConns(1)
+
+	db := bun.NewDB(sqldb, sqlitedialect.New())
+
+	// Temporary tables are per connection and CREATE fails if the table exists,
+	// so the init query is executed once on the connection.
+	initDB := db.WithConnectionInitSQL("CREATE TEMP TABLE conn_init AS SELECT 'init' AS name")
+	require.Equal(t, db.DB, initDB.DB, "the pool is shared")
+
+	for i := 0; i < 2; i++ {
+		var name string
+		err := initDB.NewSelect().ColumnExpr("name").TableExpr("conn_init").Scan(ctx, &name)
+		require.NoError(t, err)
+		require.Equal(t, "init", name)
+	}
+
+	// Other queries get a connection without the effects of the init query.
+	_, err = db.NewSelect().ColumnExpr("name").TableExpr("conn_init").Exec(ctx)
+	require.Error(t, err)
+
+	type tenantKey struct{}
+
+	tenantDB := db.WithConnectionInitFunc(func(ctx context.Context) string {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		if tenant == "" {
+			return ""
+		}
+		return fmt.Sprintf("CREATE TEMP TABLE conn_tenant AS SELECT '%s' AS name", tenant)
+	})
+
+	// The init query can depend on the request.
+	for _, tenant := range []string{"acme", "acme", "globex", "acme"} {
+		ctx := context.WithValue(ctx, tenantKey{}, tenant)
+
+		var name string
+		err := tenantDB.NewSelect().ColumnExpr("name").TableExpr("conn_tenant").Scan(ctx, &name)
+		require.NoError(t, err)
+		require.Equal(t, tenant, name)
+	}
+
+	// Transactions and connections are initialized once.
+	ctx := context.WithValue(ctx, tenantKey{}, "initech")
+	err = tenantDB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		for i := 0; i < 2; i++ {
+			var name string
+			if err := tx.NewSelect().
+				ColumnExpr("name").
+				TableExpr("conn_tenant").
+				Scan(ctx, &name); err != nil {
+				return err
+			}
+			require.Equal(t, "initech", name)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	conn, err := tenantDB.Conn(ctx)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		var name string
+		err := conn.NewSelect().ColumnExpr("name").TableExpr("conn_tenant").Scan(ctx, &name)
+		require.NoError(t, err)
+		require.Equal(t, "initech", name)
+	}
+	require.NoError(t, conn.Close())
+
+	// Rows keep the connection until they are closed.
+	rows, err := tenantDB.NewSelect().ColumnExpr("name").TableExpr("conn_tenant").Rows(ctx)
+	require.NoError(t, err)
+	require.True(t, rows.Next())
+	require.NoError(t, rows.Close())
+	_, err = tenantDB.NewSelect().ColumnExpr("1").Exec(ctx)
+	require.NoError(t, err)
+}
+
+func testTableNameOverride(t *testing.T, db *bun.DB) {
+	type Order struct {
+		ID     int64 `bun:",pk"`
+		UserID int64
+		Item   string
+	}
+	type Address struct {
+		ID     int64 `bun:",pk"`
+		UserID int64
+		City   string
+	}
+	type User struct {
+		ID      int64 `bun:",pk"`
+		Name    string
+		Address *Address `bun:"rel:has-one,join:id=user_id"`
+		Orders  []*Order `bun:"rel:has-many,join:id=user_id"`
+	}
+
+	tenantCtx := func(tenant string) context.Context {
+		ctx := bun.WithTableNameOverride(ctx, (*User)(nil), tenant+"_users")
+		ctx = bun.WithTableNameOverride(ctx, (*Address)(nil), tenant+"_addresses")
+		ctx = bun.WithTableNameOverride(ctx, []Order(nil), tenant+"_orders")
+		return ctx
+	}
+
+	for i, tenant := range []string{"tenant1", "tenant2"} {
+		ctx := tenantCtx(tenant)
+		mustResetModel(t, ctx, db, (*User)(nil), (*Address)(nil), (*Order)(nil))
+
+		user := &User{ID: 1, Name: tenant + "-user"}
+		_, err := db.NewInsert().Model(user).Exec(ctx)
+		require.NoError(t, err)
+
+		_, err = db.NewInsert().Model(&Address{ID: 1, UserID: 1, City: tenant + "-city"}).Exec(ctx)
+		require.NoError(t, err)
+
+		orders := make([]Order, i+1)
+		for j := range orders {
+			orders[j] = Order{ID: int64(j + 1), UserID: 1, Item: tenant + "-item"}
+		}
+		_, err = db.NewInsert().Model(&orders).Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	for i, tenant := range []string{"tenant1", "tenant2"} {
+		ctx := tenantCtx(tenant)
+
+		user := new(User)
+		err := db.NewSelect().
+			Model(user).
+			Relation("Address").
+			Relation("Orders").
+			Where("?TableAlias.id = 1").
+			Scan(ctx)
+		require.NoError(t, err)
+		require.Equal(t, tenant+"-user", user.Name)
+		require.NotNil(t, user.Address)
+		require.Equal(t, tenant+"-city", user.Address.City)
+		require.Len(t, user.Orders, i+1)
+		for _, order := range user.Orders {
+			require.Equal(t, tenant+"-item", order.Item)
+		}
+
+		_, err = db.NewUpdate().Model(user).Set("name = ?", "updated").WherePK().Exec(ctx)
+		require.NoError(t, err)
+
+		count, err := db.NewSelect().Model((*User)(nil)).Where("name = ?", "updated").Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+	}
+}
+
+func testScanChan(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID   int64 `bun:",pk"`
+		Name string
+	}
+
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	models := []Model{{ID: 1, Name: "one"}, {ID: 2, Name: "two"}, {ID: 3, Name: "three"}}
+	_, err := db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+
+	var got []Model
+	data, errc := db.NewSelect().Model((*Model)(nil)).Order("id").ScanChan(ctx, &got)
+	for v := range data {
+		got = append(got, v.(Model))
+	}
+	require.NoError(t, <-errc)
+	require.Equal(t, models, got)
+
+	dest := make(chan *Model)
+	_, errc = db.NewSelect().Model((*Model)(nil)).Order("id").ScanChan(ctx, dest)
+	var names []string
+	for model := range dest {
+		names = append(names, model.Name)
+	}
+	require.NoError(t, <-errc)
+	require.Equal(t, []string{"one", "two", "three"}, names)
+
+	ctx, cancel := context.WithCancel(ctx)
+	data, errc = db.NewSelect().Model((*Model)(nil)).Order("id").ScanChan(ctx, &got)
+	<-data
+	cancel()
+	for range data {
+	}
+	require.ErrorIs(t, <-errc, context.Canceled)
+
+	_, errc = db.NewSelect().Model((*Model)(nil)).ScanChan(ctx, got)
+	require.Error(t, <-errc)
+}
+
+type ScanChanModel struct {
+	ID      int64 `bun:",pk"`
+	Scanned bool  `bun:"-"`
+}
+
+var _ bun.AfterScanRowHook = (*ScanChanModel)(nil)
+
+func (m *ScanChanModel) AfterScanRow(ctx context.Context) error {
+	m.Scanned = true
+	return nil
+}
+
+func testScanChanLarge(t *testing.T, db *bun.DB) {
+	const numRows = 10000
+
+	mustResetModel(t, ctx, db, (*ScanChanModel)(nil))
+
+	models := make([]ScanChanModel, numRows)
+	for i := range models {
+		models[i].ID = int64(i + 1)
+	}
+	for i := 0; i < numRows; i += 1000 {
+		batch := models[i : i+1000]
+		_, err := db.NewInsert().Model(&batch).Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	dest := make(chan *ScanChanModel, 100)
+	_, errc := db.NewSelect().Model((*ScanChanModel)(nil)).Order("id").ScanChan(ctx, dest)
+	var n int64
+	for model := range dest {
+		n++
+		require.Equal(t, n, model.ID)
+		require.True(t, model.Scanned)
+	}
+	require.NoError(t, <-errc)
+	require.Equal(t, int64(numRows), n)
+
+	// Canceling the context stops the scan before all rows are read.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	dest = make(chan *ScanChanModel, 100)
+	_, errc = db.NewSelect().Model((*ScanChanModel)(nil)).Order("id").ScanChan(ctx, dest)
+	n = 0
+	for range dest {
+		n++
+		if n == 10 {
+			cancel()
+		}
+	}
+	require.ErrorIs(t, <-errc, context.Canceled)
+	require.Less(t, n, int64(numRows))
+}
+
+func testUpdateMap(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID    int64 `bun:",pk"`
+		Name  string
+		Count int
+	}
+
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	_, err := db.NewInsert().Model(&[]Model{{ID: 1, Name: "one"}, {ID: 2, Name: "two"}}).Exec(ctx)
+	require.NoError(t, err)
+
+	res, err := db.NewUpdate().
+		Model(map[string]interface{}{"name": "updated", "count": 10}).
+		TableExpr("models").
+		Where("id = ?", 1).
+		Exec(ctx)
+	require.NoError(t, err)
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	var models []Model
+	err = db.NewSelect().Model(&models).Order("id").Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Model{{ID: 1, Name: "updated", Count: 10}, {ID: 2, Name: "two"}}, models)
+
+	_, err = db.NewUpdate().
+		Model(map[string]interface{}{"name": "updated"}).
+		Where("id = ?", 1).
+		Exec(ctx)
+	require.Error(t, err)
+
+	_, err = db.NewUpdate().
+		Model(map[string]interface{}{}).
+		TableExpr("models").
+		Where("id = ?", 1).
+		Exec(ctx)
+	require.Error(t, err)
+}
+
+func testCreatePartialIndex(t *testing.T, db *bun.DB) {
+	type User struct {
+		ID        int64 `bun:",pk"`
+		Email     string
+		DeletedAt *time.Time
+	}
+
+	var indexDefQuery string
+	switch db.Dialect().Name() {
+	case dialect.PG:
+		indexDefQuery = "SELECT indexdef FROM pg_indexes WHERE indexname = ?"
+	case dialect.SQLite:
+		indexDefQuery = "SELECT sql FROM sqlite_master WHERE type = 'index' AND name = ?"
+	case dialect.MSSQL:
+		indexDefQuery = "SELECT filter_definition FROM sys.indexes WHERE name = ?"
+	default:
+		t.Skip("partial indexes are not supported")
+	}
+
+	mustResetModel(t, ctx, db, (*User)(nil))
+
+	_, err := db.NewCreateIndex().
+		Model((*User)(nil)).
+		Concurrently().
+		Index("idx_active_users").
+		Column("email").
+		Where("deleted_at IS NULL").
+		Exec(ctx)
+	require.NoError(t, err)
+
+	var def string
+	err = db.QueryRowContext(ctx, indexDefQuery, "idx_active_users").Scan(&def)
+	require.NoError(t, err)
+	require.Contains(t, strings.ToLower(def), "deleted_at")
+	require.Contains(t, strings.ToLower(def), "is null")
+}
+
+func testCreateExpressionIndex(t *testing.T, db *bun.DB) {
+	if !db.HasFeature(feature.ExpressionIndex) {
+		t.Skip()
+	}
+
+	type User struct {
+		ID    int64 `bun:",pk"`
+		Email string
+	}
+
+	mustResetModel(t, ctx, db, (*User)(nil))
+
+	_, err := db.NewCreateIndex().
+		Model((*User)(nil)).
+		Unique().
+		Index("idx_users_lower_email").
+		ColumnExpr("lower(?)", bun.Ident("email")).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = db.NewInsert().Model(&User{ID: 1, Email: "Hello@example.com"}).Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = db.NewInsert().Model(&User{ID: 2, Email: "hello@EXAMPLE.com"}).Exec(ctx)
+	require.Error(t, err)
+}
+
+func testTableComment(t *testing.T, db *bun.DB) {
+	type Account struct {
+		bun.BaseModel `bun:"table:comment_accounts,comment:'User account record'"`
+
+		ID    int64  `bun:",pk"`
+		Email string `bun:",comment:'Login, it''s lowercase'"`
+	}
+
+	var tableCommentQuery, columnCommentQuery string
+	switch db.Dialect().Name() {
+	case dialect.PG:
+		tableCommentQuery = "SELECT obj_description(?::regclass, 'pg_class')"
+		columnCommentQuery = "SELECT col_description(?::regclass, " +
+			"(SELECT attnum FROM pg_attribute WHERE attrelid = ?0::regclass AND attname = ?))"
+	case dialect.MySQL:
+		tableCommentQuery = "SELECT table_comment FROM information_schema.tables " +
+			"WHERE table_schema = DATABASE() AND table_name = ?"
+		columnCommentQuery = "SELECT column_comment FROM information_schema.columns " +
+			"WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?"
+	default:
+		t.Skip("table comments are not supported")
+	}
+
+	mustResetModel(t, ctx, db, (*Account)(nil))
+
+	var comment string
+	err := db.QueryRowContext(ctx, tableCommentQuery, "comment_accounts").Scan(&comment)
+	require.NoError(t, err)
+	require.Equal(t, "User account record", comment)
+
+	err = db.QueryRowContext(ctx, columnCommentQuery, "comment_accounts", "email").Scan(&comment)
+	require.NoError(t, err)
+	require.Equal(t, "Login, it's lowercase", comment)
+}
+
+func testTxCallbacks(t *testing.T, db *bun.DB) {
+	var calls []string
+	onCommit := func(name string) func() {
+		return func() { calls = append(calls, "commit "+name) }
+	}
+	onRollback := func(name string) func() {
+		return func() { calls = append(calls, "rollback "+name) }
+	}
+
+	err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		tx.OnCommit(onCommit("tx"))
+		tx.OnRollback(onRollback("tx"))
+		require.Empty(t, calls)
+		return errors.New("fake error")
+	})
+	require.Error(t, err)
+	require.Equal(t, []string{"rollback tx"}, calls)
+
+	calls = nil
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		tx.OnCommit(onCommit("tx"))
+		tx.OnRollback(onRollback("tx"))
+
+		err := tx.RunInTx(ctx, nil, func(ctx context.Context, sp bun.Tx) error {
+			sp.OnCommit(onCommit("sp1"))
+			sp.OnRollback(onRollback("sp1"))
+			return errors.New("fake error")
+		})
+		require.Error(t, err)
+		require.Equal(t, []string{"rollback sp1"}, calls)
+
+		return tx.RunInTx(ctx, nil, func(ctx context.Context, sp bun.Tx) error {
+			sp.OnCommit(onCommit("sp2"))
+			return nil
+		})
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"rollback sp1", "commit tx", "commit sp2"}, calls)
+
+	// Hooks can defer side effects until the transaction is committed.
+	var events []*bun.QueryEvent
+	var beforeTxs []bool
+	db = db.WithNamedArg("tx_callbacks", true) // clone the db to not leak the hook
+	db.AddQueryHook(&queryHook{
+		beforeQuery: func(ctx context.Context, event *bun.QueryEvent) context.Context {
+			beforeTxs = append(beforeTxs, event.Tx != nil)
+			return ctx
+		},
+		afterQuery: func(ctx context.Context, event *bun.QueryEvent) {
+			events = append(events, event)
+			if event.Tx != nil && event.Operation() == "SELECT" {
+				event.Tx.OnCommit(onCommit(event.Query))
+			}
+		},
+	})
+
+	_, err = db.NewSelect().ColumnExpr("1").Exec(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Nil(t, events[0].Tx)
+	require.Equal(t, []bool{false}, beforeTxs)
+
+	calls, events, beforeTxs = nil, nil, nil
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewSelect().ColumnExpr("1").Exec(ctx); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "SELECT 2")
+		require.Empty(t, calls)
+		return err
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 4) // BEGIN, SELECT, SELECT, COMMIT
+	require.Equal(t, []bool{false, true, true, true}, beforeTxs)
+	for _, event := range events[1:] {
+		require.NotNil(t, event.Tx)
+	}
+	require.Equal(t, []string{"commit SELECT 1", "commit SELECT 2"}, calls)
+}
+
+func testTypedSelect(t *testing.T, db *bun.DB) {
+	type Author struct {
+		ID   int64 `bun:",pk,autoincrement"`
+		Name string
+	}
+	type Book struct {
+		ID       int64 `bun:",pk,autoincrement"`
+		Title    string
+		AuthorID int64
+		Author   *Author `bun:"rel:belongs-to,join:author_id=id"`
+	}
+
+	mustResetModel(t, ctx, db, (*Author)(nil), (*Book)(nil))
+
+	_, err := db.NewInsert().Model(&[]Author{{Name: "a"}, {Name: "b"}}).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewInsert().Model(&[]Book{
+		{Title: "b1", AuthorID: 1},
+		{Title: "b2", AuthorID: 2},
+		{Title: "b3", AuthorID: 1},
+	}).Exec(ctx)
+	require.NoError(t, err)
+
+	q := bun.NewTypedSelect[Book](db).
+		Relation("Author").
+		Where("author_id = ?", 1).
+		Order("book.id")
+
+	// The result type is checked by the compiler.
+	var books []Book
+	books, err = q.Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, books, 2)
+	require.Equal(t, "b1", books[0].Title)
+	require.Equal(t, "a", books[0].Author.Name)
+	require.Equal(t, "b3", books[1].Title)
+
+	// The previous result is not overwritten.
+	again, err := q.Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, again, 2)
+	again[0].Title = "changed"
+	require.Equal(t, "b1", books[0].Title)
+
+	var book *Book
+	book, err = bun.NewTypedSelect[Book](db).Where("title = ?", "b2").First(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), book.AuthorID)
+
+	_, err = bun.NewTypedSelect[Book](db).Where("title = ?", "missing").First(ctx)
+	require.Equal(t, sql.ErrNoRows, err)
+
+	books, count, err := bun.NewTypedSelect[Book](db).Order("id").Limit(1).ScanAndCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	require.Len(t, books, 1)
+
+	books, err = bun.NewTypedSelect[Book](db).
+		WhereIf(false, "title = ?", "b1").
+		OrderIf(true, "id DESC").
+		LimitIf(true, 2).
+		ApplyIf(false, func(q *bun.TypedSelectQuery[Book]) *bun.TypedSelectQuery[Book] {
+			return q.Where("1 = 0")
+		}).
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, books, 2)
+	require.Equal(t, "b3", books[0].Title)
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		authors, err := bun.NewTypedSelect[Author](db).Conn(tx).
+			Apply(func(q *bun.TypedSelectQuery[Author]) *bun.TypedSelectQuery[Author] {
+				return q.Where("name = ?", "b")
+			}).
+			Scan(ctx)
+		require.NoError(t, err)
+		require.Len(t, authors, 1)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func testPager(t *testing.T, db *bun.DB) {
+	type Item struct {
+		ID    int64 `bun:",pk,autoincrement"`
+		Value int
+	}
+
+	mustResetModel(t, ctx, db, (*Item)(nil))
+
+	items := make([]Item, 105)
+	for i := range items {
+		items[i].Value = i
+	}
+	_, err := db.NewInsert().Model(&items).Exec(ctx)
+	require.NoError(t, err)
+
+	page, err := bun.NewPager[Item](db).Apply(orderItemsByID).Page(1, 10).Execute(ctx)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 10)
+	require.Equal(t, int64(1), page.Items[0].ID)
+	require.Equal(t, 105, page.Total)
+	require.Equal(t, 11, page.PageCount)
+	require.True(t, page.HasNext)
+	require.False(t, page.HasPrev)
+
+	page, err = bun.NewPager[Item](db).Apply(orderItemsByID).Page(11, 10).Execute(ctx)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 5)
+	require.Equal(t, int64(101), page.Items[0].ID)
+	require.Equal(t, 11, page.Page)
+	require.False(t, page.HasNext)
+	require.True(t, page.HasPrev)
+
+	page, err = bun.NewPager[Item](db).Apply(orderItemsByID).Page(12, 10).Execute(ctx)
+	require.NoError(t, err)
+	require.Empty(t, page.Items)
+	require.Equal(t, 105, page.Total)
+	require.False(t, page.HasNext)
+
+	_, err = bun.NewPager[Item](db).Page(0, 10).Execute(ctx)
+	require.Error(t, err)
+
+	// Cursor mode.
+	var ids []int64
+	var cursor interface{}
+	for n := 0; ; n++ {
+		page, err := bun.NewPager[Item](db).
+			Apply(func(q *bun.TypedSelectQuery[Item]) *bun.TypedSelectQuery[Item] {
+				return q.Where("value >= ?", 5)
+			}).
+			CursorPage("id", cursor, 10).
+			Execute(ctx)
+		require.NoError(t, err)
+		require.Equal(t, cursor != nil, page.HasPrev)
+		for _, item := range page.Items {
+			ids = append(ids, item.ID)
+		}
+		if !page.HasNext {
+			require.Equal(t, 9, n)
+			break
+		}
+		require.Len(t, page.Items, 10)
+		cursor = page.NextCursor
+	}
+	require.Len(t, ids, 100)
+	require.Equal(t, int64(6), ids[0])
+	require.Equal(t, int64(105), ids[99])
+
+	// Pointer items.
+	ptrPage, err := bun.NewPager[*Item](db).CursorPage("id", int64(100), 10).Execute(ctx)
+	require.NoError(t, err)
+	require.Len(t, ptrPage.Items, 5)
+	require.Equal(t, int64(101), ptrPage.Items[0].ID)
+	require.Equal(t, int64(105), ptrPage.NextCursor)
+	require.False(t, ptrPage.HasNext)
+
+	// Cursor pages can't be ordered by other columns.
+	_, err = bun.NewPager[Item](db).Apply(orderItemsByID).CursorPage("id", nil, 10).Execute(ctx)
+	require.Error(t, err)
+}
+
+func testQueryBuilderPaginate(t *testing.T, db *bun.DB) {
+	type Item struct {
+		ID    int64 `bun:",pk,autoincrement"`
+		Value int
+	}
+
+	mustResetModel(t, ctx, db, (*Item)(nil))
+
+	items := make([]Item, 25)
+	for i := range items {
+		items[i].Value = i
+	}
+	_, err := db.NewInsert().Model(&items).Exec(ctx)
+	require.NoError(t, err)
+
+	var page []Item
+	err = db.NewSelect().
+		Model(&page).
+		ApplyQueryBuilder(func(q bun.QueryBuilder) bun.QueryBuilder {
+			return paginate(q, 3, 10)
+		}).
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, page, 5)
+	require.Equal(t, int64(21), page[0].ID)
+
+	err = db.NewSelect().
+		Model(&page).
+		ApplyQueryBuilder(func(q bun.QueryBuilder) bun.QueryBuilder {
+			return q.Group("id").Order("id DESC").Limit(2)
+		}).
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	require.Equal(t, int64(25), page[0].ID)
+
+	_, err = db.NewDelete().
+		Model((*Item)(nil)).
+		ApplyQueryBuilder(func(q bun.QueryBuilder) bun.QueryBuilder {
+			return paginate(q.Where("id > 0"), 2, 10)
+		}).
+		Exec(ctx)
+	require.EqualError(t, err, "bun: OFFSET is not supported by DELETE queries")
+}
+
+func testWhereMap(t *testing.T, db *bun.DB) {
+	type Item struct {
+		ID    int64 `bun:",pk,autoincrement"`
+		Name  string
+		Email *string
+	}
+
+	mustResetModel(t, ctx, db, (*Item)(nil))
+
+	email := "foo@example.com"
+	items := []Item{
+		{Name: "foo", Email: &email},
+		{Name: "bar"},
+		{Name: "foo"},
+	}
+	_, err := db.NewInsert().Model(&items).Exec(ctx)
+	require.NoError(t, err)
+
+	conditions := map[string]interface{}{
+		"name":  "foo",
+		"email": nil,
+	}
+
+	// Map iteration order is random, but the SQL must not be.
+	query := db.NewSelect().Model((*Item)(nil)).WhereMap(conditions).String()
+	for i := 0; i < 10; i++ {
+		require.Equal(t, query, db.NewSelect().Model((*Item)(nil)).WhereMap(conditions).String())
+	}
+
+	var found []Item
+	err = db.NewSelect().Model(&found).WhereMap(conditions).Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, int64(3), found[0].ID)
+
+	res, err := db.NewDelete().
+		Model((*Item)(nil)).
+		WhereMap(map[string]interface{}{"name": "foo", "email": email}).
+		Exec(ctx)
+	require.NoError(t, err)
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	err = db.NewSelect().
+		Model(&found).
+		WhereMap(map[string]interface{}{"name": map[string]string{}}).
+		Scan(ctx)
+	require.EqualError(t, err, `bun: WhereMap does not support map[string]string (column "name")`)
+}
+
+func testOrderByRandom(t *testing.T, db *bun.DB) {
+	type Item struct {
+		ID    int64 `bun:",pk,autoincrement"`
+		Value int
+	}
+
+	mustResetModel(t, ctx, db, (*Item)(nil))
+
+	items := make([]Item, 100)
+	for i := range items {
+		items[i].Value = i
+	}
+	_, err := db.NewInsert().Model(&items).Exec(ctx)
+	require.NoError(t, err)
+
+	var random []Item
+	err = db.NewSelect().Model(&random).OrderByRandom().Limit(10).Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, random, 10)
+
+	var sampled []Item
+	err = db.NewSelect().Model(&sampled).Sample(100).Scan(ctx)
+	if db.Dialect().Name() != dialect.PG {
+		require.Error(t, err)
+		return
+	}
+	require.NoError(t, err)
+	require.Len(t, sampled, 100)
+}
+
+func testPluck(t *testing.T, db *bun.DB) {
+	type User struct {
+		ID     int64 `bun:",pk,autoincrement"`
+		Name   string
+		UUID   uuid.UUID `bun:"type:varchar(36)"`
+		Active bool
+	}
+
+	mustResetModel(t, ctx, db, (*User)(nil))
+
+	users := []User{
+		{Name: "alice", UUID: uuid.New(), Active: true},
+		{Name: "bob", UUID: uuid.New()},
+		{Name: "carol", UUID: uuid.New(), Active: true},
+	}
+	_, err := db.NewInsert().Model(&users).Exec(ctx)
+	require.NoError(t, err)
+
+	var ids []int64
+	err = db.NewSelect().
+		Model((*User)(nil)).
+		Column("id").
+		Where("active = ?", true).
+		Order("id").
+		Pluck(ctx, &ids)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 3}, ids)
+
+	var names []string
+	err = db.NewSelect().Model((*User)(nil)).Column("name", "id").Order("id").Pluck(ctx, &names)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alice", "bob", "carol"}, names)
+
+	var uuids []uuid.UUID
+	err = db.NewSelect().Model((*User)(nil)).Column("uuid").Order("id").Pluck(ctx, &uuids)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{users[0].UUID, users[1].UUID, users[2].UUID}, uuids)
+
+	var selected []User
+	err = db.NewSelect().Model((*User)(nil)).Where("active = ?", false).Pluck(ctx, &selected)
+	require.NoError(t, err)
+	require.Len(t, selected, 1)
+	require.Equal(t, "bob", selected[0].Name)
+
+	err = db.NewSelect().Model((*User)(nil)).Pluck(ctx, &ids)
+	require.EqualError(t, err, "bun: Pluck requires a column, use Column or ColumnExpr")
+}
+
+func testFind(t *testing.T, db *bun.DB) {
+	type Item struct {
+		ID   int64 `bun:",pk,autoincrement"`
+		Name string
+	}
+
+	mustResetModel(t, ctx, db, (*Item)(nil))
+
+	items := []Item{{Name: "foo"}, {Name: "bar"}, {Name: "bar"}}
+	_, err := db.NewInsert().Model(&items).Exec(ctx)
+	require.NoError(t, err)
+
+	var item Item
+	err = db.NewSelect().Model(&item).Where("name = ?", "baz").Order("id").Find(ctx, &item)
+	require.Equal(t, sql.ErrNoRows, err)
+
+	err = db.NewSelect().Model(&item).Where("name = ?", "foo").Order("id").Find(ctx, &item)
+	require.NoError(t, err)
+	require.Equal(t, Item{ID: 1, Name: "foo"}, item)
+
+	item = Item{}
+	err = db.NewSelect().Model(&item).Where("name = ?", "bar").Order("id").Find(ctx, &item)
+	require.Equal(t, bun.ErrTooManyRows, err)
+	require.Equal(t, int64(2), item.ID)
+
+	var slice []Item
+	err = db.NewSelect().Model(&slice).Find(ctx, &slice)
+	require.Error(t, err)
+}
+
+func testTemporalTable(t *testing.T, db *bun.DB) {
+	type Price struct {
+		bun.TemporalTable
+
+		ID        int64     `bun:",pk"`
+		ValidFrom time.Time `bun:",pk,notnull,default:current_timestamp"`
+		Value     int64
+	}
+
+	if db.Dialect().Name() == dialect.MSSQL {
+		testMSSQLTemporalTable(t, db)
+		return
+	}
+
+	date := func(year int) time.Time {
+		return time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	mustResetModel(t, ctx, db, (*Price)(nil))
+
+	prices := []Price{
+		{ID: 1, ValidFrom: date(2020), TemporalTable: bun.TemporalTable{ValidTo: date(2021)}, Value: 100},
+		{ID: 1, ValidFrom: date(2021), TemporalTable: bun.TemporalTable{ValidTo: date(2022)}, Value: 110},
+		{ID: 1, ValidFrom: date(2022), TemporalTable: bun.TemporalTable{ValidTo: date(9999)}, Value: 120},
+	}
+	_, err := db.NewInsert().Model(&prices).Exec(ctx)
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		at    time.Time
+		value int64
+	}{
+		{date(2020), 100},
+		{date(2020).AddDate(0, 6, 0), 100},
+		{date(2021), 110},
+		{date(2023), 120},
+	} {
+		price := new(Price)
+		err := db.NewSelect().Model(price).Where("id = ?", 1).AsOf(test.at).Scan(ctx)
+		require.NoError(t, err)
+		require.Equal(t, test.value, price.Value, test.at)
+	}
+
+	err = db.NewSelect().Model(new(Price)).Where("id = ?", 1).AsOf(date(2019)).Scan(ctx)
+	require.Equal(t, sql.ErrNoRows, err)
+
+	if db.Dialect().Name() != dialect.PG {
+		_, err := db.NewCreateTable().Model((*Price)(nil)).AsTemporalTable().Exec(ctx)
+		require.Error(t, err)
+		return
+	}
+
+	// The trigger keeps the old row versions.
+	_, err = db.NewDropTable().Model((*Price)(nil)).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().Model((*Price)(nil)).AsTemporalTable().Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = db.NewInsert().Model(&Price{ID: 1, Value: 100}).Exec(ctx)
+	require.NoError(t, err)
+
+	var inserted time.Time
+	err = db.NewSelect().Model((*Price)(nil)).Column("valid_from").Where("id = 1").Scan(ctx, &inserted)
+	require.NoError(t, err)
+
+	_, err = db.NewUpdate().Model((*Price)(nil)).Set("value = 110").Where("id = 1").Exec(ctx)
+	require.NoError(t, err)
+
+	// Repeated updates keep each row version.
+	_, err = db.NewUpdate().Model((*Price)(nil)).Set("value = 105").Where("id = 1").Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewUpdate().Model((*Price)(nil)).Set("value = 110").Where("id = 1").Exec(ctx)
+	require.NoError(t, err)
+
+	price := new(Price)
+	err = db.NewSelect().Model(price).Where("id = 1").AsOf(inserted).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), price.Value)
+
+	err = db.NewSelect().Model(price).Where("id = 1").AsOf(time.Now()).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(110), price.Value)
+
+	_, err = db.NewDelete().Model((*Price)(nil)).Where("id = 1").Exec(ctx)
+	require.NoError(t, err)
+
+	n, err := db.NewSelect().Model((*Price)(nil)).AsOf(time.Now()).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	n, err = db.NewSelect().Model((*Price)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 4, n)
+}
+
+func testMSSQLTemporalTable(t *testing.T, db *bun.DB) {
+	type Price struct {
+		bun.BaseModel `bun:"table:temporal_prices"`
+		bun.TemporalTable
+
+		ID    int64 `bun:",pk"`
+		Value int64
+	}
+
+	dropTable := func() {
+		_, _ = db.ExecContext(ctx, "IF OBJECT_ID('temporal_prices') IS NOT NULL "+
+			"ALTER TABLE temporal_prices SET (SYSTEM_VERSIONING = OFF)")
+		_, err := db.NewDropTable().Model((*Price)(nil)).IfExists().Exec(ctx)
+		require.NoError(t, err)
+		_, err = db.NewDropTable().Table("temporal_prices_history").IfExists().Exec(ctx)
+		require.NoError(t, err)
+	}
+	dropTable()
+	t.Cleanup(dropTable)
+
+	_, err := db.NewCreateTable().Model((*Price)(nil)).AsTemporalTable().Exec(ctx)
+	require.NoError(t, err)
+
+	// The period columns are set by the database.
+	price := &Price{ID: 1, Value: 100}
+	_, err = db.NewInsert().Model(price).Exec(ctx)
+	require.NoError(t, err)
+
+	var inserted time.Time
+	err = db.NewSelect().Model((*Price)(nil)).Column("valid_from").Where("id = 1").Scan(ctx, &inserted)
+	require.NoError(t, err)
+
+	price.Value = 110
+	_, err = db.NewUpdate().Model(price).WherePK().Exec(ctx)
+	require.NoError(t, err)
+
+	var now time.Time
+	err = db.NewRaw("SELECT SYSUTCDATETIME()").Scan(ctx, &now)
+	require.NoError(t, err)
+
+	price = new(Price)
+	err = db.NewSelect().Model(price).Where("id = 1").AsOf(inserted).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), price.Value)
+
+	err = db.NewSelect().Model(price).Where("id = 1").AsOf(now).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(110), price.Value)
+}
+
+func paginate(q bun.QueryBuilder, page, size int) bun.QueryBuilder {
+	return q.Order("id").Limit(size).Offset((page - 1) * size)
+}
+
+func orderItemsByID[T any](q *bun.TypedSelectQuery[T]) *bun.TypedSelectQuery[T] {
+	return q.Order("id")
+}
+
+func testUpsertReturnAction(t *testing.T, db *bun.DB) {
+	type Upsert struct {
+		ID       int64 `bun:",pk"`
+		Name     string
+		Inserted bool `bun:"inserted,scanonly"`
+	}
+
+	mustResetModel(t, ctx, db, (*Upsert)(nil))
+
+	_, err := db.NewInsert().Model(&[]Upsert{{ID: 1, Name: "a"}, {ID: 3, Name: "c"}}).Exec(ctx)
+	require.NoError(t, err)
+
+	switch db.Dialect().Name() {
+	case dialect.PG:
+		upserts := []Upsert{{ID: 1, Name: "a2"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c2"}}
+		_, err := db.NewInsert().
+			Model(&upserts).
+			OnConflictPK().
+			DoUpdateAllColumns().
+			ReturnInserted().
+			Exec(ctx)
+		require.NoError(t, err)
+		require.False(t, upserts[0].Inserted)
+		require.True(t, upserts[1].Inserted)
+		require.False(t, upserts[2].Inserted)
+	case dialect.MySQL:
+		for _, upsert := range []Upsert{{ID: 1, Name: "a2"}, {ID: 2, Name: "b"}} {
+			upsert := upsert
+			_, err := db.NewInsert().
+				Model(&upsert).
+				OnConflictPK().
+				DoUpdateAllColumns().
+				ReturnInserted().
+				Exec(ctx)
+			require.NoError(t, err)
+			require.Equal(t, upsert.ID == 2, upsert.Inserted)
+		}
+
+		upserts := []Upsert{{ID: 4}}
+		_, err := db.NewInsert().
+			Model(&upserts).
+			OnConflictPK().
+			DoUpdateAllColumns().
+			ReturnInserted().
+			Exec(ctx)
+		require.NoError(t, err)
+		require.True(t, upserts[0].Inserted)
+	default:
+		_, err := db.NewInsert().
+			Model(&Upsert{ID: 1}).
+			OnConflictPK().
+			DoUpdateAllColumns().
+			ReturnInserted().
+			Exec(ctx)
+		require.Error(t, err)
+	}
+}
+
+func testUpsertReturnInserted(t *testing.T, db *bun.DB) {
+	type Upsert struct {
+		ID          int64 `bun:",pk"`
+		Name        string
+		WasInserted bool `bun:",was_inserted,scanonly"`
+	}
+
+	mustResetModel(t, ctx, db, (*Upsert)(nil))
+
+	_, err := db.NewInsert().Model(&[]Upsert{{ID: 1, Name: "a"}, {ID: 3, Name: "c"}}).Exec(ctx)
+	require.NoError(t, err)
+
+	upsert := func(upserts []Upsert) error {
+		_, err := db.NewInsert().
+			Model(&upserts).
+			OnConflictPK().
+			DoUpdateAllColumns().
+			ReturnInserted().
+			Exec(ctx)
+		return err
+	}
+
+	switch db.Dialect().Name() {
+	case dialect.PG:
+		upserts := []Upsert{{ID: 1, Name: "a2"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c2"}, {ID: 4, Name: "d"}}
+		require.NoError(t, upsert(upserts))
+		require.Equal(t, []bool{false, true, false, true}, []bool{
+			upserts[0].WasInserted,
+			upserts[1].WasInserted,
+			upserts[2].WasInserted,
+			upserts[3].WasInserted,
+		})
+	case dialect.MySQL:
+		inserted := []Upsert{{ID: 2, Name: "b"}}
+		require.NoError(t, upsert(inserted))
+		require.True(t, inserted[0].WasInserted)
+
+		updated := []Upsert{{ID: 1, Name: "a2", WasInserted: true}}
+		require.NoError(t, upsert(updated))
+		require.False(t, updated[0].WasInserted)
+
+		unchanged := []Upsert{{ID: 1, Name: "a2", WasInserted: true}}
+		require.NoError(t, upsert(unchanged))
+		require.False(t, unchanged[0].WasInserted)
+
+		// Two inserted rows affect as many rows as one updated and one unchanged row,
+		// so batches are rejected before they are executed.
+		require.Error(t, upsert([]Upsert{{ID: 4, Name: "d"}, {ID: 5, Name: "e"}}))
+		exists, err := db.NewSelect().Model((*Upsert)(nil)).Where("id = ?", 4).Exists(ctx)
+		require.NoError(t, err)
+		require.False(t, exists)
+	default:
+		require.Error(t, upsert([]Upsert{{ID: 1}}))
+	}
+}
+
+func testMySQLJSON(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() != dialect.MySQL {
+		t.Skip()
+	}
+
+	type Item struct {
+		ID    int64                  `bun:",pk,autoincrement"`
+		Attrs map[string]interface{} `bun:"type:json"`
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Item)(nil))
+
+	items := []Item{
+		{Attrs: map[string]interface{}{
+			"size":   map[string]interface{}{"width": 10, "unit": "cm"},
+			"colors": []string{"red", "green"},
+		}},
+		{Attrs: map[string]interface{}{
+			"size":   map[string]interface{}{"width": 20, "unit": "in"},
+			"colors": []string{"blue"},
+		}},
+	}
+	_, err := db.NewInsert().Model(&items).Exec(ctx)
+	require.NoError(t, err)
+
+	attrs := mysqldialect.JSON("attrs")
+
+	var units []string
+	err = db.NewSelect().
+		Model((*Item)(nil)).
+		ColumnExpr("?", attrs.Extract("$.size.unit")).
+		Order("id").
+		Scan(ctx, &units)
+	require.NoError(t, err)
+	require.Equal(t, []string{"cm", "in"}, units)
+
+	var ids []int64
+	err = db.NewSelect().
+		Model((*Item)(nil)).
+		Column("id").
+		Where("? = ?", attrs.Extract("$.size.width"), "20").
+		Scan(ctx, &ids)
+	require.NoError(t, err)
+	require.Equal(t, []int64{items[1].ID}, ids)
+
+	ids = nil
+	err = db.NewSelect().
+		Model((*Item)(nil)).
+		Column("id").
+		Where("?", attrs.Contains("green", "$.colors")).
+		Scan(ctx, &ids)
+	require.NoError(t, err)
+	require.Equal(t, []int64{items[0].ID}, ids)
+
+	var path string
+	err = db.NewSelect().
+		Model((*Item)(nil)).
+		ColumnExpr("JSON_UNQUOTE(?)", attrs.Search("one", "blue", "")).
+		Where("id = ?", items[1].ID).
+		Scan(ctx, &path)
+	require.NoError(t, err)
+	require.Equal(t, "$.colors[0]", path)
+}
+
+func testQueryComment(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+
+	var queries []string
+	db = db.WithNamedArg("query_comment", true) // clone the db to not leak the hook
+	db.AddQueryHook(&queryHook{
+		beforeQuery: func(ctx context.Context, event *bun.QueryEvent) context.Context {
+			queries = append(queries, event.Query)
+			return ctx
+		},
+	})
+
+	ctx = bun.WithQueryComment(ctx, "trace_id=123 */ DROP TABLE users; /*")
+
+	var num int
+	err := db.NewSelect().ColumnExpr("1").Scan(ctx, &num)
+	require.NoError(t, err)
+	require.Equal(t, 1, num)
+
+	_, err = db.ExecContext(ctx, "SELECT 2")
+	require.NoError(t, err)
+
+	// The query comment takes precedence over the context comment.
+	err = db.NewSelect().ColumnExpr("3").Comment("method").Scan(ctx, &num)
+	require.NoError(t, err)
+	require.Equal(t, 3, num)
+
+	require.Len(t, queries, 3)
+	require.True(t, strings.HasPrefix(queries[0], "/* trace_id=123  DROP TABLE users;  */ SELECT 1"), queries[0])
+	require.True(t, strings.HasPrefix(queries[1], "/* trace_id=123  DROP TABLE users;  */ SELECT 2"), queries[1])
+	require.True(t, strings.HasPrefix(queries[2], "/* method */ SELECT 3"), queries[2])
+}
+
+func testInsertOmitZero(t *testing.T, db *bun.DB) {
+	type ItemTable struct {
+		bun.BaseModel `bun:"table:omit_zero_items"`
+
+		ID     int64  `bun:",pk,autoincrement"`
+		Name   string `bun:",notnull,default:'unnamed'"`
+		Status string `bun:",default:'new'"`
+	}
+
+	// Item does not know about the defaults.
+	type Item struct {
+		bun.BaseModel `bun:"table:omit_zero_items"`
+
+		ID     int64 `bun:",pk,autoincrement"`
+		Name   string
+		Status *string
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*ItemTable)(nil))
+
+	item := &Item{Name: "hello"}
+	_, err := db.NewInsert().Model(item).OmitZero().Exec(ctx)
+	require.NoError(t, err)
+	require.NotZero(t, item.ID)
+	if db.HasFeature(feature.InsertReturning) {
+		require.NotNil(t, item.Status)
+		require.Equal(t, "new", *item.Status)
+	}
+
+	// Rows that are zero in the included columns get NULL.
+	status := "done"
+	items := []Item{{Name: "foo"}, {Name: "bar", Status: &status}}
+	_, err = db.NewInsert().Model(&items).OmitZero().Exec(ctx)
+	require.NoError(t, err)
+
+	var rows []ItemTable
+	err = db.NewSelect().Model(&rows).Order("id").Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	require.Equal(t, "hello", rows[0].Name)
+	require.Equal(t, "new", rows[0].Status)
+	require.Equal(t, "foo", rows[1].Name)
+	require.Equal(t, "", rows[1].Status)
+	require.Equal(t, "done", rows[2].Status)
+}
+
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	val       []byte
+	expiresAt time.Time
+}
+
+func (c *memoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		return nil, false
+	}
+	return e.val, true
+}
+
+func (c *memoryCache) Set(key string, val []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]memoryCacheEntry)
+	}
+	c.entries[key] = memoryCacheEntry{val: val, expiresAt: time.Now().Add(ttl)}
+}
+
+func testCacheHook(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID   int64 `bun:",pk"`
+		Name string
+	}
+
+	const ttl = 200 * time.Millisecond
+
+	mustResetModel(t, ctx, db, (*Model)(nil))
+	_, err := db.NewInsert().Model(&Model{ID: 1, Name: "one"}).Exec(ctx)
+	require.NoError(t, err)
+
+	// The same database without the hook to change the data behind the cache.
+	uncached := bun.NewDB(db.DB, db.Dialect())
+
+	cached := db.WithNamedArg("cache_hook", true) // clone the db to not leak the hook
+	cached.AddQueryHook(bun.NewCacheHook(new(memoryCache), ttl))
+
+	selectName := func() string {
+		model := new(Model)
+		err := cached.NewSelect().Model(model).Where("id = ?", 1).Scan(ctx)
+		require.NoError(t, err)
+		return model.Name
+	}
+
+	require.Equal(t, "one", selectName())
+
+	_, err = uncached.NewUpdate().Model(&Model{ID: 1, Name: "two"}).WherePK().Exec(ctx)
+	require.NoError(t, err)
+
+	// Cache hit avoids the database.
+	require.Equal(t, "one", selectName())
+
+	// TTL expiry executes the query.
+	time.Sleep(ttl)
+	require.Equal(t, "two", selectName())
+
+	// Updates invalidate the cached results.
+	_, err = cached.NewUpdate().Model(&Model{ID: 1, Name: "three"}).WherePK().Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "three", selectName())
+
+	// Updates in transactions invalidate the results cached before the commit.
+	err = cached.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().Model(&Model{ID: 1, Name: "four"}).WherePK().Exec(ctx)
+		if err != nil {
+			return err
+		}
+		// MSSQL blocks reading the rows changed by the transaction.
+		if db.Dialect().Name() != dialect.MSSQL {
+			require.Equal(t, "three", selectName())
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "four", selectName())
+
+	// The results are not cached if the rows can depend on the RLS settings.
+	rls := cached.WithNamedArg("rls", true)
+	rls.AddQueryHook(bun.NewRLSHook(func(ctx context.Context, conn bun.IConn) error {
+		return nil
+	}))
+	selectRLS := func() string {
+		model := new(Model)
+		err := rls.NewSelect().Model(model).Where("id = ?", 1).Scan(ctx)
+		require.NoError(t, err)
+		return model.Name
+	}
+	require.Equal(t, "four", selectRLS())
+	_, err = uncached.NewUpdate().Model(&Model{ID: 1, Name: "five"}).WherePK().Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "five", selectRLS())
+}
+
+func testStats(t *testing.T, db *bun.DB) {
+	const sleep = time.Millisecond
+
+	// A new DB to not leak the middleware and count the queries from scratch.
+	db = bun.NewDB(db.DB, db.Dialect(), bun.WithSlowQueryThreshold(sleep/2))
+	db.Use(func(next bun.QueryFunc) bun.QueryFunc {
+		return func(ctx context.Context, conn bun.IConn, query string) (sql.Result, error) {
+			time.Sleep(sleep)
+			return next(ctx, conn, query)
+		}
+	})
+
+	for i := 0; i < 1000; i++ {
+		_, err := db.NewSelect().ColumnExpr("1").Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	stats := db.Stats()
+	require.Equal(t, uint64(1000), stats.TotalQueries)
+	require.Zero(t, stats.TotalErrors)
+	require.Equal(t, uint64(1000), stats.SlowQueries)
+	require.GreaterOrEqual(t, stats.MeanQueryDuration, sleep)
+	require.Less(t, stats.MeanQueryDuration, 10*sleep)
+	require.GreaterOrEqual(t, stats.P99QueryDuration, stats.MeanQueryDuration*9/10)
+	require.NotZero(t, stats.OpenConnections)
+
+	_, err := db.NewSelect().ColumnExpr("1").Table("missing_table").Exec(ctx)
+	require.Error(t, err)
+	require.Equal(t, uint64(1), db.Stats().TotalErrors)
+	require.Equal(t, bun.DBStats{Queries: 1001, Errors: 1}, db.DBStats())
+
+	db.ResetStats()
+	stats = db.Stats()
+	require.Zero(t, stats.TotalQueries)
+	require.Zero(t, stats.MeanQueryDuration)
+}
+
+func testTableSample(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID int64 `bun:",pk"`
+	}
+
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	if db.Dialect().Name() != dialect.PG {
+		models := []Model{{ID: 1}, {ID: 2}, {ID: 3}}
+		_, err := db.NewInsert().Model(&models).Exec(ctx)
+		require.NoError(t, err)
+
+		// TableSample is ignored.
+		count, err := db.NewSelect().Model((*Model)(nil)).TableSample("BERNOULLI", "1").Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+		return
+	}
+
+	_, err := db.NewRaw("INSERT INTO ? SELECT generate_series(1, 1000000)", bun.Ident("models")).Exec(ctx)
+	require.NoError(t, err)
+
+	count, err := db.NewSelect().Model((*Model)(nil)).TableSample("BERNOULLI", "1").Count(ctx)
+	require.NoError(t, err)
+	require.InDelta(t, 10000, count, 1000)
+}
+
+func testInsertRow(t *testing.T, db *bun.DB) {
+	type Model struct {
+		bun.BaseModel `bun:"table:insert_rows"`
+
+		ID    int64 `bun:",pk"`
+		Name  sql.NullString
+		Count sql.NullInt64
+	}
+
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	_, err := db.NewInsert().
+		Model((*Model)(nil)).
+		Row(map[string]interface{}{"id": 1, "name": "one"}).
+		Row(map[string]interface{}{"id": 2, "count": 2}).
+		Row(map[string]interface{}{"id": 3, "name": "three", "count": 3}).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	var models []Model
+	err = db.NewSelect().Model(&models).Order("id").Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Model{
+		{ID: 1, Name: sql.NullString{String: "one", Valid: true}},
+		{ID: 2, Count: sql.NullInt64{Int64: 2, Valid: true}},
+		{ID: 3, Name: sql.NullString{String: "three", Valid: true}, Count: sql.NullInt64{Int64: 3, Valid: true}},
+	}, models)
+}
+
+func testDropTableCascade(t *testing.T, db *bun.DB) {
+	type Parent struct {
+		bun.BaseModel `bun:"table:cascade_parents"`
+
+		ID int64 `bun:",pk,autoincrement"`
+	}
+
+	type Child struct {
+		bun.BaseModel `bun:"table:cascade_children"`
+
+		ID       int64 `bun:",pk,autoincrement"`
+		ParentID int64
+	}
+
+	ctx := context.Background()
+
+	if !db.HasFeature(feature.TableCascade) {
+		_, err := db.NewDropTable().Model((*Parent)(nil)).IfExists().Cascade().Exec(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not supported")
+		return
+	}
+
+	mustResetModel(t, ctx, db, (*Parent)(nil), (*Child)(nil))
+	_, err := db.NewDropTable().Model((*Child)(nil)).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().
+		Model((*Child)(nil)).
+		ForeignKey(`("parent_id") REFERENCES "cascade_parents" ("id")`).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = db.NewDropTable().Model((*Parent)(nil)).Restrict().Exec(ctx)
+	require.Error(t, err)
+
+	_, err = db.NewDropTable().Model((*Parent)(nil)).IfExists().Cascade().Exec(ctx)
+	require.NoError(t, err)
+
+	table, err := sqlschema.InspectTable(ctx, db, "", "cascade_children")
+	require.NoError(t, err)
+	require.Empty(t, table.ForeignKeys)
+	require.NotNil(t, table.Column("parent_id"))
+
+	_, err = db.NewDropTable().Model((*Parent)(nil)).IfExists().Cascade().Exec(ctx)
+	require.NoError(t, err)
+}
+
+func testSearchPath(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() != dialect.PG {
+		t.Skip()
+	}
+
+	type Profile struct {
+		bun.BaseModel `bun:"table:search_path_profiles"`
+
+		ID   int64 `bun:",pk"`
+		Lang string
+	}
+
+	type User struct {
+		bun.BaseModel `bun:"table:search_path_users"`
+
+		ID        int64 `bun:",pk"`
+		Name      string
+		ProfileID int64
+		Profile   *Profile `bun:"rel:belongs-to"`
+	}
+
+	ctx := context.Background()
+	tenants := []string{"tenant_a", "tenant_b"}
+
+	for i, tenant := range tenants {
+		_, err := db.ExecContext(ctx, "DROP SCHEMA IF EXISTS ? CASCADE", bun.Ident(tenant))
+		require.NoError(t, err)
+		_, err = db.ExecContext(ctx, "CREATE SCHEMA ?", bun.Ident(tenant))
+		require.NoError(t, err)
+
+		ctx := bun.WithSearchPath(ctx, tenant)
+		_, err = db.NewCreateTable().Model((*Profile)(nil)).Exec(ctx)
+		require.NoError(t, err)
+		_, err = db.NewCreateTable().Model((*User)(nil)).Exec(ctx)
+		require.NoError(t, err)
+
+		_, err = db.NewInsert().Model(&Profile{ID: 1, Lang: tenant}).Exec(ctx)
+		require.NoError(t, err)
+		_, err = db.NewInsert().Model(&User{ID: int64(i + 1), Name: tenant, ProfileID: 1}).Exec(ctx)
+		require.NoError(t, err)
+	}
+	defer func() {
+		for _, tenant := range tenants {
+			_, err := db.ExecContext(ctx, "DROP SCHEMA IF EXISTS ? CASCADE", bun.Ident(tenant))
+			require.NoError(t, err)
+		}
+	}()
+
+	for _, tenant := range tenants {
+		ctx := bun.WithSearchPath(ctx, tenant)
+
+		var users []User
+		err := db.NewSelect().Model(&users).Relation("Profile").Scan(ctx)
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		require.Equal(t, tenant, users[0].Name)
+		require.Equal(t, tenant, users[0].Profile.Lang)
+
+		err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			var user User
+			if err := tx.NewSelect().Model(&user).Scan(ctx); err != nil {
+				return err
+			}
+			require.Equal(t, tenant, user.Name)
+			return nil
+		})
+		require.NoError(t, err)
+
+		count, err := db.NewSelect().Model((*User)(nil)).Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		rows, err := db.NewSelect().Model((*User)(nil)).Column("name").Rows(ctx)
+		require.NoError(t, err)
+		require.True(t, rows.Next())
+		var name string
+		require.NoError(t, rows.Scan(&name))
+		require.Equal(t, tenant, name)
+		require.NoError(t, rows.Close())
+
+		name = ""
+		err = db.QueryRowContext(ctx, "SELECT name FROM search_path_users").Scan(&name)
+		require.NoError(t, err)
+		require.Equal(t, tenant, name)
+
+		// The models are cached for each search path.
+		profile := &Profile{ID: 1}
+		err = db.NewCachedSelect(time.Minute).Model(profile).WherePK().Scan(ctx)
+		require.NoError(t, err)
+		require.Equal(t, tenant, profile.Lang)
+
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		name = ""
+		err = conn.NewSelect().Model((*User)(nil)).Column("name").Scan(ctx, &name)
+		require.NoError(t, err)
+		require.Equal(t, tenant, name)
+		require.NoError(t, conn.Close())
+	}
+
+	// Queries without the search path don't see the tenant tables.
+	for i := 0; i < 3; i++ {
+		exists, err := db.NewSelect().
+			Table("pg_tables").
+			Where("tablename = ?", "search_path_users").
+			Where("schemaname = current_schema()").
+			Exists(ctx)
+		require.NoError(t, err)
+		require.False(t, exists)
+	}
+}
+
+func testOutbox(t *testing.T, db *bun.DB) {
+	type Payload struct {
+		OrderID int64 `json:"order_id"`
+	}
+
+	ctx := context.Background()
+	outbox := bun.NewOutbox(db, "test_outbox")
+
+	_, err := db.NewDropTable().Model((*bun.OutboxEvent)(nil)).ModelTableExpr("test_outbox").IfExists().Exec(ctx)
+	require.NoError(t, err)
+	require.NoError(t, outbox.CreateOutboxTable(ctx))
+
+	poll := func() []bun.OutboxEvent {
+		var delivered []bun.OutboxEvent
+		err := outbox.Poll(ctx, 10, func(events []bun.OutboxEvent) error {
+			delivered = append(delivered, events...)
+			return nil
+		})
+		require.NoError(t, err)
+		return delivered
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, outbox.Publish(ctx, tx, "order.created", Payload{OrderID: 1}))
+	require.NoError(t, tx.Rollback())
+
+	require.Empty(t, poll())
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return outbox.Publish(ctx, tx, "order.created", Payload{OrderID: 2})
+	})
+	require.NoError(t, err)
+
+	handlerErr := errors.New("broker is unavailable")
+	err = outbox.Poll(ctx, 10, func(events []bun.OutboxEvent) error {
+		return handlerErr
+	})
+	require.Equal(t, handlerErr, err)
+
+	var event bun.OutboxEvent
+	err = db.NewSelect().ColumnExpr("*").Model(&event).ModelTableExpr("test_outbox").Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, event.Retries)
+	require.Equal(t, handlerErr.Error(), event.LastError)
+	require.True(t, event.DeliveredAt.IsZero())
+
+	delivered := poll()
+	require.Len(t, delivered, 1)
+	require.Equal(t, "order.created", delivered[0].EventType)
+
+	var payload Payload
+	require.NoError(t, delivered[0].Unmarshal(&payload))
+	require.Equal(t, Payload{OrderID: 2}, payload)
+
+	require.Empty(t, poll())
+
+	err = db.NewSelect().ColumnExpr("*").Model(&event).ModelTableExpr("test_outbox").Scan(ctx)
+	require.NoError(t, err)
+	require.False(t, event.DeliveredAt.IsZero())
+
+	// Events that failed too many times don't block the events published after them.
+	outbox = bun.NewOutbox(db, "test_outbox", bun.WithOutboxMaxRetries(2))
+	require.NoError(t, outbox.Publish(ctx, db, "order.created", Payload{OrderID: 3}))
+	for i := 0; i < 2; i++ {
+		err = outbox.Poll(ctx, 10, func(events []bun.OutboxEvent) error {
+			return handlerErr
+		})
+		require.Equal(t, handlerErr, err)
+	}
+	require.NoError(t, outbox.Publish(ctx, db, "order.created", Payload{OrderID: 4}))
+
+	delivered = poll()
+	require.Len(t, delivered, 1)
+	require.NoError(t, delivered[0].Unmarshal(&payload))
+	require.Equal(t, Payload{OrderID: 4}, payload)
+
+	event = bun.OutboxEvent{}
+	err = db.NewSelect().ColumnExpr("*").Model(&event).ModelTableExpr("test_outbox").
+		Where("retries > 0").Where("delivered_at IS NULL").Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, event.Retries)
+	require.NoError(t, event.Unmarshal(&payload))
+	require.Equal(t, Payload{OrderID: 3}, payload)
+}
+
+func testRegisterType(t *testing.T, db *bun.DB) {
+	schema.RegisterType[netip.Addr](
+		func(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
+			return fmter.Dialect().AppendString(b, v.Interface().(netip.Addr).String())
+		},
+		func(dest reflect.Value, src interface{}) error {
+			var s string
+			switch src := src.(type) {
+			case nil:
+				dest.Set(reflect.Zero(dest.Type()))
+				return nil
+			case string:
+				s = src
+			case []byte:
+				s = string(src)
+			default:
+				return fmt.Errorf("can't scan %T into netip.Addr", src)
+			}
+			addr, err := netip.ParseAddr(s)
+			if err != nil {
+				return err
+			}
+			dest.Set(reflect.ValueOf(addr))
+			return nil
+		},
+	)
+
+	type Host struct {
+		ID     int64       `bun:",pk,autoincrement"`
+		Addr   netip.Addr  `bun:"type:varchar(64)"`
+		Backup *netip.Addr `bun:"type:varchar(64)"`
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Host)(nil))
+
+	backup := netip.MustParseAddr("::1")
+	hosts := []Host{
+		{Addr: netip.MustParseAddr("10.0.0.1"), Backup: &backup},
+		{Addr: netip.MustParseAddr("192.168.1.1")},
+	}
+	_, err := db.NewInsert().Model(&hosts).Exec(ctx)
+	require.NoError(t, err)
+
+	var selected []Host
+	err = db.NewSelect().Model(&selected).Order("id").Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, selected, 2)
+	require.Equal(t, hosts[0].Addr, selected[0].Addr)
+	require.Equal(t, backup, *selected[0].Backup)
+	require.Equal(t, hosts[1].Addr, selected[1].Addr)
+	require.Nil(t, selected[1].Backup)
+
+	// Registered types are also used for query arguments and scan destinations.
+	var addr netip.Addr
+	err = db.NewSelect().
+		Model((*Host)(nil)).
+		Column("addr").
+		Where("addr = ?", netip.MustParseAddr("192.168.1.1")).
+		Scan(ctx, &addr)
+	require.NoError(t, err)
+	require.Equal(t, hosts[1].Addr, addr)
+}
+
+func testDecimal(t *testing.T, db *bun.DB) {
+	type Account struct {
+		ID      int64           `bun:",pk,autoincrement"`
+		Balance decimal.Decimal `bun:"type:numeric(19,4)"`
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Account)(nil))
+
+	sum := decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2"))
+	_, err := db.NewInsert().Model(&Account{Balance: sum}).Exec(ctx)
+	require.NoError(t, err)
+
+	account := new(Account)
+	err = db.NewSelect().Model(account).Where("balance = ?", decimal.RequireFromString("0.3")).Scan(ctx)
+	require.NoError(t, err)
+	require.True(t, account.Balance.Equal(decimal.RequireFromString("0.3")), "got %s", account.Balance)
+
+	// Arithmetic on the fetched values is exact.
+	total := account.Balance.Mul(decimal.NewFromInt(3))
+	require.Equal(t, "0.9", total.String())
+}
+
+func testUUIDGenerate(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.PG, dialect.SQLite:
+	default:
+		t.Skip()
+	}
+
+	type Model struct {
+		ID   uuid.UUID `bun:",pk,type:uuid,default:gen_random_uuid()"`
+		Ref  string    `bun:"type:uuid,default:gen_random_uuid()"`
+		Name string
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	model := &Model{Name: "one"}
+	_, err := db.NewInsert().Model(model).Exec(ctx)
+	require.NoError(t, err)
+	require.NotEqual(t, uuid.Nil, model.ID)
+	require.NotEmpty(t, model.Ref)
+
+	models := []Model{{Name: "two"}, {Name: "three"}}
+	_, err = db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+	require.NotEqual(t, uuid.Nil, models[0].ID)
+	require.NotEqual(t, models[0].ID, models[1].ID)
+
+	selected := new(Model)
+	err = db.NewSelect().Model(selected).Where("id = ?", model.ID).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, model, selected)
+}
+
+func testULID(t *testing.T, db *bun.DB) {
+	schema.RegisterULID()
+
+	type Model struct {
+		ID   bun.ULID `bun:",pk,type:ulid"`
+		Name string
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	start := time.Now().Truncate(time.Millisecond)
+
+	// SQLite returns SQLITE_BUSY for concurrent writes, so the inserts are serialized there.
+	var sqliteMu sync.Mutex
+	isSQLite := db.Dialect().Name() == dialect.SQLite
+
+	const n = 100
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if isSQLite {
+				sqliteMu.Lock()
+				defer sqliteMu.Unlock()
+			}
+			model := &Model{Name: fmt.Sprint(i)}
+			if _, err := db.NewInsert().Model(model).Exec(ctx); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	var models []Model
+	err := db.NewSelect().Model(&models).Order("id").Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, models, n)
+
+	for i := range models {
+		require.False(t, models[i].ID.IsZero())
+		require.False(t, models[i].ID.Time().Before(start))
+		if i == 0 {
+			continue
+		}
+		prev, curr := models[i-1].ID, models[i].ID
+		require.Less(t, prev.String(), curr.String())
+		require.False(t, curr.Time().Before(prev.Time()))
+	}
+
+	b, err := json.Marshal(models[0].ID)
+	require.NoError(t, err)
+	var id bun.ULID
+	require.NoError(t, json.Unmarshal(b, &id))
+	require.Equal(t, models[0].ID, id)
+}
+
+func testWhereIn(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID   int64 `bun:",pk,autoincrement"`
+		Name string
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	models := []Model{{Name: "one"}, {Name: "two"}, {Name: "three"}}
+	_, err := db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+
+	count, err := db.NewSelect().Model((*Model)(nil)).WhereIn("id", models[:2]).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	subq := db.NewSelect().Model((*Model)(nil)).Column("id").Where("name = ?", "three")
+	selected := new(Model)
+	err = db.NewSelect().Model(selected).WhereIn("id", subq).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "three", selected.Name)
+
+	count, err = db.NewSelect().Model((*Model)(nil)).WhereIn("id", []int64{}).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	count, err = db.NewSelect().Model((*Model)(nil)).WhereNotIn("id", []int64{}).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+}
+
+func testExplain(t *testing.T, db *bun.DB) {
+	type Author struct {
+		ID   int64 `bun:",pk,autoincrement"`
+		Name string
+	}
+	type Book struct {
+		ID       int64 `bun:",pk,autoincrement"`
+		Title    string
+		AuthorID int64
+		Author   *Author `bun:"rel:belongs-to"`
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Author)(nil), (*Book)(nil))
+
+	var operation string
+	db = db.WithNamedArg("explain", true) // clone the db to not leak the hook
+	db.AddQueryHook(&queryHook{
+		beforeQuery: func(ctx context.Context, event *bun.QueryEvent) context.Context {
+			operation = event.Operation()
+			return ctx
+		},
+	})
+
+	q := db.NewSelect().Model((*Book)(nil)).Relation("Author").Where("book.id > ?", 0)
+	plan, err := q.Explain(ctx)
+	if db.Dialect().Name() == dialect.MSSQL {
+		require.EqualError(t, err, "bun: EXPLAIN is not supported by mssql")
+		return
+	}
+	require.NoError(t, err)
+	require.Equal(t, "EXPLAIN", operation)
+
+	var decoded interface{}
+	require.NoError(t, json.Unmarshal(plan, &decoded))
+
+	switch db.Dialect().Name() {
+	case dialect.PG:
+		require.Contains(t, string(plan), `"Node Type"`)
+
+		plan, err = q.Explain(ctx, bun.ExplainAnalyze(), bun.ExplainBuffers(), bun.ExplainVerbose())
+		require.NoError(t, err)
+		require.Contains(t, string(plan), `"Actual Rows"`)
+
+		plan, err = q.Explain(ctx, bun.ExplainFormat("text"))
+		require.NoError(t, err)
+		var text string
+		require.NoError(t, json.Unmarshal(plan, &text))
+		require.Contains(t, text, "Join")
+	case dialect.MySQL:
+		require.Contains(t, string(plan), `"query_block"`)
+	case dialect.SQLite:
+		require.Contains(t, string(plan), `"detail"`)
+	}
+
+	_, err = q.Explain(ctx, bun.ExplainFormat("yaml"))
+	require.EqualError(t, err, `bun: unsupported EXPLAIN format "yaml"`)
+}
+
+func testPreload(t *testing.T, db *bun.DB) {
+	type Item struct {
+		ID      int64 `bun:",pk,autoincrement"`
+		OrderID int64
+		Name    string
+	}
+	type Order struct {
+		ID     int64 `bun:",pk,autoincrement"`
+		UserID int64
+		Items  []*Item `bun:"rel:has-many"`
+	}
+	type User struct {
+		ID     int64 `bun:",pk,autoincrement"`
+		Name   string
+		Orders []Order `bun:"rel:has-many"`
+	}
+	type Profile struct {
+		ID     int64 `bun:",pk,autoincrement"`
+		UserID int64
+		User   *User `bun:"rel:belongs-to"`
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*User)(nil), (*Order)(nil), (*Item)(nil))
+
+	users := make([]User, 10)
+	for i := range users {
+		users[i].Name = fmt.Sprintf("user%d", i)
+	}
+	_, err := db.NewInsert().Model(&users).Exec(ctx)
+	require.NoError(t, err)
+
+	// Every user has id % 4 orders with 2 items each.
+	var orders []Order
+	for _, user := range users {
+		for i := 0; i < int(user.ID)%4; i++ {
+			orders = append(orders, Order{UserID: user.ID})
+		}
+	}
+	_, err = db.NewInsert().Model(&orders).Exec(ctx)
+	require.NoError(t, err)
+
+	var items []Item
+	for _, order := range orders {
+		for i := 0; i < 2; i++ {
+			items = append(items, Item{OrderID: order.ID, Name: fmt.Sprintf("item%d-%d", order.ID, i)})
+		}
+	}
+	_, err = db.NewInsert().Model(&items).Exec(ctx)
+	require.NoError(t, err)
+
+	users = nil
+	err = db.NewRaw("SELECT * FROM ? ORDER BY id", bun.Ident(db.Table(reflect.TypeOf(User{})).Name)).
+		Scan(ctx, &users)
+	require.NoError(t, err)
+	require.Len(t, users, 10)
+
+	var queries int
+	hookDB := db.WithNamedArg("preload", true) // clone the db to not leak the hook
+	hookDB.AddQueryHook(&queryHook{
+		beforeQuery: func(ctx context.Context, event *bun.QueryEvent) context.Context {
+			queries++
+			return ctx
+		},
+	})
+
+	// Preloading twice does not duplicate the relations.
+	for i := 0; i < 2; i++ {
+		queries = 0
+		err = hookDB.NewSelect().Preload(ctx, &users, "Orders", "Orders.Items")
+		require.NoError(t, err)
+		require.Equal(t, 2, queries)
+	}
+
+	for _, user := range users {
+		require.Len(t, user.Orders, int(user.ID)%4, user.Name)
+		for _, order := range user.Orders {
+			require.Equal(t, user.ID, order.UserID)
+			require.Len(t, order.Items, 2)
+			for i, item := range order.Items {
+				require.Equal(t, order.ID, item.OrderID)
+				require.Equal(t, fmt.Sprintf("item%d-%d", order.ID, i), item.Name)
+			}
+		}
+	}
+
+	// Relations can be preloaded for a single model too.
+	order := users[2].Orders[0]
+	order.Items = nil
+	err = db.NewSelect().Preload(ctx, &order, "Items")
+	require.NoError(t, err)
+	require.Len(t, order.Items, 2)
+
+	err = db.NewSelect().Preload(ctx, &[]Profile{{UserID: 1}}, "User")
+	require.EqualError(t, err, "bun: Preload supports only has-many and m2m relations, got relation=User")
+}
+
+func testBelongsToChain(t *testing.T, db *bun.DB) {
+	type Link struct {
+		ID        int64 `bun:",pk,autoincrement"`
+		ProfileID int64
+		URL       string
+	}
+	type Profile struct {
+		ID     int64 `bun:",pk,autoincrement"`
+		UserID int64
+		Bio    string
+		Links  []Link `bun:"rel:has-many"`
+	}
+	type User struct {
+		ID      int64 `bun:",pk,autoincrement"`
+		Name    string
+		Profile *Profile `bun:"rel:has-one"`
+	}
+	type Order struct {
+		ID     int64 `bun:",pk,autoincrement"`
+		UserID int64
+		User   *User `bun:"rel:belongs-to"`
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Order)(nil), (*User)(nil), (*Profile)(nil), (*Link)(nil))
+
+	users := []User{{Name: "alice"}, {Name: "bob"}}
+	_, err := db.NewInsert().Model(&users).Exec(ctx)
+	require.NoError(t, err)
+
+	profiles := []Profile{{UserID: users[0].ID, Bio: "alice bio"}, {UserID: users[1].ID, Bio: "bob bio"}}
+	_, err = db.NewInsert().Model(&profiles).Exec(ctx)
+	require.NoError(t, err)
+
+	links := []Link{
+		{ProfileID: profiles[0].ID, URL: "alice1"},
+		{ProfileID: profiles[0].ID, URL: "alice2"},
+		{ProfileID: profiles[1].ID, URL: "bob1"},
+	}
+	_, err = db.NewInsert().Model(&links).Exec(ctx)
+	require.NoError(t, err)
+
+	orders := []Order{{UserID: users[0].ID}, {UserID: users[1].ID}, {UserID: users[0].ID}, {}}
+	_, err = db.NewInsert().Model(&orders).Exec(ctx)
+	require.NoError(t, err)
+
+	requireOrders := func(orders []Order) {
+		require.Len(t, orders, 4)
+		// The order without a user does not break the nested relations.
+		require.Nil(t, orders[3].User)
+
+		wantUsers := []string{"alice", "bob", "alice"}
+		wantLinks := [][]string{{"alice1", "alice2"}, {"bob1"}, {"alice1", "alice2"}}
+		for i, order := range orders[:3] {
+			require.NotNil(t, order.User)
+			require.Equal(t, order.UserID, order.User.ID)
+			require.Equal(t, wantUsers[i], order.User.Name)
+			require.NotNil(t, order.User.Profile)
+			require.Equal(t, wantUsers[i]+" bio", order.User.Profile.Bio)
+
+			var urls []string
+			for _, link := range order.User.Profile.Links {
+				urls = append(urls, link.URL)
+			}
+			require.Equal(t, wantLinks[i], urls)
+		}
+	}
+
+	orders = nil
+	err = db.NewSelect().
+		Model(&orders).
+		Relation("User").
+		Relation("User.Profile").
+		Relation("User.Profile.Links", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("link.id")
+		}).
+		Order("order.id").
+		Scan(ctx)
+	require.NoError(t, err)
+	requireOrders(orders)
+
+	// The intermediate relations are selected implicitly.
+	orders = nil
+	err = db.NewSelect().
+		Model(&orders).
+		Relation("User.Profile.Links", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("link.id")
+		}).
+		Order("order.id").
+		Scan(ctx)
+	require.NoError(t, err)
+	requireOrders(orders)
+}
+
+func testFieldRedaction(t *testing.T, db *bun.DB) {
+	type Review struct {
+		ID         int64 `bun:",pk,autoincrement"`
+		EmployeeID int64
+		Notes      *string `bun:",sensitive"`
+	}
+	type Employee struct {
+		ID      int64 `bun:",pk,autoincrement"`
+		Name    string
+		Salary  int64     `bun:",sensitive"`
+		SSN     string    `bun:",sensitive"`
+		Reviews []*Review `bun:"rel:has-many"`
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Employee)(nil), (*Review)(nil))
+
+	employee := &Employee{Name: "alice", Salary: 100000, SSN: "123-45-6789"}
+	_, err := db.NewInsert().Model(employee).Exec(ctx)
+	require.NoError(t, err)
+
+	notes := "exceeds expectations"
+	_, err = db.NewInsert().Model(&Review{EmployeeID: employee.ID, Notes: &notes}).Exec(ctx)
+	require.NoError(t, err)
+
+	db = db.WithRedactionPolicy(bun.AllowRoles("admin"))
+
+	selectEmployees := func(ctx context.Context) []Employee {
+		var employees []Employee
+		err := db.NewSelect().Model(&employees).Relation("Reviews").Scan(ctx)
+		require.NoError(t, err)
+		require.Len(t, employees, 1)
+		require.Len(t, employees[0].Reviews, 1)
+		return employees
+	}
+
+	employees := selectEmployees(bun.WithRole(ctx, "admin"))
+	require.Equal(t, "alice", employees[0].Name)
+	require.Equal(t, int64(100000), employees[0].Salary)
+	require.Equal(t, "123-45-6789", employees[0].SSN)
+	require.Equal(t, notes, *employees[0].Reviews[0].Notes)
+
+	for _, ctx := range []context.Context{bun.WithRole(ctx, "user"), ctx} {
+		employees := selectEmployees(ctx)
+		require.Equal(t, "alice", employees[0].Name)
+		require.Equal(t, int64(0), employees[0].Salary)
+		require.Equal(t, bun.Redacted, employees[0].SSN)
+		require.Equal(t, bun.Redacted, *employees[0].Reviews[0].Notes)
+	}
+
+	userCtx := bun.WithRole(ctx, "user")
+	employee = new(Employee)
+	err = db.NewSelect().Model(employee).Where("name = ?", "alice").Scan(userCtx)
+	require.NoError(t, err)
+	require.Equal(t, bun.Redacted, employee.SSN)
+
+	// The rows scanned with DB.ScanRows are redacted too.
+	rows, err := db.NewSelect().Model((*Employee)(nil)).Rows(userCtx)
+	require.NoError(t, err)
+	defer rows.Close()
+	employees = nil
+	require.NoError(t, db.ScanRows(userCtx, rows, &employees))
+	require.Len(t, employees, 1)
+	require.Equal(t, bun.Redacted, employees[0].SSN)
+
+	// The redacted model can't overwrite the sensitive fields.
+	employee.Name = "alice2"
+	_, err = db.NewUpdate().Model(employee).WherePK().Exec(userCtx)
+	require.Error(t, err)
+	_, err = db.NewInsert().Model(&Employee{Name: "bob"}).Exec(userCtx)
+	require.Error(t, err)
+
+	_, err = db.NewUpdate().Model(employee).ExcludeColumn("salary", "ssn").WherePK().Exec(userCtx)
+	require.NoError(t, err)
+
+	employee = &Employee{ID: employee.ID}
+	err = db.NewSelect().Model(employee).WherePK().Scan(bun.WithRole(ctx, "admin"))
+	require.NoError(t, err)
+	require.Equal(t, "alice2", employee.Name)
+	require.Equal(t, "123-45-6789", employee.SSN)
+
+	// FieldRedactionHook sets the policy on the DB it is added to.
+	hookDB := db.WithRedactionPolicy(nil)
+	hookDB.AddQueryHook(bun.NewFieldRedactionHook(bun.AllowRoles("admin")))
+
+	employee = &Employee{ID: employee.ID}
+	err = hookDB.NewSelect().Model(employee).WherePK().Scan(userCtx)
+	require.NoError(t, err)
+	require.Equal(t, bun.Redacted, employee.SSN)
+}
+
+func testLazyColumns(t *testing.T, db *bun.DB) {
+	type User struct {
+		ID   int64 `bun:",pk,autoincrement"`
+		Name string
+		Bio  string `bun:",lazy"`
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*User)(nil))
+
+	users := make([]User, 100)
+	for i := range users {
+		users[i].Name = fmt.Sprintf("user%d", i)
+		users[i].Bio = fmt.Sprintf("bio%d", i)
+	}
+	_, err := db.NewInsert().Model(&users).Exec(ctx)
+	require.NoError(t, err)
+
+	var queries []string
+	db = db.WithNamedArg("lazy", true) // clone the db to not leak the hook
+	db.AddQueryHook(&queryHook{
+		beforeQuery: func(ctx context.Context, event *bun.QueryEvent) context.Context {
+			queries = append(queries, event.Query)
+			return ctx
+		},
+	})
+
+	users = nil
+	err = db.NewSelect().Model(&users).Order("id").Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, users, 100)
+	require.Len(t, queries, 1)
+	require.NotContains(t, queries[0], "bio")
+	for _, user := range users {
+		require.Empty(t, user.Bio)
+	}
+
+	queries = nil
+	err = db.NewSelect().LoadLazy(ctx, &users)
+	require.NoError(t, err)
+	require.Len(t, queries, 1)
+	require.Contains(t, queries[0], "bio")
+	for i, user := range users {
+		require.Equal(t, fmt.Sprintf("user%d", i), user.Name)
+		require.Equal(t, fmt.Sprintf("bio%d", i), user.Bio)
+	}
+
+	user := &User{ID: users[0].ID}
+	err = db.NewSelect().LoadLazy(ctx, user, "bio")
+	require.NoError(t, err)
+	require.Equal(t, "bio0", user.Bio)
+	require.Empty(t, user.Name)
+
+	queries = nil
+	user = new(User)
+	err = db.NewSelect().Model(user).ForceLazy("bio").Where("name = ?", "user1").Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "bio1", user.Bio)
+	require.Len(t, queries, 1)
+
+	// Updating a model selected without the lazy columns keeps them.
+	user = new(User)
+	err = db.NewSelect().Model(user).Where("name = ?", "user2").Scan(ctx)
+	require.NoError(t, err)
+	require.Empty(t, user.Bio)
+
+	user.Name = "user2-updated"
+	_, err = db.NewUpdate().Model(user).WherePK().Exec(ctx)
+	require.NoError(t, err)
+
+	users = []User{*user}
+	_, err = db.NewUpdate().Model(&users).Bulk().Exec(ctx)
+	require.NoError(t, err)
+
+	user2 := new(User)
+	err = db.NewSelect().Model(user2).ForceLazy().Where("id = ?", user.ID).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "user2-updated", user2.Name)
+	require.Equal(t, "bio2", user2.Bio)
+
+	// The lazy columns loaded with LoadLazy are updated.
+	err = db.NewSelect().LoadLazy(ctx, user)
+	require.NoError(t, err)
+	user.Bio = "bio2-updated"
+	_, err = db.NewUpdate().Model(user).WherePK().Exec(ctx)
+	require.NoError(t, err)
+
+	err = db.NewSelect().Model(user2).ForceLazy().Where("id = ?", user.ID).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "bio2-updated", user2.Bio)
+
+	// Bulk updates of models with the lazy columns loaded only in some models are rejected.
+	users = nil
+	err = db.NewSelect().Model(&users).Where("name IN (?)", bun.In([]string{"user3", "user4"})).
+		Order("id").Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	users[0].Bio = "bio3-updated"
+
+	_, err = db.NewUpdate().Model(&users).Bulk().Exec(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `lazy column "bio"`)
+
+	err = db.NewSelect().Model(user2).ForceLazy().Where("id = ?", users[1].ID).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "bio4", user2.Bio)
+}
+
+func testModelCache(t *testing.T, db *bun.DB) {
+	type User struct {
+		ID   int64 `bun:",pk,autoincrement"`
+		Name string
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*User)(nil))
+
+	var queries int
+	db = bun.NewDB(db.DB, db.Dialect(), bun.WithModelCacheSize(2))
+	db.AddQueryHook(&queryHook{
+		beforeQuery: func(ctx context.Context, event *bun.QueryEvent) context.Context {
+			queries++
+			return ctx
+		},
+	})
+
+	users := []User{{Name: "alice"}, {Name: "bob"}, {Name: "carol"}}
+	_, err := db.NewInsert().Model(&users).Exec(ctx)
+	require.NoError(t, err)
+
+	selectUser := func(id int64) *User {
+		user := &User{ID: id}
+		err := db.NewCachedSelect(time.Minute).Model(user).WherePK().Scan(ctx)
+		require.NoError(t, err)
+		return user
+	}
+
+	queries = 0
+	require.Equal(t, "alice", selectUser(users[0].ID).Name)
+	require.Equal(t, 1, queries)
+	require.Equal(t, "alice", selectUser(users[0].ID).Name)
+	require.Equal(t, 1, queries, "the second select must be cached")
+
+	// Updates evict the updated models.
+	_, err = db.NewUpdate().Model(&User{ID: users[0].ID, Name: "alice2"}).WherePK().Exec(ctx)
+	require.NoError(t, err)
+	queries = 0
+	require.Equal(t, "alice2", selectUser(users[0].ID).Name)
+	require.Equal(t, 1, queries)
+
+	// Updates without WherePK evict the table.
+	_, err = db.NewUpdate().Model((*User)(nil)).Set("name = ?", "alice3").Where("name = ?", "alice2").Exec(ctx)
+	require.NoError(t, err)
+	queries = 0
+	require.Equal(t, "alice3", selectUser(users[0].ID).Name)
+	require.Equal(t, 1, queries)
+
+	// The cache is bounded: bob and carol evict alice.
+	selectUser(users[1].ID)
+	selectUser(users[2].ID)
+	queries = 0
+	selectUser(users[2].ID)
+	require.Equal(t, 0, queries)
+	selectUser(users[0].ID)
+	require.Equal(t, 1, queries)
+
+	// Deletes evict the deleted models.
+	_, err = db.NewDelete().Model(&users[0]).WherePK().Exec(ctx)
+	require.NoError(t, err)
+	err = db.NewCachedSelect(time.Minute).Model(&User{ID: users[0].ID}).WherePK().Scan(ctx)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Models changed in transactions are evicted after the commit too.
+	require.Equal(t, "bob", selectUser(users[1].ID).Name)
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().Model(&User{ID: users[1].ID, Name: "bob2"}).WherePK().Exec(ctx)
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, "bob2", selectUser(users[1].ID).Name)
+
+	// Other queries are not cached.
+	queries = 0
+	for i := 0; i < 2; i++ {
+		user := &User{ID: users[1].ID}
+		err = db.NewCachedSelect(time.Minute).Model(user).WherePK().Where("name = ?", "bob2").Scan(ctx)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, queries)
+
+	// The models are cached for each role, so the redacted models are not shared.
+	type Employee struct {
+		ID  int64  `bun:",pk,autoincrement"`
+		SSN string `bun:",sensitive"`
+	}
+
+	mustResetModel(t, ctx, db, (*Employee)(nil))
+	db = db.WithRedactionPolicy(bun.AllowRoles("admin"))
+
+	employee := &Employee{SSN: "123-45-6789"}
+	_, err = db.NewInsert().Model(employee).Exec(bun.WithRole(ctx, "admin"))
+	require.NoError(t, err)
+
+	selectEmployee := func(ctx context.Context) *Employee {
+		selected := &Employee{ID: employee.ID}
+		err := db.NewCachedSelect(time.Minute).Model(selected).WherePK().Scan(ctx)
+		require.NoError(t, err)
+		return selected
+	}
+
+	userCtx, adminCtx := bun.WithRole(ctx, "user"), bun.WithRole(ctx, "admin")
+	require.Equal(t, bun.Redacted, selectEmployee(userCtx).SSN)
+	require.Equal(t, "123-45-6789", selectEmployee(adminCtx).SSN)
+	queries = 0
+	require.Equal(t, bun.Redacted, selectEmployee(userCtx).SSN)
+	require.Equal(t, "123-45-6789", selectEmployee(adminCtx).SSN)
+	require.Equal(t, 0, queries)
+
+	// The models are not cached if the rows can depend on the RLS settings.
+	rls := db.WithNamedArg("rls", true) // clone the db to not leak the hook
+	rls.AddQueryHook(bun.NewRLSHook(func(ctx context.Context, conn bun.IConn) error {
+		return nil
+	}))
+	queries = 0
+	for i := 0; i < 2; i++ {
+		user := &User{ID: users[1].ID}
+		err := rls.NewCachedSelect(time.Minute).Model(user).WherePK().Scan(ctx)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, queries)
+}
+
+func testDeleteChunk(t *testing.T, db *bun.DB) {
+	type Item struct {
+		ID   int64 `bun:",pk,autoincrement"`
+		Name string
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Item)(nil))
+
+	insertItems := func(t *testing.T) []Item {
+		items := make([]Item, 10000)
+		// Insert in batches, because some databases limit the number of rows in VALUES.
+		for i := 0; i < len(items); i += 1000 {
+			batch := items[i : i+1000]
+			_, err := db.NewInsert().Model(&batch).Exec(ctx)
+			require.NoError(t, err)
+		}
+		return items
+	}
+
+	var queries int
+	db = db.WithNamedArg("x", true) // clone the db to not leak the hook
+	db.AddQueryHook(&queryHook{
+		beforeQuery: func(ctx context.Context, event *bun.QueryEvent) context.Context {
+			queries++
+			return ctx
+		},
+	})
+
+	t.Run("model", func(t *testing.T) {
+		items := insertItems(t)
+
+		queries = 0
+		res, err := db.NewDelete().Model(&items).WherePK().Chunk(500).Exec(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 20, queries)
+
+		n, err := res.RowsAffected()
+		require.NoError(t, err)
+		require.Equal(t, int64(10000), n)
+
+		count, err := db.NewSelect().Model((*Item)(nil)).Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+	})
+
+	t.Run("in", func(t *testing.T) {
+		items := insertItems(t)
+		ids := make([]int64, len(items))
+		for i := range items {
+			ids[i] = items[i].ID
+		}
+
+		err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			queries = 0
+			res, err := tx.NewDelete().Model((*Item)(nil)).
+				Where("id IN (?)", bun.In(ids)).
+				Chunk(500).
+				Exec(ctx)
+			require.NoError(t, err)
+			require.Equal(t, 20, queries)
+
+			n, err := res.RowsAffected()
+			require.NoError(t, err)
+			require.Equal(t, int64(10000), n)
+			return nil
+		})
+		require.NoError(t, err)
+
+		count, err := db.NewSelect().Model((*Item)(nil)).Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+	})
+
+	t.Run("no keys", func(t *testing.T) {
+		_, err := db.NewDelete().Model((*Item)(nil)).Where("id > 0").Chunk(500).Exec(ctx)
+		require.EqualError(t, err, "bun: Chunk requires a slice model with WherePK or a bun.In argument")
+	})
+}
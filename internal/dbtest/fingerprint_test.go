@@ -0,0 +1,97 @@
+package dbtest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun"
+)
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		query  string
+		wanted string
+	}{
+		{
+			query:  `SELECT "u"."id", "u"."Name" FROM "users" AS "u" WHERE ("u"."id" = 42) AND name = 'it''s'`,
+			wanted: `select "u"."id", "u"."Name" from "users" as "u" where ("u"."id" = ?) and name = ?`,
+		},
+		{
+			query:  "select `id`  from\n\t`users` where id in (1, 2, 3) limit 10",
+			wanted: "select `id` from `users` where id in (?) limit ?",
+		},
+		{
+			query:  `SELECT * FROM users WHERE id IN ($1, $2) AND age > $3 AND x = ? -- comment`,
+			wanted: `select * from users where id in (?) and age > ? and x = ?`,
+		},
+		{
+			query:  `INSERT INTO "users" ("id", "name") VALUES (1, 'a'), (2, 'b'), (3, NULL) RETURNING "id"`,
+			wanted: `insert into "users" ("id", "name") values (?, ?) returning "id"`,
+		},
+		{
+			query:  `SELECT count(*), COALESCE(x, -1.5e3), E'\n' /* c */ FROM t1 WHERE a - 1 > 0 AND b = TRUE`,
+			wanted: `select count(*), coalesce(x, ?), ? from t1 where a - ? > ? and b = ?`,
+		},
+		{
+			query:  `SELECT [Id] FROM [Users] WHERE x::text = '1' AND y IN (SELECT id FROM t)`,
+			wanted: `select [Id] from [Users] where x :: text = ? and y in (select id from t)`,
+		},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.wanted, bun.Fingerprint(test.query), test.query)
+	}
+
+	require.Equal(t,
+		bun.Fingerprint("SELECT * FROM t WHERE id IN (1)"),
+		bun.Fingerprint("select *\nfrom t where ID in (4, 5, 6)"))
+}
+
+type fingerprintHook struct {
+	fingerprints []string
+}
+
+func (h *fingerprintHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *fingerprintHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	h.fingerprints = append(h.fingerprints, event.Fingerprint())
+}
+
+func TestQueryEventFingerprint(t *testing.T) {
+	testEachDB(t, func(t *testing.T, dbName string, db *bun.DB) {
+		hook := new(fingerprintHook)
+		db.AddQueryHook(hook)
+
+		for _, ids := range [][]int{{1}, {1, 2, 3}} {
+			var n int
+			err := db.NewSelect().
+				ColumnExpr("count(*)").
+				TableExpr("(SELECT 1 AS id) AS t").
+				Where("id IN (?)", bun.In(ids)).
+				Scan(ctx, &n)
+			require.NoError(t, err)
+		}
+
+		require.Len(t, hook.fingerprints, 2)
+		require.Equal(t, hook.fingerprints[0], hook.fingerprints[1])
+		require.Contains(t, hook.fingerprints[0], "in (?)")
+	})
+}
+
+func BenchmarkFingerprint(b *testing.B) {
+	queries := make([]string, 100)
+	for i := range queries {
+		queries[i] = fmt.Sprintf(`SELECT "u"."id", "u"."name" FROM "users" AS "u" `+
+			`WHERE ("u"."id" IN (%d, %d, %d)) AND "u"."name" = 'user %d' `+
+			`ORDER BY "u"."id" LIMIT %d`, i, i+1, i+2, i, i)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		bun.Fingerprint(queries[i%len(queries)])
+	}
+}
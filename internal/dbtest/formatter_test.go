@@ -0,0 +1,95 @@
+package dbtest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/mysqldialect"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/schema"
+)
+
+// quoteColumns is the kind of helper dialect-neutral libraries write.
+func quoteColumns(fmter schema.Formatter, columns ...string) []string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = fmter.QuoteIdent(column)
+	}
+	return quoted
+}
+
+func TestFormatterQuoteIdent(t *testing.T) {
+	pgFmter := schema.NewFormatter(pgdialect.New())
+	mysqlFmter := schema.NewFormatter(mysqldialect.New())
+
+	require.Equal(t, []string{`"id"`, `"user""name"`}, quoteColumns(pgFmter, "id", `user"name`))
+	require.Equal(t, []string{"`id`", "`user\"name`"}, quoteColumns(mysqlFmter, "id", `user"name`))
+}
+
+func TestWithDialectFormatter(t *testing.T) {
+	type Model struct {
+		ID int64
+	}
+
+	db := sqlite(t)
+	t.Cleanup(func() { db.Close() })
+
+	var queries []string
+	hook := &queryHook{
+		beforeQuery: func(ctx context.Context, event *bun.QueryEvent) context.Context {
+			queries = append(queries, event.Query)
+			return ctx
+		},
+	}
+	db.AddQueryHook(hook)
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Model)(nil))
+	queries = nil
+
+	newQuery := func() *bun.SelectQuery {
+		return db.NewSelect().ColumnExpr("?", bun.Ident("id")).TableExpr("?", bun.Ident("models"))
+	}
+
+	_, err := newQuery().Exists(ctx)
+	require.NoError(t, err)
+
+	// SQLite accepts MySQL backticks.
+	ctx = bun.WithDialectFormatter(ctx, schema.NewFormatter(mysqldialect.New()))
+	_, err = newQuery().Exists(ctx)
+	require.NoError(t, err)
+
+	// Named args of the DB are used with the formatter.
+	_, err = db.WithNamedArg("table", bun.Ident("models")).
+		NewSelect().ColumnExpr("?", bun.Ident("id")).TableExpr("?table").Exists(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{
+		`SELECT EXISTS (SELECT "id" FROM "models")`,
+		"SELECT EXISTS (SELECT `id` FROM `models`)",
+		"SELECT EXISTS (SELECT `id` FROM `models`)",
+	}, queries)
+}
+
+func TestCol(t *testing.T) {
+	type Model struct {
+		ID        int64 `bun:",pk"`
+		Email     string
+		CreatedBy string `bun:"author"`
+	}
+
+	require.Equal(t, bun.Ident("email"), bun.Col((*Model)(nil), "Email"))
+	require.Equal(t, bun.Ident("author"), bun.Col(&Model{}, "CreatedBy"))
+	require.Equal(t, bun.Ident("id"), bun.Col([]Model{}, "ID"))
+
+	require.PanicsWithError(t, "bun: Model does not have field Emial", func() {
+		bun.Col((*Model)(nil), "Emial")
+	})
+
+	query := schema.NewFormatter(pgdialect.New()).
+		FormatQuery("SELECT * FROM users WHERE ? = ?", bun.Col((*Model)(nil), "Email"), "alice@example.com")
+	require.Equal(t, `SELECT * FROM users WHERE "email" = 'alice@example.com'`, query)
+}
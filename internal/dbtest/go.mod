@@ -22,13 +22,20 @@ replace github.com/uptrace/bun/dialect/mssqldialect => ../../dialect/mssqldialec
 
 replace github.com/uptrace/bun/extra/bundebug => ../../extra/bundebug
 
+replace github.com/uptrace/bun/extra/bundecimal => ../../extra/bundecimal
+
+replace github.com/uptrace/bun/extra/bunpgx => ../../extra/bunpgx
+
+replace github.com/uptrace/bun/extra/bunpostgis => ../../extra/bunpostgis
+
 require (
 	github.com/bradleyjkemp/cupaloy v2.3.0+incompatible
 	github.com/brianvoe/gofakeit/v6 v6.4.1
 	github.com/denisenkom/go-mssqldb v0.12.2
 	github.com/go-sql-driver/mysql v1.6.0
 	github.com/google/uuid v1.6.0
-	github.com/jackc/pgx/v4 v4.11.0
+	github.com/jackc/pgx/v4 v4.18.3
+	github.com/shopspring/decimal v1.4.0
 	github.com/stretchr/testify v1.8.1
 	github.com/uptrace/bun v1.2.1
 	github.com/uptrace/bun/dbfixture v1.2.1
@@ -39,6 +46,9 @@ require (
 	github.com/uptrace/bun/driver/pgdriver v1.2.1
 	github.com/uptrace/bun/driver/sqliteshim v1.2.1
 	github.com/uptrace/bun/extra/bundebug v1.2.1
+	github.com/uptrace/bun/extra/bundecimal v1.2.1
+	github.com/uptrace/bun/extra/bunpgx v1.2.1
+	github.com/uptrace/bun/extra/bunpostgis v1.2.1
 )
 
 require (
@@ -49,12 +59,12 @@ require (
 	github.com/golang-sql/sqlexp v0.1.0 // indirect
 	github.com/hashicorp/golang-lru/v2 v2.0.7 // indirect
 	github.com/jackc/chunkreader/v2 v2.0.1 // indirect
-	github.com/jackc/pgconn v1.8.1 // indirect
+	github.com/jackc/pgconn v1.14.3 // indirect
 	github.com/jackc/pgio v1.0.0 // indirect
 	github.com/jackc/pgpassfile v1.0.0 // indirect
-	github.com/jackc/pgproto3/v2 v2.0.6 // indirect
-	github.com/jackc/pgservicefile v0.0.0-20200714003250-2b9c44734f2b // indirect
-	github.com/jackc/pgtype v1.7.0 // indirect
+	github.com/jackc/pgproto3/v2 v2.3.3 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20221227161230-091c0ba34f0a // indirect
+	github.com/jackc/pgtype v1.14.0 // indirect
 	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
@@ -84,3 +84,29 @@ func TestListenerChannel(t *testing.T) {
 		return !ok
 	}, 3*time.Second, 100*time.Millisecond)
 }
+
+func TestListenerChannelNotification(t *testing.T) {
+	ctx := context.Background()
+
+	db := pg(t)
+
+	ln := pgdriver.NewListener(db)
+	defer ln.Close()
+
+	ch := ln.Channel()
+	err := ln.Listen(ctx, "test_channel")
+	require.NoError(t, err)
+
+	go func() {
+		_ = pgdriver.Notify(ctx, db, "test_channel", "hello")
+	}()
+
+	select {
+	case n := <-ch:
+		require.Equal(t, "test_channel", n.Channel)
+		require.Equal(t, "hello", n.Payload)
+		require.NotZero(t, n.PID)
+	case <-time.After(time.Second):
+		t.Fatal("notification is not received within 1s")
+	}
+}
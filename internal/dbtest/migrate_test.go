@@ -2,12 +2,20 @@ package dbtest_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/migrate"
+	"github.com/uptrace/bun/migrate/sqlschema"
+	"github.com/uptrace/bun/schema"
 )
 
 const (
@@ -34,6 +42,21 @@ func TestMigrate(t *testing.T) {
 	tests := []Test{
 		{run: testMigrateUpAndDown},
 		{run: testMigrateUpError},
+		{run: testMigrateChecksum},
+		{run: testMigrateDependencies},
+		{run: testMigratePlan},
+		{run: testSchemaDiff},
+		{run: testInspectTable},
+		{run: testIndexTags},
+		{run: testUniqueConstraint},
+		{run: testCheckConstraint},
+		{run: testColumnDefault},
+		{run: testGeneratedColumnDiff},
+		{run: testSequence},
+		{run: testExtensions},
+		{run: testViews},
+		{run: testTablePartitions},
+		{run: testColumnRename},
 	}
 
 	testEachDB(t, func(t *testing.T, dbName string, db *bun.DB) {
@@ -158,3 +181,1220 @@ func testMigrateUpError(t *testing.T, db *bun.DB) {
 	require.Len(t, group.Migrations, 2)
 	require.Equal(t, []string{"down2", "down1"}, history)
 }
+
+func testMigrateChecksum(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{
+		"20060102150405_foo.up.sql":   {Data: []byte("SELECT 1")},
+		"20060102150405_foo.down.sql": {Data: []byte("SELECT 2")},
+	}
+
+	newMigrator := func() *migrate.Migrator {
+		migrations := migrate.NewMigrations()
+		require.NoError(t, migrations.Discover(fsys))
+		return migrate.NewMigrator(db, migrations,
+			migrate.WithTableName(migrationsTable),
+			migrate.WithLocksTableName(migrationLocksTable),
+		)
+	}
+
+	// Migrations table created before checksums were added.
+	type LegacyMigration struct {
+		ID         int64 `bun:",pk,autoincrement"`
+		Name       string
+		GroupID    int64
+		MigratedAt time.Time `bun:",notnull,nullzero,default:current_timestamp"`
+	}
+
+	m := newMigrator()
+	require.NoError(t, m.Reset(ctx))
+	_, err := db.NewDropTable().ModelTableExpr(migrationsTable).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().Model((*LegacyMigration)(nil)).ModelTableExpr(migrationsTable).Exec(ctx)
+	require.NoError(t, err)
+	require.NoError(t, m.Init(ctx))
+
+	_, err = m.Migrate(ctx)
+	require.NoError(t, err)
+
+	applied, err := m.AppliedMigrations(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	require.Len(t, applied[0].Checksum, 64)
+
+	// Editing the down migration does not change the checksum.
+	fsys["20060102150405_foo.down.sql"] = &fstest.MapFile{Data: []byte("SELECT 3")}
+	require.NoError(t, newMigrator().VerifyChecksums(ctx))
+
+	fsys["20060102150405_foo.up.sql"] = &fstest.MapFile{Data: []byte("SELECT 4")}
+	m = newMigrator()
+
+	_, err = m.Migrate(ctx)
+	require.Error(t, err)
+	require.True(t, errors.Is(err, migrate.ErrMigrationChecksumMismatch))
+
+	var mismatch *migrate.ChecksumMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	require.Equal(t, "20060102150405_foo", mismatch.Migration)
+	require.Equal(t, applied[0].Checksum, mismatch.Expected)
+	require.NotEqual(t, mismatch.Expected, mismatch.Actual)
+
+	_, err = m.Migrate(ctx, migrate.WithChecksumSkip())
+	require.NoError(t, err)
+}
+
+func testMigrateDependencies(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+
+	var history []string
+
+	newMigrations := func(names ...string) *migrate.Migrations {
+		migrations := migrate.NewMigrations()
+		for _, name := range names {
+			name := name
+			migrations.Add(migrate.Migration{
+				Name: name,
+				Up: func(ctx context.Context, db *bun.DB) error {
+					history = append(history, "up"+name)
+					return nil
+				},
+				Down: func(ctx context.Context, db *bun.DB) error {
+					history = append(history, "down"+name)
+					return nil
+				},
+			})
+		}
+		return migrations
+	}
+	newMigrator := func(migrations *migrate.Migrations) *migrate.Migrator {
+		return migrate.NewMigrator(db, migrations,
+			migrate.WithTableName(migrationsTable),
+			migrate.WithLocksTableName(migrationLocksTable),
+		)
+	}
+
+	t.Run("diamond", func(t *testing.T) {
+		history = nil
+
+		// 4 depends on 2 and 3, which depend on 1.
+		migrations := newMigrations("1", "2", "3", "4")
+		require.NoError(t, migrations.DependsOn("1", "3", "2"))
+		require.NoError(t, migrations.DependsOn("2", "4"))
+		require.NoError(t, migrations.DependsOn("3", "4"))
+		require.Error(t, migrations.DependsOn("5", "1"))
+
+		m := newMigrator(migrations)
+		require.NoError(t, m.Reset(ctx))
+
+		_, err := m.Migrate(ctx, migrate.WithDependencyCheck())
+		require.NoError(t, err)
+		require.Equal(t, []string{"up4", "up2", "up3", "up1"}, history)
+
+		applied, err := m.AppliedMigrations(ctx)
+		require.NoError(t, err)
+		require.Len(t, applied, 4)
+		for _, migration := range applied {
+			if migration.Name == "1" {
+				require.Equal(t, []string{"3", "2"}, migration.DependsOn)
+			}
+		}
+
+		history = nil
+		_, err = m.Rollback(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"down1", "down3", "down2", "down4"}, history)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		history = nil
+
+		migrations := newMigrations("1", "2", "3")
+		require.NoError(t, migrations.DependsOn("1", "2"))
+		require.NoError(t, migrations.DependsOn("2", "3"))
+		require.NoError(t, migrations.DependsOn("3", "1"))
+
+		m := newMigrator(migrations)
+		require.NoError(t, m.Reset(ctx))
+
+		_, err := m.Migrate(ctx)
+		require.Error(t, err)
+		require.Equal(t, "migrate: migration dependency cycle: 1 -> 2 -> 3 -> 1", err.Error())
+		require.Nil(t, history)
+	})
+
+	t.Run("out-of-order apply", func(t *testing.T) {
+		history = nil
+
+		migrations := newMigrations("1", "2", "3")
+		require.NoError(t, migrations.DependsOn("2", "1"))
+
+		m := newMigrator(migrations)
+		require.NoError(t, m.Reset(ctx))
+		require.NoError(t, m.MarkApplied(ctx, &migrate.Migration{
+			Name:      "2",
+			GroupID:   1,
+			DependsOn: []string{"1"},
+		}))
+
+		_, err := m.Migrate(ctx, migrate.WithDependencyCheck())
+		require.Error(t, err)
+		require.Equal(t, "migrate: migration 2 is applied, but its dependency 1 is not", err.Error())
+		require.Nil(t, history)
+
+		_, err = m.Migrate(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"up1", "up3"}, history)
+	})
+}
+
+func testMigratePlan(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{
+		"20060102150405_foo.tx.up.sql":   {Data: []byte("SELECT 1;\n--bun:split\nSELECT 2;\n")},
+		"20060102150405_foo.tx.down.sql": {Data: []byte("SELECT 3;\n")},
+		"20060102160405_bar.up.sql":      {Data: []byte("SELECT 4\n")},
+		"20060102160405_bar.down.sql":    {Data: []byte("SELECT 5\n")},
+	}
+
+	migrations := migrate.NewMigrations()
+	require.NoError(t, migrations.Discover(fsys))
+	migrations.Add(migrate.Migration{
+		Name:    "20060102170405",
+		Comment: "baz",
+		Up: func(ctx context.Context, db *bun.DB) error {
+			return errors.New("not expected")
+		},
+	})
+
+	m := migrate.NewMigrator(db, migrations,
+		migrate.WithTableName(migrationsTable),
+		migrate.WithLocksTableName(migrationLocksTable),
+	)
+	require.NoError(t, m.Reset(ctx))
+	require.NoError(t, m.MarkApplied(ctx, &migrate.Migration{Name: "20060102150405", GroupID: 1}))
+
+	plan, err := m.Plan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []migrate.PlannedMigration{
+		{Name: "20060102160405_bar", Queries: []string{"SELECT 4"}},
+		{Name: "20060102170405_baz", IsGo: true},
+	}, plan.Migrate)
+	require.Equal(t, []migrate.PlannedMigration{
+		{Name: "20060102150405_foo", Transactional: true, Queries: []string{"SELECT 3"}},
+	}, plan.Rollback)
+
+	require.Equal(t, `-- migrate
+-- 20060102160405_bar (not transactional)
+SELECT 4;
+-- 20060102170405_baz (Go migration, the queries are unknown)
+
+-- rollback
+-- 20060102150405_foo
+BEGIN;
+SELECT 3;
+ROLLBACK;
+`, plan.String())
+
+	applied, err := m.AppliedMigrations(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+}
+
+func testInspectTable(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.PG, dialect.MySQL, dialect.SQLite:
+	default:
+		t.Skip("not supported")
+	}
+
+	type InspectAuthor struct {
+		ID int64 `bun:",pk,autoincrement"`
+	}
+
+	type InspectBook struct {
+		ID       int64  `bun:",pk,autoincrement"`
+		Title    string `bun:",notnull,unique"`
+		Price    int64  `bun:",notnull,default:10"`
+		AuthorID int64
+		Author   *InspectAuthor `bun:"rel:belongs-to"`
+	}
+
+	ctx := context.Background()
+
+	mustResetModel(t, ctx, db, (*InspectAuthor)(nil))
+	mustDropTableOnCleanup(t, ctx, db, (*InspectBook)(nil))
+	_, err := db.NewDropTable().Model((*InspectBook)(nil)).IfExists().Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().Model((*InspectBook)(nil)).WithForeignKeys().Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateIndex().Model((*InspectBook)(nil)).Index("inspect_books_price_idx").Column("price").Exec(ctx)
+	require.NoError(t, err)
+
+	table, err := sqlschema.InspectTable(ctx, db, "", "inspect_books")
+	require.NoError(t, err)
+
+	require.Len(t, table.Columns, 4)
+	require.Equal(t, []string{"id"}, table.PrimaryKey)
+	require.Equal(t, "price", table.Columns[2].Name)
+	require.False(t, table.Columns[2].IsNullable)
+	require.Contains(t, table.Columns[2].Default, "10")
+
+	if db.Dialect().Name() == dialect.MySQL {
+		// MySQL reports the unique constraint as an index and creates an index
+		// for the foreign key.
+		require.Len(t, table.Indexes, 3)
+		require.Empty(t, table.UniqueConstraints)
+		var unique int
+		for _, idx := range table.Indexes {
+			if idx.Unique {
+				unique++
+				require.Equal(t, []string{"title"}, idx.Columns)
+			}
+		}
+		require.Equal(t, 1, unique)
+	} else {
+		require.Len(t, table.Indexes, 1)
+		require.Equal(t, []string{"price"}, table.Indexes[0].Columns)
+		require.Len(t, table.UniqueConstraints, 1)
+		require.Equal(t, []string{"title"}, table.UniqueConstraints[0].Columns)
+	}
+
+	require.Len(t, table.ForeignKeys, 1)
+	require.Equal(t, []string{"author_id"}, table.ForeignKeys[0].Columns)
+	require.Equal(t, []string{"id"}, table.ForeignKeys[0].RefColumns)
+
+	_, err = sqlschema.InspectTable(ctx, db, "", "inspect_missing")
+	require.Error(t, err)
+}
+
+func testSchemaDiff(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.PG, dialect.SQLite:
+	default:
+		t.Skip("not supported")
+	}
+
+	type Author struct {
+		ID int64 `bun:",pk,autoincrement"`
+	}
+
+	type Book struct {
+		ID       int64  `bun:",pk,autoincrement"`
+		Title    string `bun:",notnull,unique"`
+		AuthorID int64
+		Author   *Author `bun:"rel:belongs-to"`
+	}
+
+	ctx := context.Background()
+
+	mustResetModel(t, ctx, db, (*Author)(nil))
+	mustDropTableOnCleanup(t, ctx, db, (*Book)(nil))
+	_, err := db.NewDropTable().Model((*Book)(nil)).IfExists().Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().Model((*Book)(nil)).WithForeignKeys().Exec(ctx)
+	require.NoError(t, err)
+
+	changes, err := sqlschema.Diff(ctx, db, (*Author)(nil), (*Book)(nil))
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	_, err = db.NewAddColumn().Model((*Book)(nil)).ColumnExpr("isbn VARCHAR").Exec(ctx)
+	require.NoError(t, err)
+
+	changes, err = sqlschema.Diff(ctx, db, (*Book)(nil))
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+
+	change, ok := changes[0].(sqlschema.AddColumn)
+	require.True(t, ok, "got %T", changes[0])
+	require.Equal(t, "books", change.Table)
+	require.Equal(t, "isbn", change.Column.Name)
+	require.True(t, change.Column.IsNullable)
+	require.Contains(t, change.String(), "books: added column isbn")
+
+	b, err := json.Marshal(changes)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `[{"type":"add_column","table":"books","column":{"name":"isbn"`)
+
+	// A model that doesn't match the table.
+	type OtherBook struct {
+		bun.BaseModel `bun:"table:books"`
+
+		ID       int64 `bun:",pk,autoincrement"`
+		Title    string
+		AuthorID int64
+		ISBN     string `bun:"isbn,notnull"`
+		Price    int64
+	}
+
+	changes, err = sqlschema.Diff(ctx, db, (*OtherBook)(nil))
+	require.NoError(t, err)
+
+	var types []string
+	for _, change := range changes {
+		b, err := json.Marshal(change)
+		require.NoError(t, err)
+
+		var v struct{ Type string }
+		require.NoError(t, json.Unmarshal(b, &v))
+		types = append(types, v.Type)
+	}
+	require.Equal(t, []string{
+		"alter_column",          // title is nullable
+		"alter_column",          // isbn is not nullable
+		"drop_column",           // price
+		"add_unique_constraint", // unique title
+		"add_constraint",
+	}, types, "%s", changes)
+}
+
+func testIndexTags(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.PG, dialect.SQLite, dialect.MySQL:
+	default:
+		t.Skip("not supported")
+	}
+
+	type Account struct {
+		bun.BaseModel `bun:"table:index_accounts,index:idx_name_email,columns:name email"`
+
+		ID    int64  `bun:",pk,autoincrement"`
+		Email string `bun:"type:varchar(100),index:idx_email,unique"`
+		Name  string `bun:"type:varchar(100)"`
+	}
+
+	ctx := context.Background()
+
+	mustDropTableOnCleanup(t, ctx, db, (*Account)(nil))
+	_, err := db.NewDropTable().Model((*Account)(nil)).IfExists().Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().Model((*Account)(nil)).WithIndexes().Exec(ctx)
+	require.NoError(t, err)
+
+	table, err := sqlschema.InspectTable(ctx, db, "", "index_accounts")
+	require.NoError(t, err)
+
+	indexes := make(map[string]sqlschema.Index)
+	for _, idx := range table.Indexes {
+		indexes[idx.Name] = idx
+	}
+	require.Equal(t, []string{"name", "email"}, indexes["idx_name_email"].Columns)
+	require.False(t, indexes["idx_name_email"].Unique)
+	require.Equal(t, []string{"email"}, indexes["idx_email"].Columns)
+	require.True(t, indexes["idx_email"].Unique)
+
+	changes, err := sqlschema.Diff(ctx, db, (*Account)(nil))
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	_, err = db.NewDropIndex().Model((*Account)(nil)).Index("idx_name_email").Exec(ctx)
+	require.NoError(t, err)
+
+	changes, err = sqlschema.Diff(ctx, db, (*Account)(nil))
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Equal(t, "index_accounts: dropped index idx_name_email (name, email)", changes[0].String())
+}
+
+func testUniqueConstraint(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.PG, dialect.SQLite:
+	default:
+		t.Skip("not supported")
+	}
+
+	type User struct {
+		bun.BaseModel `bun:"table:uq_users"`
+
+		ID    int64  `bun:",pk,autoincrement"`
+		Email string `bun:"type:varchar(100),notnull,unique:uq_email"`
+	}
+
+	type Profile struct {
+		bun.BaseModel `bun:"table:uq_profiles"`
+
+		ID    int64  `bun:",pk,autoincrement"`
+		Email string `bun:"type:varchar(100),notnull"`
+	}
+
+	ctx := context.Background()
+
+	mustDropTableOnCleanup(t, ctx, db, (*User)(nil))
+	mustDropTableOnCleanup(t, ctx, db, (*Profile)(nil))
+	for _, model := range []interface{}{(*Profile)(nil), (*User)(nil)} {
+		_, err := db.NewDropTable().Model(model).IfExists().Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	q := db.NewCreateTable().Model((*User)(nil))
+	require.Contains(t, q.String(), `CONSTRAINT "uq_email" UNIQUE ("email")`)
+	_, err := q.Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = db.NewCreateTable().
+		Model((*Profile)(nil)).
+		ForeignKey(`("email") REFERENCES "uq_users" ("email") ON DELETE CASCADE`).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	table, err := sqlschema.InspectTable(ctx, db, "", "uq_users")
+	require.NoError(t, err)
+	require.Empty(t, table.Indexes)
+	require.Len(t, table.UniqueConstraints, 1)
+	require.Equal(t, []string{"email"}, table.UniqueConstraints[0].Columns)
+	if db.Dialect().Name() == dialect.PG {
+		require.Equal(t, "uq_email", table.UniqueConstraints[0].Name)
+	}
+
+	_, err = db.NewInsert().Model(&User{Email: "hello@example.com"}).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewInsert().Model(&Profile{Email: "hello@example.com"}).Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = db.NewInsert().Model(&User{Email: "hello@example.com"}).Exec(ctx)
+	require.Error(t, err)
+
+	if db.Dialect().Name() == dialect.SQLite {
+		_, err = db.Exec("PRAGMA foreign_keys = ON;")
+		require.NoError(t, err)
+	}
+	_, err = db.NewInsert().Model(&Profile{Email: "missing@example.com"}).Exec(ctx)
+	require.Error(t, err)
+
+	changes, err := sqlschema.Diff(ctx, db, (*User)(nil))
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// The same column with a unique index instead of the unique constraint.
+	type IndexUser struct {
+		bun.BaseModel `bun:"table:uq_users"`
+
+		ID    int64  `bun:",pk,autoincrement"`
+		Email string `bun:"type:varchar(100),notnull,index:uq_users_email_idx,unique"`
+	}
+
+	changes, err = sqlschema.Diff(ctx, db, (*IndexUser)(nil))
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.IsType(t, sqlschema.AddUniqueConstraint{}, changes[0])
+	require.IsType(t, sqlschema.DropIndex{}, changes[1])
+
+	type PlainUser struct {
+		bun.BaseModel `bun:"table:uq_users"`
+
+		ID    int64  `bun:",pk,autoincrement"`
+		Email string `bun:"type:varchar(100),notnull"`
+	}
+
+	changes, err = sqlschema.Diff(ctx, db, (*PlainUser)(nil))
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	require.Contains(t, changes[0].String(), "uq_users: added unique constraint")
+
+	b, err := json.Marshal(changes[0])
+	require.NoError(t, err)
+	require.Contains(t, string(b), `{"type":"add_unique_constraint","table":"uq_users"`)
+
+	type OtherUser struct {
+		bun.BaseModel `bun:"table:uq_profiles"`
+
+		ID    int64  `bun:",pk,autoincrement"`
+		Email string `bun:"type:varchar(100),notnull,unique:uq_profile_email"`
+	}
+
+	changes, err = sqlschema.Diff(ctx, db, (*OtherUser)(nil))
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t,
+		"uq_profiles: dropped unique constraint uq_profile_email (email)", changes[0].String())
+	require.IsType(t, sqlschema.AddConstraint{}, changes[1])
+}
+
+func testColumnDefault(t *testing.T, db *bun.DB) {
+	type Event struct {
+		bun.BaseModel `bun:"table:default_events"`
+
+		ID        int64     `bun:",pk,autoincrement"`
+		Name      string    `bun:",notnull,default:'unnamed'"`
+		Attempts  int64     `bun:",notnull,default:0"`
+		CreatedAt time.Time `bun:",notnull,default:current_timestamp"`
+	}
+
+	ctx := context.Background()
+
+	if db.Dialect().Name() == dialect.MSSQL {
+		_, err := sqlschema.Diff(ctx, db, (*Event)(nil))
+		require.EqualError(t, err, "sqlschema: mssql is not supported")
+		return
+	}
+
+	mustResetModel(t, ctx, db, (*Event)(nil))
+
+	q := db.NewCreateTable().Model((*Event)(nil))
+	require.Contains(t, q.String(), `NOT NULL DEFAULT current_timestamp`)
+
+	_, err := db.NewInsert().Model(&Event{ID: 1}).Exec(ctx)
+	require.NoError(t, err)
+
+	event := new(Event)
+	err = db.NewSelect().Model(event).Limit(1).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "unnamed", event.Name)
+	require.Equal(t, int64(0), event.Attempts)
+	require.WithinDuration(t, time.Now(), event.CreatedAt, time.Minute)
+
+	table, err := sqlschema.InspectTable(ctx, db, "", "default_events")
+	require.NoError(t, err)
+	require.Contains(t, table.Column("name").Default, "'unnamed'")
+
+	changes, err := sqlschema.Diff(ctx, db, (*Event)(nil))
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	type OtherEvent struct {
+		bun.BaseModel `bun:"table:default_events"`
+
+		ID        int64     `bun:",pk,autoincrement"`
+		Name      string    `bun:",notnull,default:'untitled'"`
+		Attempts  int64     `bun:",notnull"`
+		CreatedAt time.Time `bun:",notnull,default:current_timestamp"`
+	}
+
+	changes, err = sqlschema.Diff(ctx, db, (*OtherEvent)(nil))
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	change, ok := changes[0].(sqlschema.AlterColumnDefault)
+	require.True(t, ok, "got %T", changes[0])
+	require.Equal(t, "name", change.Column)
+	require.Equal(t, "'untitled'", change.From)
+	require.Equal(t, "default_events: altered column attempts default from none to 0",
+		changes[1].String())
+
+	b, err := json.Marshal(changes[1])
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"type":"alter_column_default","table":"default_events","column":"attempts","to":"0"}`,
+		string(b))
+
+	query, err := changes[1].(sqlschema.AlterColumnDefault).Query(db).AppendQuery(db.Formatter(), nil)
+	require.NoError(t, err)
+	wantQuery := `ALTER TABLE "default_events" ALTER COLUMN "attempts" DROP DEFAULT`
+	if db.Dialect().Name() == dialect.MySQL {
+		wantQuery = "ALTER TABLE `default_events` ALTER COLUMN `attempts` DROP DEFAULT"
+	}
+	require.Equal(t, wantQuery, string(query))
+
+	if db.Dialect().Name() == dialect.SQLite {
+		// SQLite doesn't support ALTER COLUMN.
+		return
+	}
+
+	for _, change := range changes {
+		_, err := change.(sqlschema.AlterColumnDefault).Query(db).Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	changes, err = sqlschema.Diff(ctx, db, (*OtherEvent)(nil))
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func testGeneratedColumnDiff(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.PG, dialect.MySQL, dialect.SQLite:
+	default:
+		t.Skip("not supported")
+	}
+
+	type Model struct {
+		bun.BaseModel `bun:"table:generated_models"`
+
+		ID         int64 `bun:",pk"`
+		Name       string
+		SearchName string `bun:",generated:'lower(name)'"`
+	}
+
+	ctx := context.Background()
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	changes, err := sqlschema.Diff(ctx, db, (*Model)(nil))
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	type UpperModel struct {
+		bun.BaseModel `bun:"table:generated_models"`
+
+		ID         int64 `bun:",pk"`
+		Name       string
+		SearchName string `bun:",generated:'upper(name)'"`
+	}
+
+	type PlainModel struct {
+		bun.BaseModel `bun:"table:generated_models"`
+
+		ID         int64 `bun:",pk"`
+		Name       string
+		SearchName string
+	}
+
+	for _, model := range []interface{}{(*UpperModel)(nil), (*PlainModel)(nil)} {
+		changes, err = sqlschema.Diff(ctx, db, model)
+		require.NoError(t, err)
+
+		if db.Dialect().Name() == dialect.SQLite {
+			// SQLite doesn't report the expressions.
+			require.Empty(t, changes)
+			continue
+		}
+
+		require.Len(t, changes, 1)
+		change, ok := changes[0].(sqlschema.AlterColumnExpression)
+		require.True(t, ok, "got %T", changes[0])
+		require.Equal(t, "search_name", change.Column)
+		require.Contains(t, strings.ToLower(change.To), "lower")
+		if _, ok := model.(*UpperModel); ok {
+			require.Equal(t, "upper(name)", change.From)
+		} else {
+			require.Empty(t, change.From)
+		}
+	}
+}
+
+func testSequence(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+
+	if !db.HasFeature(feature.Sequence) {
+		_, err := db.NewCreateSequence("test_seq").Exec(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not supported")
+		return
+	}
+
+	_, err := db.NewDropSequence("test_seq").IfExists().Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateSequence("test_seq").
+		Start(10).
+		Increment(5).
+		Min(1).
+		Max(1000).
+		NoCycle().
+		Exec(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := db.NewDropSequence("test_seq").IfExists().Exec(ctx)
+		require.NoError(t, err)
+	})
+
+	_, err = db.NewCreateSequence("test_seq").IfNotExists().Exec(ctx)
+	require.NoError(t, err)
+
+	nextval := func() int64 {
+		var n int64
+		err := db.NewRaw("SELECT nextval(?)", "test_seq").Scan(ctx, &n)
+		require.NoError(t, err)
+		return n
+	}
+	require.Equal(t, int64(10), nextval())
+	require.Equal(t, int64(15), nextval())
+	require.Equal(t, int64(20), nextval())
+
+	_, err = db.NewAlterSequence("test_seq").Restart().Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), nextval())
+
+	_, err = db.NewAlterSequence("test_seq").RestartWith(100).Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), nextval())
+
+	var current int64
+	err = db.NewRaw("SELECT currval(?)", "test_seq").Scan(ctx, &current)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), current)
+
+	_, err = db.NewAlterSequence("test_seq").Exec(ctx)
+	require.Error(t, err)
+
+	seq, err := sqlschema.InspectSequence(ctx, db, "", "test_seq")
+	require.NoError(t, err)
+	require.Equal(t, sqlschema.Sequence{
+		Name:      "test_seq",
+		Start:     10,
+		Increment: 5,
+		Min:       1,
+		Max:       1000,
+		Cache:     1,
+	}, *seq)
+
+	want := sqlschema.Sequence{Name: "test_seq", Start: 10, Increment: 5, Max: 1000}
+	changes, err := sqlschema.DiffSequences(ctx, db, want)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	want.Increment = 2
+	want.Cycle = true
+	changes, err = sqlschema.DiffSequences(ctx, db, want, sqlschema.Sequence{Name: "missing_seq"})
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t,
+		"altered sequence test_seq: increment from 2 to 5, cycle from true to false",
+		changes[0].String())
+	require.Equal(t, "dropped sequence missing_seq", changes[1].String())
+
+	_, err = changes[0].(sqlschema.AlterSequence).Query(db).Exec(ctx)
+	require.NoError(t, err)
+
+	changes, err = sqlschema.DiffSequences(ctx, db, want)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func testExtensions(t *testing.T, db *bun.DB) {
+	ctx := context.Background()
+
+	var uuids []string
+	migrations := migrate.NewMigrations(migrate.WithExtensions("uuid-ossp"))
+	migrations.Add(migrate.Migration{
+		Name: "20060102150405",
+		Up: func(ctx context.Context, db *bun.DB) error {
+			if !db.HasFeature(feature.Extension) {
+				return nil
+			}
+			var uuid string
+			if err := db.NewRaw("SELECT uuid_generate_v4()::text").Scan(ctx, &uuid); err != nil {
+				return err
+			}
+			uuids = append(uuids, uuid)
+			return nil
+		},
+	})
+	require.Equal(t, []string{"uuid-ossp"}, migrations.Extensions())
+
+	if db.HasFeature(feature.Extension) {
+		_, err := db.NewDropExtension("uuid-ossp").IfExists().Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	m := migrate.NewMigrator(db, migrations,
+		migrate.WithTableName(migrationsTable),
+		migrate.WithLocksTableName(migrationLocksTable),
+	)
+	require.NoError(t, m.Reset(ctx))
+
+	_, err := m.Migrate(ctx)
+	require.NoError(t, err)
+
+	if !db.HasFeature(feature.Extension) {
+		_, err := db.NewCreateExtension("uuid-ossp").Exec(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not supported")
+		return
+	}
+
+	require.Len(t, uuids, 1)
+	require.Len(t, uuids[0], 36)
+
+	extensions, err := sqlschema.InspectExtensions(ctx, db)
+	require.NoError(t, err)
+	require.Contains(t, extensionNames(extensions), "uuid-ossp")
+
+	_, err = db.NewCreateExtension("uuid-ossp").IfNotExists().Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = db.NewDropExtension("uuid-ossp").IfExists().Cascade().Exec(ctx)
+	require.NoError(t, err)
+
+	extensions, err = sqlschema.InspectExtensions(ctx, db)
+	require.NoError(t, err)
+	require.NotContains(t, extensionNames(extensions), "uuid-ossp")
+
+	err = db.NewRaw("SELECT uuid_generate_v4()").Scan(ctx, new(string))
+	require.Error(t, err)
+}
+
+func testViews(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.PG, dialect.SQLite:
+	default:
+		t.Skip("not supported")
+	}
+
+	type Order struct {
+		bun.BaseModel `bun:"table:view_orders"`
+
+		ID     int64 `bun:",pk,autoincrement"`
+		Amount int64
+	}
+
+	ctx := context.Background()
+
+	mustResetModel(t, ctx, db, (*Order)(nil))
+	t.Cleanup(func() {
+		_, err := db.NewDropView("big_orders").IfExists().Exec(ctx)
+		require.NoError(t, err)
+	})
+
+	orders := []Order{{Amount: 10}, {Amount: 100}, {Amount: 1000}}
+	_, err := db.NewInsert().Model(&orders).Exec(ctx)
+	require.NoError(t, err)
+
+	sel := db.NewSelect().Model((*Order)(nil)).Where("amount >= ?", 100)
+	_, err = db.NewDropView("big_orders").IfExists().Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateView("big_orders").Query(sel).Exec(ctx)
+	require.NoError(t, err)
+
+	var got []Order
+	err = db.NewSelect().Model(&got).ModelTableExpr("big_orders AS ?TableAlias").Order("id").Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, int64(100), got[0].Amount)
+
+	views, err := sqlschema.InspectViews(ctx, db)
+	require.NoError(t, err)
+	require.Len(t, views, 1)
+	require.Equal(t, "big_orders", views[0].Name)
+	require.False(t, views[0].Materialized)
+	require.Contains(t, views[0].Definition, "amount")
+
+	view := sqlschema.View{Name: "big_orders", Definition: sel.String()}
+	changes, err := sqlschema.DiffViews(ctx, db, view)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	changed := db.NewSelect().Model((*Order)(nil)).Where("amount >= ?", 1000)
+	changes, err = sqlschema.DiffViews(ctx, db,
+		sqlschema.View{Name: "big_orders", Definition: changed.String()},
+		sqlschema.View{Name: "missing_view", Definition: changed.String()},
+	)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, "altered view big_orders", changes[0].String())
+	require.Equal(t, "dropped view missing_view", changes[1].String())
+
+	if db.Dialect().Name() == dialect.SQLite {
+		return
+	}
+
+	_, err = db.NewCreateView("big_orders").Replace().Query(changed).Exec(ctx)
+	require.NoError(t, err)
+	changes, err = sqlschema.DiffViews(ctx, db, sqlschema.View{Name: "big_orders", Definition: changed.String()})
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	_, err = db.NewDropView("order_totals").Materialized().IfExists().Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateView("order_totals").
+		Materialized().
+		WithData(false).
+		Query(db.NewSelect().Model((*Order)(nil)).ColumnExpr("sum(amount) AS total")).
+		Exec(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := db.NewDropView("order_totals").Materialized().IfExists().Exec(ctx)
+		require.NoError(t, err)
+	})
+
+	_, err = db.NewRefreshView("order_totals").Exec(ctx)
+	require.NoError(t, err)
+
+	var total int64
+	err = db.NewSelect().TableExpr("order_totals").ColumnExpr("total").Scan(ctx, &total)
+	require.NoError(t, err)
+	require.Equal(t, int64(1110), total)
+
+	views, err = sqlschema.InspectViews(ctx, db)
+	require.NoError(t, err)
+	require.Len(t, views, 2)
+	require.Equal(t, "order_totals", views[1].Name)
+	require.True(t, views[1].Materialized)
+}
+
+func testTablePartitions(t *testing.T, db *bun.DB) {
+	type Event struct {
+		bun.BaseModel `bun:"table:partitioned_events"`
+
+		ID        int64     `bun:",notnull"`
+		CreatedAt time.Time `bun:",notnull"`
+	}
+
+	ctx := context.Background()
+
+	if !db.HasFeature(feature.TablePartition) {
+		_, err := db.NewCreateTablePartition("partitioned_events", "partitioned_events_default").
+			Default().
+			Exec(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "not supported")
+		return
+	}
+
+	_, err := db.NewDropTable().Model((*Event)(nil)).IfExists().Cascade().Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().Model((*Event)(nil)).PartitionBy("RANGE (created_at)").Exec(ctx)
+	require.NoError(t, err)
+	mustDropTableOnCleanup(t, ctx, db, (*Event)(nil))
+
+	for _, p := range []struct {
+		name     string
+		from, to string
+	}{
+		{"partitioned_events_2024_01", "2024-01-01", "2024-02-01"},
+		{"partitioned_events_2024_02", "2024-02-01", "2024-03-01"},
+	} {
+		_, err := db.NewCreateTablePartition("partitioned_events", p.name).
+			For("FROM (?) TO (?)", p.from, p.to).
+			Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	events := []Event{
+		{ID: 1, CreatedAt: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, CreatedAt: time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC)},
+		{ID: 3, CreatedAt: time.Date(2024, 2, 20, 0, 0, 0, 0, time.UTC)},
+	}
+	_, err = db.NewInsert().Model(&events).Exec(ctx)
+	require.NoError(t, err)
+
+	// There is no partition for March yet.
+	_, err = db.NewInsert().Model(&Event{ID: 4, CreatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}).Exec(ctx)
+	require.Error(t, err)
+
+	countRows := func(table string) int {
+		n, err := db.NewSelect().TableExpr(table).Count(ctx)
+		require.NoError(t, err)
+		return n
+	}
+	require.Equal(t, 1, countRows("partitioned_events_2024_01"))
+	require.Equal(t, 2, countRows("partitioned_events_2024_02"))
+	require.Equal(t, 3, countRows("partitioned_events"))
+
+	table, err := sqlschema.InspectTable(ctx, db, "", "partitioned_events")
+	require.NoError(t, err)
+	require.Equal(t, "RANGE (created_at)", table.PartitionBy)
+
+	partitions, err := sqlschema.InspectPartitions(ctx, db, "partitioned_events")
+	require.NoError(t, err)
+	require.Len(t, partitions, 2)
+	require.Equal(t, "partitioned_events_2024_01", partitions[0].Name)
+	require.Contains(t, partitions[0].Bound, "FOR VALUES FROM ('2024-01-01")
+
+	_, err = db.NewDetachPartition("partitioned_events", "partitioned_events_2024_02").Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, countRows("partitioned_events"))
+	require.Equal(t, 2, countRows("partitioned_events_2024_02"))
+
+	_, err = db.NewAttachPartition("partitioned_events", "partitioned_events_2024_02").Default().Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, countRows("partitioned_events"))
+
+	partitions, err = sqlschema.InspectPartitions(ctx, db, "partitioned_events")
+	require.NoError(t, err)
+	require.Len(t, partitions, 2)
+	require.Equal(t, "DEFAULT", partitions[1].Bound)
+
+	// The default partition accepts the rows that don't fit other partitions.
+	_, err = db.NewInsert().Model(&Event{ID: 4, CreatedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}).Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, countRows("partitioned_events_2024_02"))
+}
+
+func extensionNames(extensions []sqlschema.Extension) []string {
+	names := make([]string, len(extensions))
+	for i, ext := range extensions {
+		names[i] = ext.Name
+	}
+	return names
+}
+
+func testColumnRename(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.PG, dialect.SQLite:
+	default:
+		t.Skip("not supported")
+	}
+
+	type OldModel struct {
+		bun.BaseModel `bun:"table:rename_models"`
+
+		ID   int64 `bun:",pk,autoincrement"`
+		Name string
+	}
+
+	type NewModel struct {
+		bun.BaseModel `bun:"table:rename_models"`
+
+		ID       int64 `bun:",pk,autoincrement"`
+		FullName string
+	}
+
+	ctx := context.Background()
+
+	mustResetModel(t, ctx, db, (*OldModel)(nil))
+	t.Cleanup(func() {
+		_, err := db.NewDropTable().Table("bun_column_renames").IfExists().Exec(ctx)
+		require.NoError(t, err)
+	})
+
+	_, err := db.NewInsert().Model(&OldModel{Name: "before"}).Exec(ctx)
+	require.NoError(t, err)
+
+	r := migrate.NewColumnRename("rename_models", "name", "full_name")
+	r.BatchSize = 1
+	require.NoError(t, r.Expand(ctx, db))
+	require.Error(t, r.Expand(ctx, db))
+
+	// Data is copied and both columns are kept in sync.
+	newModel := new(NewModel)
+	require.NoError(t, db.NewSelect().Model(newModel).Where("id = 1").Scan(ctx))
+	require.Equal(t, "before", newModel.FullName)
+
+	_, err = db.NewInsert().Model(&OldModel{Name: "old"}).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewInsert().Model(&NewModel{FullName: "new"}).Exec(ctx)
+	require.NoError(t, err)
+
+	var names []string
+	err = db.NewSelect().Model((*NewModel)(nil)).Column("full_name").Order("id").Scan(ctx, &names)
+	require.NoError(t, err)
+	require.Equal(t, []string{"before", "old", "new"}, names)
+
+	_, err = db.NewUpdate().Model(&OldModel{ID: 1, Name: "updated"}).WherePK().Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewUpdate().Model(&NewModel{ID: 2, FullName: "renamed"}).WherePK().Exec(ctx)
+	require.NoError(t, err)
+
+	var oldModels []OldModel
+	require.NoError(t, db.NewSelect().Model(&oldModels).Order("id").Scan(ctx))
+	require.Equal(t, []OldModel{
+		{ID: 1, Name: "updated"},
+		{ID: 2, Name: "renamed"},
+		{ID: 3, Name: "new"},
+	}, oldModels)
+
+	err = migrate.CheckRenamedColumns(ctx, db, (*OldModel)(nil))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `uses column "name" renamed to "full_name"`)
+	require.NoError(t, migrate.CheckRenamedColumns(ctx, db, (*NewModel)(nil)))
+
+	renames, err := migrate.RenamedColumns(ctx, db)
+	require.NoError(t, err)
+	require.Equal(t, []migrate.RenamedColumn{{
+		Table:   "rename_models",
+		OldName: "name",
+		NewName: "full_name",
+		Phase:   migrate.RenamePhaseExpanded,
+	}}, renames)
+
+	changes, err := sqlschema.Diff(ctx, db, (*OldModel)(nil))
+	require.NoError(t, err)
+	require.Contains(t, changes, sqlschema.PendingColumnRename{
+		Table:   "rename_models",
+		OldName: "name",
+		NewName: "full_name",
+		Phase:   migrate.RenamePhaseExpanded,
+	})
+
+	require.NoError(t, r.Contract(ctx, db))
+	require.Error(t, r.Contract(ctx, db))
+
+	_, err = db.NewInsert().Model(&OldModel{Name: "old"}).Exec(ctx)
+	require.Error(t, err)
+	_, err = db.NewInsert().Model(&NewModel{FullName: "after"}).Exec(ctx)
+	require.NoError(t, err)
+
+	// The old column is copied back in batches of one row.
+	require.NoError(t, r.UndoContract(ctx, db))
+	oldModels = nil
+	require.NoError(t, db.NewSelect().Model(&oldModels).Order("id").Scan(ctx))
+	require.Equal(t, []OldModel{
+		{ID: 1, Name: "updated"},
+		{ID: 2, Name: "renamed"},
+		{ID: 3, Name: "new"},
+		{ID: 4, Name: "after"},
+	}, oldModels)
+
+	require.NoError(t, r.UndoExpand(ctx, db))
+	_, err = db.NewInsert().Model(&NewModel{FullName: "new"}).Exec(ctx)
+	require.Error(t, err)
+	require.NoError(t, migrate.CheckRenamedColumns(ctx, db, (*OldModel)(nil)))
+}
+
+type CheckAccount struct {
+	bun.BaseModel `bun:"table:check_accounts"`
+
+	ID        int64 `bun:",pk,autoincrement"`
+	Balance   int64 `bun:",notnull"`
+	MaxAmount int64 `bun:",notnull,check:'max_amount > 0'"`
+}
+
+func (*CheckAccount) TableChecks() []schema.CheckDef {
+	return []schema.CheckDef{
+		bun.Check("check_positive_balance", "balance >= 0"),
+	}
+}
+
+func testCheckConstraint(t *testing.T, db *bun.DB) {
+	switch db.Dialect().Name() {
+	case dialect.PG, dialect.SQLite:
+	default:
+		t.Skip("not supported")
+	}
+
+	ctx := context.Background()
+
+	mustDropTableOnCleanup(t, ctx, db, (*CheckAccount)(nil))
+	_, err := db.NewDropTable().Model((*CheckAccount)(nil)).IfExists().Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewCreateTable().Model((*CheckAccount)(nil)).Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = db.NewInsert().Model(&CheckAccount{Balance: 10, MaxAmount: 100}).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewInsert().Model(&CheckAccount{Balance: -10, MaxAmount: 100}).Exec(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "check_positive_balance")
+	_, err = db.NewInsert().Model(&CheckAccount{Balance: 10, MaxAmount: 0}).Exec(ctx)
+	require.Error(t, err)
+
+	if db.Dialect().Name() != dialect.PG {
+		return
+	}
+
+	table, err := sqlschema.InspectTable(ctx, db, "", "check_accounts")
+	require.NoError(t, err)
+	require.Len(t, table.Checks, 2)
+
+	changes, err := sqlschema.Diff(ctx, db, (*CheckAccount)(nil))
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	_, err = db.ExecContext(ctx, "ALTER TABLE check_accounts DROP CONSTRAINT check_positive_balance")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, "ALTER TABLE check_accounts ADD CONSTRAINT check_small_balance CHECK (balance < 1000)")
+	require.NoError(t, err)
+
+	changes, err = sqlschema.Diff(ctx, db, (*CheckAccount)(nil))
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, "check_accounts: dropped check check_positive_balance (balance >= 0)", changes[0].String())
+	require.Equal(t, "check_accounts: added check check_small_balance (balance < 1000)", changes[1].String())
+
+	for _, change := range changes {
+		var q *bun.RawQuery
+		switch change := change.(type) {
+		case sqlschema.DropCheck:
+			q = change.Query(db)
+		case sqlschema.AddCheck:
+			q = change.Query(db)
+		}
+		_, err := q.Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	changes, err = sqlschema.Diff(ctx, db, (*CheckAccount)(nil))
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
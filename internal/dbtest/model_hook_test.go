@@ -2,6 +2,7 @@ package dbtest_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -216,3 +217,123 @@ func assertQueryModel(query interface{ GetModel() bun.Model }) {
 		panic(fmt.Errorf("unexpected: %T", value))
 	}
 }
+
+type tenantKey struct{}
+
+type TenantHookModel struct {
+	ID       int64 `bun:",pk,autoincrement"`
+	TenantID int64
+}
+
+var _ bun.BeforeSelectHook = (*TenantHookModel)(nil)
+
+func (m *TenantHookModel) BeforeSelect(ctx context.Context, query *bun.SelectQuery) error {
+	if tenantID, ok := ctx.Value(tenantKey{}).(int64); ok {
+		query.Where("tenant_id = ?", tenantID)
+		return nil
+	}
+	if !query.HasWhere() {
+		return errors.New("tenant_hook_models must be filtered by tenant")
+	}
+	return nil
+}
+
+func TestModelHookBeforeSelectFilter(t *testing.T) {
+	testEachDB(t, testModelHookBeforeSelectFilter)
+}
+
+func testModelHookBeforeSelectFilter(t *testing.T, dbName string, db *bun.DB) {
+	mustResetModel(t, ctx, db, (*TenantHookModel)(nil))
+
+	_, err := db.NewInsert().Model(&[]TenantHookModel{
+		{TenantID: 1}, {TenantID: 1}, {TenantID: 2},
+	}).Exec(ctx)
+	require.NoError(t, err)
+
+	var models []TenantHookModel
+	err = db.NewSelect().Model(&models).Scan(ctx)
+	require.EqualError(t, err, "tenant_hook_models must be filtered by tenant")
+
+	err = db.NewSelect().Model(&models).Where("tenant_id = ?", 2).Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, models, 1)
+
+	model := &TenantHookModel{ID: 3}
+	err = db.NewSelect().Model(model).WherePK().Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), model.TenantID)
+
+	tenantCtx := context.WithValue(ctx, tenantKey{}, int64(1))
+	err = db.NewSelect().Model(&models).Scan(tenantCtx)
+	require.NoError(t, err)
+	require.Len(t, models, 2)
+	for _, m := range models {
+		require.Equal(t, int64(1), m.TenantID)
+	}
+}
+
+type SeedHookModel struct {
+	ID   int64 `bun:",pk,autoincrement"`
+	Name string
+}
+
+var (
+	_ bun.BeforeCreateTableHook = (*SeedHookModel)(nil)
+	_ bun.AfterCreateTableHook  = (*SeedHookModel)(nil)
+	_ bun.BeforeDropTableHook   = (*SeedHookModel)(nil)
+	_ bun.AfterDropTableHook    = (*SeedHookModel)(nil)
+)
+
+func (*SeedHookModel) BeforeCreateTable(ctx context.Context, query *bun.CreateTableQuery) error {
+	events.Add("BeforeCreateTable")
+	return nil
+}
+
+func (*SeedHookModel) AfterCreateTable(ctx context.Context, query *bun.CreateTableQuery) error {
+	events.Add("AfterCreateTable")
+	_, err := query.NewInsert().
+		Model(&[]SeedHookModel{{Name: "admin"}, {Name: "guest"}}).
+		Exec(ctx)
+	return err
+}
+
+func (*SeedHookModel) BeforeDropTable(ctx context.Context, query *bun.DropTableQuery) error {
+	events.Add("BeforeDropTable")
+	return nil
+}
+
+func (*SeedHookModel) AfterDropTable(ctx context.Context, query *bun.DropTableQuery) error {
+	events.Add("AfterDropTable")
+	return nil
+}
+
+func TestModelHookCreateTable(t *testing.T) {
+	testEachDB(t, testModelHookCreateTable)
+}
+
+func testModelHookCreateTable(t *testing.T, dbName string, db *bun.DB) {
+	_, err := db.NewDropTable().Model((*SeedHookModel)(nil)).IfExists().Exec(ctx)
+	require.NoError(t, err)
+	events.Flush()
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewCreateTable().Model((*SeedHookModel)(nil)).Exec(ctx)
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"BeforeCreateTable", "AfterCreateTable"}, events.Flush())
+
+	var models []SeedHookModel
+	err = db.NewSelect().Model(&models).Order("id").Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, models, 2)
+	require.Equal(t, "admin", models[0].Name)
+	require.Equal(t, "guest", models[1].Name)
+
+	_, err = db.NewDropTable().Model((*SeedHookModel)(nil)).Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"BeforeDropTable", "AfterDropTable"}, events.Flush())
+
+	_, err = db.NewCreateTable().Table("seed_hook_models").Exec(ctx)
+	require.Error(t, err)
+}
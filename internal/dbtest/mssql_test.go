@@ -2,6 +2,7 @@ package dbtest_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -50,3 +51,69 @@ func TestMssqlMerge(t *testing.T) {
 	require.Equal(t, "INSERT", changes[1])
 
 }
+
+func TestMssqlOutput(t *testing.T) {
+	db := mssql2019(t)
+	t.Cleanup(func() { db.Close() })
+
+	type Model struct {
+		ID        int64 `bun:",pk,autoincrement"`
+		Name      string
+		CreatedAt time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+	}
+
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	model := &Model{Name: "hello"}
+	err := db.NewInsert().
+		Model(model).
+		Output("INSERTED.id, INSERTED.created_at").
+		Scan(ctx)
+	require.NoError(t, err)
+	require.NotZero(t, model.ID)
+	require.False(t, model.CreatedAt.IsZero())
+
+	var names struct {
+		OldName string
+		NewName string
+	}
+	err = db.NewUpdate().
+		Model(&Model{ID: model.ID, Name: "world"}).
+		Column("name").
+		WherePK().
+		Output("DELETED.name AS old_name, INSERTED.name AS new_name").
+		Scan(ctx, &names.OldName, &names.NewName)
+	require.NoError(t, err)
+	require.Equal(t, "hello", names.OldName)
+	require.Equal(t, "world", names.NewName)
+}
+
+func TestMssqlPagination(t *testing.T) {
+	db := mssql2019(t)
+	t.Cleanup(func() { db.Close() })
+
+	type Model struct {
+		ID int64 `bun:",pk"`
+	}
+
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	models := make([]Model, 10)
+	for i := range models {
+		models[i].ID = int64(i + 1)
+	}
+	_, err := db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+
+	var ids []int64
+	err = db.NewSelect().Model((*Model)(nil)).Column("id").Order("id").Limit(3).Offset(4).Scan(ctx, &ids)
+	require.NoError(t, err)
+	require.Equal(t, []int64{5, 6, 7}, ids)
+
+	ids = nil
+	count, err := db.NewSelect().Model((*Model)(nil)).Column("id").Order("id DESC").Limit(2).
+		ScanAndCount(ctx, &ids)
+	require.NoError(t, err)
+	require.Equal(t, 10, count)
+	require.Equal(t, []int64{10, 9}, ids)
+}
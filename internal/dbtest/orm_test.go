@@ -2,6 +2,7 @@ package dbtest_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -30,8 +31,10 @@ func TestORM(t *testing.T) {
 		{testRelationColumn},
 		{testRelationExcludeAll},
 		{testM2MRelationExcludeColumn},
+		{testM2MRelationPivot},
 		{testRelationBelongsToSelf},
 		{testCompositeHasMany},
+		{testRelationError},
 	}
 
 	testEachDB(t, func(t *testing.T, dbName string, db *bun.DB) {
@@ -429,6 +432,48 @@ func testM2MRelationExcludeColumn(t *testing.T, db *bun.DB) {
 	require.NoError(t, err)
 }
 
+func testM2MRelationPivot(t *testing.T, db *bun.DB) {
+	db.RegisterModel((*UserRole)(nil))
+	mustResetModel(t, ctx, db, (*User)(nil), (*Role)(nil), (*UserRole)(nil))
+
+	_, err := db.NewInsert().Model(&[]User{{ID: 1}, {ID: 2}}).Exec(ctx)
+	require.NoError(t, err)
+	_, err = db.NewInsert().Model(&[]Role{{ID: 1, Name: "admin"}, {ID: 2, Name: "editor"}}).Exec(ctx)
+	require.NoError(t, err)
+
+	userRoles := []UserRole{
+		{UserID: 1, RoleID: 1, GrantedAt: time.Unix(1, 0), GrantedBy: 100},
+		{UserID: 1, RoleID: 2, GrantedAt: time.Unix(2, 0), GrantedBy: 200},
+		{UserID: 2, RoleID: 2, GrantedAt: time.Unix(3, 0), GrantedBy: 300},
+	}
+	_, err = db.NewInsert().Model(&userRoles).Exec(ctx)
+	require.NoError(t, err)
+
+	var users []User
+	err = db.NewSelect().
+		Model(&users).
+		Relation("Roles", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Order("role.id")
+		}).
+		Order("user.id").
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+
+	require.Equal(t, []Role{{ID: 1, Name: "admin"}, {ID: 2, Name: "editor"}}, users[0].Roles)
+	require.Len(t, users[0].UserRoles, 2)
+	for i, userRole := range users[0].UserRoles {
+		require.Equal(t, userRoles[i].UserID, userRole.UserID)
+		require.Equal(t, userRoles[i].RoleID, userRole.RoleID)
+		require.Equal(t, userRoles[i].GrantedBy, userRole.GrantedBy)
+		require.True(t, userRoles[i].GrantedAt.Equal(userRole.GrantedAt))
+	}
+
+	require.Equal(t, []Role{{ID: 2, Name: "editor"}}, users[1].Roles)
+	require.Len(t, users[1].UserRoles, 1)
+	require.Equal(t, int64(300), users[1].UserRoles[0].GrantedBy)
+}
+
 func testCompositeHasMany(t *testing.T, db *bun.DB) {
 	department := new(Department)
 	err := db.NewSelect().
@@ -452,6 +497,26 @@ type Genre struct {
 	Subgenres []Genre `bun:"rel:has-many,join:id=parent_id"`
 }
 
+func testRelationError(t *testing.T, db *bun.DB) {
+	book := new(Book)
+	err := db.NewSelect().
+		Model(book).
+		Relation("Comments", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("no_such_column = 1")
+		}).
+		OrderExpr("book.id ASC").
+		Limit(1).
+		Scan(ctx)
+	require.Error(t, err)
+
+	var relErr *bun.RelationError
+	require.True(t, errors.As(err, &relErr))
+	require.Equal(t, "Comments", relErr.Relation)
+	require.Equal(t, "Book", relErr.Model)
+	require.NotNil(t, errors.Unwrap(err))
+	require.True(t, strings.HasPrefix(err.Error(), "loading relation Comments on Book: "))
+}
+
 func (g Genre) String() string {
 	return fmt.Sprintf("Genre<Id=%d Name=%q>", g.ID, g.Name)
 }
@@ -480,6 +545,26 @@ func (*Author) BeforeAppendModel(ctx context.Context, query bun.Query) error {
 	return nil
 }
 
+type User struct {
+	ID        int64      `bun:",pk"`
+	Roles     []Role     `bun:"m2m:user_roles,join:User=Role,pivot:UserRoles"` // m2m relation with pivot
+	UserRoles []UserRole `bun:"-"`                                             // is populated by the Roles relation
+}
+
+type Role struct {
+	ID   int64 `bun:",pk"`
+	Name string
+}
+
+type UserRole struct {
+	UserID    int64     `bun:",pk"`
+	User      *User     `bun:"rel:belongs-to,join:user_id=id"`
+	RoleID    int64     `bun:",pk"`
+	Role      *Role     `bun:"rel:belongs-to,join:role_id=id"`
+	GrantedAt time.Time `bun:",notnull"`
+	GrantedBy int64
+}
+
 type BookGenre struct {
 	bun.BaseModel `bun:"alias:bg"` // custom table alias
 
@@ -2,12 +2,20 @@ package dbtest_test
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
 	"github.com/uptrace/bun/schema"
 )
 
@@ -108,3 +116,596 @@ func (h *queryHook) require(t *testing.T) {
 	require.WithinDuration(t, h.startTime, time.Now(), time.Second)
 	require.WithinDuration(t, h.endTime, time.Now(), time.Second)
 }
+
+func TestRLSHook(t *testing.T) {
+	testEachDB(t, testRLSHook)
+}
+
+func testRLSHook(t *testing.T, dbName string, db *bun.DB) {
+	var conns []bun.IConn
+	var locals []bool
+	var setupErr error
+	db.AddQueryHook(bun.NewRLSHook(func(ctx context.Context, conn bun.IConn) error {
+		conns = append(conns, conn)
+		locals = append(locals, bun.RLSLocal(ctx))
+		return setupErr
+	}))
+
+	// Queries outside transactions are executed on a connection with the setup.
+	_, err := db.NewSelect().ColumnExpr("1").Exec(ctx)
+	require.NoError(t, err)
+	require.Len(t, conns, 1)
+	require.IsType(t, (*sql.Conn)(nil), conns[0])
+
+	for i := 0; i < 2; i++ {
+		err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			for j := 0; j < 3; j++ {
+				if _, err := tx.NewSelect().ColumnExpr("1").Exec(ctx); err != nil {
+					return err
+				}
+			}
+			_, err := tx.ExecContext(ctx, "SELECT 1")
+			return err
+		})
+		require.NoError(t, err)
+	}
+	require.Len(t, conns, 3)
+	require.IsType(t, (*sql.Tx)(nil), conns[1])
+	require.IsType(t, (*sql.Tx)(nil), conns[2])
+	require.Equal(t, []bool{false, true, true}, locals)
+
+	rows, err := db.NewSelect().ColumnExpr("1").Rows(ctx)
+	require.NoError(t, err)
+	require.True(t, rows.Next())
+	require.NoError(t, rows.Close())
+	require.Len(t, conns, 4)
+	require.IsType(t, (*sql.Conn)(nil), conns[3])
+
+	setupErr = errors.New("setup failed")
+	_, err = db.NewSelect().ColumnExpr("1").Exec(ctx)
+	require.ErrorIs(t, err, setupErr)
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewSelect().ColumnExpr("1").Exec(ctx)
+		return err
+	})
+	require.ErrorIs(t, err, setupErr)
+}
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string { return "sqlstate " + string(e) }
+
+func (e sqlStateError) Field(k byte) string {
+	if k == 'C' {
+		return string(e)
+	}
+	return ""
+}
+
+func TestRetryHook(t *testing.T) {
+	testEachDB(t, testRetryHook)
+}
+
+func testRetryHook(t *testing.T, dbName string, db *bun.DB) {
+	var attempts, failures, hookCalls int
+	hook := &queryHook{}
+	db.AddQueryHook(hook)
+	db.AddQueryHook(bun.NewRetryHook(3, nil, bun.WithRetryBackoff(time.Millisecond, 5*time.Millisecond)))
+	db.Use(func(next bun.QueryFunc) bun.QueryFunc {
+		return func(ctx context.Context, conn bun.IConn, query string) (sql.Result, error) {
+			attempts++
+			if attempts <= failures {
+				return nil, fmt.Errorf("exec: %w", sqlStateError("40P01"))
+			}
+			return next(ctx, conn, query)
+		}
+	})
+	reset := func(n int) {
+		attempts, failures, hookCalls = 0, n, 0
+		hook.reset()
+		hook.beforeQuery = func(ctx context.Context, event *bun.QueryEvent) context.Context {
+			hookCalls++
+			return ctx
+		}
+	}
+
+	reset(2)
+	_, err := db.ExecContext(ctx, "SELECT 1")
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts)
+	require.Equal(t, 1, hookCalls)
+	hook.require(t)
+
+	reset(2)
+	var nums []int
+	err = db.NewSelect().ColumnExpr("1").Scan(ctx, &nums)
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, nums)
+	require.Equal(t, 3, attempts)
+
+	reset(3)
+	_, err = db.NewSelect().ColumnExpr("1").Exec(ctx)
+	require.ErrorIs(t, err, sqlStateError("40P01"))
+	require.Equal(t, 3, attempts)
+
+	// Queries that return rows are retried too.
+	reset(2)
+	rows, err := db.QueryContext(ctx, "SELECT 1")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	require.Equal(t, 3, attempts)
+
+	reset(2)
+	var n int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT 1").Scan(&n))
+	require.Equal(t, 1, n)
+	require.Equal(t, 3, attempts)
+
+	reset(2)
+	rows, err = db.NewSelect().ColumnExpr("1").Rows(ctx)
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+	require.Equal(t, 3, attempts)
+	require.Equal(t, 1, hookCalls)
+
+	reset(1)
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		attempts, failures = 0, 1
+		_, err := tx.NewSelect().ColumnExpr("1").Exec(ctx)
+		return err
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, attempts)
+}
+
+func TestIsTransientError(t *testing.T) {
+	require.True(t, bun.IsTransientError(sqlStateError("40P01")))
+	require.True(t, bun.IsTransientError(fmt.Errorf("wrapped: %w", sqlStateError("40001"))))
+	require.True(t, bun.IsTransientError(errors.New("Error 1213 (40001): Deadlock found when trying to get lock")))
+	require.False(t, bun.IsTransientError(sqlStateError("23505")))
+	require.False(t, bun.IsTransientError(sql.ErrNoRows))
+}
+
+func TestCircuitBreakerHook(t *testing.T) {
+	testEachDB(t, testCircuitBreakerHook)
+}
+
+func testCircuitBreakerHook(t *testing.T, dbName string, db *bun.DB) {
+	now := time.Now()
+	hook := bun.NewCircuitBreakerHook(0.5, 10*time.Second, 30*time.Second,
+		bun.WithCircuitBreakerMinRequests(4),
+		bun.WithCircuitBreakerClock(func() time.Time { return now }))
+	db.AddQueryHook(hook)
+
+	var attempts int
+	var queryErr error
+	db.Use(func(next bun.QueryFunc) bun.QueryFunc {
+		return func(ctx context.Context, conn bun.IConn, query string) (sql.Result, error) {
+			attempts++
+			if queryErr != nil {
+				return nil, queryErr
+			}
+			return next(ctx, conn, query)
+		}
+	})
+	exec := func() error {
+		_, err := db.NewSelect().ColumnExpr("1").Exec(ctx)
+		return err
+	}
+
+	// CLOSED: not enough queries and then a low error rate.
+	queryErr = driver.ErrBadConn
+	require.Error(t, exec())
+	queryErr = nil
+	for i := 0; i < 3; i++ {
+		require.NoError(t, exec())
+	}
+	require.Equal(t, bun.CircuitClosed, hook.State())
+
+	// Queries outside of the window are forgotten.
+	now = now.Add(11 * time.Second)
+	queryErr = driver.ErrBadConn
+	for i := 0; i < 3; i++ {
+		require.Error(t, exec())
+		require.Equal(t, bun.CircuitClosed, hook.State())
+	}
+
+	// Query errors are not failures.
+	queryErr = errors.New("syntax error")
+	for i := 0; i < 4; i++ {
+		require.Error(t, exec())
+	}
+	require.Equal(t, bun.CircuitClosed, hook.State())
+	now = now.Add(11 * time.Second)
+	queryErr = driver.ErrBadConn
+	for i := 0; i < 3; i++ {
+		require.Error(t, exec())
+	}
+	require.Equal(t, bun.CircuitClosed, hook.State())
+
+	// CLOSED -> OPEN after the threshold.
+	queryErr = nil
+	require.NoError(t, exec())
+	queryErr = driver.ErrBadConn
+	require.Error(t, exec())
+	require.Equal(t, bun.CircuitOpen, hook.State())
+
+	attempts = 0
+	require.ErrorIs(t, exec(), bun.ErrCircuitOpen)
+	require.Equal(t, 0, attempts)
+
+	// OPEN -> HALF-OPEN after resetTimeout, a failed probe opens the circuit again.
+	now = now.Add(30 * time.Second)
+	require.Equal(t, bun.CircuitHalfOpen, hook.State())
+	require.Error(t, exec())
+	require.Equal(t, 1, attempts)
+	require.Equal(t, bun.CircuitOpen, hook.State())
+	require.ErrorIs(t, exec(), bun.ErrCircuitOpen)
+
+	// Queries that return rows are rejected too.
+	_, err := db.QueryContext(ctx, "SELECT 1")
+	require.ErrorIs(t, err, bun.ErrCircuitOpen)
+	_, err = db.NewSelect().ColumnExpr("1").Rows(ctx)
+	require.ErrorIs(t, err, bun.ErrCircuitOpen)
+
+	// A canceled probe is neither a success nor a failure.
+	now = now.Add(30 * time.Second)
+	queryErr = context.Canceled
+	require.ErrorIs(t, exec(), context.Canceled)
+	require.Equal(t, bun.CircuitHalfOpen, hook.State())
+
+	// HALF-OPEN -> CLOSED on success.
+	now = now.Add(30 * time.Second)
+	queryErr = nil
+	require.NoError(t, exec())
+	require.Equal(t, bun.CircuitClosed, hook.State())
+	require.NoError(t, exec())
+}
+
+func TestCircuitBreakerHookConcurrency(t *testing.T) {
+	hook := bun.NewCircuitBreakerHook(0.5, time.Second, time.Millisecond)
+	fn := hook.Middleware(func(ctx context.Context, conn bun.IConn, query string) (sql.Result, error) {
+		if len(query)%2 == 0 {
+			return nil, driver.ErrBadConn
+		}
+		return nil, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				_, _ = fn(ctx, nil, strings.Repeat("x", i+j))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+type queryModifierHook struct {
+	modify  func(event *bun.QueryEvent)
+	queries []string
+}
+
+func (h *queryModifierHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	h.modify(event)
+	return ctx
+}
+
+func (h *queryModifierHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	h.queries = append(h.queries, event.Query)
+}
+
+func TestQueryHookModifyQuery(t *testing.T) {
+	testEachDB(t, testQueryHookModifyQuery)
+}
+
+func testQueryHookModifyQuery(t *testing.T, dbName string, db *bun.DB) {
+	type Item struct {
+		ID     int64 `bun:",pk,autoincrement"`
+		Tenant string
+	}
+
+	mustResetModel(t, ctx, db, (*Item)(nil))
+	_, err := db.NewInsert().Model(&[]Item{{Tenant: "a"}, {Tenant: "b"}}).Exec(ctx)
+	require.NoError(t, err)
+
+	hook := &queryModifierHook{modify: func(event *bun.QueryEvent) {}}
+	db.AddQueryHook(hook)
+
+	// SetQuery changes the executed query.
+	hook.modify = func(event *bun.QueryEvent) {
+		event.SetQuery(strings.Replace(event.Query, "'a'", "'b'", 1))
+	}
+	var item Item
+	err = db.NewSelect().Model(&item).Where("tenant = ?", "a").Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "b", item.Tenant)
+
+	hook.modify = func(event *bun.QueryEvent) {
+		event.SetQuery("SELECT count(*) FROM items WHERE 1 = 0")
+	}
+	count, err := db.NewSelect().Model((*Item)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	// Changing Query without QueryModified has no effect.
+	hook.modify = func(event *bun.QueryEvent) {
+		event.Query = "SELECT count(*) FROM items WHERE 1 = 0"
+	}
+	count, err = db.NewSelect().Model((*Item)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	// A comment can't escape its delimiters and alter the WHERE clause.
+	hook.queries = nil
+	hook.modify = func(event *bun.QueryEvent) {
+		event.AddComment("request_id=1 */ OR 1 = 1 /* nested")
+	}
+	var items []Item
+	err = db.NewSelect().Model(&items).Where("tenant = ?", "a").Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	require.Equal(t, "a", items[0].Tenant)
+	require.Len(t, hook.queries, 1)
+	require.True(t, strings.HasPrefix(hook.queries[0], "/* request_id=1 * / OR 1 = 1 / * nested */ SELECT"))
+
+	_, err = db.ExecContext(ctx, "UPDATE items SET tenant = ? WHERE tenant = ?", "c", "a")
+	require.NoError(t, err)
+	require.NoError(t, db.NewSelect().Model(&items).Order("id").Scan(ctx))
+	require.Equal(t, "c", items[0].Tenant)
+	require.Equal(t, "b", items[1].Tenant)
+}
+
+func TestQueryHookColumns(t *testing.T) {
+	testEachDB(t, testQueryHookColumns)
+}
+
+func testQueryHookColumns(t *testing.T, dbName string, db *bun.DB) {
+	type Model struct {
+		ID   int64 `bun:",pk,autoincrement"`
+		Name string
+	}
+
+	mustResetModel(t, ctx, db, (*Model)(nil))
+
+	var events []bun.QueryEvent
+	hook := &queryHook{
+		beforeQuery: func(ctx context.Context, event *bun.QueryEvent) context.Context { return ctx },
+		afterQuery: func(ctx context.Context, event *bun.QueryEvent) {
+			events = append(events, *event)
+		},
+	}
+	db.AddQueryHook(hook)
+
+	_, err := db.NewInsert().Model(&[]Model{{Name: "a"}, {Name: "b"}}).Exec(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, int64(2), events[0].RowsAffected)
+
+	events = nil
+	_, err = db.NewUpdate().Model((*Model)(nil)).Set("name = ?", "c").Where("name = ?", "b").Exec(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Nil(t, events[0].Columns)
+	require.Equal(t, int64(1), events[0].RowsAffected)
+
+	events = nil
+	var models []Model
+	err = db.NewSelect().Model(&models).Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, []string{"id", "name"}, events[0].Columns)
+	require.Equal(t, int64(2), events[0].RowsAffected)
+
+	events = nil
+	var ids []int64
+	err = db.NewSelect().Model((*Model)(nil)).Column("id").Scan(ctx, &ids)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, []string{"id"}, events[0].Columns)
+
+	// Hooks are called with the columns when scanning fails.
+	events = nil
+	err = db.NewSelect().Model(&models).ColumnExpr("1 AS unknown_column").Scan(ctx)
+	require.Error(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, []string{"unknown_column"}, events[0].Columns)
+	require.Equal(t, err, events[0].Err)
+}
+
+// flakyConnector opens connections that fail the first queries with the error.
+type flakyConnector struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	queries  int
+}
+
+func (c *flakyConnector) Connect(context.Context) (driver.Conn, error) { return &flakyConn{c}, nil }
+
+func (c *flakyConnector) Driver() driver.Driver { return nil }
+
+func (c *flakyConnector) next() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.queries++
+	if c.failures > 0 {
+		c.failures--
+		return c.err
+	}
+	return nil
+}
+
+type flakyConn struct {
+	c *flakyConnector
+}
+
+func (cn *flakyConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (cn *flakyConn) Close() error                        { return nil }
+func (cn *flakyConn) Begin() (driver.Tx, error)           { return flakyTx{}, nil }
+
+func (cn *flakyConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	if err := cn.c.next(); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (cn *flakyConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	if err := cn.c.next(); err != nil {
+		return nil, err
+	}
+	return &flakyRows{}, nil
+}
+
+type flakyTx struct{}
+
+func (flakyTx) Commit() error   { return nil }
+func (flakyTx) Rollback() error { return nil }
+
+type flakyRows struct {
+	done bool
+}
+
+func (r *flakyRows) Columns() []string { return []string{"n"} }
+func (r *flakyRows) Close() error      { return nil }
+
+func (r *flakyRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+type warnLogger struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (l *warnLogger) Printf(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.msgs = append(l.msgs, fmt.Sprintf(format, v...))
+}
+
+func TestWithMaxRetries(t *testing.T) {
+	logger := new(warnLogger)
+	bun.SetLogger(logger)
+	defer bun.SetLogger(nil)
+
+	connector := &flakyConnector{err: io.EOF}
+	db := bun.NewDB(sql.OpenDB(connector), sqlitedialect.New(), bun.WithMaxRetries(2))
+	defer db.Close()
+
+	connector.failures = 1
+	var n int
+	err := db.NewSelect().ColumnExpr("1").Scan(ctx, &n)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, 2, connector.queries)
+	require.Len(t, logger.msgs, 1)
+	require.Contains(t, logger.msgs[0], "WARN: bun: retrying query after connection loss: EOF")
+
+	// database/sql retries driver.ErrBadConn itself before returning it.
+	connector.err = driver.ErrBadConn
+	connector.failures, connector.queries = 3, 0
+	_, err = db.NewSelect().ColumnExpr("1").Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 4, connector.queries)
+	require.Len(t, logger.msgs, 2)
+
+	connector.err = io.EOF
+	connector.failures, connector.queries = 3, 0
+	_, err = db.ExecContext(ctx, "SELECT 1")
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, 3, connector.queries)
+
+	// Writes are not retried after io.EOF, because they could be executed.
+	connector.failures, connector.queries = 1, 0
+	_, err = db.ExecContext(ctx, "UPDATE users SET name = 'x'")
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, 1, connector.queries)
+
+	// But they are retried after driver.ErrBadConn.
+	connector.err = driver.ErrBadConn
+	connector.failures, connector.queries = 3, 0
+	_, err = db.ExecContext(ctx, "UPDATE users SET name = 'x'")
+	require.NoError(t, err)
+	require.Equal(t, 4, connector.queries)
+	connector.err = io.EOF
+
+	// Queries in transactions are not retried.
+	connector.failures, connector.queries = 1, 0
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewSelect().ColumnExpr("1").Exec(ctx)
+		return err
+	})
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, 1, connector.queries)
+}
+
+func TestQueryBuilderMiddleware(t *testing.T) {
+	testEachDB(t, testQueryBuilderMiddleware)
+}
+
+func testQueryBuilderMiddleware(t *testing.T, dbName string, db *bun.DB) {
+	var calls []string
+	requireWhere := func(next bun.QueryHandler) bun.QueryHandler {
+		return func(ctx context.Context, q bun.Query) error {
+			calls = append(calls, "requireWhere")
+			if q, ok := q.(*bun.SelectQuery); ok && !q.HasWhere() {
+				panic("SELECT without WHERE")
+			}
+			return next(ctx, q)
+		}
+	}
+	defaultLimit := func(next bun.QueryHandler) bun.QueryHandler {
+		return func(ctx context.Context, q bun.Query) error {
+			calls = append(calls, "defaultLimit")
+			if q, ok := q.(*bun.SelectQuery); ok && q.GetLimit() == 0 {
+				q.Limit(10)
+			}
+			return next(ctx, q)
+		}
+	}
+	db.UseQueryBuilder(requireWhere, defaultLimit)
+
+	var query string
+	hook := &queryHook{}
+	db.AddQueryHook(hook)
+	hook.beforeQuery = func(ctx context.Context, event *bun.QueryEvent) context.Context {
+		query = event.Query
+		return ctx
+	}
+
+	var nums []int
+	err := db.NewSelect().ColumnExpr("1").Where("1 = 1").Scan(ctx, &nums)
+	require.NoError(t, err)
+	require.Equal(t, []int{1}, nums)
+	require.Equal(t, []string{"requireWhere", "defaultLimit"}, calls)
+	require.Contains(t, query, "LIMIT 10")
+
+	calls = nil
+	n, err := db.NewSelect().ColumnExpr("1").Where("1 = 1").Limit(5).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []string{"requireWhere", "defaultLimit"}, calls)
+
+	require.PanicsWithValue(t, "SELECT without WHERE", func() {
+		_, _ = db.NewSelect().ColumnExpr("1").Exec(ctx)
+	})
+
+	// Other queries are passed to the middlewares too.
+	calls = nil
+	_, err = db.NewRaw("SELECT 1").Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"requireWhere", "defaultLimit"}, calls)
+	require.NotContains(t, query, "LIMIT")
+}
@@ -1545,6 +1545,844 @@ func TestQuery(t *testing.T) {
 				return db.NewInsert().Model(new(Model))
 			},
 		},
+		{
+			id: 167,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewInsert().Model(&Model{42, "hello"}).OnConflictPK()
+			},
+		},
+		{
+			id: 168,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewInsert().Model(&Model{42, "hello"}).OnConflictPK().DoUpdateAllColumns()
+			},
+		},
+		{
+			id: 169,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().Model((*Model)(nil)).WhereID(42)
+			},
+		},
+		{
+			id: 170,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID1 int64 `bun:",pk"`
+					ID2 int64 `bun:",pk"`
+				}
+				return db.NewSelect().Model((*Model)(nil)).WhereID(42)
+			},
+		},
+		{
+			id: 171,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID         int64 `bun:",pk"`
+					Name       string
+					SearchName string `bun:",notnull,generated:'lower(name)'"`
+				}
+				return db.NewCreateTable().Model((*Model)(nil))
+			},
+		},
+		{
+			id: 172,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID         int64 `bun:",pk"`
+					Name       string
+					SearchName string `bun:",generated:'lower(name)'"`
+				}
+				return db.NewInsert().Model(&Model{ID: 1, Name: "Hello"})
+			},
+		},
+		{
+			id: 173,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID         int64 `bun:",pk"`
+					Name       string
+					SearchName string `bun:",generated:'lower(name)'"`
+				}
+				return db.NewUpdate().Model(&Model{ID: 1, Name: "Hello"}).WherePK()
+			},
+		},
+		{
+			id: 174,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID     int64 `bun:",pk"`
+					Secret bun.EncryptedField
+				}
+				return db.NewCreateTable().Model((*Model)(nil))
+			},
+		},
+		{
+			id: 175,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewUpdate().
+					Model(map[string]interface{}{"name": "hello", "count": 42}).
+					TableExpr("models").
+					Where("id = ?", 1)
+			},
+		},
+		{
+			id: 176,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewCreateIndex().
+					Model((*Model)(nil)).
+					Concurrently().
+					Index("idx_active_models").
+					Column("str").
+					Where("deleted_at IS NULL")
+			},
+		},
+		{
+			id: 177,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewCreateIndex().
+					Model((*Model)(nil)).
+					Unique().
+					Index("idx_models_lower_str").
+					Column("id").
+					ColumnExpr("(lower(?))", bun.Ident("str"))
+			},
+		},
+		{
+			id: 178,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Account struct {
+					bun.BaseModel `bun:"table:accounts,comment:'User account record'"`
+
+					ID    int64  `bun:",pk"`
+					Email string `bun:",comment:'Login, it''s lowercase'"`
+				}
+				return db.NewCreateTable().Model((*Account)(nil))
+			},
+		},
+		{
+			id: 179,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Upsert struct {
+					ID       int64 `bun:",pk"`
+					Name     string
+					Inserted bool `bun:"inserted,scanonly"`
+				}
+				return db.NewInsert().
+					Model(&Upsert{ID: 1, Name: "name"}).
+					OnConflictPK().
+					DoUpdateAllColumns().
+					ReturnInserted()
+			},
+		},
+		{
+			id: 180,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewUpdate().Model(new(Model)).Set("str = ?", "hello").
+					QueryBuilder().Where("id > 0").Order("id DESC").Limit(10).Unwrap().(*bun.UpdateQuery)
+			},
+		},
+		{
+			id: 181,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewDelete().Model(new(Model)).
+					QueryBuilder().Where("id > 0").OrderExpr("id ASC").Limit(10).Unwrap().(*bun.DeleteQuery)
+			},
+		},
+		{
+			id: 182,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().Model(new(Model)).WhereMap(map[string]interface{}{
+					"str":     "hello",
+					"id":      42,
+					"deleted": nil,
+				})
+			},
+		},
+		{
+			id: 183,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewUpdate().Model(new(Model)).Set("str = ?", "world").
+					WhereMap(map[string]interface{}{"id": 42, "str": "hello"})
+			},
+		},
+		{
+			id: 184,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewDelete().Model(new(Model)).
+					WhereMap(map[string]interface{}{"str": (*string)(nil)})
+			},
+		},
+		{
+			id: 185,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().Model(new(Model)).
+					WhereMap(map[string]interface{}{"id": []int{1, 2}})
+			},
+		},
+		{
+			id: 186,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().Model(new(Model)).OrderByRandom().Limit(3)
+			},
+		},
+		{
+			id: 187,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().Model(new(Model)).Sample(0.1).Limit(3)
+			},
+		},
+		{
+			id: 188,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().Model(new(Model)).Sample(0)
+			},
+		},
+		{
+			id: 189,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Price struct {
+					bun.TemporalTable
+					ID    int64 `bun:",pk"`
+					Value int64
+				}
+				tm := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+				return db.NewSelect().Model(new(Price)).Where("id = ?", 1).AsOf(tm)
+			},
+		},
+		{
+			id: 190,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Price struct {
+					bun.TemporalTable
+					ID        int64     `bun:",pk"`
+					ValidFrom time.Time `bun:",pk,default:current_timestamp"`
+					Value     int64
+				}
+				return db.NewCreateTable().Model(new(Price)).AsTemporalTable()
+			},
+		},
+		{
+			id: 191,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().Model(new(Model)).Comment("trace */ DROP TABLE models; /* x").Where("id = ?", 1)
+			},
+		},
+		{
+			id: 192,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewDelete().Model(new(Model)).Comment("cleanup").Where("id = ?", 1)
+			},
+		},
+		{
+			id: 193,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Item struct {
+					ID     int64 `bun:",pk,autoincrement"`
+					Name   string
+					Count  int
+					Status *string
+				}
+				return db.NewInsert().Model(&Item{Name: "foo"}).OmitZero()
+			},
+		},
+		{
+			id: 194,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Item struct {
+					ID     int64 `bun:",pk,autoincrement"`
+					Name   string
+					Count  int
+					Status *string
+				}
+				status := ""
+				return db.NewInsert().Model(&[]Item{{Name: "foo"}, {Status: &status}}).OmitZero()
+			},
+		},
+		{
+			id: 195,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().Model(new(Model)).TableSample("bernoulli", "1")
+			},
+		},
+		{
+			id: 196,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().Model(new(Model)).TableSample("SYSTEM", "1); DROP TABLE models; --")
+			},
+		},
+		{
+			id: 197,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().Table("a", "b").TableSample("SYSTEM_ROWS", "100")
+			},
+		},
+		{
+			id: 198,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().
+					ColumnExpr("name").
+					ColumnExpr("count(*)").
+					Table("orders").
+					Group("name").
+					Having("count(*) > ?", 5).
+					HavingOr("sum(amount) > ?", 1000)
+			},
+		},
+		{
+			id: 199,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().
+					ColumnExpr("name").
+					Table("orders").
+					Group("name").
+					Having("min(amount) > ?", 0).
+					HavingGroup(" AND ", func(q *bun.SelectQuery) *bun.SelectQuery {
+						return q.
+							Having("count(*) > ?", 5).
+							HavingOr("sum(amount) > ?", 1000).
+							HavingGroup(" OR ", func(q *bun.SelectQuery) *bun.SelectQuery {
+								return q.Having("max(amount) > ?", 100).Having("avg(amount) > ?", 10)
+							})
+					})
+			},
+		},
+		{
+			id: 200,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewInsert().
+					Table("events").
+					Row(map[string]interface{}{"id": 1, "name": "signup"}).
+					Row(map[string]interface{}{"id": 2, "payload": map[string]interface{}{"plan": "pro"}}).
+					Row(map[string]interface{}{"id": 3, "payload": json.RawMessage(`{"a":1}`)}).
+					Returning("id")
+			},
+		},
+		{
+			id: 201,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewDropTable().Model(new(Model)).IfExists().Cascade()
+			},
+		},
+		{
+			id: 202,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewDropTable().Model(new(Model)).Restrict()
+			},
+		},
+		{
+			id: 203,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewCreateSequence("order_numbers").
+					IfNotExists().
+					Start(1000).
+					Increment(10).
+					Min(1).
+					Max(1000000).
+					Cache(20).
+					Cycle()
+			},
+		},
+		{
+			id: 204,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewAlterSequence("order_numbers").IfExists().Increment(1).NoCycle().RestartWith(1)
+			},
+		},
+		{
+			id: 205,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewDropSequence("order_numbers").IfExists().Cascade()
+			},
+		},
+		{
+			id: 206,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewCreateExtension("uuid-ossp").
+					IfNotExists().
+					Schema("public").
+					Version("1.1").
+					Cascade()
+			},
+		},
+		{
+			id: 207,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewDropExtension("uuid-ossp").IfExists().Cascade()
+			},
+		},
+		{
+			id: 208,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewCreateView("active_models").
+					Replace().
+					Query(db.NewSelect().Model(new(Model)).Where("id > ?", 1))
+			},
+		},
+		{
+			id: 209,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewCreateView("model_stats").
+					Materialized().
+					IfNotExists().
+					WithData(false).
+					Query(db.NewSelect().Model(new(Model)).ColumnExpr("count(*)"))
+			},
+		},
+		{
+			id: 210,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewRefreshView("model_stats").Concurrently()
+			},
+		},
+		{
+			id: 211,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewDropView("model_stats").Materialized().IfExists().Cascade()
+			},
+		},
+		{
+			id: 212,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewCreateTablePartition("events", "events_2024_01").
+					IfNotExists().
+					For("FROM (?) TO (?)", "2024-01-01", "2024-02-01")
+			},
+		},
+		{
+			id: 213,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewCreateTablePartition("events", "events_default").Default()
+			},
+		},
+		{
+			id: 214,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewAttachPartition("accounts", "accounts_0").For("WITH (MODULUS 4, REMAINDER 0)")
+			},
+		},
+		{
+			id: 215,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewDetachPartition("events", "events_2024_01").Concurrently()
+			},
+		},
+		{
+			id: 216,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type UserRole struct {
+					UserID int64 `bun:",pk"`
+					RoleID int64 `bun:",pk"`
+				}
+				models := []UserRole{{UserID: 1, RoleID: 2}, {UserID: 3, RoleID: 4}}
+				return db.NewSelect().Model(&models).WherePKComposite()
+			},
+		},
+		{
+			id: 217,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Story struct {
+					ID     int64 `bun:",pk"`
+					UserID int64
+					Title  string
+				}
+				subq := db.NewSelect().Model((*Story)(nil)).Column("user_id").Where("title = ?", "hello")
+				return db.NewSelect().Model((*User)(nil)).WhereIn("user.id", subq)
+			},
+		},
+		{
+			id: 218,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Story struct {
+					ID     int64 `bun:",pk"`
+					UserID int64
+				}
+				users := []*User{{ID: 1}, {ID: 2}, {ID: 3}}
+				return db.NewSelect().Model((*Story)(nil)).WhereIn("user_id", users)
+			},
+		},
+		{
+			id: 219,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type User struct {
+					ID   int64 `bun:",pk"`
+					Name string
+				}
+				return db.NewSelect().Model((*User)(nil)).
+					WhereIn("id", []int64{}).
+					WhereNotIn("name", []string{}).
+					WhereNotIn("name", bun.In([]string{"foo", "bar"}))
+			},
+		},
+		{
+			id: 220,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewCreateTable().Model((*CheckAccount)(nil))
+			},
+		},
+		{
+			id: 221,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID     int64 `bun:",pk"`
+					Name   string
+					Email  string `bun:",pos:4"`
+					Age    int
+					Status string `bun:",pos:2"`
+				}
+				return db.NewSelect().Model((*Model)(nil))
+			},
+		},
+		{
+			id: 222,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID     int64 `bun:",pk"`
+					Name   string
+					Email  string
+					Status string
+				}
+				return db.NewSelect().Model((*Model)(nil)).ColumnOrder(bun.Alphabetical)
+			},
+		},
+		{
+			id: 223,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type User struct {
+					ID      int64 `bun:",pk"`
+					Active  bool
+					Role    string
+					Deleted bool
+				}
+				bun.RegisterScope((*User)(nil), "active", func(q *bun.SelectQuery) *bun.SelectQuery {
+					return q.Where("?TableAlias.active = TRUE")
+				})
+				bun.RegisterScope((*User)(nil), "staff", func(q *bun.SelectQuery) *bun.SelectQuery {
+					return q.Where("?TableAlias.role = ?", "admin").WhereOr("?TableAlias.role = ?", "editor")
+				})
+				return db.NewSelect().
+					Model((*User)(nil)).
+					Where("NOT ?TableAlias.deleted").
+					Scope("active", "staff")
+			},
+		},
+		{
+			id: 224,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type User struct {
+					ID int64 `bun:",pk"`
+				}
+				return db.NewSelect().Model((*User)(nil)).Scope("unknown")
+			},
+		},
+		{
+			id: 225,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type UserFilter struct {
+					Name     *string
+					Email    *string
+					MinAge   *int     `bun:"age,gte"`
+					MaxAge   *int     `bun:"age,lte"`
+					Roles    []string `bun:"role"`
+					Statuses []string
+					internal string
+					Ignored  string `bun:"-"`
+				}
+				name, minAge := "alice", 18
+				return db.NewSelect().
+					ColumnExpr("*").
+					Table("users").
+					WhereStruct(&UserFilter{
+						Name:   &name,
+						MinAge: &minAge,
+						Roles:  []string{"admin", "editor"},
+					})
+			},
+		},
+		{
+			id: 226,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Range struct {
+					From time.Time `bun:"created_at,gte"`
+					To   time.Time `bun:"created_at,lte"`
+				}
+				type Filter struct {
+					Range
+					IDs []int64 `bun:"id"`
+				}
+				return db.NewSelect().
+					ColumnExpr("*").
+					Table("events").
+					WhereStruct(Filter{
+						Range: Range{
+							From: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+							To:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+						},
+						IDs: []int64{},
+					})
+			},
+		},
+		{
+			id: 227,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Filter struct {
+					Tags map[string]string
+				}
+				return db.NewSelect().ColumnExpr("*").Table("users").WhereStruct(Filter{Tags: map[string]string{}})
+			},
+		},
+		{
+			id: 228,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Author struct {
+					ID     int64  `bun:",pk"`
+					Avatar []byte `bun:",lazy"`
+					Name   string
+				}
+				type Book struct {
+					ID       int64 `bun:",pk"`
+					Title    string
+					Text     string `bun:",lazy"`
+					AuthorID int64
+					Author   *Author `bun:"rel:belongs-to"`
+				}
+				return db.NewSelect().Model((*Book)(nil)).Relation("Author")
+			},
+		},
+		{
+			id: 229,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Book struct {
+					ID      int64  `bun:",pk"`
+					Text    string `bun:",lazy"`
+					Summary string `bun:",lazy"`
+				}
+				return db.NewSelect().Model((*Book)(nil)).ForceLazy().ColumnOrder(bun.Alphabetical)
+			},
+		},
+		{
+			id: 230,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().
+					Table("users").
+					OrderBy("name", bun.Asc).
+					OrderBy("created at", bun.Desc)
+			},
+		},
+		{
+			id: 231,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().
+					Table("users").
+					OrderByNullsFirst("name", bun.Asc).
+					OrderByNullsLast("age", bun.Desc)
+			},
+		},
+		{
+			id: 232,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().
+					Table("users").
+					Order("id").
+					ClearOrder().
+					OrderBy("name", bun.Desc)
+			},
+		},
+		{
+			id: 233,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Upsert struct {
+					ID          int64 `bun:",pk"`
+					Name        string
+					WasInserted bool `bun:",was_inserted,scanonly"`
+				}
+				return db.NewInsert().
+					Model(&[]Upsert{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}).
+					OnConflictPK().
+					DoUpdateAllColumns().
+					Returning("id").
+					ReturnInserted()
+			},
+		},
+		{
+			id: 234,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewSelect().
+					Table("users").
+					WhereIf(true, "name = ?", "alice").
+					WhereIf(false, "email = ?", "alice@example.com").
+					ApplyIf(true, func(q *bun.SelectQuery) *bun.SelectQuery {
+						return q.Where("active")
+					}).
+					ApplyIf(false, func(q *bun.SelectQuery) *bun.SelectQuery {
+						return q.Where("deleted")
+					}).
+					OrderIf(true, "id DESC").
+					OrderIf(false, "name").
+					LimitIf(false, 10).
+					LimitIf(true, 20)
+			},
+		},
+		{
+			id: 235,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewUpdate().
+					Table("users").
+					Set("name = ?", "bob").
+					WhereIf(true, "name = ?", "alice").
+					WhereIf(false, "email = ?", "alice@example.com").
+					ApplyIf(false, func(q *bun.UpdateQuery) *bun.UpdateQuery {
+						return q.Set("email = NULL")
+					}).
+					OrderIf(true, "id").
+					LimitIf(true, 10)
+			},
+		},
+		{
+			id: 236,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewDelete().
+					Table("users").
+					WhereIf(false, "name = ?", "alice").
+					ApplyIf(true, func(q *bun.DeleteQuery) *bun.DeleteQuery {
+						return q.Where("deleted")
+					}).
+					OrderIf(false, "id").
+					LimitIf(false, 10)
+			},
+		},
+		{
+			id: 237,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID        int64 `bun:",pk,autoincrement"`
+					Name      string
+					CreatedAt time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+				}
+				return db.NewInsert().
+					Model(&Model{Name: "hello"}).
+					Output("INSERTED.id, inserted.created_at")
+			},
+		},
+		{
+			id: 238,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID   int64 `bun:",pk,autoincrement"`
+					Name string
+				}
+				return db.NewUpdate().
+					Model(&Model{ID: 1, Name: "hello"}).
+					WherePK().
+					Output("INSERTED.*")
+			},
+		},
+		{
+			id: 239,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID   int64 `bun:",pk,autoincrement"`
+					Name string
+				}
+				return db.NewUpdate().
+					Model(&Model{ID: 1, Name: "hello"}).
+					WherePK().
+					Output("DELETED.name AS old_name, INSERTED.name")
+			},
+		},
+		{
+			id: 240,
+			query: func(db *bun.DB) schema.QueryAppender {
+				tree := db.NewSelect().
+					ColumnExpr("1 AS n").
+					UnionAll(db.NewSelect().ColumnExpr("n + 1").Table("tree").Where("n < 10"))
+				return db.NewSelect().
+					WithRecursive("tree", tree).
+					Table("tree").
+					ColumnExpr("n")
+			},
+		},
+		{
+			id: 241,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Mood int
+				schema.RegisterEnum[Mood]("sad", "happy")
+				return db.NewSelect().
+					Table("users").
+					Where("mood = ?", Mood(1)).
+					Where("mood IN (?)", bun.In([]Mood{0, 1})).
+					Where("mood != ?", Mood(2))
+			},
+		},
+		{
+			id: 242,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewCreateIndex().
+					Model((*Model)(nil)).
+					Index("idx_models_lower_str_desc").
+					ColumnExpr("id DESC").
+					ColumnExpr("lower(?) DESC", bun.Ident("str"))
+			},
+		},
+		{
+			id: 243,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Owner struct {
+					ID int64 `bun:",pk"`
+				}
+				type Item struct {
+					bun.BaseModel `bun:"table:items_with_a_very_long_table_name_to_exceed_the_limit"`
+
+					ID      int64 `bun:",pk"`
+					OwnerID int64
+					Owner   *Owner `bun:"rel:belongs-to,fk:name"`
+				}
+				return db.NewCreateTable().Model((*Item)(nil)).WithForeignKeys()
+			},
+		},
+		{
+			id: 244,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Owner struct {
+					ID int64 `bun:",pk"`
+				}
+				type Item struct {
+					ID      int64 `bun:",pk"`
+					OwnerID int64
+					Owner   *Owner `bun:"rel:belongs-to,fk:name:fk_item_owner,fk:on_delete:cascade"`
+				}
+				return db.NewCreateTable().Model((*Item)(nil)).WithForeignKeys()
+			},
+		},
+		{
+			id: 245,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewCreateView("active_models").
+					IfNotExists().
+					Query(db.NewSelect().Model(new(Model)).Where("id > ?", 1))
+			},
+		},
+		{
+			id: 246,
+			query: func(db *bun.DB) schema.QueryAppender {
+				return db.NewInsert().
+					Model(&Model{42, "hello"}).
+					OnConflictPK().
+					DoUpdateAllColumns().
+					Set("str = ?", "world")
+			},
+		},
+		{
+			id: 247,
+			query: func(db *bun.DB) schema.QueryAppender {
+				type Model struct {
+					ID int64 `bun:",pk"`
+				}
+				return db.NewInsert().
+					Model(&Model{ID: 42}).
+					OnConflictPK().
+					DoUpdateAllColumns()
+			},
+		},
 	}
 
 	timeRE := regexp.MustCompile(`'2\d{3}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}(\.\d+)?(\+\d{2}:\d{2})?'`)
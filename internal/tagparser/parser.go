@@ -113,6 +113,9 @@ func (p *parser) parseValue() string {
 			return p.s[start : p.i-1]
 		case '(':
 			p.skipPairs('(', ')')
+		case '\'':
+			// Single-quoted SQL strings are kept as is, but can contain commas.
+			p.skipQuoted('\'')
 		}
 	}
 
@@ -162,6 +165,14 @@ func (p *parser) skipPairs(start, end byte) {
 	}
 }
 
+func (p *parser) skipQuoted(quote byte) {
+	for p.valid() {
+		if p.read() == quote {
+			return
+		}
+	}
+}
+
 func (p *parser) valid() bool {
 	return p.i < len(p.s)
 }
@@ -34,6 +34,8 @@ var tagTests = []struct {
 	{"foo:bar(hello(), world)", "", map[string][]string{"foo": {"bar(hello(), world)"}}},
 	{"type:geometry(POINT, 4326)", "", map[string][]string{"type": {"geometry(POINT, 4326)"}}},
 	{"foo:bar,foo:baz", "", map[string][]string{"foo": []string{"bar", "baz"}}},
+	{"default:'hello, world',foo", "", map[string][]string{"default": {"'hello, world'"}, "foo": {""}}},
+	{"comment:'it''s, ok'", "", map[string][]string{"comment": {"'it''s, ok'"}}},
 }
 
 func TestTagParser(t *testing.T) {
@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"fmt"
+	"hash/fnv"
 	"reflect"
 )
 
@@ -79,3 +81,21 @@ func indirectNil(v reflect.Value) reflect.Value {
 	}
 	return v
 }
+
+// MaxIdentLen is the identifier length limit that fits all supported databases,
+// for example, PostgreSQL truncates identifiers to 63 bytes and MySQL rejects
+// identifiers longer than 64 characters.
+const MaxIdentLen = 63
+
+// TruncateIdent shortens the generated identifier to MaxIdentLen bytes
+// by replacing the tail with a hash of the full name, so different long names
+// stay different.
+func TruncateIdent(name string) string {
+	if len(name) <= MaxIdentLen {
+		return name
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	suffix := fmt.Sprintf("_%08x", h.Sum32())
+	return name[:MaxIdentLen-len(suffix)] + suffix
+}
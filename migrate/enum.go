@@ -0,0 +1,79 @@
+package migrate
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/schema"
+)
+
+// SyncEnums creates enum types used by the models and adds values that were registered
+// with schema.RegisterEnum but are missing in the database, e.g.
+//
+//	ALTER TYPE mood ADD VALUE IF NOT EXISTS 'excited'
+//
+// Values are never removed or reordered. SyncEnums does nothing if the dialect
+// does not support enum types.
+func SyncEnums(ctx context.Context, db *bun.DB, models ...interface{}) error {
+	if !db.HasFeature(feature.EnumType) {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	for _, model := range models {
+		table := db.Table(reflect.TypeOf(model))
+		for _, field := range table.Fields {
+			typeName, ok := field.Tag.Option("type")
+			if !ok || field.Enum == nil {
+				continue
+			}
+			if _, ok := seen[typeName]; ok {
+				continue
+			}
+			seen[typeName] = struct{}{}
+
+			if err := syncEnum(ctx, db, typeName, field.Enum); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func syncEnum(ctx context.Context, db *bun.DB, typeName string, enum *schema.Enum) error {
+	var labels []string
+	if err := db.NewSelect().
+		ColumnExpr("e.enumlabel").
+		TableExpr("pg_enum AS e").
+		Join("JOIN pg_type AS t ON t.oid = e.enumtypid").
+		Where("t.typname = ?", typeName).
+		OrderExpr("e.enumsortorder").
+		Scan(ctx, &labels); err != nil {
+		return err
+	}
+
+	if len(labels) == 0 {
+		values := enum.AppendValues(db.Formatter(), nil)
+		_, err := db.ExecContext(ctx, "CREATE TYPE ? AS ENUM (?)",
+			bun.Ident(typeName), bun.Safe(values))
+		return err
+	}
+
+	existing := make(map[string]struct{}, len(labels))
+	for _, label := range labels {
+		existing[label] = struct{}{}
+	}
+
+	for _, value := range enum.Values {
+		if _, ok := existing[value]; ok {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, "ALTER TYPE ? ADD VALUE IF NOT EXISTS ?",
+			bun.Ident(typeName), value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
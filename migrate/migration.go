@@ -4,6 +4,9 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -23,8 +26,20 @@ type Migration struct {
 	GroupID    int64
 	MigratedAt time.Time `bun:",notnull,nullzero,default:current_timestamp"`
 
+	// Checksum is the SHA-256 of the file that contains the up migration.
+	// It is stored when the migration is applied and verified by Migrate.
+	Checksum string `bun:"type:varchar(64),nullzero"`
+
+	// DependsOn contains the names of the migrations that must be applied
+	// before this migration. It is stored when the migration is applied.
+	DependsOn []string `bun:"type:varchar(1000),nullzero"`
+
 	Up   MigrationFunc `bun:"-"`
 	Down MigrationFunc `bun:"-"`
+
+	// SQL files of discovered migrations, used by Migrator.Plan.
+	upFile   *sqlFile
+	downFile *sqlFile
 }
 
 func (m Migration) String() string {
@@ -35,6 +50,32 @@ func (m Migration) IsApplied() bool {
 	return m.ID > 0
 }
 
+// ErrMigrationChecksumMismatch is returned (wrapped in ChecksumMismatchError)
+// when an applied migration was changed.
+var ErrMigrationChecksumMismatch = errors.New("migrate: migration checksum mismatch")
+
+// ChecksumMismatchError reports an applied migration whose file no longer matches
+// the checksum stored in the migrations table.
+type ChecksumMismatchError struct {
+	Migration string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf("migrate: migration %s was changed after it was applied "+
+		"(expected checksum %s, got %s)", e.Migration, e.Expected, e.Actual)
+}
+
+func (e *ChecksumMismatchError) Unwrap() error {
+	return ErrMigrationChecksumMismatch
+}
+
+func checksum(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
 type MigrationFunc func(ctx context.Context, db *bun.DB) error
 
 func NewSQLMigrationFunc(fsys fs.FS, name string) MigrationFunc {
@@ -44,39 +85,15 @@ func NewSQLMigrationFunc(fsys fs.FS, name string) MigrationFunc {
 			return err
 		}
 
-		isTx := strings.HasSuffix(name, ".tx.up.sql") || strings.HasSuffix(name, ".tx.down.sql")
+		isTx := (&sqlFile{fsys: fsys, name: name}).isTx()
 		return Exec(ctx, db, f, isTx)
 	}
 }
 
 // Exec reads and executes the SQL migration in the f.
 func Exec(ctx context.Context, db *bun.DB, f io.Reader, isTx bool) error {
-	scanner := bufio.NewScanner(f)
-	var queries []string
-
-	var query []byte
-	for scanner.Scan() {
-		b := scanner.Bytes()
-
-		const prefix = "--bun:"
-		if bytes.HasPrefix(b, []byte(prefix)) {
-			b = b[len(prefix):]
-			if bytes.Equal(b, []byte("split")) {
-				queries = append(queries, string(query))
-				query = query[:0]
-				continue
-			}
-			return fmt.Errorf("bun: unknown directive: %q", b)
-		}
-
-		query = append(query, b...)
-		query = append(query, '\n')
-	}
-
-	if len(query) > 0 {
-		queries = append(queries, string(query))
-	}
-	if err := scanner.Err(); err != nil {
+	queries, err := readQueries(f)
+	if err != nil {
 		return err
 	}
 
@@ -127,6 +144,39 @@ func Exec(ctx context.Context, db *bun.DB, f io.Reader, isTx bool) error {
 	return retErr
 }
 
+// readQueries reads the SQL migration and splits it into queries.
+func readQueries(f io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(f)
+	var queries []string
+
+	var query []byte
+	for scanner.Scan() {
+		b := scanner.Bytes()
+
+		const prefix = "--bun:"
+		if bytes.HasPrefix(b, []byte(prefix)) {
+			b = b[len(prefix):]
+			if bytes.Equal(b, []byte("split")) {
+				queries = append(queries, string(query))
+				query = query[:0]
+				continue
+			}
+			return nil, fmt.Errorf("bun: unknown directive: %q", b)
+		}
+
+		query = append(query, b...)
+		query = append(query, '\n')
+	}
+
+	if len(query) > 0 {
+		queries = append(queries, string(query))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return queries, nil
+}
+
 const goTemplate = `package %s
 
 import (
@@ -268,7 +318,9 @@ type MigrationFile struct {
 //------------------------------------------------------------------------------
 
 type migrationConfig struct {
-	nop bool
+	nop               bool
+	skipChecksum      bool
+	checkDependencies bool
 }
 
 func newMigrationConfig(opts []MigrationOption) *migrationConfig {
@@ -287,6 +339,22 @@ func WithNopMigration() MigrationOption {
 	}
 }
 
+// WithChecksumSkip disables checksum verification of applied migrations,
+// for example, to deploy an emergency fix to an applied migration.
+func WithChecksumSkip() MigrationOption {
+	return func(cfg *migrationConfig) {
+		cfg.skipChecksum = true
+	}
+}
+
+// WithDependencyCheck makes Migrate check that the dependencies of applied migrations
+// are applied too before running new migrations.
+func WithDependencyCheck() MigrationOption {
+	return func(cfg *migrationConfig) {
+		cfg.checkDependencies = true
+	}
+}
+
 //------------------------------------------------------------------------------
 
 func sortAsc(ms MigrationSlice) {
@@ -300,3 +368,86 @@ func sortDesc(ms MigrationSlice) {
 		return ms[i].Name > ms[j].Name
 	})
 }
+
+// validateDependencies checks that the dependencies exist and don't form cycles.
+func validateDependencies(ms MigrationSlice) error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	msMap := migrationMap(ms)
+	state := make(map[string]int, len(ms))
+	var path []string
+
+	var visit func(m *Migration) error
+	visit = func(m *Migration) error {
+		switch state[m.Name] {
+		case visiting:
+			i := len(path) - 1
+			for path[i] != m.Name {
+				i--
+			}
+			cycle := append(path[i:len(path):len(path)], m.Name)
+			return fmt.Errorf("migrate: migration dependency cycle: %s", strings.Join(cycle, " -> "))
+		case visited:
+			return nil
+		}
+
+		state[m.Name] = visiting
+		path = append(path, m.Name)
+		for _, dep := range m.DependsOn {
+			depMigration, ok := msMap[dep]
+			if !ok {
+				return fmt.Errorf("migrate: migration %s depends on unknown migration %s",
+					m.Name, dep)
+			}
+			if err := visit(depMigration); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[m.Name] = visited
+		return nil
+	}
+
+	for i := range ms {
+		if err := visit(&ms[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortByDependencies sorts unapplied migrations so that dependencies go first.
+// Otherwise, the migrations keep the ascending order. The dependencies must be
+// validated with validateDependencies.
+func sortByDependencies(ms MigrationSlice) MigrationSlice {
+	sortAsc(ms)
+
+	msMap := migrationMap(ms)
+	done := make(map[string]bool, len(ms))
+	sorted := make(MigrationSlice, 0, len(ms))
+
+	var visit func(m *Migration)
+	visit = func(m *Migration) {
+		if done[m.Name] {
+			return
+		}
+		done[m.Name] = true
+
+		deps := append([]string(nil), m.DependsOn...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if depMigration, ok := msMap[dep]; ok {
+				visit(depMigration)
+			}
+		}
+		sorted = append(sorted, *m)
+	}
+
+	for i := range ms {
+		visit(&ms[i])
+	}
+	return sorted
+}
@@ -19,8 +19,19 @@ func WithMigrationsDirectory(directory string) MigrationsOption {
 	}
 }
 
+// WithExtensions declares the database extensions that the migrations require,
+// for example, "uuid-ossp" or "pgcrypto". Migrator.Migrate creates the missing
+// extensions before running the migrations. Extensions are ignored for the
+// dialects that don't support them.
+func WithExtensions(names ...string) MigrationsOption {
+	return func(m *Migrations) {
+		m.extensions = append(m.extensions, names...)
+	}
+}
+
 type Migrations struct {
-	ms MigrationSlice
+	ms         MigrationSlice
+	extensions []string
 
 	explicitDirectory string
 	implicitDirectory string
@@ -35,6 +46,11 @@ func NewMigrations(opts ...MigrationsOption) *Migrations {
 	return m
 }
 
+// Extensions returns the extensions declared with WithExtensions.
+func (m *Migrations) Extensions() []string {
+	return m.extensions
+}
+
 func (m *Migrations) Sorted() MigrationSlice {
 	migrations := make(MigrationSlice, len(m.ms))
 	copy(migrations, m.ms)
@@ -55,11 +71,19 @@ func (m *Migrations) Register(up, down MigrationFunc) error {
 		return err
 	}
 
+	// The source file is usually not available in production builds,
+	// so the checksum is optional for Go migrations.
+	var sum string
+	if b, err := os.ReadFile(fpath); err == nil {
+		sum = checksum(b)
+	}
+
 	m.Add(Migration{
-		Name:    name,
-		Comment: comment,
-		Up:      up,
-		Down:    down,
+		Name:     name,
+		Comment:  comment,
+		Checksum: sum,
+		Up:       up,
+		Down:     down,
 	})
 
 	return nil
@@ -72,6 +96,20 @@ func (m *Migrations) Add(migration Migration) {
 	m.ms = append(m.ms, migration)
 }
 
+// DependsOn declares that the migration must be applied after the dependencies,
+// for example, after migrations contributed by other modules. Migrate applies
+// migrations in the dependency order and returns an error on dependency cycles.
+func (m *Migrations) DependsOn(name string, dependencies ...string) error {
+	for i := range m.ms {
+		migration := &m.ms[i]
+		if migration.Name == name {
+			migration.DependsOn = append(migration.DependsOn, dependencies...)
+			return nil
+		}
+	}
+	return fmt.Errorf("migrate: migration %s is not registered", name)
+}
+
 func (m *Migrations) DiscoverCaller() error {
 	dir := filepath.Dir(migrationFile())
 	return m.Discover(os.DirFS(dir))
@@ -104,11 +142,18 @@ func (m *Migrations) Discover(fsys fs.FS) error {
 		migrationFunc := NewSQLMigrationFunc(fsys, path)
 
 		if strings.HasSuffix(path, ".up.sql") {
+			b, err := fs.ReadFile(fsys, path)
+			if err != nil {
+				return err
+			}
+			migration.Checksum = checksum(b)
 			migration.Up = migrationFunc
+			migration.upFile = &sqlFile{fsys: fsys, name: path}
 			return nil
 		}
 		if strings.HasSuffix(path, ".down.sql") {
 			migration.Down = migrationFunc
+			migration.downFile = &sqlFile{fsys: fsys, name: path}
 			return nil
 		}
 
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
 )
 
 type MigratorOption func(m *Migrator)
@@ -100,6 +101,9 @@ func (m *Migrator) Init(ctx context.Context) error {
 		Exec(ctx); err != nil {
 		return err
 	}
+	if err := m.addMissingColumns(ctx); err != nil {
+		return err
+	}
 	if _, err := m.db.NewCreateTable().
 		Model((*migrationLock)(nil)).
 		ModelTableExpr(m.locksTable).
@@ -110,6 +114,34 @@ func (m *Migrator) Init(ctx context.Context) error {
 	return nil
 }
 
+// addMissingColumns adds the columns to migrations tables created by older versions.
+func (m *Migrator) addMissingColumns(ctx context.Context) error {
+	columns := []struct {
+		name string
+		typ  string
+	}{
+		{"checksum", "varchar(64)"},
+		{"depends_on", "varchar(1000)"},
+	}
+
+	for _, col := range columns {
+		// The column is not quoted, because SQLite treats unknown quoted identifiers as strings.
+		if _, err := m.db.NewSelect().
+			ColumnExpr(col.name).
+			TableExpr(m.table).
+			Where("1 = 0").
+			Exec(ctx); err == nil {
+			continue
+		}
+
+		if _, err := m.db.ExecContext(ctx, "ALTER TABLE ? ADD ? ?",
+			bun.Safe(m.table), bun.Ident(col.name), bun.Safe(col.typ)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *Migrator) Reset(ctx context.Context) error {
 	if _, err := m.db.NewDropTable().
 		Model((*Migration)(nil)).
@@ -136,11 +168,24 @@ func (m *Migrator) Migrate(ctx context.Context, opts ...MigrationOption) (*Migra
 		return nil, err
 	}
 
+	if !cfg.skipChecksum {
+		if err := m.VerifyChecksums(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	migrations, lastGroupID, err := m.migrationsWithStatus(ctx)
 	if err != nil {
 		return nil, err
 	}
-	migrations = migrations.Unapplied()
+
+	if cfg.checkDependencies {
+		if err := m.checkDependencies(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	migrations = sortByDependencies(migrations.Unapplied())
 
 	group := new(MigrationGroup)
 	if len(migrations) == 0 {
@@ -148,6 +193,12 @@ func (m *Migrator) Migrate(ctx context.Context, opts ...MigrationOption) (*Migra
 	}
 	group.ID = lastGroupID + 1
 
+	if !cfg.nop {
+		if err := m.createExtensions(ctx); err != nil {
+			return group, err
+		}
+	}
+
 	for i := range migrations {
 		migration := &migrations[i]
 		migration.GroupID = group.ID
@@ -176,6 +227,18 @@ func (m *Migrator) Migrate(ctx context.Context, opts ...MigrationOption) (*Migra
 	return group, nil
 }
 
+func (m *Migrator) createExtensions(ctx context.Context) error {
+	if !m.db.HasFeature(feature.Extension) {
+		return nil
+	}
+	for _, name := range m.migrations.extensions {
+		if _, err := m.db.NewCreateExtension(name).IfNotExists().Exec(ctx); err != nil {
+			return fmt.Errorf("migrate: create extension %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
 func (m *Migrator) Rollback(ctx context.Context, opts ...MigrationOption) (*MigrationGroup, error) {
 	cfg := newMigrationConfig(opts)
 
@@ -189,6 +252,7 @@ func (m *Migrator) Rollback(ctx context.Context, opts ...MigrationOption) (*Migr
 	}
 
 	lastGroup := migrations.LastGroup()
+	lastGroup.Migrations = sortByDependencies(lastGroup.Migrations)
 
 	for i := len(lastGroup.Migrations) - 1; i >= 0; i-- {
 		migration := &lastGroup.Migrations[i]
@@ -369,6 +433,34 @@ func (m *Migrator) TruncateTable(ctx context.Context) error {
 	return err
 }
 
+// VerifyChecksums returns a ChecksumMismatchError if an applied migration was changed.
+// Migrations without a checksum, for example, applied by older versions or
+// Go migrations without the source file, are not verified.
+func (m *Migrator) VerifyChecksums(ctx context.Context) error {
+	applied, err := m.AppliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	sorted := m.migrations.Sorted()
+	appliedMap := migrationMap(applied)
+	for i := range sorted {
+		m1 := &sorted[i]
+		m2, ok := appliedMap[m1.Name]
+		if !ok || m1.Checksum == "" || m2.Checksum == "" {
+			continue
+		}
+		if m1.Checksum != m2.Checksum {
+			return &ChecksumMismatchError{
+				Migration: m1.String(),
+				Expected:  m2.Checksum,
+				Actual:    m1.Checksum,
+			}
+		}
+	}
+	return nil
+}
+
 // MissingMigrations returns applied migrations that can no longer be found.
 func (m *Migrator) MissingMigrations(ctx context.Context) (MigrationSlice, error) {
 	applied, err := m.AppliedMigrations(ctx)
@@ -408,6 +500,29 @@ func (m *Migrator) validate() error {
 	if len(m.ms) == 0 {
 		return errors.New("migrate: there are no migrations")
 	}
+	return validateDependencies(m.migrations.Sorted())
+}
+
+// checkDependencies checks that the dependencies of applied migrations, as they were
+// recorded when the migrations were applied, are applied too. The check fails,
+// for example, when a migration was marked as applied manually out of order.
+func (m *Migrator) checkDependencies(ctx context.Context) error {
+	applied, err := m.AppliedMigrations(ctx)
+	if err != nil {
+		return err
+	}
+
+	appliedMap := migrationMap(applied)
+	sortAsc(applied)
+	for i := range applied {
+		migration := &applied[i]
+		for _, dep := range migration.DependsOn {
+			if _, ok := appliedMap[dep]; !ok {
+				return fmt.Errorf("migrate: migration %s is applied, but its dependency %s is not",
+					migration.Name, dep)
+			}
+		}
+	}
 	return nil
 }
 
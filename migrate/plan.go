@@ -0,0 +1,159 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// MigrationPlan describes the migrations that Migrate would apply and
+// Rollback would roll back, without executing them.
+type MigrationPlan struct {
+	// Migrate contains unapplied migrations in the order they would be applied.
+	Migrate []PlannedMigration
+	// Rollback contains migrations of the last group in the order they would be rolled back.
+	Rollback []PlannedMigration
+}
+
+// PlannedMigration is a migration in a MigrationPlan.
+type PlannedMigration struct {
+	Name string
+	// Transactional is true if the SQL migration runs in a transaction.
+	Transactional bool
+	// Queries contains the queries of SQL migrations. Go migrations don't have
+	// queries, because they run arbitrary code.
+	Queries []string
+	IsGo    bool
+}
+
+// String returns the plan as SQL that can be reviewed before running the migrations.
+// Transactional migrations are wrapped in BEGIN/ROLLBACK and non-transactional
+// migrations are annotated.
+func (p *MigrationPlan) String() string {
+	var b strings.Builder
+
+	b.WriteString("-- migrate\n")
+	writePlannedMigrations(&b, p.Migrate)
+
+	b.WriteString("\n-- rollback\n")
+	writePlannedMigrations(&b, p.Rollback)
+
+	return b.String()
+}
+
+func writePlannedMigrations(b *strings.Builder, ms []PlannedMigration) {
+	if len(ms) == 0 {
+		b.WriteString("-- there are no migrations\n")
+		return
+	}
+
+	for i := range ms {
+		m := &ms[i]
+
+		switch {
+		case m.IsGo:
+			fmt.Fprintf(b, "-- %s (Go migration, the queries are unknown)\n", m.Name)
+			continue
+		case m.Transactional:
+			fmt.Fprintf(b, "-- %s\n", m.Name)
+			b.WriteString("BEGIN;\n")
+		default:
+			fmt.Fprintf(b, "-- %s (not transactional)\n", m.Name)
+		}
+
+		for _, q := range m.Queries {
+			b.WriteString(q)
+			b.WriteString(";\n")
+		}
+
+		if m.Transactional {
+			b.WriteString("ROLLBACK;\n")
+		}
+	}
+}
+
+// Plan returns the migrations that Migrate would apply and Rollback would roll back
+// with their SQL. Plan only reads the migrations table.
+func (m *Migrator) Plan(ctx context.Context) (*MigrationPlan, error) {
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+
+	migrations, err := m.MigrationsWithStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := new(MigrationPlan)
+
+	for _, migration := range sortByDependencies(migrations.Unapplied()) {
+		pm, err := newPlannedMigration(&migration, migration.Up, migration.upFile)
+		if err != nil {
+			return nil, err
+		}
+		plan.Migrate = append(plan.Migrate, pm)
+	}
+
+	lastGroup := sortByDependencies(migrations.LastGroup().Migrations)
+	for i := len(lastGroup) - 1; i >= 0; i-- {
+		migration := &lastGroup[i]
+		pm, err := newPlannedMigration(migration, migration.Down, migration.downFile)
+		if err != nil {
+			return nil, err
+		}
+		plan.Rollback = append(plan.Rollback, pm)
+	}
+
+	return plan, nil
+}
+
+func newPlannedMigration(
+	migration *Migration, fn MigrationFunc, file *sqlFile,
+) (PlannedMigration, error) {
+	pm := PlannedMigration{
+		Name: migration.String(),
+	}
+	if file == nil {
+		pm.IsGo = fn != nil
+		return pm, nil
+	}
+
+	queries, err := file.queries()
+	if err != nil {
+		return pm, err
+	}
+
+	pm.Transactional = file.isTx()
+	pm.Queries = queries
+	return pm, nil
+}
+
+//------------------------------------------------------------------------------
+
+type sqlFile struct {
+	fsys fs.FS
+	name string
+}
+
+func (f *sqlFile) isTx() bool {
+	return strings.HasSuffix(f.name, ".tx.up.sql") || strings.HasSuffix(f.name, ".tx.down.sql")
+}
+
+func (f *sqlFile) queries() ([]string, error) {
+	file, err := f.fsys.Open(f.name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	queries, err := readQueries(file)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, q := range queries {
+		queries[i] = strings.TrimSuffix(strings.TrimSpace(q), ";")
+	}
+	return queries, nil
+}
@@ -0,0 +1,595 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// Phases of a ColumnRename.
+const (
+	RenamePhaseExpanded   = "expanded"   // both columns exist and are kept in sync
+	RenamePhaseContracted = "contracted" // the old column is dropped
+)
+
+type columnRenameState struct {
+	bun.BaseModel `bun:"table:bun_column_renames"`
+
+	TableName string `bun:",pk"`
+	OldName   string `bun:",pk"`
+	NewName   string `bun:",notnull"`
+	Phase     string `bun:",notnull"`
+}
+
+// ColumnRename renames a column without downtime in two migrations:
+//
+//   - Expand adds the new column, creates a trigger that keeps both columns in sync,
+//     and copies the data, so the old and the new application code can run at the same time.
+//     The old column is recorded as deprecated in the bun_column_renames table.
+//   - Contract drops the trigger and the old column once the old code is no longer deployed.
+//
+// The schema changes of each phase run in a transaction. The data is copied after
+// the transaction in batches of BatchSize rows ordered by the single-column primary key,
+// so the table is not locked for the whole copy. Tables without such a key are copied
+// with a single UPDATE.
+//
+// The current phase is stored in the bun_column_renames table, see RenamedColumns.
+// Only PostgreSQL and SQLite are supported for now.
+type ColumnRename struct {
+	Table   string
+	OldName string
+	NewName string
+
+	// BatchSize is the number of rows copied by each UPDATE. The default is 1000.
+	BatchSize int
+}
+
+func NewColumnRename(table, oldName, newName string) *ColumnRename {
+	return &ColumnRename{
+		Table:   table,
+		OldName: oldName,
+		NewName: newName,
+	}
+}
+
+// RenameColumnCompat registers the expand phase of a ColumnRename as a migration
+// named after the caller's file, like Register. The returned ColumnRename is used
+// to register the contract phase in a follow-up migration:
+//
+//	Migrations.MustRegister(rename.Contract, rename.UndoContract)
+func (m *Migrations) RenameColumnCompat(table, oldName, newName string) *ColumnRename {
+	r := NewColumnRename(table, oldName, newName)
+	m.MustRegister(r.Expand, r.UndoExpand)
+	return r
+}
+
+// Expand adds the new column and starts syncing it with the old one.
+func (r *ColumnRename) Expand(ctx context.Context, db *bun.DB) error {
+	syncer, err := r.syncer(db)
+	if err != nil {
+		return err
+	}
+
+	if err := runDDL(ctx, db, func(ctx context.Context, db bun.IDB) error {
+		state, err := r.state(ctx, db)
+		if err != nil {
+			return err
+		}
+		if state.Phase != "" {
+			return fmt.Errorf("migrate: %s is already %s", r, state.Phase)
+		}
+
+		if err := r.addColumn(ctx, db, syncer, r.OldName, r.NewName); err != nil {
+			return err
+		}
+		if err := syncer.createSync(ctx, db, r); err != nil {
+			return err
+		}
+
+		state.NewName = r.NewName
+		state.Phase = RenamePhaseExpanded
+		_, err = db.NewInsert().Model(state).Exec(ctx)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return r.copyColumn(ctx, db, syncer, r.OldName, r.NewName)
+}
+
+// UndoExpand drops the trigger and the new column.
+func (r *ColumnRename) UndoExpand(ctx context.Context, db *bun.DB) error {
+	syncer, err := r.syncer(db)
+	if err != nil {
+		return err
+	}
+
+	return runDDL(ctx, db, func(ctx context.Context, db bun.IDB) error {
+		if err := r.requirePhase(ctx, db, RenamePhaseExpanded); err != nil {
+			return err
+		}
+
+		if err := syncer.dropSync(ctx, db, r); err != nil {
+			return err
+		}
+		if err := r.dropColumn(ctx, db, r.NewName); err != nil {
+			return err
+		}
+
+		_, err := db.NewDelete().
+			Model((*columnRenameState)(nil)).
+			Where("table_name = ?", r.Table).
+			Where("old_name = ?", r.OldName).
+			Exec(ctx)
+		return err
+	})
+}
+
+// Contract drops the trigger and the old column.
+func (r *ColumnRename) Contract(ctx context.Context, db *bun.DB) error {
+	syncer, err := r.syncer(db)
+	if err != nil {
+		return err
+	}
+
+	return runDDL(ctx, db, func(ctx context.Context, db bun.IDB) error {
+		if err := r.requirePhase(ctx, db, RenamePhaseExpanded); err != nil {
+			return err
+		}
+
+		if err := syncer.dropSync(ctx, db, r); err != nil {
+			return err
+		}
+		if err := r.dropColumn(ctx, db, r.OldName); err != nil {
+			return err
+		}
+
+		return r.setPhase(ctx, db, RenamePhaseExpanded, RenamePhaseContracted)
+	})
+}
+
+// UndoContract restores the old column and the trigger.
+func (r *ColumnRename) UndoContract(ctx context.Context, db *bun.DB) error {
+	syncer, err := r.syncer(db)
+	if err != nil {
+		return err
+	}
+
+	if err := runDDL(ctx, db, func(ctx context.Context, db bun.IDB) error {
+		if err := r.requirePhase(ctx, db, RenamePhaseContracted); err != nil {
+			return err
+		}
+
+		if err := r.addColumn(ctx, db, syncer, r.NewName, r.OldName); err != nil {
+			return err
+		}
+		if err := syncer.createSync(ctx, db, r); err != nil {
+			return err
+		}
+
+		return r.setPhase(ctx, db, RenamePhaseContracted, RenamePhaseExpanded)
+	}); err != nil {
+		return err
+	}
+
+	return r.copyColumn(ctx, db, syncer, r.NewName, r.OldName)
+}
+
+func (r *ColumnRename) String() string {
+	return fmt.Sprintf("column rename %s.%s -> %s", r.Table, r.OldName, r.NewName)
+}
+
+func (r *ColumnRename) syncer(db *bun.DB) (columnSyncer, error) {
+	switch db.Dialect().Name() {
+	case dialect.PG:
+		return pgColumnSyncer{}, nil
+	case dialect.SQLite:
+		return sqliteColumnSyncer{}, nil
+	default:
+		return nil, fmt.Errorf("migrate: ColumnRename is not supported by %s", db.Dialect().Name())
+	}
+}
+
+// runDDL runs fn in a transaction on the dialects that support transactional DDL.
+func runDDL(ctx context.Context, db *bun.DB, fn func(context.Context, bun.IDB) error) error {
+	switch db.Dialect().Name() {
+	case dialect.PG, dialect.SQLite:
+		return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return fn(ctx, tx)
+		})
+	default:
+		return fn(ctx, db)
+	}
+}
+
+func (r *ColumnRename) addColumn(
+	ctx context.Context, db bun.IDB, syncer columnSyncer, src, dest string,
+) error {
+	typ, err := syncer.columnType(ctx, db, r.Table, src)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, "ALTER TABLE ? ADD COLUMN ? ?",
+		bun.Ident(r.Table), bun.Ident(dest), bun.Safe(typ))
+	return err
+}
+
+// copyColumn copies src to dest in batches of rows ordered by the primary key.
+// The sync trigger already copies the rows written concurrently.
+func (r *ColumnRename) copyColumn(
+	ctx context.Context, db *bun.DB, syncer columnSyncer, src, dest string,
+) error {
+	pk, err := syncer.primaryKey(ctx, db, r.Table)
+	if err != nil {
+		return err
+	}
+	if pk == "" {
+		_, err := db.ExecContext(ctx, "UPDATE ? SET ? = ?",
+			bun.Ident(r.Table), bun.Ident(dest), bun.Ident(src))
+		return err
+	}
+
+	batchSize := r.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	var last interface{}
+	for {
+		batch := db.NewSelect().
+			ColumnExpr("?", bun.Ident(pk)).
+			TableExpr("?", bun.Ident(r.Table)).
+			OrderExpr("? ASC", bun.Ident(pk)).
+			Limit(batchSize)
+		if last != nil {
+			batch = batch.Where("? > ?", bun.Ident(pk), last)
+		}
+
+		var upper interface{}
+		if err := db.QueryRowContext(ctx, "SELECT max(?) FROM (?) AS batch",
+			bun.Ident(pk), batch).Scan(&upper); err != nil {
+			return err
+		}
+		if upper == nil {
+			return nil
+		}
+
+		update := db.NewUpdate().
+			TableExpr("?", bun.Ident(r.Table)).
+			Set("? = ?", bun.Ident(dest), bun.Ident(src)).
+			Where("? <= ?", bun.Ident(pk), upper)
+		if last != nil {
+			update = update.Where("? > ?", bun.Ident(pk), last)
+		}
+		if _, err := update.Exec(ctx); err != nil {
+			return err
+		}
+
+		last = upper
+	}
+}
+
+func (r *ColumnRename) dropColumn(ctx context.Context, db bun.IDB, column string) error {
+	_, err := db.ExecContext(ctx, "ALTER TABLE ? DROP COLUMN ?", bun.Ident(r.Table), bun.Ident(column))
+	return err
+}
+
+func (r *ColumnRename) state(ctx context.Context, db bun.IDB) (*columnRenameState, error) {
+	if err := createColumnRenamesTable(ctx, db); err != nil {
+		return nil, err
+	}
+
+	state := &columnRenameState{
+		TableName: r.Table,
+		OldName:   r.OldName,
+	}
+	err := db.NewSelect().Model(state).WherePK().Scan(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (r *ColumnRename) requirePhase(ctx context.Context, db bun.IDB, phase string) error {
+	state, err := r.state(ctx, db)
+	if err != nil {
+		return err
+	}
+	if state.Phase != phase {
+		if state.Phase == "" {
+			return fmt.Errorf("migrate: %s is not started, expected %s", r, phase)
+		}
+		return fmt.Errorf("migrate: %s is %s, expected %s", r, state.Phase, phase)
+	}
+	return nil
+}
+
+func (r *ColumnRename) setPhase(ctx context.Context, db bun.IDB, from, to string) error {
+	_, err := db.NewUpdate().
+		Model(&columnRenameState{TableName: r.Table, OldName: r.OldName, Phase: to}).
+		Column("phase").
+		WherePK().
+		Where("phase = ?", from).
+		Exec(ctx)
+	return err
+}
+
+func (r *ColumnRename) syncName() string {
+	name := "bun_sync_" + r.Table + "_" + r.OldName
+	return strings.ReplaceAll(name, ".", "_")
+}
+
+func createColumnRenamesTable(ctx context.Context, db bun.IDB) error {
+	_, err := db.NewCreateTable().
+		Model((*columnRenameState)(nil)).
+		IfNotExists().
+		Exec(ctx)
+	return err
+}
+
+// RenamedColumn is a column rename started with ColumnRename.
+type RenamedColumn struct {
+	Table   string
+	OldName string
+	NewName string
+	Phase   string // RenamePhaseExpanded or RenamePhaseContracted
+}
+
+// RenamedColumns returns the column renames recorded in the bun_column_renames table.
+// It doesn't create the table, so it returns nothing if no rename was started.
+func RenamedColumns(ctx context.Context, db bun.IDB) ([]RenamedColumn, error) {
+	exists, err := columnRenamesTableExists(ctx, db)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	var states []columnRenameState
+	if err := db.NewSelect().Model(&states).Order("table_name", "old_name").Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	renames := make([]RenamedColumn, len(states))
+	for i, state := range states {
+		renames[i] = RenamedColumn{
+			Table:   state.TableName,
+			OldName: state.OldName,
+			NewName: state.NewName,
+			Phase:   state.Phase,
+		}
+	}
+	return renames, nil
+}
+
+func columnRenamesTableExists(ctx context.Context, db bun.IDB) (bool, error) {
+	table := db.Dialect().Tables().Get(reflect.TypeOf((*columnRenameState)(nil))).Name
+	switch db.Dialect().Name() {
+	case dialect.PG:
+		return db.NewSelect().
+			TableExpr("pg_catalog.pg_tables").
+			Where("tablename = ?", table).
+			Where("schemaname = current_schema()").
+			Exists(ctx)
+	case dialect.SQLite:
+		return db.NewSelect().
+			TableExpr("sqlite_master").
+			Where("type = 'table'").
+			Where("name = ?", table).
+			Exists(ctx)
+	default:
+		// ColumnRename is not supported, so there are no renames.
+		return false, nil
+	}
+}
+
+// CheckRenamedColumns returns an error if the models still use the old name
+// of a column that is being renamed or was renamed with ColumnRename.
+// sqlschema.Diff reports the same problems as PendingColumnRename changes.
+func CheckRenamedColumns(ctx context.Context, db *bun.DB, models ...interface{}) error {
+	renames, err := RenamedColumns(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, model := range models {
+		table := db.Table(reflect.TypeOf(model))
+		for _, rename := range renames {
+			if table.Name != rename.Table {
+				continue
+			}
+			if _, ok := table.FieldMap[rename.OldName]; ok {
+				problems = append(problems, fmt.Sprintf("%s uses column %q renamed to %q (%s)",
+					table.TypeName, rename.OldName, rename.NewName, rename.Phase))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("migrate: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+//------------------------------------------------------------------------------
+
+type columnSyncer interface {
+	columnType(ctx context.Context, db bun.IDB, table, column string) (string, error)
+	createSync(ctx context.Context, db bun.IDB, r *ColumnRename) error
+	dropSync(ctx context.Context, db bun.IDB, r *ColumnRename) error
+	// primaryKey returns the primary key column or "" if the key has several columns.
+	primaryKey(ctx context.Context, db bun.IDB, table string) (string, error)
+}
+
+type pgColumnSyncer struct{}
+
+func (pgColumnSyncer) columnType(
+	ctx context.Context, db bun.IDB, table, column string,
+) (string, error) {
+	var typ string
+	err := db.NewSelect().
+		ColumnExpr("format_type(a.atttypid, a.atttypmod)").
+		TableExpr("pg_attribute AS a").
+		Where("a.attrelid = ?::regclass", table).
+		Where("a.attname = ?", column).
+		Where("NOT a.attisdropped").
+		Scan(ctx, &typ)
+	return typ, err
+}
+
+func (pgColumnSyncer) primaryKey(ctx context.Context, db bun.IDB, table string) (string, error) {
+	var columns []string
+	if err := db.NewSelect().
+		ColumnExpr("a.attname").
+		TableExpr("pg_index AS i").
+		Join("JOIN pg_attribute AS a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)").
+		Where("i.indrelid = ?::regclass", table).
+		Where("i.indisprimary").
+		Scan(ctx, &columns); err != nil {
+		return "", err
+	}
+	if len(columns) != 1 {
+		return "", nil
+	}
+	return columns[0], nil
+}
+
+func (pgColumnSyncer) createSync(ctx context.Context, db bun.IDB, r *ColumnRename) error {
+	name := bun.Ident(r.syncName())
+	oldCol := bun.Ident(r.OldName)
+	newCol := bun.Ident(r.NewName)
+
+	if _, err := db.ExecContext(ctx, `
+		CREATE OR REPLACE FUNCTION ?0() RETURNS trigger AS $$
+		BEGIN
+			IF TG_OP = 'INSERT' THEN
+				NEW.?2 := COALESCE(NEW.?2, NEW.?1);
+				NEW.?1 := COALESCE(NEW.?1, NEW.?2);
+			ELSIF NEW.?2 IS DISTINCT FROM OLD.?2 THEN
+				NEW.?1 := NEW.?2;
+			ELSIF NEW.?1 IS DISTINCT FROM OLD.?1 THEN
+				NEW.?2 := NEW.?1;
+			END IF;
+			RETURN NEW;
+		END
+		$$ LANGUAGE plpgsql
+	`, name, oldCol, newCol); err != nil {
+		return err
+	}
+
+	_, err := db.ExecContext(ctx,
+		"CREATE TRIGGER ? BEFORE INSERT OR UPDATE ON ? FOR EACH ROW EXECUTE PROCEDURE ?()",
+		name, bun.Ident(r.Table), name)
+	return err
+}
+
+func (pgColumnSyncer) dropSync(ctx context.Context, db bun.IDB, r *ColumnRename) error {
+	name := bun.Ident(r.syncName())
+	if _, err := db.ExecContext(ctx,
+		"DROP TRIGGER IF EXISTS ? ON ?", name, bun.Ident(r.Table)); err != nil {
+		return err
+	}
+	_, err := db.ExecContext(ctx, "DROP FUNCTION IF EXISTS ?()", name)
+	return err
+}
+
+type sqliteColumnSyncer struct{}
+
+func (sqliteColumnSyncer) columnType(
+	ctx context.Context, db bun.IDB, table, column string,
+) (string, error) {
+	var typ string
+	err := db.NewSelect().
+		ColumnExpr("type").
+		TableExpr("pragma_table_info(?)", table).
+		Where("name = ?", column).
+		Scan(ctx, &typ)
+	return typ, err
+}
+
+func (sqliteColumnSyncer) primaryKey(ctx context.Context, db bun.IDB, table string) (string, error) {
+	var columns []string
+	if err := db.NewSelect().
+		ColumnExpr("name").
+		TableExpr("pragma_table_info(?)", table).
+		Where("pk > 0").
+		Scan(ctx, &columns); err != nil {
+		return "", err
+	}
+	if len(columns) != 1 {
+		return "", nil
+	}
+	return columns[0], nil
+}
+
+// SQLite does not support modifying NEW in triggers, so the triggers update the row
+// after it is written. Recursive triggers are disabled by default, so the updates
+// don't fire the triggers again.
+func (sqliteColumnSyncer) createSync(ctx context.Context, db bun.IDB, r *ColumnRename) error {
+	name := r.syncName()
+	table := bun.Ident(r.Table)
+	oldCol := bun.Ident(r.OldName)
+	newCol := bun.Ident(r.NewName)
+
+	queries := []struct {
+		query string
+		args  []interface{}
+	}{
+		{
+			query: `CREATE TRIGGER ? AFTER INSERT ON ? FOR EACH ROW BEGIN
+				UPDATE ? SET ? = COALESCE(NEW.?, NEW.?), ? = COALESCE(NEW.?, NEW.?)
+				WHERE rowid = NEW.rowid;
+			END`,
+			args: []interface{}{
+				bun.Ident(name + "_insert"), table,
+				table, newCol, newCol, oldCol, oldCol, oldCol, newCol,
+			},
+		},
+		{
+			query: `CREATE TRIGGER ? AFTER UPDATE OF ? ON ? FOR EACH ROW
+			WHEN NEW.? IS NOT OLD.? BEGIN
+				UPDATE ? SET ? = NEW.? WHERE rowid = NEW.rowid;
+			END`,
+			args: []interface{}{
+				bun.Ident(name + "_update_old"), oldCol, table,
+				oldCol, oldCol,
+				table, newCol, oldCol,
+			},
+		},
+		{
+			query: `CREATE TRIGGER ? AFTER UPDATE OF ? ON ? FOR EACH ROW
+			WHEN NEW.? IS NOT OLD.? BEGIN
+				UPDATE ? SET ? = NEW.? WHERE rowid = NEW.rowid;
+			END`,
+			args: []interface{}{
+				bun.Ident(name + "_update_new"), newCol, table,
+				newCol, newCol,
+				table, oldCol, newCol,
+			},
+		},
+	}
+
+	for _, q := range queries {
+		if _, err := db.ExecContext(ctx, q.query, q.args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sqliteColumnSyncer) dropSync(ctx context.Context, db bun.IDB, r *ColumnRename) error {
+	name := r.syncName()
+	for _, suffix := range []string{"_insert", "_update_old", "_update_new"} {
+		if _, err := db.ExecContext(ctx,
+			"DROP TRIGGER IF EXISTS ?", bun.Ident(name+suffix)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,888 @@
+package sqlschema
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/migrate"
+	"github.com/uptrace/bun/schema"
+)
+
+// Change is a difference between a model and the live database table.
+// Changes describe what was changed in the database compared to the models,
+// for example, AddColumn is a column that exists only in the database.
+//
+// Change is one of AddColumn, DropColumn, AlterColumn, AlterColumnDefault,
+// AlterColumnExpression, AddIndex, DropIndex,
+// AddUniqueConstraint, DropUniqueConstraint, AddConstraint, AddCheck, DropCheck, DropSequence,
+// AlterSequence, DropView, AlterView, and PendingColumnRename. Changes are encoded to JSON as objects with the "type" field.
+type Change interface {
+	fmt.Stringer
+	json.Marshaler
+	change()
+}
+
+// AddColumn is a column that exists in the database, but not in the model.
+type AddColumn struct {
+	Table  string `json:"table"`
+	Column Column `json:"column"`
+}
+
+// DropColumn is a model column that is missing in the database.
+type DropColumn struct {
+	Table  string `json:"table"`
+	Column Column `json:"column"`
+}
+
+// AlterColumn is a column with a different type or nullability in the database.
+type AlterColumn struct {
+	Table string `json:"table"`
+	From  Column `json:"from"` // model column
+	To    Column `json:"to"`   // database column
+}
+
+// AlterColumnDefault is a column with a different default value in the database.
+// Defaults are compared as SQL expressions after removing the type casts and
+// the letter case differences outside of string literals, for example, now()
+// and CURRENT_TIMESTAMP are equal, but 'a' and 'A' are not.
+type AlterColumnDefault struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	From   string `json:"from,omitempty"` // model default
+	To     string `json:"to,omitempty"`   // database default
+}
+
+// Query returns the query that changes the default in the database to the model default
+// with ALTER COLUMN SET DEFAULT or ALTER COLUMN DROP DEFAULT.
+// SQLite doesn't support changing column defaults.
+func (c AlterColumnDefault) Query(db bun.IDB) *bun.RawQuery {
+	if c.From == "" {
+		return db.NewRaw("ALTER TABLE ? ALTER COLUMN ? DROP DEFAULT",
+			bun.Ident(c.Table), bun.Ident(c.Column))
+	}
+	return db.NewRaw("ALTER TABLE ? ALTER COLUMN ? SET DEFAULT ?",
+		bun.Ident(c.Table), bun.Ident(c.Column), bun.Safe(c.From))
+}
+
+// AlterColumnExpression is a generated column with a different expression in the database,
+// including a column that is generated only in the model or only in the database.
+// Expressions are compared like CHECK expressions. SQLite doesn't report the expressions,
+// so they are not compared.
+type AlterColumnExpression struct {
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	From   string `json:"from,omitempty"` // model expression
+	To     string `json:"to,omitempty"`   // database expression
+}
+
+// AddIndex is an index that exists in the database, but not in the model.
+type AddIndex struct {
+	Table string `json:"table"`
+	Index Index  `json:"index"`
+}
+
+// DropIndex is a model index that is missing in the database.
+type DropIndex struct {
+	Table string `json:"table"`
+	Index Index  `json:"index"`
+}
+
+// AddUniqueConstraint is a unique constraint that exists in the database,
+// but not in the model.
+type AddUniqueConstraint struct {
+	Table      string           `json:"table"`
+	Constraint UniqueConstraint `json:"constraint"`
+}
+
+// DropUniqueConstraint is a model unique constraint that is missing in the database.
+type DropUniqueConstraint struct {
+	Table      string           `json:"table"`
+	Constraint UniqueConstraint `json:"constraint"`
+}
+
+// AddConstraint is a foreign key that exists in the database, but is not
+// described by a model relation.
+type AddConstraint struct {
+	Table      string     `json:"table"`
+	ForeignKey ForeignKey `json:"foreign_key"`
+}
+
+// AddCheck is a CHECK constraint that exists in the database, but not in the model.
+type AddCheck struct {
+	Table string `json:"table"`
+	Check Check  `json:"check"`
+}
+
+// Query returns the query that drops the constraint from the database.
+func (c AddCheck) Query(db bun.IDB) *bun.RawQuery {
+	return db.NewRaw("ALTER TABLE ? DROP CONSTRAINT ?", bun.Ident(c.Table), bun.Ident(c.Check.Name))
+}
+
+// DropCheck is a model CHECK constraint that is missing in the database.
+type DropCheck struct {
+	Table string `json:"table"`
+	Check Check  `json:"check"`
+}
+
+// Query returns the query that adds the constraint to the database.
+func (c DropCheck) Query(db bun.IDB) *bun.RawQuery {
+	if c.Check.Name == "" {
+		return db.NewRaw("ALTER TABLE ? ADD CHECK (?)", bun.Ident(c.Table), bun.Safe(c.Check.Expression))
+	}
+	return db.NewRaw("ALTER TABLE ? ADD CONSTRAINT ? CHECK (?)",
+		bun.Ident(c.Table), bun.Ident(c.Check.Name), bun.Safe(c.Check.Expression))
+}
+
+// DropSequence is a sequence that is missing in the database.
+type DropSequence struct {
+	Sequence Sequence `json:"sequence"`
+}
+
+// AlterSequence is a sequence with different options in the database.
+type AlterSequence struct {
+	From Sequence `json:"from"` // expected sequence
+	To   Sequence `json:"to"`   // database sequence
+}
+
+// Query returns the query that changes the sequence options in the database
+// to the expected options.
+func (c AlterSequence) Query(db bun.IDB) *bun.AlterSequenceQuery {
+	q := db.NewAlterSequence(c.From.Name)
+	if c.From.Start != 0 {
+		q = q.Start(c.From.Start)
+	}
+	if c.From.Increment != 0 {
+		q = q.Increment(c.From.Increment)
+	}
+	if c.From.Min != 0 {
+		q = q.Min(c.From.Min)
+	}
+	if c.From.Max != 0 {
+		q = q.Max(c.From.Max)
+	}
+	if c.From.Cache != 0 {
+		q = q.Cache(c.From.Cache)
+	}
+	if c.From.Cycle {
+		q = q.Cycle()
+	} else {
+		q = q.NoCycle()
+	}
+	return q
+}
+
+// DropView is a view that is missing in the database.
+type DropView struct {
+	View View `json:"view"`
+}
+
+// AlterView is a view with a different definition in the database.
+type AlterView struct {
+	From View `json:"from"` // expected view
+	To   View `json:"to"`   // database view
+}
+
+// PendingColumnRename is a model column that is renamed with migrate.ColumnRename.
+// The model must use the new name before the rename is contracted.
+type PendingColumnRename struct {
+	Table   string `json:"table"`
+	OldName string `json:"old_name"`
+	NewName string `json:"new_name"`
+	Phase   string `json:"phase"`
+}
+
+func (AddColumn) change()             {}
+func (DropColumn) change()            {}
+func (AlterColumn) change()           {}
+func (AlterColumnDefault) change()    {}
+func (AlterColumnExpression) change() {}
+func (AddIndex) change()              {}
+func (DropIndex) change()             {}
+func (AddUniqueConstraint) change()   {}
+func (DropUniqueConstraint) change()  {}
+func (AddConstraint) change()         {}
+func (AddCheck) change()              {}
+func (DropCheck) change()             {}
+func (DropSequence) change()          {}
+func (AlterSequence) change()         {}
+func (DropView) change()              {}
+func (AlterView) change()             {}
+func (PendingColumnRename) change()   {}
+
+func (c AddColumn) String() string {
+	return fmt.Sprintf("%s: added column %s", c.Table, formatColumn(c.Column))
+}
+
+func (c DropColumn) String() string {
+	return fmt.Sprintf("%s: dropped column %s", c.Table, formatColumn(c.Column))
+}
+
+func (c AlterColumn) String() string {
+	return fmt.Sprintf("%s: altered column %s to %s",
+		c.Table, formatColumn(c.From), formatColumn(c.To))
+}
+
+func (c AlterColumnDefault) String() string {
+	return fmt.Sprintf("%s: altered column %s default from %s to %s",
+		c.Table, c.Column, formatDefault(c.From), formatDefault(c.To))
+}
+
+func (c AlterColumnExpression) String() string {
+	return fmt.Sprintf("%s: altered column %s expression from %s to %s",
+		c.Table, c.Column, formatDefault(c.From), formatDefault(c.To))
+}
+
+func (c AddIndex) String() string {
+	return fmt.Sprintf("%s: added %s", c.Table, formatIndex(c.Index))
+}
+
+func (c DropIndex) String() string {
+	return fmt.Sprintf("%s: dropped %s", c.Table, formatIndex(c.Index))
+}
+
+func (c AddUniqueConstraint) String() string {
+	return fmt.Sprintf("%s: added %s", c.Table, formatUniqueConstraint(c.Constraint))
+}
+
+func (c DropUniqueConstraint) String() string {
+	return fmt.Sprintf("%s: dropped %s", c.Table, formatUniqueConstraint(c.Constraint))
+}
+
+func (c AddConstraint) String() string {
+	fk := c.ForeignKey
+	return fmt.Sprintf("%s: added foreign key (%s) references %s (%s)",
+		c.Table, strings.Join(fk.Columns, ", "), fk.RefTable, strings.Join(fk.RefColumns, ", "))
+}
+
+func (c AddCheck) String() string {
+	return fmt.Sprintf("%s: added %s", c.Table, formatCheck(c.Check))
+}
+
+func (c DropCheck) String() string {
+	return fmt.Sprintf("%s: dropped %s", c.Table, formatCheck(c.Check))
+}
+
+func (c DropSequence) String() string {
+	return fmt.Sprintf("dropped sequence %s", c.Sequence.Name)
+}
+
+func (c AlterSequence) String() string {
+	return fmt.Sprintf("altered sequence %s: %s", c.From.Name, formatSequenceDiff(c.From, c.To))
+}
+
+func (c DropView) String() string {
+	return fmt.Sprintf("dropped %s", formatView(c.View))
+}
+
+func (c AlterView) String() string {
+	return fmt.Sprintf("altered %s", formatView(c.From))
+}
+
+func (c PendingColumnRename) String() string {
+	return fmt.Sprintf("%s: column %s is renamed to %s (%s)",
+		c.Table, c.OldName, c.NewName, c.Phase)
+}
+
+func (c AddColumn) MarshalJSON() ([]byte, error) {
+	type change AddColumn
+	return marshalChange("add_column", change(c))
+}
+
+func (c DropColumn) MarshalJSON() ([]byte, error) {
+	type change DropColumn
+	return marshalChange("drop_column", change(c))
+}
+
+func (c AlterColumn) MarshalJSON() ([]byte, error) {
+	type change AlterColumn
+	return marshalChange("alter_column", change(c))
+}
+
+func (c AlterColumnDefault) MarshalJSON() ([]byte, error) {
+	type change AlterColumnDefault
+	return marshalChange("alter_column_default", change(c))
+}
+
+func (c AlterColumnExpression) MarshalJSON() ([]byte, error) {
+	type change AlterColumnExpression
+	return marshalChange("alter_column_expression", change(c))
+}
+
+func (c AddIndex) MarshalJSON() ([]byte, error) {
+	type change AddIndex
+	return marshalChange("add_index", change(c))
+}
+
+func (c DropIndex) MarshalJSON() ([]byte, error) {
+	type change DropIndex
+	return marshalChange("drop_index", change(c))
+}
+
+func (c AddUniqueConstraint) MarshalJSON() ([]byte, error) {
+	type change AddUniqueConstraint
+	return marshalChange("add_unique_constraint", change(c))
+}
+
+func (c DropUniqueConstraint) MarshalJSON() ([]byte, error) {
+	type change DropUniqueConstraint
+	return marshalChange("drop_unique_constraint", change(c))
+}
+
+func (c AddConstraint) MarshalJSON() ([]byte, error) {
+	type change AddConstraint
+	return marshalChange("add_constraint", change(c))
+}
+
+func (c AddCheck) MarshalJSON() ([]byte, error) {
+	type change AddCheck
+	return marshalChange("add_check", change(c))
+}
+
+func (c DropCheck) MarshalJSON() ([]byte, error) {
+	type change DropCheck
+	return marshalChange("drop_check", change(c))
+}
+
+func (c DropSequence) MarshalJSON() ([]byte, error) {
+	type change DropSequence
+	return marshalChange("drop_sequence", change(c))
+}
+
+func (c AlterSequence) MarshalJSON() ([]byte, error) {
+	type change AlterSequence
+	return marshalChange("alter_sequence", change(c))
+}
+
+func (c DropView) MarshalJSON() ([]byte, error) {
+	type change DropView
+	return marshalChange("drop_view", change(c))
+}
+
+func (c AlterView) MarshalJSON() ([]byte, error) {
+	type change AlterView
+	return marshalChange("alter_view", change(c))
+}
+
+func (c PendingColumnRename) MarshalJSON() ([]byte, error) {
+	type change PendingColumnRename
+	return marshalChange("pending_column_rename", change(c))
+}
+
+func marshalChange(typ string, change interface{}) ([]byte, error) {
+	b, err := json.Marshal(change)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf(`{"type":%q,`, typ)
+	return append([]byte(prefix), b[1:]...), nil
+}
+
+func formatColumn(col Column) string {
+	null := "NOT NULL"
+	if col.IsNullable {
+		null = "NULL"
+	}
+	return fmt.Sprintf("%s %s %s", col.Name, col.SQLType, null)
+}
+
+func formatDefault(expr string) string {
+	if expr == "" {
+		return "none"
+	}
+	return expr
+}
+
+func formatSequenceDiff(from, to Sequence) string {
+	var diffs []string
+	for _, f := range sequenceFields(from, to) {
+		if f.from != f.to {
+			diffs = append(diffs, fmt.Sprintf("%s from %d to %d", f.name, f.from, f.to))
+		}
+	}
+	if from.Cycle != to.Cycle {
+		diffs = append(diffs, fmt.Sprintf("cycle from %t to %t", from.Cycle, to.Cycle))
+	}
+	return strings.Join(diffs, ", ")
+}
+
+func formatView(view View) string {
+	if view.Materialized {
+		return "materialized view " + view.Name
+	}
+	return "view " + view.Name
+}
+
+func formatIndex(idx Index) string {
+	kind := "index"
+	if idx.Unique {
+		kind = "unique index"
+	}
+	if idx.Name != "" {
+		kind += " " + idx.Name
+	}
+	return fmt.Sprintf("%s (%s)", kind, strings.Join(idx.Columns, ", "))
+}
+
+func formatUniqueConstraint(c UniqueConstraint) string {
+	kind := "unique constraint"
+	if c.Name != "" {
+		kind += " " + c.Name
+	}
+	return fmt.Sprintf("%s (%s)", kind, strings.Join(c.Columns, ", "))
+}
+
+func formatCheck(c Check) string {
+	kind := "check"
+	if c.Name != "" {
+		kind += " " + c.Name
+	}
+	return fmt.Sprintf("%s (%s)", kind, c.Expression)
+}
+
+//------------------------------------------------------------------------------
+
+// Diff compares the models with the live database tables and returns the changes.
+//
+// Models describe foreign keys only with relations, so foreign keys are only reported
+// when they exist in the database. The unique tag option describes unique constraints
+// and the index tag option describes indexes, see schema.IndexDef. MySQL doesn't
+// distinguish unique constraints from unique indexes, so they are compared as indexes.
+// The indexes that MySQL creates for foreign keys are ignored.
+//
+// Expression indexes, for example, the indexes created with CreateIndexQuery.ColumnExpr,
+// are out of scope: models can't describe them, so Diff never reports them as added
+// or dropped. Use Inspector.InspectTable to read them, see Index.Expression.
+//
+// CHECK constraints are defined with the check tag option and with schema.TableChecker.
+// They are compared by the expressions ignoring the whitespace, the parentheses, and the type casts,
+// so the expressions that the database rewrites can be reported as changed.
+// Only PostgreSQL and MySQL report CHECK constraints.
+//
+// Models that still use the old name of a column renamed with migrate.ColumnRename
+// are reported as PendingColumnRename, see migrate.CheckRenamedColumns.
+func Diff(ctx context.Context, db *bun.DB, models ...interface{}) ([]Change, error) {
+	insp, err := NewInspector(db)
+	if err != nil {
+		return nil, err
+	}
+
+	renames, err := migrate.RenamedColumns(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for _, model := range models {
+		table := db.Table(reflect.TypeOf(model))
+
+		live, err := insp.InspectTable(ctx, table.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, diffTable(table, live, db.Dialect().Name())...)
+
+		for _, rename := range renames {
+			if rename.Table != table.Name {
+				continue
+			}
+			if _, ok := table.FieldMap[rename.OldName]; ok {
+				changes = append(changes, PendingColumnRename{
+					Table:   rename.Table,
+					OldName: rename.OldName,
+					NewName: rename.NewName,
+					Phase:   rename.Phase,
+				})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// DiffSequences compares the expected sequences with the live database sequences
+// and returns the changes. Zero options of the expected sequences are not compared,
+// so only the options that were set when the sequence was created can be described.
+// Only PostgreSQL is supported.
+func DiffSequences(ctx context.Context, db *bun.DB, sequences ...Sequence) ([]Change, error) {
+	var changes []Change
+	for _, seq := range sequences {
+		live, err := inspectSequence(ctx, db, "", seq.Name)
+		if err != nil {
+			return nil, err
+		}
+		if live == nil {
+			changes = append(changes, DropSequence{Sequence: seq})
+			continue
+		}
+		live.Name = seq.Name
+
+		if !sameSequence(seq, *live) {
+			changes = append(changes, AlterSequence{From: seq, To: *live})
+		}
+	}
+	return changes, nil
+}
+
+type sequenceField struct {
+	name     string
+	from, to int64
+}
+
+// sequenceFields returns the options that are set in the expected sequence.
+func sequenceFields(from, to Sequence) []sequenceField {
+	var fields []sequenceField
+	for _, f := range []sequenceField{
+		{"start", from.Start, to.Start},
+		{"increment", from.Increment, to.Increment},
+		{"min", from.Min, to.Min},
+		{"max", from.Max, to.Max},
+		{"cache", from.Cache, to.Cache},
+	} {
+		if f.from != 0 {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func sameSequence(seq, live Sequence) bool {
+	for _, f := range sequenceFields(seq, live) {
+		if f.from != f.to {
+			return false
+		}
+	}
+	return seq.Cycle == live.Cycle
+}
+
+// DiffViews compares the expected views with the live database views and returns
+// the changes. The expected definitions are usually created with SelectQuery.String.
+//
+// PostgreSQL rewrites the view definitions, so the expected definitions are normalized
+// by creating temporary views. SQLite definitions are compared as written ignoring
+// the whitespace. PostgreSQL and SQLite are supported.
+func DiffViews(ctx context.Context, db *bun.DB, views ...View) ([]Change, error) {
+	liveViews, err := InspectViews(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	live := make(map[string]View, len(liveViews))
+	for _, view := range liveViews {
+		live[view.Name] = view
+	}
+
+	var changes []Change
+	for _, view := range views {
+		liveView, ok := live[view.Name]
+		if !ok {
+			changes = append(changes, DropView{View: view})
+			continue
+		}
+
+		def, err := normalizeViewDefinition(ctx, db, view.Definition)
+		if err != nil {
+			return nil, err
+		}
+		if view.Materialized != liveView.Materialized ||
+			def != normalizeSQL(liveView.Definition) {
+			changes = append(changes, AlterView{From: view, To: liveView})
+		}
+	}
+	return changes, nil
+}
+
+// normalizeViewDefinition returns the definition as the database would report it.
+func normalizeViewDefinition(ctx context.Context, db *bun.DB, def string) (string, error) {
+	if db.Dialect().Name() != dialect.PG {
+		return normalizeSQL(def), nil
+	}
+
+	// Temporary views are only visible to the connection that created them.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	const name = "bun_diff_view"
+	if _, err := conn.Conn.ExecContext(ctx, "CREATE TEMP VIEW "+name+" AS "+def); err != nil {
+		return "", err
+	}
+	defer func() {
+		_, _ = conn.Conn.ExecContext(ctx, "DROP VIEW IF EXISTS "+name)
+	}()
+
+	if err := conn.Conn.QueryRowContext(ctx,
+		"SELECT pg_get_viewdef('"+name+"'::regclass)").Scan(&def); err != nil {
+		return "", err
+	}
+	return normalizeSQL(def), nil
+}
+
+func normalizeSQL(query string) string {
+	query = spacesRE.ReplaceAllString(strings.TrimSpace(query), " ")
+	return strings.TrimSuffix(query, ";")
+}
+
+func diffTable(table *schema.Table, live *Table, dialectName dialect.Name) []Change {
+	var changes []Change
+	uniqueAsIndexes := dialectName == dialect.MySQL
+
+	for _, field := range table.Fields {
+		col := Column{
+			Name:       field.Name,
+			SQLType:    field.CreateTableSQLType,
+			IsNullable: !field.NotNull && !field.IsPK,
+			Default:    field.SQLDefault,
+		}
+
+		liveCol := live.Column(field.Name)
+		if liveCol == nil {
+			changes = append(changes, DropColumn{Table: table.Name, Column: col})
+			continue
+		}
+
+		if normalizeType(col.SQLType) != normalizeType(liveCol.SQLType) ||
+			col.IsNullable != liveCol.IsNullable {
+			changes = append(changes, AlterColumn{Table: table.Name, From: col, To: *liveCol})
+		}
+
+		// Sequences and generated columns use defaults that are not described by the model.
+		if !field.AutoIncrement && !field.Identity && field.SQLGenerated == "" &&
+			normalizeDefault(col.Default, dialectName) != normalizeDefault(liveCol.Default, dialectName) {
+			changes = append(changes, AlterColumnDefault{
+				Table:  table.Name,
+				Column: col.Name,
+				From:   col.Default,
+				To:     liveCol.Default,
+			})
+		}
+
+		if dialectName != dialect.SQLite &&
+			normalizeCheck(field.SQLGenerated) != normalizeCheck(liveCol.Expression) {
+			changes = append(changes, AlterColumnExpression{
+				Table:  table.Name,
+				Column: col.Name,
+				From:   field.SQLGenerated,
+				To:     liveCol.Expression,
+			})
+		}
+	}
+
+	for _, col := range live.Columns {
+		if _, ok := table.FieldMap[col.Name]; !ok {
+			changes = append(changes, AddColumn{Table: table.Name, Column: col})
+		}
+	}
+
+	// Models can't describe expression indexes, so they are ignored.
+	liveIndexes := make([]Index, 0, len(live.Indexes))
+	for _, idx := range live.Indexes {
+		if idx.Expression == "" {
+			liveIndexes = append(liveIndexes, idx)
+		}
+	}
+
+	constraints := modelUniqueConstraints(table)
+	unique := modelUniqueIndexes(table)
+	if uniqueAsIndexes {
+		for _, c := range constraints {
+			unique = append(unique, Index{Name: c.Name, Columns: c.Columns, Unique: true})
+		}
+		constraints = nil
+	}
+
+	for _, c := range constraints {
+		if !hasUniqueConstraint(live.UniqueConstraints, c.Columns) {
+			changes = append(changes, DropUniqueConstraint{Table: table.Name, Constraint: c})
+		}
+	}
+	for _, c := range live.UniqueConstraints {
+		if !hasUniqueConstraint(constraints, c.Columns) {
+			changes = append(changes, AddUniqueConstraint{Table: table.Name, Constraint: c})
+		}
+	}
+
+	for _, idx := range unique {
+		if !hasUniqueIndex(liveIndexes, idx.Columns) {
+			changes = append(changes, DropIndex{Table: table.Name, Index: idx})
+		}
+	}
+	for _, idx := range liveIndexes {
+		if idx.Unique && !hasUniqueIndex(unique, idx.Columns) {
+			changes = append(changes, AddIndex{Table: table.Name, Index: idx})
+		}
+	}
+
+	indexes := modelIndexes(table)
+	for _, idx := range indexes {
+		if !hasIndex(liveIndexes, idx.Columns) {
+			changes = append(changes, DropIndex{Table: table.Name, Index: idx})
+		}
+	}
+	for _, idx := range liveIndexes {
+		if idx.Unique || isForeignKeyIndex(live, idx) {
+			continue
+		}
+		if !hasIndex(indexes, idx.Columns) {
+			changes = append(changes, AddIndex{Table: table.Name, Index: idx})
+		}
+	}
+
+	for _, fk := range live.ForeignKeys {
+		if !hasRelation(table, fk) {
+			changes = append(changes, AddConstraint{Table: table.Name, ForeignKey: fk})
+		}
+	}
+
+	if dialectName == dialect.PG || dialectName == dialect.MySQL {
+		checks := modelChecks(table)
+		for _, c := range checks {
+			if !hasCheck(live.Checks, c.Expression) {
+				changes = append(changes, DropCheck{Table: table.Name, Check: c})
+			}
+		}
+		for _, c := range live.Checks {
+			if !hasCheck(checks, c.Expression) {
+				changes = append(changes, AddCheck{Table: table.Name, Check: c})
+			}
+		}
+	}
+
+	return changes
+}
+
+// modelChecks returns the CHECK constraints defined with the check tag option
+// and with schema.TableChecker.
+func modelChecks(table *schema.Table) []Check {
+	var checks []Check
+	for _, field := range table.Fields {
+		if field.SQLCheck != "" {
+			checks = append(checks, Check{Expression: field.SQLCheck})
+		}
+	}
+	for _, c := range table.Checks {
+		checks = append(checks, Check{Name: c.Name, Expression: c.Expr})
+	}
+	return checks
+}
+
+func hasCheck(checks []Check, expr string) bool {
+	expr = normalizeCheck(expr)
+	for _, c := range checks {
+		if normalizeCheck(c.Expression) == expr {
+			return true
+		}
+	}
+	return false
+}
+
+// modelUniqueConstraints returns the unique constraints defined with the unique tag option.
+func modelUniqueConstraints(table *schema.Table) []UniqueConstraint {
+	keys := make([]string, 0, len(table.Unique))
+	for key := range table.Unique {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var constraints []UniqueConstraint
+	for _, key := range keys {
+		fields := table.Unique[key]
+		if key == "" {
+			for _, field := range fields {
+				constraints = append(constraints, UniqueConstraint{Columns: []string{field.Name}})
+			}
+			continue
+		}
+
+		constraints = append(constraints, UniqueConstraint{Name: key, Columns: fieldNames(fields)})
+	}
+	return constraints
+}
+
+// modelUniqueIndexes returns the unique indexes defined with the index tag option.
+func modelUniqueIndexes(table *schema.Table) []Index {
+	var indexes []Index
+	for _, def := range table.Indexes {
+		if def.Unique {
+			indexes = append(indexes, Index{Name: def.Name, Columns: def.Columns, Unique: true})
+		}
+	}
+	return indexes
+}
+
+// modelIndexes returns the non-unique indexes defined with the index tag option.
+func modelIndexes(table *schema.Table) []Index {
+	var indexes []Index
+	for _, def := range table.Indexes {
+		if !def.Unique {
+			indexes = append(indexes, Index{Name: def.Name, Columns: def.Columns})
+		}
+	}
+	return indexes
+}
+
+func hasIndex(indexes []Index, columns []string) bool {
+	for _, idx := range indexes {
+		if !idx.Unique && sameColumns(idx.Columns, columns) {
+			return true
+		}
+	}
+	return false
+}
+
+// isForeignKeyIndex reports whether the index has the columns of a foreign key.
+// MySQL creates such indexes automatically.
+func isForeignKeyIndex(table *Table, idx Index) bool {
+	for _, fk := range table.ForeignKeys {
+		if sameColumns(fk.Columns, idx.Columns) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasUniqueIndex(indexes []Index, columns []string) bool {
+	for _, idx := range indexes {
+		if idx.Unique && sameColumnSet(idx.Columns, columns) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasUniqueConstraint(constraints []UniqueConstraint, columns []string) bool {
+	for _, c := range constraints {
+		if sameColumnSet(c.Columns, columns) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRelation(table *schema.Table, fk ForeignKey) bool {
+	for _, rel := range table.Relations {
+		if !rel.References() || rel.JoinTable.Name != strings.Trim(fk.RefTable, `"`) {
+			continue
+		}
+		if sameColumns(fieldNames(rel.BaseFields), fk.Columns) &&
+			sameColumns(fieldNames(rel.JoinFields), fk.RefColumns) {
+			return true
+		}
+	}
+	return false
+}
+
+func fieldNames(fields []*schema.Field) []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	return names
+}
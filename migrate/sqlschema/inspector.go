@@ -0,0 +1,721 @@
+package sqlschema
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// Inspector reads the schema of tables from a live database.
+type Inspector interface {
+	// InspectTable returns the table or an error if the table does not exist.
+	InspectTable(ctx context.Context, name string) (*Table, error)
+}
+
+// NewInspector returns an Inspector for the database dialect.
+// PostgreSQL, MySQL, and SQLite are supported.
+func NewInspector(db *bun.DB) (Inspector, error) {
+	switch db.Dialect().Name() {
+	case dialect.PG:
+		return &pgInspector{db: db}, nil
+	case dialect.MySQL:
+		return &mysqlInspector{db: db}, nil
+	case dialect.SQLite:
+		return &sqliteInspector{db: db}, nil
+	default:
+		return nil, fmt.Errorf("sqlschema: %s is not supported", db.Dialect().Name())
+	}
+}
+
+// InspectTable returns the columns, the primary key, indexes, unique constraints,
+// foreign keys, and CHECK constraints of the table in the schema. An empty schema means
+// the current schema, for example, the search_path in PostgreSQL.
+func InspectTable(ctx context.Context, db *bun.DB, schema, table string) (*Table, error) {
+	insp, err := NewInspector(db)
+	if err != nil {
+		return nil, err
+	}
+
+	name := table
+	if schema != "" {
+		name = schema + "." + table
+	}
+	return insp.InspectTable(ctx, name)
+}
+
+// InspectSequence returns the sequence in the schema. An empty schema means
+// the current schema. Only PostgreSQL is supported.
+func InspectSequence(ctx context.Context, db *bun.DB, schema, name string) (*Sequence, error) {
+	seq, err := inspectSequence(ctx, db, schema, name)
+	if err != nil {
+		return nil, err
+	}
+	if seq == nil {
+		return nil, fmt.Errorf("sqlschema: sequence %q does not exist", name)
+	}
+	return seq, nil
+}
+
+// inspectSequence returns nil if the sequence does not exist.
+func inspectSequence(ctx context.Context, db *bun.DB, schema, name string) (*Sequence, error) {
+	if db.Dialect().Name() != dialect.PG {
+		return nil, fmt.Errorf("sqlschema: sequences are not supported by %s", db.Dialect().Name())
+	}
+
+	if schema == "" {
+		schema, name = splitTableName(name)
+	}
+	var schemaArg interface{} = bun.Safe("current_schema()")
+	if schema != "" {
+		schemaArg = schema
+	}
+
+	seqs := make([]Sequence, 0, 1)
+	if err := db.NewSelect().
+		ColumnExpr("sequencename AS name").
+		ColumnExpr("start_value AS start").
+		ColumnExpr("increment_by AS increment").
+		ColumnExpr("min_value AS min").
+		ColumnExpr("max_value AS max").
+		ColumnExpr("cache_size AS cache").
+		ColumnExpr("cycle").
+		TableExpr("pg_sequences").
+		Where("schemaname = ?", schemaArg).
+		Where("sequencename = ?", name).
+		Scan(ctx, &seqs); err != nil {
+		return nil, err
+	}
+	if len(seqs) == 0 {
+		return nil, nil
+	}
+	return &seqs[0], nil
+}
+
+// InspectViews returns the views and materialized views in the current schema
+// ordered by name. PostgreSQL and SQLite are supported. PostgreSQL reports the view
+// definitions reconstructed from the parsed queries and SQLite reports them as written.
+func InspectViews(ctx context.Context, db *bun.DB) ([]View, error) {
+	var views []View
+	switch db.Dialect().Name() {
+	case dialect.PG:
+		if err := db.NewRaw(`
+			SELECT viewname AS name, definition, false AS materialized
+			FROM pg_views
+			WHERE schemaname = current_schema()
+			UNION ALL
+			SELECT matviewname AS name, definition, true AS materialized
+			FROM pg_matviews
+			WHERE schemaname = current_schema()
+			ORDER BY name
+		`).Scan(ctx, &views); err != nil {
+			return nil, err
+		}
+	case dialect.SQLite:
+		if err := db.NewSelect().
+			ColumnExpr("name").
+			ColumnExpr("sql AS definition").
+			TableExpr("sqlite_master").
+			Where("type = 'view'").
+			Order("name").
+			Scan(ctx, &views); err != nil {
+			return nil, err
+		}
+		// SQLite reports the CREATE VIEW statement.
+		for i := range views {
+			def := views[i].Definition
+			if j := strings.Index(strings.ToUpper(def), " AS "); j >= 0 {
+				views[i].Definition = strings.TrimSpace(def[j+len(" AS "):])
+			}
+		}
+	default:
+		return nil, fmt.Errorf("sqlschema: views are not supported by %s", db.Dialect().Name())
+	}
+	return views, nil
+}
+
+// InspectPartitions returns the partitions of the partitioned table ordered by name.
+// Only PostgreSQL is supported.
+func InspectPartitions(ctx context.Context, db *bun.DB, table string) ([]Partition, error) {
+	if db.Dialect().Name() != dialect.PG {
+		return nil, fmt.Errorf("sqlschema: partitions are not supported by %s", db.Dialect().Name())
+	}
+
+	var partitions []Partition
+	if err := db.NewSelect().
+		ColumnExpr("c.relname AS name").
+		ColumnExpr("pg_get_expr(c.relpartbound, c.oid) AS bound").
+		TableExpr("pg_inherits AS i").
+		Join("JOIN pg_class AS c ON c.oid = i.inhrelid").
+		Where("i.inhparent = to_regclass(?)", table).
+		Where("c.relispartition").
+		OrderExpr("c.relname").
+		Scan(ctx, &partitions); err != nil {
+		return nil, err
+	}
+	return partitions, nil
+}
+
+// InspectExtensions returns the extensions installed in the database ordered by name.
+// Only PostgreSQL is supported.
+func InspectExtensions(ctx context.Context, db *bun.DB) ([]Extension, error) {
+	if db.Dialect().Name() != dialect.PG {
+		return nil, fmt.Errorf("sqlschema: extensions are not supported by %s", db.Dialect().Name())
+	}
+
+	var extensions []Extension
+	if err := db.NewSelect().
+		ColumnExpr("e.extname AS name").
+		ColumnExpr("e.extversion AS version").
+		ColumnExpr("n.nspname AS schema").
+		TableExpr("pg_extension AS e").
+		Join("JOIN pg_namespace AS n ON n.oid = e.extnamespace").
+		OrderExpr("e.extname").
+		Scan(ctx, &extensions); err != nil {
+		return nil, err
+	}
+	return extensions, nil
+}
+
+//------------------------------------------------------------------------------
+
+type pgInspector struct {
+	db *bun.DB
+}
+
+func (insp *pgInspector) InspectTable(ctx context.Context, name string) (*Table, error) {
+	table := &Table{Name: name}
+
+	if err := insp.db.NewSelect().
+		ColumnExpr("a.attname AS name").
+		ColumnExpr("format_type(a.atttypid, a.atttypmod) AS sql_type").
+		ColumnExpr("NOT a.attnotnull AS is_nullable").
+		ColumnExpr(`CASE WHEN a.attgenerated = '' THEN pg_get_expr(d.adbin, d.adrelid) END AS "default"`).
+		ColumnExpr(`CASE WHEN a.attgenerated != '' THEN pg_get_expr(d.adbin, d.adrelid) END AS expression`).
+		TableExpr("pg_attribute AS a").
+		Join("LEFT JOIN pg_attrdef AS d ON d.adrelid = a.attrelid AND d.adnum = a.attnum").
+		Where("a.attrelid = to_regclass(?)", name).
+		Where("a.attnum > 0").
+		Where("NOT a.attisdropped").
+		Order("a.attnum").
+		Scan(ctx, &table.Columns); err != nil {
+		return nil, err
+	}
+	if len(table.Columns) == 0 {
+		return nil, fmt.Errorf("sqlschema: table %q does not exist", name)
+	}
+
+	var pk []string
+	if err := insp.db.NewSelect().
+		ColumnExpr("a.attname").
+		TableExpr("pg_index AS ix").
+		Join("JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k (attnum, n) ON true").
+		Join("JOIN pg_attribute AS a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum").
+		Where("ix.indrelid = to_regclass(?)", name).
+		Where("ix.indisprimary").
+		Order("k.n").
+		Scan(ctx, &pk); err != nil {
+		return nil, err
+	}
+	table.PrimaryKey = pk
+
+	var indexes []struct {
+		Name       string
+		Columns    string
+		Unique     bool
+		Expression string
+	}
+	if err := insp.db.NewSelect().
+		ColumnExpr("ic.relname AS name").
+		ColumnExpr("ix.indisunique AS unique").
+		ColumnExpr(`(
+			SELECT string_agg(a.attname, ',' ORDER BY k.n)
+			FROM unnest(ix.indkey) WITH ORDINALITY AS k (attnum, n)
+			JOIN pg_attribute AS a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum
+		) AS columns`).
+		ColumnExpr("pg_get_expr(ix.indexprs, ix.indrelid) AS expression").
+		TableExpr("pg_index AS ix").
+		Join("JOIN pg_class AS ic ON ic.oid = ix.indexrelid").
+		Where("ix.indrelid = to_regclass(?)", name).
+		Where("NOT ix.indisprimary").
+		// Indexes that back unique constraints are reported as constraints.
+		Where(`NOT EXISTS (
+			SELECT 1 FROM pg_constraint AS c
+			WHERE c.conindid = ix.indexrelid AND c.contype = 'u'
+		)`).
+		Order("ic.relname").
+		Scan(ctx, &indexes); err != nil {
+		return nil, err
+	}
+	for _, idx := range indexes {
+		table.Indexes = append(table.Indexes, Index{
+			Name:       idx.Name,
+			Columns:    splitColumns(idx.Columns),
+			Unique:     idx.Unique,
+			Expression: idx.Expression,
+		})
+	}
+
+	var uniques []struct {
+		Name    string
+		Columns string
+	}
+	if err := insp.db.NewSelect().
+		ColumnExpr("c.conname AS name").
+		ColumnExpr(`(
+			SELECT string_agg(a.attname, ',' ORDER BY k.n)
+			FROM unnest(c.conkey) WITH ORDINALITY AS k (attnum, n)
+			JOIN pg_attribute AS a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+		) AS columns`).
+		TableExpr("pg_constraint AS c").
+		Where("c.conrelid = to_regclass(?)", name).
+		Where("c.contype = 'u'").
+		Order("c.conname").
+		Scan(ctx, &uniques); err != nil {
+		return nil, err
+	}
+	for _, u := range uniques {
+		table.UniqueConstraints = append(table.UniqueConstraints, UniqueConstraint{
+			Name:    u.Name,
+			Columns: splitColumns(u.Columns),
+		})
+	}
+
+	var fks []struct {
+		Name       string
+		Columns    string
+		RefTable   string
+		RefColumns string
+		OnUpdate   string
+		OnDelete   string
+	}
+	if err := insp.db.NewSelect().
+		ColumnExpr("c.conname AS name").
+		ColumnExpr(`(
+			SELECT string_agg(a.attname, ',' ORDER BY k.n)
+			FROM unnest(c.conkey) WITH ORDINALITY AS k (attnum, n)
+			JOIN pg_attribute AS a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+		) AS columns`).
+		ColumnExpr("c.confrelid::regclass::text AS ref_table").
+		ColumnExpr(`(
+			SELECT string_agg(a.attname, ',' ORDER BY k.n)
+			FROM unnest(c.confkey) WITH ORDINALITY AS k (attnum, n)
+			JOIN pg_attribute AS a ON a.attrelid = c.confrelid AND a.attnum = k.attnum
+		) AS ref_columns`).
+		ColumnExpr("? AS on_update", pgRefAction("c.confupdtype")).
+		ColumnExpr("? AS on_delete", pgRefAction("c.confdeltype")).
+		TableExpr("pg_constraint AS c").
+		Where("c.conrelid = to_regclass(?)", name).
+		Where("c.contype = 'f'").
+		Order("c.conname").
+		Scan(ctx, &fks); err != nil {
+		return nil, err
+	}
+	for _, fk := range fks {
+		table.ForeignKeys = append(table.ForeignKeys, ForeignKey{
+			Name:       fk.Name,
+			Columns:    splitColumns(fk.Columns),
+			RefTable:   fk.RefTable,
+			RefColumns: splitColumns(fk.RefColumns),
+			OnUpdate:   fk.OnUpdate,
+			OnDelete:   fk.OnDelete,
+		})
+	}
+
+	var partitionBy sql.NullString
+	if err := insp.db.NewRaw("SELECT pg_get_partkeydef(to_regclass(?))", name).
+		Scan(ctx, &partitionBy); err != nil {
+		return nil, err
+	}
+	table.PartitionBy = partitionBy.String
+
+	if err := insp.db.NewSelect().
+		ColumnExpr("c.conname AS name").
+		ColumnExpr("pg_get_expr(c.conbin, c.conrelid) AS expression").
+		TableExpr("pg_constraint AS c").
+		Where("c.conrelid = to_regclass(?)", name).
+		Where("c.contype = 'c'").
+		Order("c.conname").
+		Scan(ctx, &table.Checks); err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+func pgRefAction(col string) bun.Safe {
+	return bun.Safe(`CASE ` + col + `
+		WHEN 'r' THEN 'RESTRICT'
+		WHEN 'c' THEN 'CASCADE'
+		WHEN 'n' THEN 'SET NULL'
+		WHEN 'd' THEN 'SET DEFAULT'
+		ELSE 'NO ACTION'
+	END`)
+}
+
+//------------------------------------------------------------------------------
+
+type mysqlInspector struct {
+	db *bun.DB
+}
+
+func (insp *mysqlInspector) InspectTable(ctx context.Context, name string) (*Table, error) {
+	schemaName, tableName := splitTableName(name)
+	table := &Table{Name: tableName}
+
+	var schemaArg interface{} = bun.Safe("DATABASE()")
+	if schemaName != "" {
+		schemaArg = schemaName
+	}
+
+	var version string
+	if err := insp.db.QueryRowContext(ctx, "SELECT version()").Scan(&version); err != nil {
+		return nil, err
+	}
+	mariaDB := strings.Contains(version, "MariaDB")
+
+	var columns []struct {
+		Name       string
+		SQLType    string
+		IsNullable bool
+		Default    sql.NullString
+		Expression string
+		DataType   string
+		Extra      string
+	}
+	if err := insp.db.NewSelect().
+		ColumnExpr("column_name AS name").
+		ColumnExpr("column_type AS sql_type").
+		ColumnExpr("is_nullable = 'YES' AS is_nullable").
+		ColumnExpr("column_default AS `default`").
+		ColumnExpr("generation_expression AS expression").
+		ColumnExpr("data_type").
+		ColumnExpr("extra").
+		TableExpr("information_schema.columns").
+		Where("table_schema = ?", schemaArg).
+		Where("table_name = ?", tableName).
+		Order("ordinal_position").
+		Scan(ctx, &columns); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("sqlschema: table %q does not exist", name)
+	}
+	for _, col := range columns {
+		table.Columns = append(table.Columns, Column{
+			Name:       col.Name,
+			SQLType:    col.SQLType,
+			IsNullable: col.IsNullable,
+			Default:    mysqlDefault(col.Default, col.DataType, col.Extra, mariaDB),
+			Expression: col.Expression,
+		})
+	}
+
+	// Expression indexes were added in MySQL 8.0.13.
+	hasExpressions, err := insp.db.NewSelect().
+		TableExpr("information_schema.columns").
+		Where("table_schema = 'information_schema'").
+		Where("table_name = 'STATISTICS'").
+		Where("column_name = 'EXPRESSION'").
+		Exists(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []struct {
+		Name       string
+		Columns    string
+		Unique     bool
+		Expression string
+	}
+	q := insp.db.NewSelect().
+		ColumnExpr("index_name AS name").
+		ColumnExpr("GROUP_CONCAT(column_name ORDER BY seq_in_index SEPARATOR ',') AS columns").
+		ColumnExpr("non_unique = 0 AS `unique`").
+		TableExpr("information_schema.statistics").
+		Where("table_schema = ?", schemaArg).
+		Where("table_name = ?", tableName).
+		GroupExpr("index_name, non_unique").
+		OrderExpr("index_name")
+	if hasExpressions {
+		q = q.ColumnExpr("GROUP_CONCAT(expression ORDER BY seq_in_index SEPARATOR ',') AS expression")
+	}
+	if err := q.Scan(ctx, &indexes); err != nil {
+		return nil, err
+	}
+	for _, idx := range indexes {
+		if idx.Name == "PRIMARY" {
+			table.PrimaryKey = splitColumns(idx.Columns)
+			continue
+		}
+		table.Indexes = append(table.Indexes, Index{
+			Name:       idx.Name,
+			Columns:    splitColumns(idx.Columns),
+			Unique:     idx.Unique,
+			Expression: idx.Expression,
+		})
+	}
+
+	var fks []struct {
+		Name       string
+		Columns    string
+		RefTable   string
+		RefColumns string
+		OnUpdate   string
+		OnDelete   string
+	}
+	if err := insp.db.NewSelect().
+		ColumnExpr("rc.constraint_name AS name").
+		ColumnExpr("GROUP_CONCAT(k.column_name ORDER BY k.ordinal_position SEPARATOR ',') AS columns").
+		ColumnExpr("rc.referenced_table_name AS ref_table").
+		ColumnExpr("GROUP_CONCAT(k.referenced_column_name ORDER BY k.ordinal_position SEPARATOR ',') AS ref_columns").
+		ColumnExpr("rc.update_rule AS on_update").
+		ColumnExpr("rc.delete_rule AS on_delete").
+		TableExpr("information_schema.referential_constraints AS rc").
+		Join(`JOIN information_schema.key_column_usage AS k
+			ON k.constraint_schema = rc.constraint_schema
+			AND k.constraint_name = rc.constraint_name
+			AND k.table_name = rc.table_name`).
+		Where("rc.constraint_schema = ?", schemaArg).
+		Where("rc.table_name = ?", tableName).
+		GroupExpr("rc.constraint_name, rc.referenced_table_name, rc.update_rule, rc.delete_rule").
+		OrderExpr("rc.constraint_name").
+		Scan(ctx, &fks); err != nil {
+		return nil, err
+	}
+	for _, fk := range fks {
+		table.ForeignKeys = append(table.ForeignKeys, ForeignKey{
+			Name:       fk.Name,
+			Columns:    splitColumns(fk.Columns),
+			RefTable:   fk.RefTable,
+			RefColumns: splitColumns(fk.RefColumns),
+			OnUpdate:   fk.OnUpdate,
+			OnDelete:   fk.OnDelete,
+		})
+	}
+
+	// MySQL 5.7 doesn't have CHECK constraints.
+	hasChecks, err := insp.db.NewSelect().
+		TableExpr("information_schema.tables").
+		Where("table_schema = 'information_schema'").
+		Where("table_name = 'CHECK_CONSTRAINTS'").
+		Exists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if hasChecks {
+		if err := insp.db.NewSelect().
+			ColumnExpr("cc.constraint_name AS name").
+			ColumnExpr("cc.check_clause AS expression").
+			TableExpr("information_schema.check_constraints AS cc").
+			Join(`JOIN information_schema.table_constraints AS tc
+				ON tc.constraint_schema = cc.constraint_schema
+				AND tc.constraint_name = cc.constraint_name`).
+			Where("tc.table_schema = ?", schemaArg).
+			Where("tc.table_name = ?", tableName).
+			Where("tc.constraint_type = 'CHECK'").
+			OrderExpr("cc.constraint_name").
+			Scan(ctx, &table.Checks); err != nil {
+			return nil, err
+		}
+	}
+
+	return table, nil
+}
+
+// mysqlDefault returns the column default as an SQL expression. MySQL reports
+// string literals without quotes, MariaDB quotes them, but reports no default as NULL.
+func mysqlDefault(def sql.NullString, dataType, extra string, mariaDB bool) string {
+	switch {
+	case !def.Valid:
+		return ""
+	case mariaDB:
+		if def.String == "NULL" {
+			return ""
+		}
+		return def.String
+	case strings.Contains(extra, "DEFAULT_GENERATED"),
+		strings.HasPrefix(strings.ToUpper(def.String), "CURRENT_TIMESTAMP"):
+		return def.String
+	}
+
+	switch strings.ToLower(dataType) {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint",
+		"decimal", "numeric", "float", "double", "real", "bit", "year":
+		return def.String
+	}
+	return "'" + strings.ReplaceAll(def.String, "'", "''") + "'"
+}
+
+//------------------------------------------------------------------------------
+
+type sqliteInspector struct {
+	db *bun.DB
+}
+
+func (insp *sqliteInspector) InspectTable(ctx context.Context, name string) (*Table, error) {
+	schemaName, tableName := splitTableName(name)
+	if schemaName == "" {
+		schemaName = "main"
+	}
+	table := &Table{Name: tableName}
+
+	var columns []struct {
+		Column
+		PK int
+	}
+	if err := insp.db.NewSelect().
+		ColumnExpr("name").
+		ColumnExpr("type AS sql_type").
+		// SQLite allows NULL in primary keys that are not INTEGER PRIMARY KEY,
+		// but bun always creates them as NOT NULL.
+		ColumnExpr(`"notnull" = 0 AND pk = 0 AS is_nullable`).
+		ColumnExpr(`dflt_value AS "default"`).
+		ColumnExpr("pk").
+		// pragma_table_info doesn't report generated columns.
+		TableExpr("pragma_table_xinfo(?, ?)", tableName, schemaName).
+		// Hidden columns of virtual tables.
+		Where("hidden != 1").
+		Order("cid").
+		Scan(ctx, &columns); err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("sqlschema: table %q does not exist", name)
+	}
+
+	pk := make(map[int]string)
+	for _, col := range columns {
+		table.Columns = append(table.Columns, col.Column)
+		if col.PK > 0 {
+			pk[col.PK] = col.Name
+		}
+	}
+	for i := 1; i <= len(pk); i++ {
+		table.PrimaryKey = append(table.PrimaryKey, pk[i])
+	}
+
+	var indexes []struct {
+		Name   string
+		Unique bool
+		Origin string
+	}
+	if err := insp.db.NewSelect().
+		ColumnExpr("name").
+		ColumnExpr(`"unique"`).
+		ColumnExpr("origin").
+		TableExpr("pragma_index_list(?, ?)", tableName, schemaName).
+		Where("origin != 'pk'").
+		Order("name").
+		Scan(ctx, &indexes); err != nil {
+		return nil, err
+	}
+	for _, idx := range indexes {
+		var columns []string
+		if err := insp.db.NewSelect().
+			ColumnExpr("name").
+			TableExpr("pragma_index_info(?, ?)", idx.Name, schemaName).
+			// Expression columns don't have a name.
+			Where("name IS NOT NULL").
+			Order("seqno").
+			Scan(ctx, &columns); err != nil {
+			return nil, err
+		}
+
+		// SQLite creates the indexes named sqlite_autoindex_* for unique constraints
+		// and doesn't keep the constraint names.
+		if idx.Origin == "u" {
+			table.UniqueConstraints = append(table.UniqueConstraints, UniqueConstraint{
+				Columns: columns,
+			})
+			continue
+		}
+		expr, err := insp.indexExpression(ctx, schemaName, idx.Name)
+		if err != nil {
+			return nil, err
+		}
+		table.Indexes = append(table.Indexes, Index{
+			Name:       idx.Name,
+			Columns:    columns,
+			Unique:     idx.Unique,
+			Expression: expr,
+		})
+	}
+
+	var fkColumns []struct {
+		ID       int
+		RefTable string `bun:"table"`
+		From     string
+		To       string
+		OnUpdate string
+		OnDelete string
+	}
+	if err := insp.db.NewSelect().
+		ColumnExpr(`id, "table", "from", "to", on_update, on_delete`).
+		TableExpr("pragma_foreign_key_list(?, ?)", tableName, schemaName).
+		Order("id", "seq").
+		Scan(ctx, &fkColumns); err != nil {
+		return nil, err
+	}
+	for i, col := range fkColumns {
+		if i == 0 || col.ID != fkColumns[i-1].ID {
+			table.ForeignKeys = append(table.ForeignKeys, ForeignKey{
+				RefTable: col.RefTable,
+				OnUpdate: col.OnUpdate,
+				OnDelete: col.OnDelete,
+			})
+		}
+		fk := &table.ForeignKeys[len(table.ForeignKeys)-1]
+		fk.Columns = append(fk.Columns, col.From)
+		fk.RefColumns = append(fk.RefColumns, col.To)
+	}
+
+	return table, nil
+}
+
+// indexExpression returns the indexed columns and expressions as written in CREATE INDEX
+// if the index has expressions.
+func (insp *sqliteInspector) indexExpression(
+	ctx context.Context, schemaName, indexName string,
+) (string, error) {
+	// Expressions are reported with cid -2.
+	hasExpr, err := insp.db.NewSelect().
+		TableExpr("pragma_index_info(?, ?)", indexName, schemaName).
+		Where("cid = -2").
+		Exists(ctx)
+	if err != nil || !hasExpr {
+		return "", err
+	}
+
+	var query string
+	if err := insp.db.NewSelect().
+		ColumnExpr("sql").
+		TableExpr("?.sqlite_master", bun.Ident(schemaName)).
+		Where("type = 'index'").
+		Where("name = ?", indexName).
+		Scan(ctx, &query); err != nil {
+		return "", err
+	}
+
+	// CREATE INDEX name ON table (columns) [WHERE ...]
+	start := strings.IndexByte(query, '(')
+	if start == -1 {
+		return query, nil
+	}
+	var depth int
+	for i := start; i < len(query); i++ {
+		switch query[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return strings.TrimSpace(query[start+1 : i]), nil
+			}
+		}
+	}
+	return query, nil
+}
@@ -0,0 +1,285 @@
+package sqlschema
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/uptrace/bun/dialect"
+)
+
+// Table is a database table as seen by an Inspector.
+type Table struct {
+	Name       string   `json:"name"`
+	Columns    []Column `json:"columns"`
+	PrimaryKey []string `json:"primary_key,omitempty"`
+	// Indexes don't include the indexes that back unique constraints.
+	Indexes           []Index            `json:"indexes,omitempty"`
+	UniqueConstraints []UniqueConstraint `json:"unique_constraints,omitempty"`
+	ForeignKeys       []ForeignKey       `json:"foreign_keys,omitempty"`
+	Checks            []Check            `json:"checks,omitempty"`
+	// PartitionBy is the partitioning strategy and key of a partitioned table,
+	// for example, RANGE (created_at). Only PostgreSQL reports it.
+	PartitionBy string `json:"partition_by,omitempty"`
+}
+
+// Column returns the column with the name or nil.
+func (t *Table) Column(name string) *Column {
+	for i := range t.Columns {
+		if t.Columns[i].Name == name {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+type Column struct {
+	Name       string `json:"name"`
+	SQLType    string `json:"sql_type"`
+	IsNullable bool   `json:"is_nullable"`
+	// Default is the SQL expression of the column default value.
+	Default string `json:"default,omitempty"`
+	// Expression is the SQL expression of a generated column.
+	// SQLite doesn't report it.
+	Expression string `json:"expression,omitempty"`
+}
+
+// Index is a secondary index. Primary keys are not reported as indexes.
+type Index struct {
+	Name    string   `json:"name,omitempty"`
+	Columns []string `json:"columns"`
+	Unique  bool     `json:"unique"`
+	// Expression is the SQL expression of an expression index. MySQL reports the
+	// indexed expressions separated by commas and SQLite reports the indexed columns
+	// and expressions as written in CREATE INDEX.
+	Expression string `json:"expression,omitempty"`
+}
+
+// UniqueConstraint is a UNIQUE table constraint, for example, the one created
+// for the unique tag option.
+//
+// MySQL doesn't distinguish unique constraints from unique indexes and reports
+// both as unique indexes. SQLite doesn't report constraint names.
+type UniqueConstraint struct {
+	Name    string   `json:"name,omitempty"`
+	Columns []string `json:"columns"`
+}
+
+// Sequence is a standalone sequence. Only PostgreSQL reports sequences.
+type Sequence struct {
+	Name      string `json:"name"`
+	Start     int64  `json:"start,omitempty"`
+	Increment int64  `json:"increment,omitempty"`
+	Min       int64  `json:"min,omitempty"`
+	Max       int64  `json:"max,omitempty"`
+	Cache     int64  `json:"cache,omitempty"`
+	Cycle     bool   `json:"cycle"`
+}
+
+// View is a view or a materialized view. Definition is the SELECT query of the view.
+type View struct {
+	Name         string `json:"name"`
+	Definition   string `json:"definition"`
+	Materialized bool   `json:"materialized,omitempty"`
+}
+
+// Partition is a partition of a PostgreSQL partitioned table.
+type Partition struct {
+	Name string `json:"name"`
+	// Bound is the partition bound, for example,
+	// FOR VALUES FROM ('2024-01-01') TO ('2024-02-01') or DEFAULT.
+	Bound string `json:"bound"`
+}
+
+// Extension is an installed PostgreSQL extension.
+type Extension struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Schema  string `json:"schema"`
+}
+
+type ForeignKey struct {
+	Name       string   `json:"name,omitempty"`
+	Columns    []string `json:"columns"`
+	RefTable   string   `json:"ref_table"`
+	RefColumns []string `json:"ref_columns"`
+	OnUpdate   string   `json:"on_update,omitempty"`
+	OnDelete   string   `json:"on_delete,omitempty"`
+}
+
+// Check is a CHECK constraint. SQLite doesn't report CHECK constraints.
+type Check struct {
+	Name       string `json:"name,omitempty"`
+	Expression string `json:"expression"`
+}
+
+//------------------------------------------------------------------------------
+
+var (
+	typeParamsRE = regexp.MustCompile(`\s*\([^)]*\)`)
+	spacesRE     = regexp.MustCompile(`\s+`)
+)
+
+// typeAliases maps SQL types to the names reported by PostgreSQL.
+var typeAliases = map[string]string{
+	"int":         "integer",
+	"int2":        "smallint",
+	"int4":        "integer",
+	"int8":        "bigint",
+	"smallserial": "smallint",
+	"serial":      "integer",
+	"bigserial":   "bigint",
+	"bool":        "boolean",
+	"float4":      "real",
+	"float8":      "double precision",
+	"decimal":     "numeric",
+	"char":        "character",
+	"varchar":     "character varying",
+	"timestamp":   "timestamp without time zone",
+	"timestamptz": "timestamp with time zone",
+	"time":        "time without time zone",
+	"timetz":      "time with time zone",
+}
+
+// normalizeType returns the SQL type without parameters (e.g. the VARCHAR length)
+// and with the aliases resolved, so the types can be compared.
+func normalizeType(typ string) string {
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	typ = typeParamsRE.ReplaceAllString(typ, "")
+	typ = spacesRE.ReplaceAllString(typ, " ")
+
+	var suffix string
+	if strings.HasSuffix(typ, "[]") {
+		typ, suffix = strings.TrimSuffix(typ, "[]"), "[]"
+	}
+	if alias, ok := typeAliases[typ]; ok {
+		typ = alias
+	}
+	return typ + suffix
+}
+
+var pgCastRE = regexp.MustCompile(`(?i)::[a-z ]+(\[\])?$`)
+
+// normalizeDefault returns the default expression without the type casts
+// (e.g. 'x'::character varying) and the enclosing parentheses, so the defaults
+// reported by the databases can be compared with the model defaults.
+// The letter case is ignored outside of the quoted literals.
+func normalizeDefault(expr string, dialectName dialect.Name) string {
+	expr = strings.TrimSpace(expr)
+	for {
+		prev := expr
+		expr = pgCastRE.ReplaceAllString(expr, "")
+		if isEnclosed(expr) {
+			expr = strings.TrimSpace(expr[1 : len(expr)-1])
+		}
+		if expr == prev {
+			break
+		}
+	}
+	expr = toLowerUnquoted(expr)
+
+	switch expr {
+	case "current_timestamp", "current_timestamp()", "now()":
+		return "now()"
+	case "null":
+		return ""
+	}
+	if dialectName == dialect.MySQL {
+		// MySQL stores booleans as TINYINT(1).
+		switch expr {
+		case "false":
+			return "0"
+		case "true":
+			return "1"
+		}
+	}
+	return expr
+}
+
+// toLowerUnquoted returns the expression with the letters outside of the single-quoted
+// literals in lower case.
+func toLowerUnquoted(expr string) string {
+	b := []byte(expr)
+	var quoted bool
+	for i, c := range b {
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case !quoted && c >= 'A' && c <= 'Z':
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+var checkCastRE = regexp.MustCompile(
+	`::[a-z_]+(\s+(varying|precision|without time zone|with time zone))?(\[\])?`)
+
+// normalizeCheck returns the CHECK expression without the type casts, the parentheses,
+// the identifier quotes, and the whitespace, so the expressions reported by the databases,
+// for example, (balance >= (0)::numeric), can be compared with the model expressions.
+func normalizeCheck(expr string) string {
+	expr = checkCastRE.ReplaceAllString(strings.ToLower(expr), "")
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '(', ')', '"', '`', ' ', '\t', '\n', '\r':
+			return -1
+		}
+		return r
+	}, expr)
+}
+
+// isEnclosed reports whether the whole expression is enclosed in parentheses.
+func isEnclosed(expr string) bool {
+	if !strings.HasPrefix(expr, "(") || !strings.HasSuffix(expr, ")") {
+		return false
+	}
+	var depth int
+	for i, c := range expr {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 && i != len(expr)-1 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// splitTableName splits "schema.table" into the schema and the table name.
+func splitTableName(name string) (schema, table string) {
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		return name[:i], name[i+1:]
+	}
+	return "", name
+}
+
+func splitColumns(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+func sameColumns(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameColumnSet(a, b []string) bool {
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return sameColumns(a, b)
+}
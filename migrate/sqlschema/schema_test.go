@@ -0,0 +1,104 @@
+package sqlschema
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun/dialect"
+)
+
+func TestNormalizeType(t *testing.T) {
+	tests := []struct {
+		model string
+		live  string
+	}{
+		{"BIGSERIAL", "bigint"},
+		{"VARCHAR", "character varying(255)"},
+		{"varchar(100)", "character varying(100)"},
+		{"TIMESTAMPTZ", "timestamp with time zone"},
+		{"TIMESTAMP", "timestamp without time zone"},
+		{"BOOLEAN", "boolean"},
+		{"DOUBLE PRECISION", "double precision"},
+		{"bigint[]", "int8[]"},
+		{"numeric(10, 2)", "numeric(10,2)"},
+	}
+	for _, test := range tests {
+		require.Equal(t, normalizeType(test.model), normalizeType(test.live), test.model)
+	}
+
+	require.NotEqual(t, normalizeType("BIGINT"), normalizeType("integer"))
+}
+
+func TestNormalizeDefault(t *testing.T) {
+	tests := []struct {
+		model string
+		live  string
+	}{
+		{"", ""},
+		{"0", "0"},
+		{"'hello'", "'hello'::character varying"},
+		{"current_timestamp", "CURRENT_TIMESTAMP"},
+		{"now()", "CURRENT_TIMESTAMP"},
+		{"current_timestamp", "(now())"},
+		{"'{}'", "'{}'::jsonb"},
+		{"'a'", "('a'::text)"},
+		{"'Hello'", "'Hello'::character varying"},
+		{"current_timestamp", "CURRENT_TIMESTAMP()"},
+	}
+	for _, test := range tests {
+		require.Equal(t,
+			normalizeDefault(test.model, dialect.PG), normalizeDefault(test.live, dialect.PG), test.model)
+	}
+
+	require.NotEqual(t, normalizeDefault("0", dialect.PG), normalizeDefault("1", dialect.PG))
+	require.NotEqual(t, normalizeDefault("'Hello'", dialect.PG), normalizeDefault("'hello'", dialect.PG))
+	require.Equal(t, "(1) + (2)", normalizeDefault("(1) + (2)", dialect.PG))
+	require.Equal(t, "upper('Hello')", normalizeDefault("UPPER('Hello')", dialect.PG))
+
+	require.Equal(t, normalizeDefault("false", dialect.MySQL), normalizeDefault("0", dialect.MySQL))
+	require.NotEqual(t, normalizeDefault("false", dialect.PG), normalizeDefault("0", dialect.PG))
+}
+
+func TestMySQLDefault(t *testing.T) {
+	tests := []struct {
+		def      sql.NullString
+		dataType string
+		extra    string
+		mariaDB  bool
+		want     string
+	}{
+		{sql.NullString{}, "varchar", "", false, ""},
+		{sql.NullString{String: "hello", Valid: true}, "varchar", "", false, "'hello'"},
+		{sql.NullString{String: "it's", Valid: true}, "text", "", false, "'it''s'"},
+		{sql.NullString{String: "", Valid: true}, "varchar", "", false, "''"},
+		{sql.NullString{String: "0", Valid: true}, "bigint", "", false, "0"},
+		{sql.NullString{String: "CURRENT_TIMESTAMP", Valid: true}, "datetime", "DEFAULT_GENERATED", false, "CURRENT_TIMESTAMP"},
+		{sql.NullString{String: "CURRENT_TIMESTAMP", Valid: true}, "timestamp", "", false, "CURRENT_TIMESTAMP"},
+		{sql.NullString{String: "uuid()", Valid: true}, "varchar", "DEFAULT_GENERATED", false, "uuid()"},
+		{sql.NullString{String: "NULL", Valid: true}, "varchar", "", true, ""},
+		{sql.NullString{String: "'hello'", Valid: true}, "varchar", "", true, "'hello'"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.want, mysqlDefault(test.def, test.dataType, test.extra, test.mariaDB))
+	}
+}
+
+func TestNormalizeCheck(t *testing.T) {
+	tests := []struct {
+		model string
+		live  string
+	}{
+		{"balance >= 0", "(balance >= (0)::numeric)"},
+		{"balance >= 0", "(`balance` >= 0)"},
+		{"name <> ''", `("name" <> ''::character varying)`},
+		{"created_at < now()", "(created_at < now())"},
+		{"price > 0 AND price < 100", "((price > (0)::double precision) AND (price < (100)::double precision))"},
+	}
+	for _, test := range tests {
+		require.Equal(t, normalizeCheck(test.model), normalizeCheck(test.live), test.model)
+	}
+
+	require.NotEqual(t, normalizeCheck("balance >= 0"), normalizeCheck("balance > 0"))
+}
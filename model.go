@@ -3,6 +3,7 @@ package bun
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"reflect"
@@ -13,9 +14,14 @@ import (
 
 var errNilModel = errors.New("bun: Model(nil)")
 
+// ErrTooManyRows is returned by SelectQuery.Find when the query returns more than one row.
+var ErrTooManyRows = errors.New("bun: query returned more than one row")
+
 var (
 	timeType  = reflect.TypeOf((*time.Time)(nil)).Elem()
 	bytesType = reflect.TypeOf((*[]byte)(nil)).Elem()
+
+	driverValuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
 )
 
 type Model = schema.Model
@@ -45,6 +51,7 @@ type TableModel interface {
 	mount(reflect.Value)
 
 	updateSoftDeleteField(time.Time) error
+	generateIDs() error
 }
 
 func newModel(db *DB, dest []interface{}) (Model, error) {
@@ -86,6 +93,11 @@ func _newModel(db *DB, dest interface{}, scan bool) (Model, error) {
 			return nil, fmt.Errorf("bun: Model(unsupported %T)", dest)
 		}
 		return newScanModel(db, []interface{}{dest}), nil
+	case map[string]interface{}:
+		if !scan {
+			// Maps are references, so there is no need for a pointer to use the map as a model.
+			return newMapModel(db, &dest), nil
+		}
 	}
 
 	v := reflect.ValueOf(dest)
@@ -111,6 +123,9 @@ func _newModel(db *DB, dest interface{}, scan bool) (Model, error) {
 	case timeType, bytesType:
 		return newScanModel(db, []interface{}{dest}), nil
 	}
+	if scan && db.dialect.Tables().Types().Scanner(typ) != nil {
+		return newScanModel(db, []interface{}{dest}), nil
+	}
 
 	switch v.Kind() {
 	case reflect.Map:
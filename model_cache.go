@@ -0,0 +1,321 @@
+package bun
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultModelCacheSize = 1000
+
+// WithModelCacheSize sets the maximum number of models cached by the queries created
+// with NewCachedSelect. The least recently used models are evicted first.
+// The default size is 1000.
+func WithModelCacheSize(n int) DBOption {
+	return func(db *DB) {
+		db.modelCache.size = n
+	}
+}
+
+// NewCachedSelect returns a SelectQuery that caches the model selected by
+// the primary key in memory for the ttl:
+//
+//	user := &User{ID: id}
+//	err := db.NewCachedSelect(time.Minute).Model(user).WherePK().Scan(ctx)
+//
+// Only the queries that select a single struct model with a single primary key
+// using WherePK and without other conditions, columns, or relations are cached.
+// Other queries are executed as usual. The cache is shared by the DB and its clones.
+// The models are cached separately for each search path set with WithSearchPath
+// and each role set with WithRole. DBs that use RLSHook or WithConnectionInitFunc
+// don't cache models, because the selected rows can depend on the session settings.
+//
+// INSERT, UPDATE, and DELETE queries evict the cached models they change:
+// the queries with WherePK evict the models by the primary keys and other queries
+// evict all models of the table. Queries created with NewRaw evict all models
+// unless they are SELECT queries. Queries executed with ExecContext don't evict models.
+// The cached models are shallow copies, so slices, maps, and pointers
+// in the selected models must not be modified.
+func (db *DB) NewCachedSelect(ttl time.Duration) *SelectQuery {
+	q := NewSelectQuery(db)
+	q.cacheTTL = ttl
+	return q
+}
+
+type modelCacheKey struct {
+	table string
+	pk    string
+
+	// The search path and the role change the selected model,
+	// so they are a part of the key.
+	searchPath string
+	role       string
+}
+
+type modelCacheEntry struct {
+	key       modelCacheKey
+	strct     reflect.Value
+	expiresAt time.Time
+}
+
+type modelCache struct {
+	mu       sync.Mutex
+	size     int
+	ll       *list.List
+	entries  map[modelCacheKey]*list.Element
+	versions map[string]uint64 // table -> the number of invalidations
+	used     bool
+}
+
+func newModelCache() *modelCache {
+	return &modelCache{
+		size:     defaultModelCacheSize,
+		ll:       list.New(),
+		entries:  make(map[modelCacheKey]*list.Element),
+		versions: make(map[string]uint64),
+	}
+}
+
+// load copies the cached model into strct and reports whether the model was found.
+func (c *modelCache) load(key modelCacheKey, strct reflect.Value) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+	entry := el.Value.(*modelCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.remove(el)
+		return false
+	}
+
+	c.ll.MoveToFront(el)
+	strct.Set(entry.strct)
+	return true
+}
+
+func (c *modelCache) version(table string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.used = true
+	version, ok := c.versions[table]
+	if !ok {
+		// Register the table, so evictAll changes its version.
+		c.versions[table] = 0
+	}
+	return version
+}
+
+// store caches a copy of the model unless the table was changed after the version was taken,
+// because the model could be selected before the change.
+func (c *modelCache) store(key modelCacheKey, strct reflect.Value, ttl time.Duration, version uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.size <= 0 || c.versions[key.table] != version {
+		return
+	}
+
+	clone := reflect.New(strct.Type()).Elem()
+	clone.Set(strct)
+	entry := &modelCacheEntry{key: key, strct: clone, expiresAt: time.Now().Add(ttl)}
+
+	if el, ok := c.entries[key]; ok {
+		el.Value = entry
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.ll.PushFront(entry)
+	for c.ll.Len() > c.size {
+		c.remove(c.ll.Back())
+	}
+}
+
+func (c *modelCache) remove(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.entries, el.Value.(*modelCacheEntry).key)
+}
+
+func (c *modelCache) evict(table string, pks []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.versions[table]++
+	for key, el := range c.entries {
+		if key.table == table && slices.Contains(pks, key.pk) {
+			c.remove(el)
+		}
+	}
+}
+
+func (c *modelCache) evictTable(table string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.versions[table]++
+	for key, el := range c.entries {
+		if key.table == table {
+			c.remove(el)
+		}
+	}
+}
+
+func (c *modelCache) evictAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for table := range c.versions {
+		c.versions[table]++
+	}
+	for _, el := range c.entries {
+		c.remove(el)
+	}
+}
+
+// isUsed reports whether NewCachedSelect queries have been executed.
+func (c *modelCache) isUsed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.used
+}
+
+//------------------------------------------------------------------------------
+
+// cachedScanResult selects the model from the cache or caches the selected model.
+func (q *SelectQuery) cachedScanResult(ctx context.Context, dest []interface{}) (sql.Result, error) {
+	key, ok := q.modelCacheKey(ctx, dest)
+	if !ok {
+		return q._scanResult(ctx, dest)
+	}
+
+	cache := q.db.modelCache
+	strct := q.tableModel.(*structTableModel).strct
+	if cache.load(key, strct) {
+		return driver.RowsAffected(1), nil
+	}
+
+	version := cache.version(key.table)
+	res, err := q._scanResult(ctx, dest)
+	if err != nil {
+		return nil, err
+	}
+	cache.store(key, strct, q.cacheTTL, version)
+	return res, nil
+}
+
+func (q *SelectQuery) modelCacheKey(ctx context.Context, dest []interface{}) (modelCacheKey, bool) {
+	if q.cacheTTL <= 0 || q.err != nil || len(dest) > 0 || q.tx != nil || isTxConn(q.conn) ||
+		q.db.HasSessionSetup(ctx) {
+		return modelCacheKey{}, false
+	}
+
+	model, ok := q.tableModel.(*structTableModel)
+	if !ok || !model.strct.IsValid() || len(model.joins) > 0 {
+		return modelCacheKey{}, false
+	}
+	if len(q.table.PKs) != 1 || len(q.whereFields) != 1 || q.whereFields[0] != q.table.PKs[0] {
+		return modelCacheKey{}, false
+	}
+	if len(q.where) > 0 || len(q.joins) > 0 || q.columns != nil || len(q.with) > 0 ||
+		len(q.union) > 0 || q.forceAllLazy || len(q.forceLazy) > 0 ||
+		q.flags.Has(deletedFlag) || q.flags.Has(allWithDeletedFlag) {
+		return modelCacheKey{}, false
+	}
+
+	pk := q.table.PKs[0].Value(model.strct).Interface()
+	return modelCacheKey{
+		table:      q.table.Name,
+		pk:         fmt.Sprint(pk),
+		searchPath: q.db.searchPath(ctx),
+		role:       RoleFromContext(ctx),
+	}, true
+}
+
+// evictModelCache evicts the models changed by the query from the model cache.
+// In a transaction the models are evicted again after the commit,
+// because other connections can cache the old models before the commit.
+func (db *DB) evictModelCache(q Query) {
+	if !db.modelCache.isUsed() {
+		return
+	}
+
+	var evict func()
+	switch q := q.(type) {
+	case *SelectQuery:
+		return
+	case *InsertQuery:
+		// Upserts can update the rows with other primary keys.
+		upsert := q.on.Query != "" || q.replace || q.conflictPK
+		evict = db.modelCacheEvicter(&q.baseQuery, !upsert)
+	case *UpdateQuery:
+		evict = db.modelCacheEvicter(&q.baseQuery, q.whereFields != nil)
+	case *DeleteQuery:
+		evict = db.modelCacheEvicter(&q.baseQuery, q.whereFields != nil)
+	case *RawQuery:
+		if strings.ToUpper(queryOperation(q.query)) == "SELECT" {
+			return
+		}
+		evict = db.modelCache.evictAll
+	default:
+		if table := cacheTableName(q); table != "" {
+			evict = func() { db.modelCache.evictTable(table) }
+		} else {
+			evict = db.modelCache.evictAll
+		}
+	}
+
+	evict()
+	if tx := queryTx(q); tx != nil {
+		tx.OnCommit(evict)
+	}
+}
+
+// modelCacheEvicter returns the function that evicts the models of the query
+// by the primary keys if byPK is true or all models of the table otherwise.
+func (db *DB) modelCacheEvicter(q *baseQuery, byPK bool) func() {
+	if q.table == nil {
+		return db.modelCache.evictAll
+	}
+
+	table := q.table.Name
+	if !byPK || q.tableModel == nil || len(q.table.PKs) != 1 {
+		return func() { db.modelCache.evictTable(table) }
+	}
+
+	pk := q.table.PKs[0]
+	var pks []string
+	walk(q.tableModel.rootValue(), nil, func(v reflect.Value) {
+		if v.IsValid() {
+			pks = append(pks, fmt.Sprint(pk.Value(v).Interface()))
+		}
+	})
+	if len(pks) == 0 {
+		return func() { db.modelCache.evictTable(table) }
+	}
+	return func() { db.modelCache.evict(table, pks) }
+}
+
+func queryTx(q Query) *Tx {
+	type txQuery interface {
+		getTx() *Tx
+	}
+	if q, ok := q.(txQuery); ok {
+		return q.getTx()
+	}
+	return nil
+}
+
+func (q *baseQuery) getTx() *Tx {
+	return q.tx
+}
@@ -51,6 +51,9 @@ func (m *scanModel) Scan(src interface{}) error {
 	dest := reflect.ValueOf(m.dest[m.scanIndex])
 	m.scanIndex++
 
-	scanner := schema.Scanner(dest.Type())
+	scanner := m.db.dialect.Tables().Types().Scanner(dest.Type())
+	if scanner == nil {
+		scanner = schema.Scanner(dest.Type())
+	}
 	return scanner(dest, src)
 }
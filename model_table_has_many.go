@@ -49,6 +49,7 @@ func (m *hasManyModel) ScanRows(ctx context.Context, rows *sql.Rows) (int, error
 
 	m.columns = columns
 	dest := makeDest(m, len(columns))
+	policy, role := m.db.redactionPolicy, RoleFromContext(ctx)
 
 	var n int
 
@@ -65,6 +66,9 @@ func (m *hasManyModel) ScanRows(ctx context.Context, rows *sql.Rows) (int, error
 		if err := rows.Scan(dest...); err != nil {
 			return 0, err
 		}
+		if policy != nil {
+			redactFields(role, policy, m.table, m.strct)
+		}
 
 		if err := m.parkStruct(); err != nil {
 			return 0, err
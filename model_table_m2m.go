@@ -5,11 +5,15 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/uptrace/bun/internal"
 	"github.com/uptrace/bun/schema"
 )
 
+// m2mPivotPrefix is the alias prefix of the m2m table columns that populate pivot models.
+const m2mPivotPrefix = "pivot__"
+
 type m2mModel struct {
 	*sliceTableModel
 	baseTable *schema.Table
@@ -17,6 +21,9 @@ type m2mModel struct {
 
 	baseValues map[internal.MapKey][]reflect.Value
 	structKey  []interface{}
+
+	pivotValues map[internal.MapKey][]reflect.Value
+	pivot       reflect.Value
 }
 
 var _ TableModel = (*m2mModel)(nil)
@@ -38,6 +45,22 @@ func newM2MModel(j *relationJoin) *m2mModel {
 	if !m.sliceOfPtr {
 		m.strct = reflect.New(m.table.Type).Elem()
 	}
+	if j.Relation.M2MPivotIndex != nil {
+		m.pivotValues = pivotValues(joinModel, baseTable.PKs, j.Relation.M2MPivotIndex)
+	}
+	return m
+}
+
+func pivotValues(
+	model TableModel, fields []*schema.Field, index []int,
+) map[internal.MapKey][]reflect.Value {
+	m := make(map[internal.MapKey][]reflect.Value)
+	key := make([]interface{}, 0, len(fields))
+	walk(model.rootValue(), model.parentIndex(), func(v reflect.Value) {
+		key = modelKey(key[:0], v, fields)
+		mapKey := internal.NewMapKey(key)
+		m[mapKey] = append(m[mapKey], v.FieldByIndex(index))
+	})
 	return m
 }
 
@@ -49,6 +72,7 @@ func (m *m2mModel) ScanRows(ctx context.Context, rows *sql.Rows) (int, error) {
 
 	m.columns = columns
 	dest := makeDest(m, len(columns))
+	policy, role := m.db.redactionPolicy, RoleFromContext(ctx)
 
 	var n int
 
@@ -59,12 +83,18 @@ func (m *m2mModel) ScanRows(ctx context.Context, rows *sql.Rows) (int, error) {
 			m.strct.Set(m.table.ZeroValue)
 		}
 		m.structInited = false
+		if m.pivotValues != nil {
+			m.pivot = reflect.New(m.rel.M2MTable.Type).Elem()
+		}
 
 		m.scanIndex = 0
 		m.structKey = m.structKey[:0]
 		if err := rows.Scan(dest...); err != nil {
 			return 0, err
 		}
+		if policy != nil {
+			redactFields(role, policy, m.table, m.strct)
+		}
 
 		if err := m.parkStruct(); err != nil {
 			return 0, err
@@ -103,6 +133,12 @@ func (m *m2mModel) Scan(src interface{}) error {
 }
 
 func (m *m2mModel) scanM2MColumn(column string, src interface{}) error {
+	if m.pivotValues != nil && strings.HasPrefix(column, m2mPivotPrefix) {
+		if field, ok := m.rel.M2MTable.FieldMap[column[len(m2mPivotPrefix):]]; ok {
+			return field.ScanValue(m.pivot, src)
+		}
+	}
+
 	for _, field := range m.rel.M2MBaseFields {
 		if field.Name == column {
 			dest := reflect.New(field.IndirectType).Elem()
@@ -134,5 +170,13 @@ func (m *m2mModel) parkStruct() error {
 		}
 	}
 
+	for _, v := range m.pivotValues[internal.NewMapKey(m.structKey)] {
+		if v.Type().Elem().Kind() == reflect.Ptr {
+			v.Set(reflect.Append(v, m.pivot.Addr()))
+		} else {
+			v.Set(reflect.Append(v, m.pivot))
+		}
+	}
+
 	return nil
 }
@@ -113,6 +113,16 @@ var (
 	_ schema.AfterScanRowHook  = (*sliceTableModel)(nil)
 )
 
+func (m *sliceTableModel) generateIDs() error {
+	sliceLen := m.slice.Len()
+	for i := 0; i < sliceLen; i++ {
+		if err := m.table.GenerateIDs(indirect(m.slice.Index(i))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *sliceTableModel) updateSoftDeleteField(tm time.Time) error {
 	sliceLen := m.slice.Len()
 	for i := 0; i < sliceLen; i++ {
@@ -126,6 +126,13 @@ func (m *structTableModel) AfterScanRow(ctx context.Context) error {
 		return nil
 	}
 
+	if err := m.decryptFields(ctx); err != nil {
+		return err
+	}
+	if policy := m.db.redactionPolicy; policy != nil {
+		m.redactFields(RoleFromContext(ctx), policy)
+	}
+
 	if m.table.HasAfterScanRowHook() {
 		firstErr := m.strct.Addr().Interface().(schema.AfterScanRowHook).AfterScanRow(ctx)
 
@@ -144,6 +151,35 @@ func (m *structTableModel) AfterScanRow(ctx context.Context) error {
 	return nil
 }
 
+func (m *structTableModel) decryptFields(ctx context.Context) error {
+	if err := decryptFields(ctx, m.table, m.strct); err != nil {
+		return err
+	}
+	for _, j := range m.joins {
+		switch j.Relation.Type {
+		case schema.HasOneRelation, schema.BelongsToRelation:
+			if jm, ok := j.JoinModel.(*structTableModel); ok && jm.structInited {
+				if err := jm.decryptFields(ctx); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (m *structTableModel) redactFields(role string, policy RedactionPolicy) {
+	redactFields(role, policy, m.table, m.strct)
+	for _, j := range m.joins {
+		switch j.Relation.Type {
+		case schema.HasOneRelation, schema.BelongsToRelation:
+			if jm, ok := j.JoinModel.(*structTableModel); ok && jm.structInited {
+				jm.redactFields(role, policy)
+			}
+		}
+	}
+}
+
 func (m *structTableModel) getJoin(name string) *relationJoin {
 	for i := range m.joins {
 		j := &m.joins[i]
@@ -221,6 +257,13 @@ func (m *structTableModel) mount(host reflect.Value) {
 	m.structInited = false
 }
 
+func (m *structTableModel) generateIDs() error {
+	if !m.strct.IsValid() {
+		return nil
+	}
+	return m.table.GenerateIDs(m.strct)
+}
+
 func (m *structTableModel) updateSoftDeleteField(tm time.Time) error {
 	if !m.strct.IsValid() {
 		return nil
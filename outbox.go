@@ -0,0 +1,174 @@
+package bun
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
+)
+
+// OutboxEvent is an event stored in the outbox table.
+type OutboxEvent struct {
+	BaseModel
+
+	ID        int64  `bun:",pk,autoincrement"`
+	EventType string `bun:",notnull"`
+	// Payload is the JSON-encoded payload passed to Outbox.Publish.
+	Payload     string    `bun:"type:text"`
+	CreatedAt   time.Time `bun:",notnull,nullzero,default:current_timestamp"`
+	DeliveredAt time.Time `bun:",nullzero"`
+	// Retries is the number of the failed delivery attempts.
+	Retries   int    `bun:",notnull,default:0"`
+	LastError string `bun:"type:text,nullzero"`
+}
+
+// Unmarshal decodes the event payload into v.
+func (e *OutboxEvent) Unmarshal(v interface{}) error {
+	return json.Unmarshal([]byte(e.Payload), v)
+}
+
+// Outbox implements the transactional outbox pattern: events are published
+// in the same transaction as the changes they describe and are delivered
+// by a background worker after the transaction commits:
+//
+//	err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+//		if _, err := tx.NewInsert().Model(order).Exec(ctx); err != nil {
+//			return err
+//		}
+//		return outbox.Publish(ctx, tx, "order.created", order)
+//	})
+//
+//	// In the worker.
+//	err := outbox.Poll(ctx, 100, func(events []bun.OutboxEvent) error {
+//		return broker.Send(events)
+//	})
+type Outbox struct {
+	db         *DB
+	table      string
+	maxRetries int
+}
+
+type OutboxOption func(o *Outbox)
+
+// WithOutboxMaxRetries sets the number of failed delivery attempts after which Poll
+// skips the event, so a failing event doesn't block the events published after it.
+// The skipped events stay in the outbox table with the last error and can be delivered
+// again by resetting their retries column. The default is 10, 0 means no limit.
+func WithOutboxMaxRetries(n int) OutboxOption {
+	return func(o *Outbox) {
+		o.maxRetries = n
+	}
+}
+
+func NewOutbox(db *DB, tableName string, opts ...OutboxOption) *Outbox {
+	o := &Outbox{
+		db:         db,
+		table:      tableName,
+		maxRetries: 10,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// CreateOutboxTable creates the outbox table if it does not exist.
+func (o *Outbox) CreateOutboxTable(ctx context.Context) error {
+	_, err := o.db.NewCreateTable().
+		Model((*OutboxEvent)(nil)).
+		ModelTableExpr(o.table).
+		IfNotExists().
+		Exec(ctx)
+	return err
+}
+
+// Publish encodes the payload as JSON and writes the event to the outbox table
+// using the conn, which is usually the transaction that makes the domain change.
+func (o *Outbox) Publish(ctx context.Context, conn IConn, eventType string, payload interface{}) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	event := &OutboxEvent{
+		EventType: eventType,
+		Payload:   string(b),
+	}
+	_, err = o.db.NewInsert().
+		Conn(conn).
+		Model(event).
+		ModelTableExpr(o.table).
+		Exec(ctx)
+	return err
+}
+
+// Poll selects up to batchSize undelivered events in the order they were published
+// and passes them to the handler. When the handler succeeds, the events are marked
+// as delivered. Otherwise, Poll increments the retry count of the events, stores the error,
+// and returns it. Poll does nothing when there are no undelivered events.
+//
+// The events that failed WithOutboxMaxRetries times are skipped.
+//
+// On PostgreSQL, MySQL 8 and MariaDB 10.6, the events are locked with FOR UPDATE SKIP LOCKED,
+// and on MSSQL with the UPDLOCK and READPAST table hints, so multiple workers can poll
+// the same outbox. Older MySQL and MariaDB servers lock the events with FOR UPDATE,
+// so the workers wait for each other. SQLite can't lock rows, so the workers could
+// deliver the same events twice; use a single worker.
+func (o *Outbox) Poll(
+	ctx context.Context, batchSize int, handler func([]OutboxEvent) error,
+) error {
+	var handlerErr error
+	err := o.db.RunInTx(ctx, nil, func(ctx context.Context, tx Tx) error {
+		var events []OutboxEvent
+		q := tx.NewSelect().
+			ColumnExpr("*").
+			Model(&events).
+			Where("delivered_at IS NULL").
+			OrderExpr("id ASC").
+			Limit(batchSize)
+		if o.maxRetries > 0 {
+			q = q.Where("retries < ?", o.maxRetries)
+		}
+		switch {
+		case o.db.HasFeature(feature.SkipLocked):
+			q = q.ModelTableExpr(o.table).For("UPDATE SKIP LOCKED")
+		case o.db.dialect.Name() == dialect.MySQL:
+			q = q.ModelTableExpr(o.table).For("UPDATE")
+		case o.db.dialect.Name() == dialect.MSSQL:
+			q = q.ModelTableExpr("? WITH (UPDLOCK, READPAST, ROWLOCK)", Safe(o.table))
+		default:
+			q = q.ModelTableExpr(o.table)
+		}
+		if err := q.Scan(ctx); err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		ids := make([]int64, len(events))
+		for i := range events {
+			ids[i] = events[i].ID
+		}
+
+		update := tx.NewUpdate().
+			Model((*OutboxEvent)(nil)).
+			ModelTableExpr(o.table).
+			Where("id IN (?)", In(ids))
+		if handlerErr = handler(events); handlerErr != nil {
+			update = update.
+				Set("retries = retries + 1").
+				Set("last_error = ?", handlerErr.Error())
+		} else {
+			update = update.Set("delivered_at = ?", time.Now())
+		}
+		_, err := update.Exec(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return handlerErr
+}
@@ -0,0 +1,163 @@
+package bun
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Page is a page of results returned by Pager.
+type Page[T any] struct {
+	Items []T
+
+	// Total, Page, and PageCount are zero in cursor mode.
+	Total     int
+	Page      int
+	PageSize  int
+	PageCount int
+
+	HasNext bool
+	HasPrev bool
+
+	// NextCursor is the cursor column value of the last item in cursor mode.
+	NextCursor interface{}
+}
+
+// Pager selects a page of T using either LIMIT/OFFSET and a count query:
+//
+//	page, err := bun.NewPager[User](db).
+//		Apply(func(q *bun.TypedSelectQuery[User]) *bun.TypedSelectQuery[User] {
+//			return q.Where("active").Order("id")
+//		}).
+//		Page(2, 20).
+//		Execute(ctx)
+//
+// or a cursor, that is, a WHERE condition on a unique column, which doesn't count the rows:
+//
+//	page, err := bun.NewPager[User](db).CursorPage("id", lastID, 20).Execute(ctx)
+type Pager[T any] struct {
+	q *TypedSelectQuery[T]
+
+	page int
+	size int
+
+	cursorColumn string
+	cursor       interface{}
+}
+
+func NewPager[T any](db *DB) *Pager[T] {
+	return &Pager[T]{
+		q:    NewTypedSelect[T](db),
+		page: 1,
+		size: 10,
+	}
+}
+
+// Conn sets the connection used by the query, for example, a transaction.
+func (p *Pager[T]) Conn(db IConn) *Pager[T] {
+	p.q.Conn(db)
+	return p
+}
+
+// Apply modifies the underlying query, for example, to add filters.
+func (p *Pager[T]) Apply(fn func(*TypedSelectQuery[T]) *TypedSelectQuery[T]) *Pager[T] {
+	p.q = p.q.Apply(fn)
+	return p
+}
+
+// Page selects the page n of the given size. Pages start with 1.
+func (p *Pager[T]) Page(n, size int) *Pager[T] {
+	p.page = n
+	p.size = size
+	p.cursorColumn = ""
+	p.cursor = nil
+	return p
+}
+
+// CursorPage selects up to size rows ordered by the column that are greater than
+// the cursor. A nil cursor selects the first page. The column must be unique.
+// The rows are ordered only by the column, so Execute returns an error
+// if the query has other ORDER BY expressions.
+func (p *Pager[T]) CursorPage(column string, cursor interface{}, size int) *Pager[T] {
+	p.cursorColumn = column
+	p.cursor = cursor
+	p.size = size
+	return p
+}
+
+// Execute selects the page. It modifies the underlying query,
+// so use a new Pager for each page.
+func (p *Pager[T]) Execute(ctx context.Context) (*Page[T], error) {
+	if p.size <= 0 {
+		return nil, fmt.Errorf("bun: page size must be positive, got %d", p.size)
+	}
+	if p.cursorColumn != "" {
+		return p.executeCursor(ctx)
+	}
+	if p.page < 1 {
+		return nil, fmt.Errorf("bun: page must be positive, got %d", p.page)
+	}
+
+	items, total, err := p.q.
+		Limit(p.size).
+		Offset((p.page - 1) * p.size).
+		ScanAndCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pageCount := (total + p.size - 1) / p.size
+	return &Page[T]{
+		Items:     items,
+		Total:     total,
+		Page:      p.page,
+		PageSize:  p.size,
+		PageCount: pageCount,
+		HasNext:   p.page < pageCount,
+		HasPrev:   p.page > 1,
+	}, nil
+}
+
+func (p *Pager[T]) executeCursor(ctx context.Context) (*Page[T], error) {
+	if len(p.q.order) > 0 {
+		return nil, fmt.Errorf("bun: cursor pages are ordered by %s and can't be ordered by other columns",
+			p.cursorColumn)
+	}
+
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	table := p.q.db.Table(typ)
+	field, err := table.Field(p.cursorColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	q := p.q.OrderExpr("?TableAlias.? ASC", Ident(field.Name))
+	if p.cursor != nil {
+		q = q.Where("?TableAlias.? > ?", Ident(field.Name), p.cursor)
+	}
+
+	// Select one more row to know whether there is a next page.
+	items, err := q.Limit(p.size + 1).Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page[T]{
+		PageSize: p.size,
+		HasPrev:  p.cursor != nil,
+	}
+	if len(items) > p.size {
+		items = items[:p.size]
+		page.HasNext = true
+	}
+	page.Items = items
+
+	if len(items) > 0 {
+		strct := reflect.Indirect(reflect.ValueOf(&items[len(items)-1]).Elem())
+		page.NextCursor = field.Value(strct).Interface()
+	}
+	return page, nil
+}
@@ -6,10 +6,17 @@ import (
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/uptrace/bun/dialect"
 	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/internal/tagparser"
 	"github.com/uptrace/bun/schema"
 )
 
@@ -17,6 +24,7 @@ const (
 	forceDeleteFlag internal.Flag = 1 << iota
 	deletedFlag
 	allWithDeletedFlag
+	wherePKCompositeFlag
 )
 
 type withQuery struct {
@@ -60,6 +68,17 @@ type IDB interface {
 	NewTruncateTable() *TruncateTableQuery
 	NewAddColumn() *AddColumnQuery
 	NewDropColumn() *DropColumnQuery
+	NewCreateSequence(name string) *CreateSequenceQuery
+	NewAlterSequence(name string) *AlterSequenceQuery
+	NewDropSequence(name string) *DropSequenceQuery
+	NewCreateExtension(name string) *CreateExtensionQuery
+	NewDropExtension(name string) *DropExtensionQuery
+	NewCreateView(name string) *CreateViewQuery
+	NewDropView(name string) *DropViewQuery
+	NewRefreshView(name string) *RefreshViewQuery
+	NewCreateTablePartition(parent, partition string) *CreateTablePartitionQuery
+	NewAttachPartition(parent, partition string) *AttachPartitionQuery
+	NewDetachPartition(parent, partition string) *DetachPartitionQuery
 
 	BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error)
 	RunInTx(ctx context.Context, opts *sql.TxOptions, f func(ctx context.Context, tx Tx) error) error
@@ -80,6 +99,12 @@ type QueryBuilder interface {
 	WhereDeleted() QueryBuilder
 	WhereAllWithDeleted() QueryBuilder
 	WherePK(cols ...string) QueryBuilder
+	Order(orders ...string) QueryBuilder
+	OrderExpr(query string, args ...interface{}) QueryBuilder
+	Limit(n int) QueryBuilder
+	Offset(n int) QueryBuilder
+	Group(columns ...string) QueryBuilder
+	GroupExpr(query string, args ...interface{}) QueryBuilder
 	Unwrap() interface{}
 }
 
@@ -92,6 +117,8 @@ var (
 type baseQuery struct {
 	db   *DB
 	conn IConn
+	// tx is the transaction the conn belongs to, if any.
+	tx *Tx
 
 	model Model
 	err   error
@@ -105,6 +132,8 @@ type baseQuery struct {
 	columns        []schema.QueryWithArgs
 
 	flags internal.Flag
+
+	comment string
 }
 
 func (q *baseQuery) DB() *DB {
@@ -148,6 +177,7 @@ func (q *baseQuery) GetTableName() string {
 
 func (q *baseQuery) setConn(db IConn) {
 	// Unwrap Bun wrappers to not call query hooks twice.
+	q.tx = nil
 	switch db := db.(type) {
 	case *DB:
 		q.conn = db.DB
@@ -155,6 +185,7 @@ func (q *baseQuery) setConn(db IConn) {
 		q.conn = db.Conn
 	case Tx:
 		q.conn = db.Tx
+		q.tx = &db
 	default:
 		q.conn = db
 	}
@@ -254,6 +285,17 @@ func (q *baseQuery) addWith(name string, query schema.QueryAppender, recursive b
 	})
 }
 
+func (q *baseQuery) setComment(comment string) {
+	q.comment = sanitizeQueryComment(comment)
+}
+
+func (q *baseQuery) appendComment(b []byte) []byte {
+	if q.comment == "" {
+		return b
+	}
+	return appendQueryComment(b, q.comment)
+}
+
 func (q *baseQuery) appendWith(fmter schema.Formatter, b []byte) (_ []byte, err error) {
 	if len(q.with) == 0 {
 		return b, nil
@@ -265,7 +307,8 @@ func (q *baseQuery) appendWith(fmter schema.Formatter, b []byte) (_ []byte, err
 			b = append(b, ", "...)
 		}
 
-		if with.recursive {
+		// MSSQL doesn't have the RECURSIVE keyword and detects recursive CTEs itself.
+		if with.recursive && fmter.Dialect().Name() != dialect.MSSQL {
 			b = append(b, "RECURSIVE "...)
 		}
 
@@ -416,8 +459,9 @@ func (q *baseQuery) _appendTables(
 				return nil, err
 			}
 		} else {
-			b = fmter.AppendQuery(b, string(q.table.SQLNameForSelects))
-			if withAlias && q.table.SQLAlias != q.table.SQLNameForSelects {
+			name := fmter.TableNameForSelects(q.table)
+			b = fmter.AppendQuery(b, string(name))
+			if withAlias && q.table.SQLAlias != name {
 				b = append(b, " AS "...)
 				b = append(b, q.table.SQLAlias...)
 			}
@@ -455,7 +499,7 @@ func (q *baseQuery) _appendFirstTable(
 	}
 
 	if q.table != nil {
-		b = fmter.AppendQuery(b, string(q.table.SQLName))
+		b = fmter.AppendQuery(b, string(fmter.TableName(q.table)))
 		if withAlias {
 			b = append(b, " AS "...)
 			b = append(b, q.table.SQLAlias...)
@@ -529,6 +573,55 @@ func (q *baseQuery) getDataFields() ([]*schema.Field, error) {
 	return q._getFields(true)
 }
 
+// withoutUnloadedLazyFields removes the lazy fields that have zero values in all models,
+// because such fields are not selected by default, so the zero values would overwrite
+// the data. The fields are kept if the columns are set explicitly or loaded with LoadLazy.
+//
+// Zero values can't be told apart from unloaded fields, so when overwrite is true,
+// that is the values replace existing rows, models where a lazy field is zero
+// only in some of the models are rejected instead of overwriting those rows.
+func (q *baseQuery) withoutUnloadedLazyFields(
+	fields []*schema.Field, overwrite bool,
+) ([]*schema.Field, error) {
+	if len(q.columns) > 0 || q.table == nil || !q.table.HasLazyFields() || q.tableModel == nil {
+		return fields, nil
+	}
+
+	var strcts []reflect.Value
+	walk(q.tableModel.rootValue(), nil, func(v reflect.Value) {
+		if v.IsValid() {
+			strcts = append(strcts, v)
+		}
+	})
+
+	filtered := make([]*schema.Field, 0, len(fields))
+	for _, f := range fields {
+		if !f.Lazy {
+			filtered = append(filtered, f)
+			continue
+		}
+
+		var numZero int
+		for _, strct := range strcts {
+			if f.HasZeroValue(strct) {
+				numZero++
+			}
+		}
+
+		switch {
+		case numZero == len(strcts):
+			continue
+		case numZero > 0 && overwrite:
+			return nil, fmt.Errorf(
+				"bun: lazy column %q is loaded only in some %s models "+
+					"(use LoadLazy to load it or Column to choose the updated columns)",
+				f.Name, q.table.TypeName)
+		}
+		filtered = append(filtered, f)
+	}
+	return filtered, nil
+}
+
 func (q *baseQuery) _getFields(omitPK bool) ([]*schema.Field, error) {
 	fields := make([]*schema.Field, 0, len(q.columns))
 	for _, col := range q.columns {
@@ -557,27 +650,42 @@ func (q *baseQuery) scan(
 	model Model,
 	hasDest bool,
 ) (sql.Result, error) {
-	ctx, event := q.db.beforeQuery(ctx, iquery, query, nil, query, q.model)
+	query = withQueryComment(ctx, query)
+	ctx, event, start := q.db.beforeQuery(ctx, q.conn, q.tx, iquery, query, nil, query, q.model)
+	query = event.query(query)
 
-	rows, err := q.conn.QueryContext(ctx, query)
-	if err != nil {
-		q.db.afterQuery(ctx, event, nil, err)
-		return nil, err
+	conn := q.conn
+	if cache := queryCacheFromContext(ctx); cache != nil {
+		conn = cache.conn(conn)
 	}
-	defer rows.Close()
 
-	numRow, err := model.ScanRows(ctx, rows)
-	if err != nil {
-		q.db.afterQuery(ctx, event, nil, err)
-		return nil, err
-	}
+	res, err := q.db.runQuery(ctx, conn, query, func(
+		ctx context.Context, conn IConn, query string,
+	) (sql.Result, error) {
+		rows, err := conn.QueryContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
 
-	if numRow == 0 && hasDest && isSingleRowModel(model) {
-		err = sql.ErrNoRows
-	}
+		if event != nil {
+			event.Columns, _ = rows.Columns()
+		}
+
+		numRow, err := model.ScanRows(ctx, rows)
+		if err != nil {
+			if event != nil {
+				event.RowsAffected = int64(numRow)
+			}
+			return nil, err
+		}
 
-	res := driver.RowsAffected(numRow)
-	q.db.afterQuery(ctx, event, res, err)
+		if numRow == 0 && hasDest && isSingleRowModel(model) {
+			return driver.RowsAffected(numRow), sql.ErrNoRows
+		}
+		return driver.RowsAffected(numRow), nil
+	})
+	q.db.afterQuery(ctx, event, start, res, err)
 
 	return res, err
 }
@@ -587,9 +695,11 @@ func (q *baseQuery) exec(
 	iquery Query,
 	query string,
 ) (sql.Result, error) {
-	ctx, event := q.db.beforeQuery(ctx, iquery, query, nil, query, q.model)
-	res, err := q.conn.ExecContext(ctx, query)
-	q.db.afterQuery(ctx, event, res, err)
+	query = withQueryComment(ctx, query)
+	ctx, event, start := q.db.beforeQuery(ctx, q.conn, q.tx, iquery, query, nil, query, q.model)
+	query = event.query(query)
+	res, err := q.db.runQuery(ctx, q.conn, query, execQuery)
+	q.db.afterQuery(ctx, event, start, res, err)
 	return res, err
 }
 
@@ -608,7 +718,7 @@ func (q *baseQuery) AppendNamedArg(fmter schema.Formatter, b []byte, name string
 
 	switch name {
 	case "TableName":
-		b = fmter.AppendQuery(b, string(q.table.SQLName))
+		b = fmter.AppendQuery(b, string(fmter.TableName(q.table)))
 		return b, true
 	case "TableAlias":
 		b = fmter.AppendQuery(b, string(q.table.SQLAlias))
@@ -688,6 +798,50 @@ func (q *baseQuery) NewDropColumn() *DropColumnQuery {
 	return NewDropColumnQuery(q.db).Conn(q.conn)
 }
 
+func (q *baseQuery) NewCreateSequence(name string) *CreateSequenceQuery {
+	return NewCreateSequenceQuery(q.db, name).Conn(q.conn)
+}
+
+func (q *baseQuery) NewAlterSequence(name string) *AlterSequenceQuery {
+	return NewAlterSequenceQuery(q.db, name).Conn(q.conn)
+}
+
+func (q *baseQuery) NewDropSequence(name string) *DropSequenceQuery {
+	return NewDropSequenceQuery(q.db, name).Conn(q.conn)
+}
+
+func (q *baseQuery) NewCreateExtension(name string) *CreateExtensionQuery {
+	return NewCreateExtensionQuery(q.db, name).Conn(q.conn)
+}
+
+func (q *baseQuery) NewDropExtension(name string) *DropExtensionQuery {
+	return NewDropExtensionQuery(q.db, name).Conn(q.conn)
+}
+
+func (q *baseQuery) NewCreateView(name string) *CreateViewQuery {
+	return NewCreateViewQuery(q.db, name).Conn(q.conn)
+}
+
+func (q *baseQuery) NewDropView(name string) *DropViewQuery {
+	return NewDropViewQuery(q.db, name).Conn(q.conn)
+}
+
+func (q *baseQuery) NewRefreshView(name string) *RefreshViewQuery {
+	return NewRefreshViewQuery(q.db, name).Conn(q.conn)
+}
+
+func (q *baseQuery) NewCreateTablePartition(parent, partition string) *CreateTablePartitionQuery {
+	return NewCreateTablePartitionQuery(q.db, parent, partition).Conn(q.conn)
+}
+
+func (q *baseQuery) NewAttachPartition(parent, partition string) *AttachPartitionQuery {
+	return NewAttachPartitionQuery(q.db, parent, partition).Conn(q.conn)
+}
+
+func (q *baseQuery) NewDetachPartition(parent, partition string) *DetachPartitionQuery {
+	return NewDetachPartitionQuery(q.db, parent, partition).Conn(q.conn)
+}
+
 //------------------------------------------------------------------------------
 
 func appendColumns(b []byte, table schema.Safe, fields []*schema.Field) []byte {
@@ -721,6 +875,12 @@ type whereBaseQuery struct {
 	whereFields []*schema.Field
 }
 
+// HasWhere reports whether the query has WHERE conditions, for example,
+// so BeforeSelect hooks can reject queries without filters.
+func (q *whereBaseQuery) HasWhere() bool {
+	return len(q.where) > 0 || len(q.whereFields) > 0
+}
+
 func (q *whereBaseQuery) addWhere(where schema.QueryWithSep) {
 	q.where = append(q.where, where)
 }
@@ -739,6 +899,207 @@ func (q *whereBaseQuery) addWhereGroup(sep string, where []schema.QueryWithSep)
 	q.addWhere(schema.SafeQueryWithSep("", nil, ")"))
 }
 
+func (q *whereBaseQuery) addWhereMap(conditions map[string]interface{}) {
+	keys := make([]string, 0, len(conditions))
+	for key := range conditions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := conditions[key]
+		if isNilValue(value) {
+			q.addWhere(schema.SafeQueryWithSep("? IS NULL", []interface{}{Ident(key)}, " AND "))
+			continue
+		}
+		if !isWhereMapValue(value) {
+			q.setErr(fmt.Errorf("bun: WhereMap does not support %T (column %q)", value, key))
+			return
+		}
+		q.addWhere(schema.SafeQueryWithSep("? = ?", []interface{}{Ident(key), value}, " AND "))
+	}
+}
+
+// addWhereStruct adds a condition for each exported field of the filter struct
+// that is not a nil pointer or a nil slice.
+func (q *whereBaseQuery) addWhereStruct(filter interface{}) {
+	v := reflect.ValueOf(filter)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		q.setErr(fmt.Errorf("bun: WhereStruct(unsupported %T)", filter))
+		return
+	}
+	q._addWhereStruct(v)
+}
+
+func (q *whereBaseQuery) _addWhereStruct(strct reflect.Value) {
+	typ := strct.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		tag := tagparser.Parse(sf.Tag.Get("bun"))
+		if tag.Name == "-" {
+			continue
+		}
+
+		fv := strct.Field(i)
+		if sf.Anonymous && tag.Name == "" && indirectType(sf.Type).Kind() == reflect.Struct {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			q._addWhereStruct(fv)
+			continue
+		}
+
+		column := tag.Name
+		if column == "" {
+			column = internal.Underscore(sf.Name)
+		}
+
+		switch fv.Kind() {
+		case reflect.Ptr:
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		case reflect.Slice:
+			if fv.IsNil() {
+				continue
+			}
+		}
+
+		value := fv.Interface()
+		if fv.Kind() == reflect.Slice && !isWhereMapValue(value) {
+			q.addWhereIn(column, value, false)
+			continue
+		}
+		if !isWhereMapValue(value) {
+			q.setErr(fmt.Errorf("bun: WhereStruct does not support %T (field %s)", value, sf.Name))
+			return
+		}
+
+		op := " = "
+		switch {
+		case tag.HasOption("gte"):
+			op = " >= "
+		case tag.HasOption("lte"):
+			op = " <= "
+		}
+		q.addWhere(schema.SafeQueryWithSep("?"+op+"?", []interface{}{Ident(column), value}, " AND "))
+	}
+}
+
+// addWhereIn adds `column IN (values)` or `column NOT IN (values)` where values is
+// a subquery, a slice of models whose primary keys are used, an In appender, or a slice of values.
+func (q *whereBaseQuery) addWhereIn(column string, values interface{}, not bool) {
+	op := " IN "
+	if not {
+		op = " NOT IN "
+	}
+
+	switch values := values.(type) {
+	case *SelectQuery:
+		q.addWhere(schema.SafeQueryWithSep("?"+op+"(?)", []interface{}{Ident(column), values}, " AND "))
+		return
+	case schema.QueryAppender:
+		q.addWhere(schema.SafeQueryWithSep("?"+op+"(?)", []interface{}{Ident(column), values}, " AND "))
+		return
+	}
+
+	slice := reflect.ValueOf(values)
+	if slice.Kind() == reflect.Ptr {
+		slice = slice.Elem()
+	}
+	if slice.Kind() != reflect.Slice && slice.Kind() != reflect.Array {
+		q.setErr(fmt.Errorf("bun: WhereIn does not support %T", values))
+		return
+	}
+
+	if slice.Len() == 0 {
+		// Nothing is in the empty set, so IN is always false and NOT IN is always true.
+		cond := "FALSE"
+		if not {
+			cond = "TRUE"
+		}
+		if q.db.dialect.Name() == dialect.MSSQL {
+			cond = "1 = 0"
+			if not {
+				cond = "1 = 1"
+			}
+		}
+		q.addWhere(schema.SafeQueryWithSep(cond, nil, " AND "))
+		return
+	}
+
+	if typ := indirectType(slice.Type().Elem()); typ.Kind() == reflect.Struct &&
+		!reflect.PtrTo(typ).Implements(driverValuerType) && typ != timeType {
+		pks, err := q.modelPKs(slice, typ)
+		if err != nil {
+			q.setErr(err)
+			return
+		}
+		values = pks
+	}
+
+	q.addWhere(schema.SafeQueryWithSep(
+		"?"+op+"(?)", []interface{}{Ident(column), schema.In(values)}, " AND "))
+}
+
+// modelPKs returns the primary key values of the models in the slice.
+func (q *whereBaseQuery) modelPKs(slice reflect.Value, typ reflect.Type) ([]interface{}, error) {
+	table := q.db.Table(typ)
+	if len(table.PKs) != 1 {
+		return nil, fmt.Errorf(
+			"bun: WhereIn requires %s to have a single primary key, got %d", table, len(table.PKs))
+	}
+	pk := table.PKs[0]
+
+	pks := make([]interface{}, 0, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		strct := indirect(slice.Index(i))
+		if !strct.IsValid() {
+			return nil, errNilModel
+		}
+		pks = append(pks, pk.Value(strct).Interface())
+	}
+	return pks, nil
+}
+
+func isNilValue(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// isWhereMapValue reports whether v can be compared with a column using =.
+func isWhereMapValue(v interface{}) bool {
+	switch v.(type) {
+	case driver.Valuer, schema.QueryAppender, []byte, time.Time:
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		return isWhereMapValue(rv.Elem().Interface())
+	}
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct,
+		reflect.Func, reflect.Chan, reflect.Interface, reflect.UnsafePointer:
+		return false
+	}
+	return true
+}
+
 func (q *whereBaseQuery) addWhereCols(cols []string) {
 	if q.table == nil {
 		err := fmt.Errorf("bun: got %T, but WherePK requires a struct or slice-based model", q.model)
@@ -806,7 +1167,7 @@ func (q *whereBaseQuery) appendWhere(
 		if withAlias {
 			b = append(b, q.tableModel.Table().SQLAlias...)
 		} else {
-			b = append(b, q.tableModel.Table().SQLName...)
+			b = append(b, fmter.TableName(q.tableModel.Table())...)
 		}
 		b = append(b, '.')
 
@@ -922,6 +1283,11 @@ func (q *whereBaseQuery) appendWhereSliceFields(
 	fields []*schema.Field,
 	withAlias bool,
 ) (_ []byte, err error) {
+	if len(fields) > 1 && q.flags.Has(wherePKCompositeFlag) &&
+		!fmter.HasFeature(feature.CompositeIn) {
+		return q.appendWhereSliceFieldsOr(fmter, b, model, fields, withAlias), nil
+	}
+
 	if len(fields) > 1 {
 		b = append(b, '(')
 	}
@@ -972,6 +1338,54 @@ func (q *whereBaseQuery) appendWhereSliceFields(
 	return b, nil
 }
 
+// appendWhereSliceFieldsOr appends `((a = 1 AND b = 2) OR (a = 3 AND b = 4))`
+// for the dialects that don't support `(a, b) IN ((1, 2), (3, 4))`.
+func (q *whereBaseQuery) appendWhereSliceFieldsOr(
+	fmter schema.Formatter,
+	b []byte,
+	model *sliceTableModel,
+	fields []*schema.Field,
+	withAlias bool,
+) []byte {
+	isTemplate := fmter.IsNop()
+	slice := model.slice
+	sliceLen := slice.Len()
+
+	b = append(b, '(')
+	for i := 0; i < sliceLen; i++ {
+		if i > 0 {
+			if isTemplate {
+				break
+			}
+			b = append(b, " OR "...)
+		}
+
+		el := indirect(slice.Index(i))
+
+		b = append(b, '(')
+		for j, f := range fields {
+			if j > 0 {
+				b = append(b, " AND "...)
+			}
+			if withAlias {
+				b = append(b, q.table.SQLAlias...)
+				b = append(b, '.')
+			}
+			b = append(b, f.SQLName...)
+			b = append(b, " = "...)
+			if isTemplate {
+				b = append(b, '?')
+			} else {
+				b = f.AppendValue(fmter, b, el)
+			}
+		}
+		b = append(b, ')')
+	}
+	b = append(b, ')')
+
+	return b
+}
+
 //------------------------------------------------------------------------------
 
 type returningQuery struct {
@@ -1028,6 +1442,24 @@ func (q *returningQuery) _appendReturning(
 	return b, nil
 }
 
+// outputTableRE matches the INSERTED and DELETED qualifiers used in MSSQL OUTPUT clauses.
+var outputTableRE = regexp.MustCompile(`(?i)\b(INSERTED|DELETED)\.`)
+
+// outputQuery converts the OUTPUT clause written for MSSQL to the RETURNING clause
+// on the other dialects by removing the INSERTED qualifiers. RETURNING can't select
+// the old values, so the DELETED qualifiers are rejected.
+func outputQuery(db *DB, query string) (string, error) {
+	if db.features.Has(feature.Output) {
+		return query, nil
+	}
+	for _, m := range outputTableRE.FindAllStringSubmatch(query, -1) {
+		if strings.EqualFold(m[1], "DELETED") {
+			return "", fmt.Errorf("bun: OUTPUT DELETED is not supported by %s", db.dialect.Name())
+		}
+	}
+	return outputTableRE.ReplaceAllString(query, ""), nil
+}
+
 func (q *returningQuery) hasReturning() bool {
 	if len(q.returning) == 1 {
 		if ret := q.returning[0]; len(ret.Args) == 0 {
@@ -1098,6 +1530,67 @@ func (q setQuery) appendSet(fmter schema.Formatter, b []byte) (_ []byte, err err
 
 //------------------------------------------------------------------------------
 
+func appendOrder(dst []schema.QueryWithArgs, orders ...string) []schema.QueryWithArgs {
+	for _, order := range orders {
+		if order == "" {
+			continue
+		}
+
+		index := strings.IndexByte(order, ' ')
+		if index == -1 {
+			dst = append(dst, schema.UnsafeIdent(order))
+			continue
+		}
+
+		field := order[:index]
+		sort := order[index+1:]
+
+		switch strings.ToUpper(sort) {
+		case "ASC", "DESC", "ASC NULLS FIRST", "DESC NULLS FIRST",
+			"ASC NULLS LAST", "DESC NULLS LAST":
+			dst = append(dst, schema.SafeQuery("? ?", []interface{}{
+				Ident(field),
+				Safe(sort),
+			}))
+		default:
+			dst = append(dst, schema.UnsafeIdent(order))
+		}
+	}
+	return dst
+}
+
+// orderLimitQuery is ORDER BY and LIMIT in UPDATE and DELETE queries.
+type orderLimitQuery struct {
+	order []schema.QueryWithArgs
+	limit int32
+}
+
+func (q *orderLimitQuery) hasOrderLimit() bool {
+	return len(q.order) > 0 || q.limit > 0
+}
+
+func (q *orderLimitQuery) appendOrderLimit(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if len(q.order) > 0 {
+		b = append(b, " ORDER BY "...)
+		for i, f := range q.order {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b, err = f.AppendQuery(fmter, b)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if q.limit > 0 {
+		b = append(b, " LIMIT "...)
+		b = strconv.AppendInt(b, int64(q.limit), 10)
+	}
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
 type cascadeQuery struct {
 	cascade  bool
 	restrict bool
@@ -0,0 +1,46 @@
+package bun
+
+import (
+	"context"
+	"strings"
+)
+
+type queryCommentKey struct{}
+
+// WithQueryComment returns a context that prepends the comment to the queries executed
+// with the context, for example, to tag queries with the trace ID for monitoring:
+//
+//	ctx = bun.WithQueryComment(ctx, "trace_id="+traceID)
+//	err := db.NewSelect().Model(&users).Scan(ctx)
+//	// /* trace_id=... */ SELECT ...
+//
+// Comment delimiters are removed from the comment. Queries that already start with
+// a comment, for example, set with SelectQuery.Comment, are left unchanged.
+func WithQueryComment(ctx context.Context, comment string) context.Context {
+	return context.WithValue(ctx, queryCommentKey{}, sanitizeQueryComment(comment))
+}
+
+func withQueryComment(ctx context.Context, query string) string {
+	comment, _ := ctx.Value(queryCommentKey{}).(string)
+	if comment == "" || strings.HasPrefix(query, "/*") {
+		return query
+	}
+	return string(appendQueryComment(nil, comment)) + query
+}
+
+func appendQueryComment(b []byte, comment string) []byte {
+	b = append(b, "/* "...)
+	b = append(b, comment...)
+	b = append(b, " */ "...)
+	return b
+}
+
+// sanitizeQueryComment removes comment delimiters, so the comment can't end early
+// and inject SQL into the query.
+func sanitizeQueryComment(comment string) string {
+	for strings.Contains(comment, "/*") || strings.Contains(comment, "*/") {
+		comment = strings.ReplaceAll(comment, "/*", "")
+		comment = strings.ReplaceAll(comment, "*/", "")
+	}
+	return comment
+}
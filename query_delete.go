@@ -3,6 +3,10 @@ package bun
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/uptrace/bun/dialect/feature"
@@ -13,6 +17,9 @@ import (
 type DeleteQuery struct {
 	whereBaseQuery
 	returningQuery
+	orderLimitQuery
+
+	chunkSize int
 }
 
 var _ Query = (*DeleteQuery)(nil)
@@ -44,6 +51,14 @@ func (q *DeleteQuery) Err(err error) *DeleteQuery {
 	return q
 }
 
+// Comment prepends the comment to the query, for example, /* comment */ DELETE ...
+// Comment delimiters are removed from the comment. The comment takes precedence over
+// the comment set with WithQueryComment.
+func (q *DeleteQuery) Comment(comment string) *DeleteQuery {
+	q.setComment(comment)
+	return q
+}
+
 // Apply calls the fn passing the DeleteQuery as an argument.
 func (q *DeleteQuery) Apply(fn func(*DeleteQuery) *DeleteQuery) *DeleteQuery {
 	if fn != nil {
@@ -52,6 +67,14 @@ func (q *DeleteQuery) Apply(fn func(*DeleteQuery) *DeleteQuery) *DeleteQuery {
 	return q
 }
 
+// ApplyIf calls the fn passing the DeleteQuery as an argument only if cond is true.
+func (q *DeleteQuery) ApplyIf(cond bool, fn func(*DeleteQuery) *DeleteQuery) *DeleteQuery {
+	if cond {
+		return q.Apply(fn)
+	}
+	return q
+}
+
 func (q *DeleteQuery) With(name string, query schema.QueryAppender) *DeleteQuery {
 	q.addWith(name, query, false)
 	return q
@@ -91,11 +114,27 @@ func (q *DeleteQuery) Where(query string, args ...interface{}) *DeleteQuery {
 	return q
 }
 
+// WhereIf is like Where, but adds the condition only if cond is true, for example,
+// WhereIf(name != "", "name = ?", name).
+func (q *DeleteQuery) WhereIf(cond bool, query string, args ...interface{}) *DeleteQuery {
+	if cond {
+		return q.Where(query, args...)
+	}
+	return q
+}
+
 func (q *DeleteQuery) WhereOr(query string, args ...interface{}) *DeleteQuery {
 	q.addWhere(schema.SafeQueryWithSep(query, args, " OR "))
 	return q
 }
 
+// WhereMap adds a "column = value" condition for each map entry, or "column IS NULL"
+// for nil values. Conditions are sorted by column name and joined with AND.
+func (q *DeleteQuery) WhereMap(conditions map[string]interface{}) *DeleteQuery {
+	q.addWhereMap(conditions)
+	return q
+}
+
 func (q *DeleteQuery) WhereGroup(sep string, fn func(*DeleteQuery) *DeleteQuery) *DeleteQuery {
 	saved := q.where
 	q.where = nil
@@ -127,6 +166,57 @@ func (q *DeleteQuery) ForceDelete() *DeleteQuery {
 
 //------------------------------------------------------------------------------
 
+// Order adds an ORDER BY clause to the query. Only MySQL supports ORDER BY
+// in DELETE queries.
+func (q *DeleteQuery) Order(orders ...string) *DeleteQuery {
+	q.order = appendOrder(q.order, orders...)
+	return q
+}
+
+// OrderIf is like Order, but adds the orders only if cond is true.
+func (q *DeleteQuery) OrderIf(cond bool, orders ...string) *DeleteQuery {
+	if cond {
+		return q.Order(orders...)
+	}
+	return q
+}
+
+func (q *DeleteQuery) OrderExpr(query string, args ...interface{}) *DeleteQuery {
+	q.order = append(q.order, schema.SafeQuery(query, args))
+	return q
+}
+
+// Limit adds a LIMIT clause to the query. Only MySQL supports LIMIT
+// in DELETE queries.
+func (q *DeleteQuery) Limit(n int) *DeleteQuery {
+	q.limit = int32(n)
+	return q
+}
+
+// LimitIf is like Limit, but sets the limit only if cond is true.
+func (q *DeleteQuery) LimitIf(cond bool, n int) *DeleteQuery {
+	if cond {
+		return q.Limit(n)
+	}
+	return q
+}
+
+// Chunk splits the deleted primary keys into batches with at most size keys
+// and executes a DELETE query for each batch. The keys come either from the slice model
+// used with WherePK or from the bun.In argument of the WHERE conditions. The batches are
+// executed one by one using the query connection, so they share the transaction
+// passed to Conn. Exec returns the total number of deleted rows.
+func (q *DeleteQuery) Chunk(size int) *DeleteQuery {
+	if size <= 0 {
+		q.setErr(fmt.Errorf("bun: Chunk(%d) requires a positive size", size))
+		return q
+	}
+	q.chunkSize = size
+	return q
+}
+
+//------------------------------------------------------------------------------
+
 // Returning adds a RETURNING clause to the query.
 //
 // To suppress the auto-generated RETURNING clause, use `Returning("NULL")`.
@@ -156,14 +246,17 @@ func (q *DeleteQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, e
 		}
 
 		upd := &UpdateQuery{
-			whereBaseQuery: q.whereBaseQuery,
-			returningQuery: q.returningQuery,
+			whereBaseQuery:  q.whereBaseQuery,
+			returningQuery:  q.returningQuery,
+			orderLimitQuery: q.orderLimitQuery,
 		}
 		upd.Set(q.softDeleteSet(fmter, now))
 
 		return upd.AppendQuery(fmter, b)
 	}
 
+	b = q.appendComment(b)
+
 	withAlias := q.db.features.Has(feature.DeleteTableAlias)
 
 	b, err = q.appendWith(fmter, b)
@@ -203,6 +296,16 @@ func (q *DeleteQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, e
 		return nil, err
 	}
 
+	if q.hasOrderLimit() {
+		if !q.hasFeature(feature.DeleteOrderLimit) {
+			return nil, fmt.Errorf("bun: DELETE ... ORDER BY/LIMIT is not supported by %s", q.db.dialect.Name())
+		}
+		b, err = q.appendOrderLimit(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if q.hasFeature(feature.Returning) && q.hasReturning() {
 		b = append(b, " RETURNING "...)
 		b, err = q.appendReturning(fmter, b)
@@ -233,12 +336,95 @@ func (q *DeleteQuery) softDeleteSet(fmter schema.Formatter, tm time.Time) string
 //------------------------------------------------------------------------------
 
 func (q *DeleteQuery) Scan(ctx context.Context, dest ...interface{}) error {
-	_, err := q.scanOrExec(ctx, dest, true)
+	_, err := q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		return q.scanOrExec(ctx, dest, true)
+	})
 	return err
 }
 
 func (q *DeleteQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
-	return q.scanOrExec(ctx, dest, len(dest) > 0)
+	return q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		if q.chunkSize > 0 {
+			return q.execChunks(ctx, dest)
+		}
+		return q.scanOrExec(ctx, dest, len(dest) > 0)
+	})
+}
+
+func (q *DeleteQuery) execChunks(ctx context.Context, dest []interface{}) (sql.Result, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if len(dest) > 0 {
+		return nil, errors.New("bun: Chunk does not support Exec with dest")
+	}
+
+	queries, err := q.chunkQueries()
+	if err != nil {
+		return nil, err
+	}
+
+	var affected int64
+	for _, chunk := range queries {
+		res, err := chunk.scanOrExec(ctx, nil, false)
+		if err != nil {
+			return nil, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		affected += n
+	}
+	return driver.RowsAffected(affected), nil
+}
+
+// chunkQueries returns copies of the query that delete the batches of primary keys.
+func (q *DeleteQuery) chunkQueries() ([]*DeleteQuery, error) {
+	if model, ok := q.model.(*sliceTableModel); ok && q.whereFields != nil {
+		queries := make([]*DeleteQuery, 0, (model.sliceLen+q.chunkSize-1)/q.chunkSize)
+		for i := 0; i < model.sliceLen; i += q.chunkSize {
+			slice := reflect.New(model.slice.Type())
+			slice.Elem().Set(model.slice.Slice(i, min(i+q.chunkSize, model.sliceLen)))
+
+			chunk := *q
+			chunk.setModel(slice.Interface())
+			queries = append(queries, &chunk)
+		}
+		return queries, nil
+	}
+
+	var queries []*DeleteQuery
+	for i, where := range q.where {
+		for j, arg := range where.Args {
+			appender, ok := arg.(schema.QueryAppender)
+			if !ok {
+				continue
+			}
+			chunks, ok := schema.SplitIn(appender, q.chunkSize)
+			if !ok {
+				continue
+			}
+			if queries != nil {
+				return nil, errors.New("bun: Chunk requires exactly one bun.In argument")
+			}
+
+			queries = make([]*DeleteQuery, len(chunks))
+			for k, in := range chunks {
+				args := append([]interface{}(nil), where.Args...)
+				args[j] = in
+
+				chunk := *q
+				chunk.where = append([]schema.QueryWithSep(nil), q.where...)
+				chunk.where[i].Args = args
+				queries[k] = &chunk
+			}
+		}
+	}
+	if queries == nil {
+		return nil, errors.New("bun: Chunk requires a slice model with WherePK or a bun.In argument")
+	}
+	return queries, nil
 }
 
 func (q *DeleteQuery) scanOrExec(
@@ -260,7 +446,7 @@ func (q *DeleteQuery) scanOrExec(
 	}
 
 	// Generate the query before checking hasReturning.
-	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
 	if err != nil {
 		return nil, err
 	}
@@ -376,6 +562,36 @@ func (q *deleteQueryBuilder) WherePK(cols ...string) QueryBuilder {
 	return q
 }
 
+func (q *deleteQueryBuilder) Order(orders ...string) QueryBuilder {
+	q.DeleteQuery.Order(orders...)
+	return q
+}
+
+func (q *deleteQueryBuilder) OrderExpr(query string, args ...interface{}) QueryBuilder {
+	q.DeleteQuery.OrderExpr(query, args...)
+	return q
+}
+
+func (q *deleteQueryBuilder) Limit(n int) QueryBuilder {
+	q.DeleteQuery.Limit(n)
+	return q
+}
+
+func (q *deleteQueryBuilder) Offset(n int) QueryBuilder {
+	q.setErr(errors.New("bun: OFFSET is not supported by DELETE queries"))
+	return q
+}
+
+func (q *deleteQueryBuilder) Group(columns ...string) QueryBuilder {
+	q.setErr(errors.New("bun: GROUP BY is not supported by DELETE queries"))
+	return q
+}
+
+func (q *deleteQueryBuilder) GroupExpr(query string, args ...interface{}) QueryBuilder {
+	q.setErr(errors.New("bun: GROUP BY is not supported by DELETE queries"))
+	return q
+}
+
 func (q *deleteQueryBuilder) Unwrap() interface{} {
 	return q.DeleteQuery
 }
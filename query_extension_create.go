@@ -0,0 +1,123 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+type CreateExtensionQuery struct {
+	baseQuery
+
+	ifNotExists bool
+	cascade     bool
+	name        string
+	schema      string
+	version     string
+}
+
+var _ Query = (*CreateExtensionQuery)(nil)
+
+func NewCreateExtensionQuery(db *DB, name string) *CreateExtensionQuery {
+	q := &CreateExtensionQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+		name: name,
+	}
+	return q
+}
+
+func (q *CreateExtensionQuery) Conn(db IConn) *CreateExtensionQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *CreateExtensionQuery) Err(err error) *CreateExtensionQuery {
+	q.setErr(err)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *CreateExtensionQuery) IfNotExists() *CreateExtensionQuery {
+	q.ifNotExists = true
+	return q
+}
+
+// Schema sets the schema for the objects of the extension.
+func (q *CreateExtensionQuery) Schema(schema string) *CreateExtensionQuery {
+	q.schema = schema
+	return q
+}
+
+// Version sets the version of the extension to install.
+// By default, the version from the extension control file is installed.
+func (q *CreateExtensionQuery) Version(version string) *CreateExtensionQuery {
+	q.version = version
+	return q
+}
+
+// Cascade installs the extensions that the extension depends on.
+func (q *CreateExtensionQuery) Cascade() *CreateExtensionQuery {
+	q.cascade = true
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *CreateExtensionQuery) Operation() string {
+	return "CREATE EXTENSION"
+}
+
+func (q *CreateExtensionQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if !fmter.HasFeature(feature.Extension) {
+		return nil, fmt.Errorf("bun: CREATE EXTENSION is not supported by %s", q.db.dialect.Name())
+	}
+
+	b = append(b, "CREATE EXTENSION "...)
+	if q.ifNotExists {
+		b = append(b, "IF NOT EXISTS "...)
+	}
+	b = fmter.AppendIdent(b, q.name)
+
+	if q.schema != "" {
+		b = append(b, " SCHEMA "...)
+		b = fmter.AppendIdent(b, q.schema)
+	}
+	if q.version != "" {
+		b = append(b, " VERSION "...)
+		b = fmter.Dialect().AppendString(b, q.version)
+	}
+	if q.cascade {
+		b = append(b, " CASCADE"...)
+	}
+
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (q *CreateExtensionQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	res, err := q.exec(ctx, q, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,104 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+type DropExtensionQuery struct {
+	baseQuery
+	cascadeQuery
+
+	ifExists bool
+	name     string
+}
+
+var _ Query = (*DropExtensionQuery)(nil)
+
+func NewDropExtensionQuery(db *DB, name string) *DropExtensionQuery {
+	q := &DropExtensionQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+		name: name,
+	}
+	return q
+}
+
+func (q *DropExtensionQuery) Conn(db IConn) *DropExtensionQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *DropExtensionQuery) Err(err error) *DropExtensionQuery {
+	q.setErr(err)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *DropExtensionQuery) IfExists() *DropExtensionQuery {
+	q.ifExists = true
+	return q
+}
+
+// Cascade drops the objects that depend on the extension, for example,
+// the columns that use the types of the extension.
+func (q *DropExtensionQuery) Cascade() *DropExtensionQuery {
+	q.cascade = true
+	return q
+}
+
+func (q *DropExtensionQuery) Restrict() *DropExtensionQuery {
+	q.restrict = true
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *DropExtensionQuery) Operation() string {
+	return "DROP EXTENSION"
+}
+
+func (q *DropExtensionQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if !fmter.HasFeature(feature.Extension) {
+		return nil, fmt.Errorf("bun: DROP EXTENSION is not supported by %s", q.db.dialect.Name())
+	}
+
+	b = append(b, "DROP EXTENSION "...)
+	if q.ifExists {
+		b = append(b, "IF EXISTS "...)
+	}
+	b = fmter.AppendIdent(b, q.name)
+
+	b = q.appendCascade(fmter, b)
+
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (q *DropExtensionQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	res, err := q.exec(ctx, q, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
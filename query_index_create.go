@@ -3,7 +3,11 @@ package bun
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
 
+	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
 	"github.com/uptrace/bun/schema"
 )
@@ -56,6 +60,8 @@ func (q *CreateIndexQuery) Unique() *CreateIndexQuery {
 	return q
 }
 
+// Concurrently builds the index without locking out writes to the table.
+// The keyword is omitted for dialects that don't support it.
 func (q *CreateIndexQuery) Concurrently() *CreateIndexQuery {
 	q.concurrently = true
 	return q
@@ -111,8 +117,15 @@ func (q *CreateIndexQuery) Column(columns ...string) *CreateIndexQuery {
 	return q
 }
 
+// ColumnExpr adds a column or an expression to the index, for example, "email DESC"
+// or "lower(?) DESC". Entries that are not a plain column name are expression indexes:
+// they are enclosed in parentheses before the ASC, DESC, NULLS, and COLLATE options,
+// which is required by MySQL and allows any expression in PostgreSQL and SQLite.
+// Dialects without expression indexes return an error for such entries.
 func (q *CreateIndexQuery) ColumnExpr(query string, args ...interface{}) *CreateIndexQuery {
-	q.addColumn(schema.SafeQuery(query, args))
+	q.addColumn(schema.SafeQuery("?", []interface{}{
+		indexExpr{query: schema.SafeQuery(query, args)},
+	}))
 	return q
 }
 
@@ -137,6 +150,7 @@ func (q *CreateIndexQuery) IncludeExpr(query string, args ...interface{}) *Creat
 
 //------------------------------------------------------------------------------
 
+// Where adds a condition that makes a partial index, which only includes matching rows.
 func (q *CreateIndexQuery) Where(query string, args ...interface{}) *CreateIndexQuery {
 	q.addWhere(schema.SafeQueryWithSep(query, args, " AND "))
 	return q
@@ -172,7 +186,7 @@ func (q *CreateIndexQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 
 	b = append(b, "INDEX "...)
 
-	if q.concurrently {
+	if q.concurrently && fmter.HasFeature(feature.IndexConcurrently) {
 		b = append(b, "CONCURRENTLY "...)
 	}
 	if q.ifNotExists {
@@ -203,6 +217,15 @@ func (q *CreateIndexQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 		if i > 0 {
 			b = append(b, ", "...)
 		}
+		if len(col.Args) == 1 {
+			if expr, ok := col.Args[0].(indexExpr); ok {
+				b, err = expr.appendQuery(fmter, b)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
 		b, err = col.AppendQuery(fmter, b)
 		if err != nil {
 			return nil, err
@@ -238,7 +261,7 @@ func (q *CreateIndexQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 //------------------------------------------------------------------------------
 
 func (q *CreateIndexQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
-	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
 	if err != nil {
 		return nil, err
 	}
@@ -252,3 +275,83 @@ func (q *CreateIndexQuery) Exec(ctx context.Context, dest ...interface{}) (sql.R
 
 	return res, nil
 }
+
+//------------------------------------------------------------------------------
+
+// indexExpr is an index entry added with ColumnExpr.
+type indexExpr struct {
+	query schema.QueryWithArgs
+}
+
+var _ schema.QueryAppender = indexExpr{}
+
+func (e indexExpr) AppendQuery(fmter schema.Formatter, b []byte) ([]byte, error) {
+	return e.appendQuery(fmter, b)
+}
+
+var (
+	indexIdentRE = `(?:[A-Za-z_][A-Za-z0-9_$]*|"[^"]*"|` + "`[^`]*`" + `|\[[^\]]*\]|\?)`
+	// indexColumnRE matches a column with options, for example, `"email" COLLATE "C" DESC`
+	// or "email text_pattern_ops".
+	indexColumnRE = regexp.MustCompile(
+		`^` + indexIdentRE + `(?:\.` + indexIdentRE + `)*(?:\s+` + indexIdentRE + `)*$`)
+	// indexOptionsRE matches the options that follow an expression.
+	indexOptionsRE = regexp.MustCompile(
+		`(?i)(?:\s+COLLATE\s+` + indexIdentRE + `)?(?:\s+(?:ASC|DESC))?(?:\s+NULLS\s+(?:FIRST|LAST))?$`)
+)
+
+func (e indexExpr) appendQuery(fmter schema.Formatter, b []byte) ([]byte, error) {
+	query, err := e.query.AppendQuery(fmter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s := strings.TrimSpace(string(query))
+	if indexColumnRE.MatchString(s) {
+		return append(b, s...), nil
+	}
+
+	if !fmter.IsNop() && !fmter.HasFeature(feature.ExpressionIndex) {
+		return nil, fmt.Errorf("bun: expression indexes are not supported by %s",
+			fmter.Dialect().Name())
+	}
+
+	loc := indexOptionsRE.FindStringIndex(s)
+	expr, opts := s[:loc[0]], s[loc[0]:]
+	if !isParenthesized(expr) {
+		b = append(b, '(')
+		b = append(b, expr...)
+		b = append(b, ')')
+	} else {
+		b = append(b, expr...)
+	}
+	return append(b, opts...), nil
+}
+
+// isParenthesized reports whether s is enclosed in a single pair of parentheses.
+func isParenthesized(s string) bool {
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return false
+	}
+	var depth int
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 && i < len(s)-1 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
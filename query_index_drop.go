@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
 	"github.com/uptrace/bun/schema"
 )
@@ -47,6 +48,8 @@ func (q *DropIndexQuery) Err(err error) *DropIndexQuery {
 
 //------------------------------------------------------------------------------
 
+// Concurrently drops the index without locking out queries on the table.
+// The keyword is omitted for dialects that don't support it.
 func (q *DropIndexQuery) Concurrently() *DropIndexQuery {
 	q.concurrently = true
 	return q
@@ -85,7 +88,7 @@ func (q *DropIndexQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte
 
 	b = append(b, "DROP INDEX "...)
 
-	if q.concurrently {
+	if q.concurrently && fmter.HasFeature(feature.IndexConcurrently) {
 		b = append(b, "CONCURRENTLY "...)
 	}
 	if q.ifExists {
@@ -105,7 +108,7 @@ func (q *DropIndexQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte
 //------------------------------------------------------------------------------
 
 func (q *DropIndexQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
-	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
 	if err != nil {
 		return nil, err
 	}
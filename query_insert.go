@@ -3,10 +3,13 @@ package bun
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
+	"github.com/uptrace/bun/dialect"
 	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
 	"github.com/uptrace/bun/schema"
@@ -20,8 +23,15 @@ type InsertQuery struct {
 	on schema.QueryWithArgs
 	setQuery
 
-	ignore  bool
-	replace bool
+	ignore   bool
+	replace  bool
+	omitZero bool
+
+	rows []map[string]interface{}
+
+	conflictPK   bool
+	doUpdateAll  bool
+	returnAction bool
 }
 
 var _ Query = (*InsertQuery)(nil)
@@ -53,6 +63,14 @@ func (q *InsertQuery) Err(err error) *InsertQuery {
 	return q
 }
 
+// Comment prepends the comment to the query, for example, /* comment */ INSERT ...
+// Comment delimiters are removed from the comment. The comment takes precedence over
+// the comment set with WithQueryComment.
+func (q *InsertQuery) Comment(comment string) *InsertQuery {
+	q.setComment(comment)
+	return q
+}
+
 // Apply calls the fn passing the SelectQuery as an argument.
 func (q *InsertQuery) Apply(fn func(*InsertQuery) *InsertQuery) *InsertQuery {
 	if fn != nil {
@@ -119,6 +137,20 @@ func (q *InsertQuery) Value(column string, expr string, args ...interface{}) *In
 	return q
 }
 
+// Row adds a row of column values to the VALUES clause. The columns are the union
+// of the keys of all rows; rows without some of the columns get NULL values.
+// Values are appended like query arguments, so maps and structs are marshaled as JSON.
+// The table is set with Table or Model and the model values are ignored:
+//
+//	db.NewInsert().
+//		Table("events").
+//		Row(map[string]interface{}{"id": 1, "name": "signup"}).
+//		Row(map[string]interface{}{"id": 2, "payload": json.RawMessage(`{}`)})
+func (q *InsertQuery) Row(values map[string]interface{}) *InsertQuery {
+	q.rows = append(q.rows, values)
+	return q
+}
+
 func (q *InsertQuery) Where(query string, args ...interface{}) *InsertQuery {
 	q.addWhere(schema.SafeQueryWithSep(query, args, " AND "))
 	return q
@@ -139,6 +171,39 @@ func (q *InsertQuery) Returning(query string, args ...interface{}) *InsertQuery
 	return q
 }
 
+// Output adds an OUTPUT clause written for MSSQL, for example,
+// Output("INSERTED.id, INSERTED.created_at"). On the other dialects, it adds
+// a RETURNING clause with the INSERTED qualifiers removed.
+func (q *InsertQuery) Output(query string, args ...interface{}) *InsertQuery {
+	query, err := outputQuery(q.db, query)
+	if err != nil {
+		q.setErr(err)
+		return q
+	}
+	q.addReturning(schema.SafeQuery(query, args))
+	return q
+}
+
+// ReturnAction reports whether each row was inserted or updated by an upsert.
+//
+// Deprecated: use ReturnInserted, which also supports the `bun:"inserted,scanonly"` field.
+func (q *InsertQuery) ReturnAction() *InsertQuery {
+	return q.ReturnInserted()
+}
+
+// ReturnInserted reports whether each row was inserted or updated by an upsert
+// in the model's field tagged with `bun:",was_inserted,scanonly"`, or in the
+// `bun:"inserted,scanonly"` field if the model has no such field:
+//   - On PostgreSQL, it appends `(xmax = 0) AS was_inserted` to the RETURNING clause.
+//   - On MySQL, it uses the number of affected rows, which is 1 for inserted rows
+//     and 2 for updated rows. The number can't tell the rows of a batch apart,
+//     for example, one updated and one unchanged row look like two inserted rows,
+//     so models with more than one row are rejected before the query is executed.
+func (q *InsertQuery) ReturnInserted() *InsertQuery {
+	q.returnAction = true
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 // Ignore generates different queries depending on the DBMS:
@@ -160,6 +225,16 @@ func (q *InsertQuery) Replace() *InsertQuery {
 	return q
 }
 
+// OmitZero excludes the columns with zero values from the query so the database defaults
+// are used instead. Pointer fields are only excluded when they are nil.
+// When inserting a slice, a column is excluded only if it is zero in every row
+// and rows with zero values in the included columns are inserted with NULL.
+// The excluded columns are added to the RETURNING clause.
+func (q *InsertQuery) OmitZero() *InsertQuery {
+	q.omitZero = true
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 func (q *InsertQuery) Operation() string {
@@ -172,6 +247,7 @@ func (q *InsertQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, e
 	}
 
 	fmter = formatterWithModel(fmter, q)
+	b = q.appendComment(b)
 
 	b, err = q.appendWith(fmter, b)
 	if err != nil {
@@ -207,17 +283,68 @@ func (q *InsertQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, e
 		return nil, err
 	}
 
+	if q.returnAction {
+		if err := q.checkReturnAction(); err != nil {
+			return nil, err
+		}
+	}
+
 	if q.hasFeature(feature.InsertReturning) && q.hasReturning() {
 		b = append(b, " RETURNING "...)
 		b, err = q.appendReturning(fmter, b)
 		if err != nil {
 			return nil, err
 		}
+		if q.returnsAction() {
+			b = append(b, ", "...)
+		}
+	}
+	if q.returnsAction() {
+		if !q.hasReturning() {
+			b = append(b, " RETURNING "...)
+		}
+		b = append(b, "(xmax = 0) AS "...)
+		b = append(b, q.insertedField().SQLName...)
 	}
 
 	return b, nil
 }
 
+// insertedColumn is the column that ReturnInserted scans the action into
+// when the model doesn't have a field with the was_inserted option.
+const insertedColumn = "inserted"
+
+func (q *InsertQuery) insertedField() *schema.Field {
+	if q.table.WasInsertedField != nil {
+		return q.table.WasInsertedField
+	}
+	return q.table.FieldMap[insertedColumn]
+}
+
+func (q *InsertQuery) checkReturnAction() error {
+	if q.table == nil || q.insertedField() == nil {
+		return fmt.Errorf("bun: ReturnInserted requires a model with the %q column "+
+			"or a field with the was_inserted option", insertedColumn)
+	}
+	switch q.db.dialect.Name() {
+	case dialect.PG:
+		return nil
+	case dialect.MySQL:
+		if model, ok := q.tableModel.(*sliceTableModel); ok && model.slice.Len() > 1 {
+			return fmt.Errorf("bun: ReturnInserted on %s supports only one row per query "+
+				"(got %d rows)", q.db.dialect.Name(), model.slice.Len())
+		}
+		return nil
+	default:
+		return fmt.Errorf("bun: ReturnInserted is not supported by %s", q.db.dialect.Name())
+	}
+}
+
+// returnsAction reports whether the action is selected with the RETURNING clause.
+func (q *InsertQuery) returnsAction() bool {
+	return q.returnAction && q.db.dialect.Name() == dialect.PG
+}
+
 func (q *InsertQuery) appendColumnsValues(
 	fmter schema.Formatter, b []byte, skipOutput bool,
 ) (_ []byte, err error) {
@@ -259,6 +386,9 @@ func (q *InsertQuery) appendColumnsValues(
 		return b, nil
 	}
 
+	if len(q.rows) > 0 {
+		return q.appendRows(fmter, b, skipOutput)
+	}
 	if m, ok := q.model.(*mapModel); ok {
 		return m.appendColumnsValues(fmter, b), nil
 	}
@@ -310,6 +440,65 @@ func (q *InsertQuery) appendColumnsValues(
 	return b, nil
 }
 
+func (q *InsertQuery) appendRows(
+	fmter schema.Formatter, b []byte, skipOutput bool,
+) (_ []byte, err error) {
+	var columns []string
+	seen := make(map[string]struct{})
+	for _, row := range q.rows {
+		for column := range row {
+			if _, ok := seen[column]; !ok {
+				seen[column] = struct{}{}
+				columns = append(columns, column)
+			}
+		}
+	}
+	if len(columns) == 0 {
+		return nil, errors.New("bun: Row requires at least one column")
+	}
+	sort.Strings(columns)
+
+	b = append(b, " ("...)
+	for i, column := range columns {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = fmter.AppendIdent(b, column)
+	}
+	b = append(b, ")"...)
+
+	if q.hasFeature(feature.Output) && q.hasReturning() && !skipOutput {
+		b = append(b, " OUTPUT "...)
+		b, err = q.appendOutput(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	b = append(b, " VALUES "...)
+
+	isTemplate := fmter.IsNop()
+	for i, row := range q.rows {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = append(b, '(')
+		for j, column := range columns {
+			if j > 0 {
+				b = append(b, ", "...)
+			}
+			if isTemplate {
+				b = append(b, '?')
+			} else {
+				b = schema.Append(fmter, b, row[column])
+			}
+		}
+		b = append(b, ')')
+	}
+
+	return b, nil
+}
+
 func (q *InsertQuery) appendStructValues(
 	fmter schema.Formatter, b []byte, fields []*schema.Field, strct reflect.Value,
 ) (_ []byte, err error) {
@@ -332,6 +521,8 @@ func (q *InsertQuery) appendStructValues(
 		switch {
 		case isTemplate:
 			b = append(b, '?')
+		case q.omitZero && isOmittedZero(f, strct):
+			b = append(b, "NULL"...)
 		case q.marshalsToDefault(f, strct):
 			if q.db.HasFeature(feature.DefaultPlaceholder) {
 				b = append(b, "DEFAULT"...)
@@ -383,11 +574,33 @@ func (q *InsertQuery) appendSliceValues(
 }
 
 func (q *InsertQuery) getFields() ([]*schema.Field, error) {
+	fields, err := q._getFields()
+	if err != nil {
+		return nil, err
+	}
+	fields, err = q.withoutUnloadedLazyFields(fields, false)
+	if err != nil {
+		return nil, err
+	}
+	return q.withoutPeriodFields(fields), nil
+}
+
+func (q *InsertQuery) _getFields() ([]*schema.Field, error) {
 	hasIdentity := q.db.features.Has(feature.Identity)
 
-	if len(q.columns) > 0 || q.db.features.Has(feature.DefaultPlaceholder) && !hasIdentity {
+	if len(q.columns) > 0 {
 		return q.baseQuery.getFields()
 	}
+	if q.omitZero {
+		return q.getNonZeroFields()
+	}
+	if q.db.features.Has(feature.DefaultPlaceholder) && !hasIdentity {
+		fields, err := q.baseQuery.getFields()
+		if err != nil {
+			return nil, err
+		}
+		return withoutGeneratedFields(fields), nil
+	}
 
 	var strct reflect.Value
 
@@ -406,6 +619,9 @@ func (q *InsertQuery) getFields() ([]*schema.Field, error) {
 	fields := make([]*schema.Field, 0, len(q.table.Fields))
 
 	for _, f := range q.table.Fields {
+		if f.SQLGenerated != "" {
+			continue
+		}
 		if hasIdentity && f.AutoIncrement {
 			q.addReturningField(f)
 			continue
@@ -420,6 +636,82 @@ func (q *InsertQuery) getFields() ([]*schema.Field, error) {
 	return fields, nil
 }
 
+// getNonZeroFields returns the fields that have non-zero values in at least one of the rows.
+func (q *InsertQuery) getNonZeroFields() ([]*schema.Field, error) {
+	hasIdentity := q.db.features.Has(feature.Identity)
+
+	var rows []reflect.Value
+
+	switch model := q.tableModel.(type) {
+	case *structTableModel:
+		rows = []reflect.Value{model.strct}
+	case *sliceTableModel:
+		if model.sliceLen == 0 {
+			return nil, fmt.Errorf("bun: Insert(empty %T)", model.slice.Type())
+		}
+		rows = make([]reflect.Value, model.sliceLen)
+		for i := range rows {
+			rows[i] = indirect(model.slice.Index(i))
+		}
+	default:
+		return nil, errNilModel
+	}
+
+	fields := make([]*schema.Field, 0, len(q.table.Fields))
+
+	for _, f := range q.table.Fields {
+		if f.SQLGenerated != "" {
+			continue
+		}
+		if hasIdentity && f.AutoIncrement {
+			q.addReturningField(f)
+			continue
+		}
+		if hasNonZeroValue(f, rows) {
+			fields = append(fields, f)
+		} else {
+			q.addReturningField(f)
+		}
+	}
+
+	return fields, nil
+}
+
+func hasNonZeroValue(f *schema.Field, rows []reflect.Value) bool {
+	for _, row := range rows {
+		if !isOmittedZero(f, row) {
+			return true
+		}
+	}
+	return false
+}
+
+// isOmittedZero reports whether OmitZero omits the value of the field.
+func isOmittedZero(f *schema.Field, strct reflect.Value) bool {
+	if f.IsPtr {
+		return f.HasNilValue(strct)
+	}
+	return f.HasZeroValue(strct)
+}
+
+func withoutGeneratedFields(fields []*schema.Field) []*schema.Field {
+	for i, f := range fields {
+		if f.SQLGenerated == "" {
+			continue
+		}
+
+		filtered := make([]*schema.Field, 0, len(fields)-1)
+		filtered = append(filtered, fields[:i]...)
+		for _, f := range fields[i+1:] {
+			if f.SQLGenerated == "" {
+				filtered = append(filtered, f)
+			}
+		}
+		return filtered
+	}
+	return fields
+}
+
 // marshalsToDefault checks if the value will be marshaled as DEFAULT or NULL (if DEFAULT placeholder is not supported)
 // when appending it to the VALUES clause in place of the given field.
 func (q InsertQuery) marshalsToDefault(f *schema.Field, v reflect.Value) bool {
@@ -447,6 +739,63 @@ func (q *InsertQuery) On(s string, args ...interface{}) *InsertQuery {
 	return q
 }
 
+// OnConflictPK uses the model's primary keys as the conflict target:
+//   - On PostgreSQL and SQLite, it generates `ON CONFLICT (pk) DO NOTHING`.
+//   - On MySQL, it generates `INSERT IGNORE INTO`.
+//
+// Combine it with DoUpdateAllColumns to update the existing row instead.
+func (q *InsertQuery) OnConflictPK() *InsertQuery {
+	if q.table == nil {
+		q.setErr(errNilModel)
+		return q
+	}
+	if err := q.table.CheckPKs(); err != nil {
+		q.setErr(err)
+		return q
+	}
+	q.conflictPK = true
+	return q.onConflictPK()
+}
+
+// DoUpdateAllColumns updates all non-PK columns on conflict, e.g.
+// `ON CONFLICT (pk) DO UPDATE SET col = EXCLUDED.col` or
+// `ON DUPLICATE KEY UPDATE col = VALUES(col)` on MySQL.
+// It can't be combined with Set, because a column can be updated only once;
+// use Set for each updated column instead. If the model has only PK columns,
+// there is nothing to update and the existing row is kept as with OnConflictPK.
+func (q *InsertQuery) DoUpdateAllColumns() *InsertQuery {
+	q.doUpdateAll = true
+	if q.conflictPK {
+		return q.onConflictPK()
+	}
+	return q
+}
+
+func (q *InsertQuery) onConflictPK() *InsertQuery {
+	// A model without data columns has nothing to update.
+	doUpdate := q.doUpdateAll && len(withoutGeneratedFields(q.table.DataFields)) > 0
+
+	switch {
+	case q.db.fmter.HasFeature(feature.InsertOnConflict):
+		action := "DO NOTHING"
+		if doUpdate {
+			action = "DO UPDATE"
+		}
+		pks := Safe(appendColumns(nil, "", q.table.PKs))
+		return q.On("CONFLICT (?) "+action, pks)
+	case q.db.fmter.HasFeature(feature.InsertOnDuplicateKey):
+		if doUpdate {
+			q.ignore = false
+			return q.On("DUPLICATE KEY UPDATE")
+		}
+		q.ignore = q.db.fmter.HasFeature(feature.InsertIgnore)
+		return q
+	default:
+		q.setErr(fmt.Errorf("bun: OnConflictPK is not supported by %s", q.db.dialect.Name()))
+		return q
+	}
+}
+
 func (q *InsertQuery) Set(query string, args ...interface{}) *InsertQuery {
 	q.addSet(schema.SafeQuery(query, args))
 	return q
@@ -464,6 +813,9 @@ func (q *InsertQuery) appendOn(fmter schema.Formatter, b []byte) (_ []byte, err
 	}
 
 	if len(q.set) > 0 {
+		if q.doUpdateAll {
+			return nil, errors.New("bun: DoUpdateAllColumns can't be combined with Set")
+		}
 		if fmter.HasFeature(feature.InsertOnDuplicateKey) {
 			b = append(b, ' ')
 		} else {
@@ -480,8 +832,12 @@ func (q *InsertQuery) appendOn(fmter schema.Formatter, b []byte) (_ []byte, err
 			return nil, err
 		}
 
-		if len(fields) == 0 {
-			fields = q.tableModel.Table().DataFields
+		if len(fields) == 0 || q.doUpdateAll {
+			fields = withoutGeneratedFields(q.tableModel.Table().DataFields)
+		}
+		fields, err = q.withoutUnloadedLazyFields(fields, true)
+		if err != nil {
+			return nil, err
 		}
 
 		b = q.appendSetExcluded(b, fields)
@@ -491,8 +847,12 @@ func (q *InsertQuery) appendOn(fmter schema.Formatter, b []byte) (_ []byte, err
 			return nil, err
 		}
 
-		if len(fields) == 0 {
-			fields = q.tableModel.Table().DataFields
+		if len(fields) == 0 || q.doUpdateAll {
+			fields = withoutGeneratedFields(q.tableModel.Table().DataFields)
+		}
+		fields, err = q.withoutUnloadedLazyFields(fields, true)
+		if err != nil {
+			return nil, err
 		}
 
 		b = q.appendSetValues(b, fields)
@@ -548,12 +908,16 @@ func (q *InsertQuery) appendSetValues(b []byte, fields []*schema.Field) []byte {
 //------------------------------------------------------------------------------
 
 func (q *InsertQuery) Scan(ctx context.Context, dest ...interface{}) error {
-	_, err := q.scanOrExec(ctx, dest, true)
+	_, err := q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		return q.scanOrExec(ctx, dest, true)
+	})
 	return err
 }
 
 func (q *InsertQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
-	return q.scanOrExec(ctx, dest, len(dest) > 0)
+	return q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		return q.scanOrExec(ctx, dest, len(dest) > 0)
+	})
 }
 
 func (q *InsertQuery) scanOrExec(
@@ -567,6 +931,15 @@ func (q *InsertQuery) scanOrExec(
 		if err := q.beforeInsertHook(ctx); err != nil {
 			return nil, err
 		}
+		if err := q.checkRedactedFields(ctx, q.baseQuery.getFields); err != nil {
+			return nil, err
+		}
+	}
+
+	if q.tableModel != nil && q.table.HasGeneratedIDFields() {
+		if err := q.tableModel.generateIDs(); err != nil {
+			return nil, err
+		}
 	}
 
 	// Run append model hooks before generating the query.
@@ -575,12 +948,13 @@ func (q *InsertQuery) scanOrExec(
 	}
 
 	// Generate the query before checking hasReturning.
-	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
 	if err != nil {
 		return nil, err
 	}
 
-	useScan := hasDest || (q.hasReturning() && q.hasFeature(feature.InsertReturning|feature.Output))
+	useScan := hasDest || q.returnsAction() ||
+		(q.hasReturning() && q.hasFeature(feature.InsertReturning|feature.Output))
 	var model Model
 
 	if useScan {
@@ -608,6 +982,11 @@ func (q *InsertQuery) scanOrExec(
 		if err := q.tryLastInsertID(res, dest); err != nil {
 			return nil, err
 		}
+		if q.returnAction {
+			if err := q.scanAffectedAction(res, dest); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if q.table != nil {
@@ -679,6 +1058,33 @@ func (q *InsertQuery) tryLastInsertID(res sql.Result, dest []interface{}) error
 	return nil
 }
 
+// scanAffectedAction sets the inserted field using the number of affected rows on MySQL.
+func (q *InsertQuery) scanAffectedAction(res sql.Result, dest []interface{}) error {
+	model, err := q.getModel(dest)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	// Inserted rows are counted once and updated rows twice. Rows that didn't change
+	// are not counted, so they are reported as updated.
+	field := q.insertedField()
+	switch model := model.(type) {
+	case *structTableModel:
+		return field.ScanValue(model.strct, n == 1)
+	case *sliceTableModel:
+		// checkReturnAction rejects the slices with more than one row.
+		if model.slice.Len() == 1 {
+			return field.ScanValue(indirect(model.slice.Index(0)), n == 1)
+		}
+	}
+	return nil
+}
+
 func (q *InsertQuery) String() string {
 	buf, err := q.AppendQuery(q.db.Formatter(), nil)
 	if err != nil {
@@ -211,12 +211,16 @@ func (q *MergeQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, er
 //------------------------------------------------------------------------------
 
 func (q *MergeQuery) Scan(ctx context.Context, dest ...interface{}) error {
-	_, err := q.scanOrExec(ctx, dest, true)
+	_, err := q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		return q.scanOrExec(ctx, dest, true)
+	})
 	return err
 }
 
 func (q *MergeQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
-	return q.scanOrExec(ctx, dest, len(dest) > 0)
+	return q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		return q.scanOrExec(ctx, dest, len(dest) > 0)
+	})
 }
 
 func (q *MergeQuery) scanOrExec(
@@ -232,7 +236,7 @@ func (q *MergeQuery) scanOrExec(
 	}
 
 	// Generate the query before checking hasReturning.
-	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
 	if err != nil {
 		return nil, err
 	}
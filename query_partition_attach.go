@@ -0,0 +1,108 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+// AttachPartitionQuery attaches an existing table as a partition:
+//
+//	ALTER TABLE parent ATTACH PARTITION partition FOR VALUES ...
+type AttachPartitionQuery struct {
+	baseQuery
+	partitionBound
+
+	parent    schema.QueryWithArgs
+	partition schema.QueryWithArgs
+}
+
+var _ Query = (*AttachPartitionQuery)(nil)
+
+func NewAttachPartitionQuery(db *DB, parent, partition string) *AttachPartitionQuery {
+	q := &AttachPartitionQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+		parent:    schema.UnsafeIdent(parent),
+		partition: schema.UnsafeIdent(partition),
+	}
+	return q
+}
+
+func (q *AttachPartitionQuery) Conn(db IConn) *AttachPartitionQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *AttachPartitionQuery) Err(err error) *AttachPartitionQuery {
+	q.setErr(err)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+// For sets the partition bound that follows FOR VALUES.
+// See CreateTablePartitionQuery.For for examples.
+func (q *AttachPartitionQuery) For(query string, args ...interface{}) *AttachPartitionQuery {
+	q.setBound(query, args)
+	return q
+}
+
+// Default attaches the table as the default partition.
+func (q *AttachPartitionQuery) Default() *AttachPartitionQuery {
+	q.setDefault()
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *AttachPartitionQuery) Operation() string {
+	return "ALTER TABLE"
+}
+
+func (q *AttachPartitionQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if !fmter.HasFeature(feature.TablePartition) {
+		return nil, fmt.Errorf("bun: table partitions are not supported by %s", q.db.dialect.Name())
+	}
+
+	b = append(b, "ALTER TABLE "...)
+	b, err = q.parent.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = append(b, " ATTACH PARTITION "...)
+	b, err = q.partition.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.appendBound(fmter, b)
+}
+
+//------------------------------------------------------------------------------
+
+func (q *AttachPartitionQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	res, err := q.exec(ctx, q, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
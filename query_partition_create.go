@@ -0,0 +1,153 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+// CreateTablePartitionQuery creates a partition of a table created with
+// CreateTableQuery.PartitionBy:
+//
+//	CREATE TABLE partition PARTITION OF parent FOR VALUES ...
+type CreateTablePartitionQuery struct {
+	baseQuery
+	partitionBound
+
+	ifNotExists bool
+	parent      schema.QueryWithArgs
+	partition   schema.QueryWithArgs
+}
+
+var _ Query = (*CreateTablePartitionQuery)(nil)
+
+func NewCreateTablePartitionQuery(db *DB, parent, partition string) *CreateTablePartitionQuery {
+	q := &CreateTablePartitionQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+		parent:    schema.UnsafeIdent(parent),
+		partition: schema.UnsafeIdent(partition),
+	}
+	return q
+}
+
+func (q *CreateTablePartitionQuery) Conn(db IConn) *CreateTablePartitionQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *CreateTablePartitionQuery) Err(err error) *CreateTablePartitionQuery {
+	q.setErr(err)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *CreateTablePartitionQuery) IfNotExists() *CreateTablePartitionQuery {
+	q.ifNotExists = true
+	return q
+}
+
+// For sets the partition bound that follows FOR VALUES, for example:
+//
+//	For("FROM (?) TO (?)", from, to)     // range partitioning
+//	For("IN (?)", bun.In(values))        // list partitioning
+//	For("WITH (MODULUS 4, REMAINDER 0)") // hash partitioning
+func (q *CreateTablePartitionQuery) For(query string, args ...interface{}) *CreateTablePartitionQuery {
+	q.setBound(query, args)
+	return q
+}
+
+// Default creates the default partition for the rows that don't fit other partitions.
+func (q *CreateTablePartitionQuery) Default() *CreateTablePartitionQuery {
+	q.setDefault()
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *CreateTablePartitionQuery) Operation() string {
+	return "CREATE TABLE"
+}
+
+func (q *CreateTablePartitionQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if !fmter.HasFeature(feature.TablePartition) {
+		return nil, fmt.Errorf("bun: table partitions are not supported by %s", q.db.dialect.Name())
+	}
+
+	b = append(b, "CREATE TABLE "...)
+	if q.ifNotExists {
+		b = append(b, "IF NOT EXISTS "...)
+	}
+
+	b, err = q.partition.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = append(b, " PARTITION OF "...)
+	b, err = q.parent.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.appendBound(fmter, b)
+}
+
+//------------------------------------------------------------------------------
+
+func (q *CreateTablePartitionQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	res, err := q.exec(ctx, q, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+//------------------------------------------------------------------------------
+
+// partitionBound is the FOR VALUES or DEFAULT clause of a partition.
+type partitionBound struct {
+	values    schema.QueryWithArgs
+	isDefault bool
+}
+
+func (p *partitionBound) setBound(query string, args []interface{}) {
+	p.values = schema.SafeQuery(query, args)
+	p.isDefault = false
+}
+
+func (p *partitionBound) setDefault() {
+	p.values = schema.QueryWithArgs{}
+	p.isDefault = true
+}
+
+func (p *partitionBound) appendBound(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if p.isDefault {
+		return append(b, " DEFAULT"...), nil
+	}
+	if p.values.IsZero() {
+		return nil, errors.New("bun: partition requires For or Default")
+	}
+
+	b = append(b, " FOR VALUES "...)
+	return p.values.AppendQuery(fmter, b)
+}
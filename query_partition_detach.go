@@ -0,0 +1,106 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+// DetachPartitionQuery detaches a partition, so it becomes a standalone table:
+//
+//	ALTER TABLE parent DETACH PARTITION partition
+type DetachPartitionQuery struct {
+	baseQuery
+
+	concurrently bool
+	parent       schema.QueryWithArgs
+	partition    schema.QueryWithArgs
+}
+
+var _ Query = (*DetachPartitionQuery)(nil)
+
+func NewDetachPartitionQuery(db *DB, parent, partition string) *DetachPartitionQuery {
+	q := &DetachPartitionQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+		parent:    schema.UnsafeIdent(parent),
+		partition: schema.UnsafeIdent(partition),
+	}
+	return q
+}
+
+func (q *DetachPartitionQuery) Conn(db IConn) *DetachPartitionQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *DetachPartitionQuery) Err(err error) *DetachPartitionQuery {
+	q.setErr(err)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+// Concurrently detaches the partition without blocking queries on the parent table.
+// It requires PostgreSQL 14 and can't run in a transaction.
+func (q *DetachPartitionQuery) Concurrently() *DetachPartitionQuery {
+	q.concurrently = true
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *DetachPartitionQuery) Operation() string {
+	return "ALTER TABLE"
+}
+
+func (q *DetachPartitionQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if !fmter.HasFeature(feature.TablePartition) {
+		return nil, fmt.Errorf("bun: table partitions are not supported by %s", q.db.dialect.Name())
+	}
+
+	b = append(b, "ALTER TABLE "...)
+	b, err = q.parent.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = append(b, " DETACH PARTITION "...)
+	b, err = q.partition.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.concurrently {
+		b = append(b, " CONCURRENTLY"...)
+	}
+
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (q *DetachPartitionQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	res, err := q.exec(ctx, q, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -48,11 +48,15 @@ func (q *RawQuery) Err(err error) *RawQuery {
 }
 
 func (q *RawQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
-	return q.scanOrExec(ctx, dest, len(dest) > 0)
+	return q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		return q.scanOrExec(ctx, dest, len(dest) > 0)
+	})
 }
 
 func (q *RawQuery) Scan(ctx context.Context, dest ...interface{}) error {
-	_, err := q.scanOrExec(ctx, dest, true)
+	_, err := q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		return q.scanOrExec(ctx, dest, true)
+	})
 	return err
 }
 
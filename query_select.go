@@ -4,11 +4,16 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/uptrace/bun/dialect"
 
@@ -29,13 +34,25 @@ type SelectQuery struct {
 	distinctOn []schema.QueryWithArgs
 	joins      []joinQuery
 	group      []schema.QueryWithArgs
-	having     []schema.QueryWithArgs
+	having     []schema.QueryWithSep
 	order      []schema.QueryWithArgs
 	limit      int32
 	offset     int32
 	selFor     schema.QueryWithArgs
+	sample     tableSample
+
+	columnOrder ColumnOrderPolicy
+	// forceLazy is the list of the lazy columns selected by default, see ForceLazy.
+	forceLazy    []string
+	forceAllLazy bool
+	// cacheTTL is set by DB.NewCachedSelect.
+	cacheTTL time.Duration
+	// countQuery replaces the count query generated by ScanAndCount, see WithCount.
+	countQuery *SelectQuery
 
 	union []union
+
+	recursiveRels []recursiveRelation
 }
 
 var _ Query = (*SelectQuery)(nil)
@@ -66,6 +83,14 @@ func (q *SelectQuery) Err(err error) *SelectQuery {
 	return q
 }
 
+// Comment prepends the comment to the query, for example, /* comment */ SELECT ...
+// Comment delimiters are removed from the comment. The comment takes precedence over
+// the comment set with WithQueryComment.
+func (q *SelectQuery) Comment(comment string) *SelectQuery {
+	q.setComment(comment)
+	return q
+}
+
 // Apply calls the fn passing the SelectQuery as an argument.
 func (q *SelectQuery) Apply(fn func(*SelectQuery) *SelectQuery) *SelectQuery {
 	if fn != nil {
@@ -74,6 +99,14 @@ func (q *SelectQuery) Apply(fn func(*SelectQuery) *SelectQuery) *SelectQuery {
 	return q
 }
 
+// ApplyIf calls the fn passing the SelectQuery as an argument only if cond is true.
+func (q *SelectQuery) ApplyIf(cond bool, fn func(*SelectQuery) *SelectQuery) *SelectQuery {
+	if cond {
+		return q.Apply(fn)
+	}
+	return q
+}
+
 func (q *SelectQuery) With(name string, query schema.QueryAppender) *SelectQuery {
 	q.addWith(name, query, false)
 	return q
@@ -132,6 +165,123 @@ func (q *SelectQuery) ExcludeColumn(columns ...string) *SelectQuery {
 	return q
 }
 
+// ColumnOrderPolicy controls the order of the model columns in the SELECT list.
+type ColumnOrderPolicy int
+
+const (
+	// RegistrationOrder selects the columns in the struct field order
+	// with the fields that have the pos tag option moved to their positions.
+	RegistrationOrder ColumnOrderPolicy = iota
+	// Alphabetical selects the columns sorted by name.
+	Alphabetical
+)
+
+// ColumnOrder sets the order of the model columns selected when the query
+// doesn't have explicit columns.
+func (q *SelectQuery) ColumnOrder(policy ColumnOrderPolicy) *SelectQuery {
+	q.columnOrder = policy
+	return q
+}
+
+// ForceLazy selects the columns tagged with the lazy option, which are not selected
+// by default. Without arguments, all lazy columns are selected.
+func (q *SelectQuery) ForceLazy(columns ...string) *SelectQuery {
+	if len(columns) == 0 {
+		q.forceAllLazy = true
+		return q
+	}
+	q.forceLazy = append(q.forceLazy, columns...)
+	return q
+}
+
+// LoadLazy selects the lazy columns of the models that were selected without them:
+//
+//	err := db.NewSelect().Model(&users).Scan(ctx)
+//	err = db.NewSelect().LoadLazy(ctx, &users, "bio")
+//
+// The model must be a struct or a slice of structs with primary keys. The columns are
+// selected with a single query by the primary keys. Without columns, all lazy columns are loaded.
+//
+// Insert and update queries without explicit columns skip the lazy columns that have
+// zero values in all models, so updating models selected without the lazy columns
+// doesn't overwrite them. Use Column to write zero values explicitly.
+func (q *SelectQuery) LoadLazy(ctx context.Context, model interface{}, columns ...string) error {
+	q = q.Model(model)
+	if q.err != nil {
+		return q.err
+	}
+	if q.table == nil {
+		return errNilModel
+	}
+	table := q.table
+	if err := table.CheckPKs(); err != nil {
+		return err
+	}
+
+	var fields []*schema.Field
+	if len(columns) == 0 {
+		for _, f := range table.Fields {
+			if f.Lazy {
+				fields = append(fields, f)
+			}
+		}
+	} else {
+		for _, column := range columns {
+			f, err := table.Field(column)
+			if err != nil {
+				return err
+			}
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	var strcts []reflect.Value
+	walk(reflect.ValueOf(model), nil, func(v reflect.Value) {
+		if v.IsValid() {
+			strcts = append(strcts, v)
+		}
+	})
+	if len(strcts) == 0 {
+		return nil
+	}
+
+	cols := make([]string, 0, len(table.PKs)+len(fields))
+	for _, f := range table.PKs {
+		cols = append(cols, f.Name)
+	}
+	for _, f := range fields {
+		cols = append(cols, f.Name)
+	}
+
+	loaded := reflect.New(reflect.SliceOf(table.Type))
+	if err := q.Column(cols...).WherePK().Scan(ctx, loaded.Interface()); err != nil {
+		return err
+	}
+
+	byPK := make(map[internal.MapKey]reflect.Value, loaded.Elem().Len())
+	var key []interface{}
+	for i := 0; i < loaded.Elem().Len(); i++ {
+		strct := loaded.Elem().Index(i)
+		key = modelKey(key[:0], strct, table.PKs)
+		byPK[internal.NewMapKey(key)] = strct
+	}
+
+	for _, strct := range strcts {
+		key = modelKey(key[:0], strct, table.PKs)
+		src, ok := byPK[internal.NewMapKey(key)]
+		if !ok {
+			continue
+		}
+		for _, f := range fields {
+			f.Value(strct).Set(f.Value(src))
+		}
+	}
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 func (q *SelectQuery) WherePK(cols ...string) *SelectQuery {
@@ -139,16 +289,90 @@ func (q *SelectQuery) WherePK(cols ...string) *SelectQuery {
 	return q
 }
 
+// WherePKComposite is like WherePK, but is meant for slices of models with composite
+// primary keys. It adds `WHERE (user_id, role_id) IN ((1, 2), (3, 4))` on the dialects
+// that support composite IN and falls back to
+// `WHERE ((user_id = 1 AND role_id = 2) OR (user_id = 3 AND role_id = 4))` on the others.
+func (q *SelectQuery) WherePKComposite() *SelectQuery {
+	q.addWhereCols(nil)
+	q.flags = q.flags.Set(wherePKCompositeFlag)
+	return q
+}
+
+// WhereID adds `WHERE pk = id` using the single primary key of the model.
+// Tables without primary keys or with composite primary keys are reported as an error.
+func (q *SelectQuery) WhereID(id interface{}) *SelectQuery {
+	if q.table == nil {
+		q.setErr(errNilModel)
+		return q
+	}
+	if len(q.table.PKs) != 1 {
+		q.setErr(fmt.Errorf(
+			"bun: WhereID requires %s to have a single primary key, got %d",
+			q.table, len(q.table.PKs)))
+		return q
+	}
+	return q.Where("?.? = ?", q.table.SQLAlias, q.table.PKs[0].SQLName, id)
+}
+
 func (q *SelectQuery) Where(query string, args ...interface{}) *SelectQuery {
 	q.addWhere(schema.SafeQueryWithSep(query, args, " AND "))
 	return q
 }
 
+// WhereIf is like Where, but adds the condition only if cond is true, for example,
+// WhereIf(name != "", "name = ?", name).
+func (q *SelectQuery) WhereIf(cond bool, query string, args ...interface{}) *SelectQuery {
+	if cond {
+		return q.Where(query, args...)
+	}
+	return q
+}
+
 func (q *SelectQuery) WhereOr(query string, args ...interface{}) *SelectQuery {
 	q.addWhere(schema.SafeQueryWithSep(query, args, " OR "))
 	return q
 }
 
+// WhereMap adds a "column = value" condition for each map entry, or "column IS NULL"
+// for nil values. Conditions are sorted by column name and joined with AND.
+func (q *SelectQuery) WhereMap(conditions map[string]interface{}) *SelectQuery {
+	q.addWhereMap(conditions)
+	return q
+}
+
+// WhereStruct adds a condition for each exported field of the filter struct, for example:
+//
+//	type UserFilter struct {
+//		Name   *string
+//		MinAge *int     `bun:"age,gte"`
+//		MaxAge *int     `bun:"age,lte"`
+//		Roles  []string `bun:"role"`
+//	}
+//
+// Nil pointers and nil slices are skipped. Other fields add "column = value",
+// "column >= value" with the gte option, "column <= value" with the lte option,
+// or "column IN (values)" for slices. The column is the field tag name or
+// the underscored field name. Conditions are joined with AND.
+func (q *SelectQuery) WhereStruct(filter interface{}) *SelectQuery {
+	q.addWhereStruct(filter)
+	return q
+}
+
+// WhereIn adds `column IN (values)`. The values can be a subquery, a slice of models
+// whose primary keys are matched, bun.In, or a slice of values. An empty slice matches no rows.
+func (q *SelectQuery) WhereIn(column string, values interface{}) *SelectQuery {
+	q.addWhereIn(column, values, false)
+	return q
+}
+
+// WhereNotIn is like WhereIn, but adds `column NOT IN (values)`.
+// An empty slice matches all rows.
+func (q *SelectQuery) WhereNotIn(column string, values interface{}) *SelectQuery {
+	q.addWhereIn(column, values, true)
+	return q
+}
+
 func (q *SelectQuery) WhereGroup(sep string, fn func(*SelectQuery) *SelectQuery) *SelectQuery {
 	saved := q.where
 	q.where = nil
@@ -274,35 +498,49 @@ func (q *SelectQuery) GroupExpr(group string, args ...interface{}) *SelectQuery
 }
 
 func (q *SelectQuery) Having(having string, args ...interface{}) *SelectQuery {
-	q.having = append(q.having, schema.SafeQuery(having, args))
+	q.having = append(q.having, schema.SafeQueryWithSep(having, args, " AND "))
 	return q
 }
 
-func (q *SelectQuery) Order(orders ...string) *SelectQuery {
-	for _, order := range orders {
-		if order == "" {
-			continue
-		}
+func (q *SelectQuery) HavingOr(having string, args ...interface{}) *SelectQuery {
+	q.having = append(q.having, schema.SafeQueryWithSep(having, args, " OR "))
+	return q
+}
 
-		index := strings.IndexByte(order, ' ')
-		if index == -1 {
-			q.order = append(q.order, schema.UnsafeIdent(order))
-			continue
-		}
+// HavingGroup groups the HAVING conditions added by fn in parentheses
+// and joins the group with the other conditions using sep, for example, " OR ".
+func (q *SelectQuery) HavingGroup(sep string, fn func(*SelectQuery) *SelectQuery) *SelectQuery {
+	saved := q.having
+	q.having = nil
 
-		field := order[:index]
-		sort := order[index+1:]
+	q = fn(q)
 
-		switch strings.ToUpper(sort) {
-		case "ASC", "DESC", "ASC NULLS FIRST", "DESC NULLS FIRST",
-			"ASC NULLS LAST", "DESC NULLS LAST":
-			q.order = append(q.order, schema.SafeQuery("? ?", []interface{}{
-				Ident(field),
-				Safe(sort),
-			}))
-		default:
-			q.order = append(q.order, schema.UnsafeIdent(order))
-		}
+	having := q.having
+	q.having = saved
+
+	if len(having) == 0 {
+		return q
+	}
+
+	having[0].Sep = ""
+	q.having = append(q.having,
+		schema.SafeQueryWithSep("", nil, sep),
+		schema.SafeQueryWithSep("", nil, "("))
+	q.having = append(q.having, having...)
+	q.having = append(q.having, schema.SafeQueryWithSep("", nil, ")"))
+
+	return q
+}
+
+func (q *SelectQuery) Order(orders ...string) *SelectQuery {
+	q.order = appendOrder(q.order, orders...)
+	return q
+}
+
+// OrderIf is like Order, but adds the orders only if cond is true.
+func (q *SelectQuery) OrderIf(cond bool, orders ...string) *SelectQuery {
+	if cond {
+		return q.Order(orders...)
 	}
 	return q
 }
@@ -312,11 +550,147 @@ func (q *SelectQuery) OrderExpr(query string, args ...interface{}) *SelectQuery
 	return q
 }
 
+// OrderDir is the sort direction used by OrderBy.
+type OrderDir string
+
+const (
+	Asc  OrderDir = "ASC"
+	Desc OrderDir = "DESC"
+)
+
+// OrderBy adds the column quoted as an identifier with the sort direction to ORDER BY.
+func (q *SelectQuery) OrderBy(column string, dir OrderDir) *SelectQuery {
+	return q.orderBy(column, dir, "")
+}
+
+// OrderByNullsFirst is like OrderBy, but sorts NULL values before other values using
+// NULLS FIRST. The dialects that don't support NULLS FIRST, for example, MySQL,
+// use their default order of NULL values.
+func (q *SelectQuery) OrderByNullsFirst(column string, dir OrderDir) *SelectQuery {
+	return q.orderBy(column, dir, " NULLS FIRST")
+}
+
+// OrderByNullsLast is like OrderByNullsFirst, but sorts NULL values after other values.
+func (q *SelectQuery) OrderByNullsLast(column string, dir OrderDir) *SelectQuery {
+	return q.orderBy(column, dir, " NULLS LAST")
+}
+
+func (q *SelectQuery) orderBy(column string, dir OrderDir, nulls string) *SelectQuery {
+	if dir != Asc && dir != Desc {
+		q.setErr(fmt.Errorf("bun: unsupported order direction: %q", dir))
+		return q
+	}
+	if !q.hasFeature(feature.NullsOrder) {
+		nulls = ""
+	}
+	q.order = append(q.order, schema.SafeQuery("? ?", []interface{}{
+		Ident(column),
+		Safe(string(dir) + nulls),
+	}))
+	return q
+}
+
+// ClearOrder removes the ORDER BY expressions added so far, for example,
+// to replace the default order set by Apply functions.
+func (q *SelectQuery) ClearOrder() *SelectQuery {
+	q.order = nil
+	return q
+}
+
+// OrderByRandom orders rows randomly using RANDOM() on PostgreSQL and SQLite,
+// RAND() on MySQL, and NEWID() on MSSQL.
+func (q *SelectQuery) OrderByRandom() *SelectQuery {
+	switch q.db.dialect.Name() {
+	case dialect.MySQL:
+		return q.OrderExpr("RAND()")
+	case dialect.MSSQL:
+		return q.OrderExpr("NEWID()")
+	default:
+		return q.OrderExpr("RANDOM()")
+	}
+}
+
+// Sample selects rows from a random sample of pct percent of the table pages
+// using TABLESAMPLE SYSTEM, which is much faster than OrderByRandom on large tables.
+// Only PostgreSQL is supported.
+func (q *SelectQuery) Sample(pct float64) *SelectQuery {
+	if q.db.dialect.Name() != dialect.PG {
+		q.setErr(fmt.Errorf("bun: TABLESAMPLE is not supported by %s", q.db.dialect.Name()))
+		return q
+	}
+	if pct <= 0 || pct > 100 {
+		q.setErr(fmt.Errorf("bun: sample percentage must be in (0, 100], got %v", pct))
+		return q
+	}
+	return q.TableSample("SYSTEM", strconv.FormatFloat(pct, 'f', -1, 64))
+}
+
+// TableSample selects rows from a random sample of the table using the sampling method,
+// for example, TableSample("BERNOULLI", "1") samples 1 percent of the rows:
+//
+//   - SYSTEM samples pct percent of the table pages,
+//   - BERNOULLI samples pct percent of the rows,
+//   - SYSTEM_ROWS samples pct rows and requires the tsm_system_rows extension.
+//
+// The query must select from a single table. On other dialects than PostgreSQL,
+// TableSample is ignored.
+func (q *SelectQuery) TableSample(method, pct string) *SelectQuery {
+	if q.db.dialect.Name() != dialect.PG {
+		return q
+	}
+
+	method = strings.ToUpper(method)
+	switch method {
+	case "SYSTEM", "BERNOULLI", "SYSTEM_ROWS":
+	default:
+		q.setErr(fmt.Errorf("bun: unsupported TABLESAMPLE method %q", method))
+		return q
+	}
+
+	// The argument is appended as is, so it must be a number.
+	if _, err := strconv.ParseFloat(pct, 64); err != nil {
+		q.setErr(fmt.Errorf("bun: TABLESAMPLE %s requires a number, got %q", method, pct))
+		return q
+	}
+
+	q.sample = tableSample{method: method, arg: pct}
+	return q
+}
+
+type tableSample struct {
+	method string
+	arg    string
+}
+
+// AsOf selects the row versions of a temporal table that were valid at the time tm,
+// see TemporalTable. On MSSQL, it uses FOR SYSTEM_TIME AS OF and replaces the model table
+// expression. Other dialects use a WHERE condition on the valid_from and valid_to columns.
+func (q *SelectQuery) AsOf(tm time.Time) *SelectQuery {
+	if q.db.dialect.Name() == dialect.MSSQL {
+		return q.ModelTableExpr("?TableName FOR SYSTEM_TIME AS OF ? AS ?TableAlias", tm)
+	}
+	return q.Where("?TableAlias.? <= ? AND ?TableAlias.? > ?",
+		Ident(temporalFromColumn), tm, Ident(temporalToColumn), tm)
+}
+
 func (q *SelectQuery) Limit(n int) *SelectQuery {
 	q.limit = int32(n)
 	return q
 }
 
+// LimitIf is like Limit, but sets the limit only if cond is true.
+func (q *SelectQuery) LimitIf(cond bool, n int) *SelectQuery {
+	if cond {
+		return q.Limit(n)
+	}
+	return q
+}
+
+// GetLimit returns the limit set with Limit or 0.
+func (q *SelectQuery) GetLimit() int {
+	return int(q.limit)
+}
+
 func (q *SelectQuery) Offset(n int) *SelectQuery {
 	q.offset = int32(n)
 	return q
@@ -484,6 +858,42 @@ func (q *SelectQuery) selectJoins(ctx context.Context, joins []relationJoin) err
 	return nil
 }
 
+// Preload loads the relations of the models that were already selected, for example,
+// with a raw query or from a cache, without selecting the models again:
+//
+//	err := db.NewSelect().Preload(ctx, &users, "Orders", "Orders.Items")
+//
+// Each relation is loaded with a single query for all models like Relation does.
+// Only has-many and many-to-many relations can be preloaded, but the nested relations
+// can be of any type. The relation fields are reset before loading.
+func (q *SelectQuery) Preload(ctx context.Context, model interface{}, relations ...string) error {
+	q = q.Model(model)
+	for _, name := range relations {
+		q = q.Relation(name)
+	}
+	if q.err != nil {
+		return q.err
+	}
+
+	joins := q.tableModel.getJoins()
+	for i := range joins {
+		j := &joins[i]
+		switch j.Relation.Type {
+		case schema.HasManyRelation, schema.ManyToManyRelation:
+		default:
+			return fmt.Errorf("bun: Preload supports only has-many and m2m relations, got %s", j.Relation)
+		}
+
+		fieldIndex := j.Relation.Field.Index
+		walk(j.JoinModel.rootValue(), j.JoinModel.parentIndex(), func(v reflect.Value) {
+			f := v.FieldByIndex(fieldIndex)
+			f.Set(reflect.Zero(f.Type()))
+		})
+	}
+
+	return q.selectJoins(ctx, joins)
+}
+
 //------------------------------------------------------------------------------
 
 func (q *SelectQuery) Operation() string {
@@ -502,6 +912,7 @@ func (q *SelectQuery) appendQuery(
 	}
 
 	fmter = formatterWithModel(fmter, q)
+	b = q.appendComment(b)
 
 	cteCount := count && (len(q.group) > 0 || q.distinctOn != nil)
 	if cteCount {
@@ -535,6 +946,12 @@ func (q *SelectQuery) appendQuery(
 		b = append(b, "DISTINCT "...)
 	}
 
+	if !count && q.useTop(fmter) {
+		b = append(b, "TOP ("...)
+		b = strconv.AppendInt(b, int64(q.limit), 10)
+		b = append(b, ") "...)
+	}
+
 	if count && !cteCount {
 		b = append(b, "count(*)"...)
 	} else {
@@ -591,16 +1008,9 @@ func (q *SelectQuery) appendQuery(
 
 	if len(q.having) > 0 {
 		b = append(b, " HAVING "...)
-		for i, f := range q.having {
-			if i > 0 {
-				b = append(b, " AND "...)
-			}
-			b = append(b, '(')
-			b, err = f.AppendQuery(fmter, b)
-			if err != nil {
-				return nil, err
-			}
-			b = append(b, ')')
+		b, err = appendWhere(fmter, b, q.having)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -610,35 +1020,9 @@ func (q *SelectQuery) appendQuery(
 			return nil, err
 		}
 
-		if fmter.Dialect().Features().Has(feature.OffsetFetch) {
-			if q.limit > 0 && q.offset > 0 {
-				b = append(b, " OFFSET "...)
-				b = strconv.AppendInt(b, int64(q.offset), 10)
-				b = append(b, " ROWS"...)
-
-				b = append(b, " FETCH NEXT "...)
-				b = strconv.AppendInt(b, int64(q.limit), 10)
-				b = append(b, " ROWS ONLY"...)
-			} else if q.limit > 0 {
-				b = append(b, " OFFSET 0 ROWS"...)
-
-				b = append(b, " FETCH NEXT "...)
-				b = strconv.AppendInt(b, int64(q.limit), 10)
-				b = append(b, " ROWS ONLY"...)
-			} else if q.offset > 0 {
-				b = append(b, " OFFSET "...)
-				b = strconv.AppendInt(b, int64(q.offset), 10)
-				b = append(b, " ROWS"...)
-			}
-		} else {
-			if q.limit > 0 {
-				b = append(b, " LIMIT "...)
-				b = strconv.AppendInt(b, int64(q.limit), 10)
-			}
-			if q.offset > 0 {
-				b = append(b, " OFFSET "...)
-				b = strconv.AppendInt(b, int64(q.offset), 10)
-			}
+		b, err = q.appendLimitOffset(fmter, b)
+		if err != nil {
+			return nil, err
 		}
 
 		if !q.selFor.IsZero() {
@@ -696,12 +1080,13 @@ func (q *SelectQuery) appendColumns(fmter schema.Formatter, b []byte) (_ []byte,
 			}
 		}
 	case q.table != nil:
-		if len(q.table.Fields) > 10 && fmter.IsNop() {
+		fields := q.orderedFields()
+		if len(fields) > 10 && fmter.IsNop() {
 			b = append(b, q.table.SQLAlias...)
 			b = append(b, '.')
-			b = fmter.Dialect().AppendString(b, fmt.Sprintf("%d columns", len(q.table.Fields)))
+			b = fmter.Dialect().AppendString(b, fmt.Sprintf("%d columns", len(fields)))
 		} else {
-			b = appendColumns(b, q.table.SQLAlias, q.table.Fields)
+			b = appendColumns(b, q.table.SQLAlias, fields)
 		}
 	default:
 		b = append(b, '*')
@@ -728,6 +1113,27 @@ func (q *SelectQuery) appendColumns(fmter schema.Formatter, b []byte) (_ []byte,
 	return b, nil
 }
 
+func (q *SelectQuery) orderedFields() []*schema.Field {
+	fields := q.table.Fields
+	if q.table.HasLazyFields() && !q.forceAllLazy {
+		fields = make([]*schema.Field, 0, len(q.table.Fields))
+		for _, f := range q.table.Fields {
+			if !f.Lazy || slices.Contains(q.forceLazy, f.Name) {
+				fields = append(fields, f)
+			}
+		}
+	} else if q.columnOrder == Alphabetical {
+		fields = slices.Clone(fields)
+	}
+
+	if q.columnOrder == Alphabetical {
+		sort.Slice(fields, func(i, j int) bool {
+			return fields[i].Name < fields[j].Name
+		})
+	}
+	return fields
+}
+
 func (q *SelectQuery) appendInlineRelColumns(
 	fmter schema.Formatter, b []byte, join *relationJoin,
 ) (_ []byte, err error) {
@@ -759,10 +1165,15 @@ func (q *SelectQuery) appendInlineRelColumns(
 		return b, nil
 	}
 
-	for i, field := range join.JoinModel.Table().Fields {
-		if i > 0 {
+	var n int
+	for _, field := range join.JoinModel.Table().Fields {
+		if field.Lazy {
+			continue
+		}
+		if n > 0 {
 			b = append(b, ", "...)
 		}
+		n++
 		b = join.appendAlias(fmter, b)
 		b = append(b, '.')
 		b = append(b, field.SQLName...)
@@ -774,7 +1185,73 @@ func (q *SelectQuery) appendInlineRelColumns(
 
 func (q *SelectQuery) appendTables(fmter schema.Formatter, b []byte) (_ []byte, err error) {
 	b = append(b, " FROM "...)
-	return q.appendTablesWithAlias(fmter, b)
+	b, err = q.appendTablesWithAlias(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.sample.method != "" {
+		if (q.modelHasTableName() && len(q.tables) > 0) || len(q.tables) > 1 {
+			return nil, errors.New("bun: TABLESAMPLE requires a query with a single table")
+		}
+		b = append(b, " TABLESAMPLE "...)
+		b = append(b, q.sample.method...)
+		b = append(b, " ("...)
+		b = append(b, q.sample.arg...)
+		b = append(b, ')')
+	}
+	return b, nil
+}
+
+// useTop reports whether LIMIT is replaced with TOP on the MSSQL servers
+// that don't support OFFSET ... FETCH, that is, before SQL Server 2012.
+func (q *SelectQuery) useTop(fmter schema.Formatter) bool {
+	return q.limit > 0 &&
+		fmter.Dialect().Name() == dialect.MSSQL &&
+		!fmter.HasFeature(feature.OffsetFetch)
+}
+
+func (q *SelectQuery) appendLimitOffset(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if fmter.HasFeature(feature.OffsetFetch) {
+		if q.limit > 0 && q.offset > 0 {
+			b = append(b, " OFFSET "...)
+			b = strconv.AppendInt(b, int64(q.offset), 10)
+			b = append(b, " ROWS"...)
+
+			b = append(b, " FETCH NEXT "...)
+			b = strconv.AppendInt(b, int64(q.limit), 10)
+			b = append(b, " ROWS ONLY"...)
+		} else if q.limit > 0 {
+			b = append(b, " OFFSET 0 ROWS"...)
+
+			b = append(b, " FETCH NEXT "...)
+			b = strconv.AppendInt(b, int64(q.limit), 10)
+			b = append(b, " ROWS ONLY"...)
+		} else if q.offset > 0 {
+			b = append(b, " OFFSET "...)
+			b = strconv.AppendInt(b, int64(q.offset), 10)
+			b = append(b, " ROWS"...)
+		}
+		return b, nil
+	}
+
+	if fmter.Dialect().Name() == dialect.MSSQL {
+		// The limit is appended as TOP, see useTop.
+		if q.offset > 0 {
+			return nil, errors.New("bun: OFFSET requires SQL Server 2012 or later")
+		}
+		return b, nil
+	}
+
+	if q.limit > 0 {
+		b = append(b, " LIMIT "...)
+		b = strconv.AppendInt(b, int64(q.limit), 10)
+	}
+	if q.offset > 0 {
+		b = append(b, " OFFSET "...)
+		b = strconv.AppendInt(b, int64(q.offset), 10)
+	}
+	return b, nil
 }
 
 func (q *SelectQuery) appendOrder(fmter schema.Formatter, b []byte) (_ []byte, err error) {
@@ -798,7 +1275,15 @@ func (q *SelectQuery) appendOrder(fmter schema.Formatter, b []byte) (_ []byte, e
 
 //------------------------------------------------------------------------------
 
-func (q *SelectQuery) Rows(ctx context.Context) (*sql.Rows, error) {
+func (q *SelectQuery) Rows(ctx context.Context) (rows *sql.Rows, err error) {
+	_, err = q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		rows, err = q.rows(ctx)
+		return nil, err
+	})
+	return rows, err
+}
+
+func (q *SelectQuery) rows(ctx context.Context) (*sql.Rows, error) {
 	if q.err != nil {
 		return nil, q.err
 	}
@@ -807,20 +1292,37 @@ func (q *SelectQuery) Rows(ctx context.Context) (*sql.Rows, error) {
 		return nil, err
 	}
 
-	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
 	if err != nil {
 		return nil, err
 	}
 
 	query := internal.String(queryBytes)
 
-	ctx, event := q.db.beforeQuery(ctx, q, query, nil, query, q.model)
-	rows, err := q.conn.QueryContext(ctx, query)
-	q.db.afterQuery(ctx, event, nil, err)
+	query = withQueryComment(ctx, query)
+	ctx, event, start := q.db.beforeQuery(ctx, q.conn, q.tx, q, query, nil, query, q.model)
+	query = event.query(query)
+	var rows *sql.Rows
+	err = q.db.runQueryRows(ctx, q.conn, query, func(
+		ctx context.Context, conn IConn, query string,
+	) (err error) {
+		rows, err = conn.QueryContext(ctx, query)
+		return err
+	})
+	if err == nil && event != nil {
+		event.Columns, _ = rows.Columns()
+	}
+	q.db.afterQuery(ctx, event, start, nil, err)
 	return rows, err
 }
 
-func (q *SelectQuery) Exec(ctx context.Context, dest ...interface{}) (res sql.Result, err error) {
+func (q *SelectQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	return q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		return q.execDest(ctx, dest)
+	})
+}
+
+func (q *SelectQuery) execDest(ctx context.Context, dest []interface{}) (res sql.Result, err error) {
 	if q.err != nil {
 		return nil, q.err
 	}
@@ -828,7 +1330,7 @@ func (q *SelectQuery) Exec(ctx context.Context, dest ...interface{}) (res sql.Re
 		return nil, err
 	}
 
-	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
 	if err != nil {
 		return nil, err
 	}
@@ -856,52 +1358,124 @@ func (q *SelectQuery) Exec(ctx context.Context, dest ...interface{}) (res sql.Re
 }
 
 func (q *SelectQuery) Scan(ctx context.Context, dest ...interface{}) error {
+	_, err := q.scanResult(ctx, dest...)
+	return err
+}
+
+// Find scans exactly one row into the struct dest. It returns sql.ErrNoRows
+// if there are no rows and ErrTooManyRows if there are two or more rows,
+// in which case dest contains the first row. Unless the query has a limit,
+// Find adds LIMIT 2 to the query.
+func (q *SelectQuery) Find(ctx context.Context, dest interface{}) error {
 	if q.err != nil {
 		return q.err
 	}
 
-	model, err := q.getModel(dest)
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bun: Find(non-pointer-to-struct %T)", dest)
+	}
+
+	if q.limit == 0 {
+		q.limit = 2
+	}
+
+	res, err := q.scanResult(ctx, dest)
 	if err != nil {
 		return err
 	}
+	if n, _ := res.RowsAffected(); n > 1 {
+		return ErrTooManyRows
+	}
+	return nil
+}
+
+func (q *SelectQuery) scanResult(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	return q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		return q.cachedScanResult(ctx, dest)
+	})
+}
+
+func (q *SelectQuery) _scanResult(ctx context.Context, dest []interface{}) (sql.Result, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+
+	model, err := q.getModel(dest)
+	if err != nil {
+		return nil, err
+	}
 
 	if q.table != nil {
 		if err := q.beforeSelectHook(ctx); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	if err := q.beforeAppendModel(ctx, q); err != nil {
-		return err
+		return nil, err
 	}
 
-	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	query := internal.String(queryBytes)
 
 	res, err := q.scan(ctx, q, query, model, true)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if n, _ := res.RowsAffected(); n > 0 {
 		if tableModel, ok := model.(TableModel); ok {
 			if err := q.selectJoins(ctx, tableModel.getJoins()); err != nil {
-				return err
+				return nil, err
+			}
+			for _, rr := range q.recursiveRels {
+				if err := q.selectRecursive(ctx, tableModel, rr); err != nil {
+					return nil, err
+				}
 			}
 		}
 	}
 
 	if q.table != nil {
 		if err := q.afterSelectHook(ctx); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	return nil
+	return res, nil
+}
+
+// Pluck scans a single column into a slice of primitive values, for example:
+//
+//	var ids []int64
+//	err := db.NewSelect().Model((*User)(nil)).Column("id").Pluck(ctx, &ids)
+//
+// The query must select at least one column and only the first one is used.
+// If the slice element is a struct, Pluck is the same as Scan.
+func (q *SelectQuery) Pluck(ctx context.Context, dest interface{}) error {
+	if q.err != nil {
+		return q.err
+	}
+
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("bun: Pluck(non-pointer-to-slice %T)", dest)
+	}
+	if elemType := sliceElemType(v.Elem()); elemType.Kind() == reflect.Struct && elemType != timeType {
+		return q.Scan(ctx, dest)
+	}
+
+	if len(q.columns) == 0 {
+		return errors.New("bun: Pluck requires a column, use Column or ColumnExpr")
+	}
+	q.columns = q.columns[:1]
+
+	return q.Scan(ctx, dest)
 }
 
 func (q *SelectQuery) beforeSelectHook(ctx context.Context) error {
@@ -922,29 +1496,50 @@ func (q *SelectQuery) afterSelectHook(ctx context.Context) error {
 	return nil
 }
 
-func (q *SelectQuery) Count(ctx context.Context) (int, error) {
+func (q *SelectQuery) Count(ctx context.Context) (num int, err error) {
+	_, err = q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		num, err = q.count(ctx)
+		return nil, err
+	})
+	return num, err
+}
+
+func (q *SelectQuery) count(ctx context.Context) (int, error) {
 	if q.err != nil {
 		return 0, q.err
 	}
 
 	qq := countQuery{q}
 
-	queryBytes, err := qq.AppendQuery(q.db.fmter, nil)
+	queryBytes, err := qq.AppendQuery(q.db.formatter(ctx), nil)
 	if err != nil {
 		return 0, err
 	}
 
 	query := internal.String(queryBytes)
-	ctx, event := q.db.beforeQuery(ctx, qq, query, nil, query, q.model)
+	query = withQueryComment(ctx, query)
+	ctx, event, start := q.db.beforeQuery(ctx, q.conn, q.tx, qq, query, nil, query, q.model)
+	query = event.query(query)
 
 	var num int
-	err = q.conn.QueryRowContext(ctx, query).Scan(&num)
+	err = q.db.withConn(ctx, q.conn, false, func(ctx context.Context, conn IConn) error {
+		return conn.QueryRowContext(ctx, query).Scan(&num)
+	})
 
-	q.db.afterQuery(ctx, event, nil, err)
+	q.db.afterQuery(ctx, event, start, nil, err)
 
 	return num, err
 }
 
+// WithCount sets the query that ScanAndCount uses to count rows instead of counting
+// the rows of the query itself, for example, to count distinct users rather than orders.
+// The count query runs on the connection of the query, and its ORDER BY, LIMIT,
+// and OFFSET clauses are ignored. Passing nil restores the default count query.
+func (q *SelectQuery) WithCount(countQuery *SelectQuery) *SelectQuery {
+	q.countQuery = countQuery
+	return q
+}
+
 func (q *SelectQuery) ScanAndCount(ctx context.Context, dest ...interface{}) (int, error) {
 	if _, ok := q.conn.(*DB); ok {
 		return q.scanAndCountConc(ctx, dest...)
@@ -978,7 +1573,7 @@ func (q *SelectQuery) scanAndCountConc(ctx context.Context, dest ...interface{})
 		defer wg.Done()
 
 		var err error
-		count, err = q.Count(ctx)
+		count, err = q.scanAndCountCount(ctx)
 		if err != nil {
 			mu.Lock()
 			if firstErr == nil {
@@ -999,7 +1594,7 @@ func (q *SelectQuery) scanAndCountSeq(ctx context.Context, dest ...interface{})
 		firstErr = q.Scan(ctx, dest...)
 	}
 
-	count, err := q.Count(ctx)
+	count, err := q.scanAndCountCount(ctx)
 	if err != nil && firstErr == nil {
 		firstErr = err
 	}
@@ -1007,7 +1602,128 @@ func (q *SelectQuery) scanAndCountSeq(ctx context.Context, dest ...interface{})
 	return count, firstErr
 }
 
-func (q *SelectQuery) Exists(ctx context.Context) (bool, error) {
+func (q *SelectQuery) scanAndCountCount(ctx context.Context) (int, error) {
+	if q.countQuery == nil {
+		return q.Count(ctx)
+	}
+
+	countQuery := *q.countQuery
+	countQuery.conn = q.conn
+	countQuery.tx = q.tx
+	return countQuery.Count(ctx)
+}
+
+// ScanChan scans rows one by one in a separate goroutine and sends them on a channel,
+// so the reader controls the pace. The dest is either a chan T or a *[]T and only specifies
+// the type of values. For a chan T, rows are sent on the dest and nothing is sent on
+// the returned data channel. Otherwise rows are sent on the returned data channel.
+//
+// When iteration is complete, the error, if any, is sent on the buffered error channel first,
+// then the dest and the data channels are closed, and then the error channel is closed.
+// So once the data channel is closed, receiving from the error channel returns the error
+// or nil without blocking. The error includes the context error when ctx is canceled,
+// which also stops the goroutine when the reader stops receiving.
+func (q *SelectQuery) ScanChan(
+	ctx context.Context, dest interface{},
+) (<-chan interface{}, <-chan error) {
+	data := make(chan interface{})
+	errc := make(chan error, 1)
+
+	v := reflect.ValueOf(dest)
+	var elemType reflect.Type
+	var destChan reflect.Value
+
+	switch {
+	case v.Kind() == reflect.Chan && v.Type().ChanDir()&reflect.SendDir != 0:
+		destChan = v
+		elemType = v.Type().Elem()
+	case v.Kind() == reflect.Ptr && v.Type().Elem().Kind() == reflect.Slice:
+		elemType = v.Type().Elem().Elem()
+	default:
+		errc <- fmt.Errorf("bun: ScanChan expects a chan or a pointer to a slice, got %T", dest)
+		close(errc)
+		close(data)
+		return data, errc
+	}
+
+	go func() {
+		defer close(errc)
+		defer close(data)
+		if destChan.IsValid() {
+			defer destChan.Close()
+		}
+
+		if err := q.scanChan(ctx, elemType, destChan, data); err != nil {
+			errc <- err
+		}
+	}()
+
+	return data, errc
+}
+
+func (q *SelectQuery) scanChan(
+	ctx context.Context, elemType reflect.Type, destChan reflect.Value, data chan<- interface{},
+) error {
+	rows, err := q.Rows(ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	isPtr := elemType.Kind() == reflect.Ptr
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var elem reflect.Value
+		if isPtr {
+			elem = reflect.New(elemType.Elem())
+		} else {
+			elem = reflect.New(elemType)
+		}
+
+		if err := q.db.ScanRow(ctx, rows, elem.Interface()); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				// The rows are closed when ctx is canceled.
+				return ctxErr
+			}
+			return err
+		}
+		if !isPtr {
+			elem = elem.Elem()
+		}
+
+		if destChan.IsValid() {
+			chosen, _, _ := reflect.Select([]reflect.SelectCase{
+				{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+				{Dir: reflect.SelectSend, Chan: destChan, Send: elem},
+			})
+			if chosen == 0 {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case data <- elem.Interface():
+		}
+	}
+
+	return rows.Err()
+}
+
+func (q *SelectQuery) Exists(ctx context.Context) (exists bool, err error) {
+	_, err = q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		exists, err = q.exists(ctx)
+		return nil, err
+	})
+	return exists, err
+}
+
+func (q *SelectQuery) exists(ctx context.Context) (bool, error) {
 	if q.err != nil {
 		return false, q.err
 	}
@@ -1021,18 +1737,22 @@ func (q *SelectQuery) Exists(ctx context.Context) (bool, error) {
 func (q *SelectQuery) selectExists(ctx context.Context) (bool, error) {
 	qq := selectExistsQuery{q}
 
-	queryBytes, err := qq.AppendQuery(q.db.fmter, nil)
+	queryBytes, err := qq.AppendQuery(q.db.formatter(ctx), nil)
 	if err != nil {
 		return false, err
 	}
 
 	query := internal.String(queryBytes)
-	ctx, event := q.db.beforeQuery(ctx, qq, query, nil, query, q.model)
+	query = withQueryComment(ctx, query)
+	ctx, event, start := q.db.beforeQuery(ctx, q.conn, q.tx, qq, query, nil, query, q.model)
+	query = event.query(query)
 
 	var exists bool
-	err = q.conn.QueryRowContext(ctx, query).Scan(&exists)
+	err = q.db.withConn(ctx, q.conn, false, func(ctx context.Context, conn IConn) error {
+		return conn.QueryRowContext(ctx, query).Scan(&exists)
+	})
 
-	q.db.afterQuery(ctx, event, nil, err)
+	q.db.afterQuery(ctx, event, start, nil, err)
 
 	return exists, err
 }
@@ -1040,7 +1760,7 @@ func (q *SelectQuery) selectExists(ctx context.Context) (bool, error) {
 func (q *SelectQuery) whereExists(ctx context.Context) (bool, error) {
 	qq := whereExistsQuery{q}
 
-	queryBytes, err := qq.AppendQuery(q.db.fmter, nil)
+	queryBytes, err := qq.AppendQuery(q.db.formatter(ctx), nil)
 	if err != nil {
 		return false, err
 	}
@@ -1059,6 +1779,151 @@ func (q *SelectQuery) whereExists(ctx context.Context) (bool, error) {
 	return n == 1, nil
 }
 
+// ExplainOption configures the EXPLAIN statement executed by SelectQuery.Explain.
+type ExplainOption func(conf *explainConfig)
+
+type explainConfig struct {
+	analyze bool
+	buffers bool
+	verbose bool
+	format  string
+}
+
+// ExplainAnalyze executes the query and reports the actual run times.
+func ExplainAnalyze() ExplainOption {
+	return func(conf *explainConfig) {
+		conf.analyze = true
+	}
+}
+
+// ExplainBuffers reports the buffer usage. Only PostgreSQL is supported.
+func ExplainBuffers() ExplainOption {
+	return func(conf *explainConfig) {
+		conf.buffers = true
+	}
+}
+
+// ExplainVerbose reports additional information about the plan. Only PostgreSQL is supported.
+func ExplainVerbose() ExplainOption {
+	return func(conf *explainConfig) {
+		conf.verbose = true
+	}
+}
+
+// ExplainFormat sets the plan format: "json" (the default) or "text".
+func ExplainFormat(format string) ExplainOption {
+	return func(conf *explainConfig) {
+		conf.format = strings.ToLower(format)
+	}
+}
+
+// Explain executes EXPLAIN for the query and returns the query plan.
+//
+// On PostgreSQL and MySQL the JSON format returns the plan produced by the database.
+// The text format returns the plan lines as a JSON string. SQLite supports only
+// EXPLAIN QUERY PLAN and returns its rows as an array of JSON objects.
+// Query hooks receive the query with Operation() == "EXPLAIN".
+func (q *SelectQuery) Explain(ctx context.Context, opts ...ExplainOption) (plan json.RawMessage, err error) {
+	_, err = q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		plan, err = q.explain(ctx, opts)
+		return nil, err
+	})
+	return plan, err
+}
+
+func (q *SelectQuery) explain(ctx context.Context, opts []ExplainOption) (json.RawMessage, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+
+	conf := explainConfig{format: "json"}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	if conf.format != "json" && conf.format != "text" {
+		return nil, fmt.Errorf("bun: unsupported EXPLAIN format %q", conf.format)
+	}
+
+	qq := explainQuery{SelectQuery: q, conf: conf}
+
+	queryBytes, err := qq.AppendQuery(q.db.formatter(ctx), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+	query = withQueryComment(ctx, query)
+	ctx, event, start := q.db.beforeQuery(ctx, q.conn, q.tx, qq, query, nil, query, q.model)
+	query = event.query(query)
+
+	var plan json.RawMessage
+	err = q.db.withConn(ctx, q.conn, false, func(ctx context.Context, conn IConn) (err error) {
+		plan, err = queryPlan(ctx, conn, query, conf.format == "json")
+		return err
+	})
+
+	q.db.afterQuery(ctx, event, start, nil, err)
+
+	return plan, err
+}
+
+func queryPlan(ctx context.Context, conn IConn, query string, isJSON bool) (json.RawMessage, error) {
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	var objects []map[string]interface{}
+	for rows.Next() {
+		if len(columns) == 1 {
+			var line string
+			if err := rows.Scan(&line); err != nil {
+				return nil, err
+			}
+			lines = append(lines, line)
+			continue
+		}
+
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		obj := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			if b, ok := values[i].([]byte); ok {
+				obj[col] = string(b)
+			} else {
+				obj[col] = values[i]
+			}
+		}
+		objects = append(objects, obj)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(columns) != 1:
+		return json.Marshal(objects)
+	case isJSON && len(lines) == 1:
+		return json.RawMessage(lines[0]), nil
+	default:
+		return json.Marshal(strings.Join(lines, "\n"))
+	}
+}
+
 func (q *SelectQuery) String() string {
 	buf, err := q.AppendQuery(q.db.Formatter(), nil)
 	if err != nil {
@@ -1116,6 +1981,36 @@ func (q *selectQueryBuilder) WherePK(cols ...string) QueryBuilder {
 	return q
 }
 
+func (q *selectQueryBuilder) Order(orders ...string) QueryBuilder {
+	q.SelectQuery.Order(orders...)
+	return q
+}
+
+func (q *selectQueryBuilder) OrderExpr(query string, args ...interface{}) QueryBuilder {
+	q.SelectQuery.OrderExpr(query, args...)
+	return q
+}
+
+func (q *selectQueryBuilder) Limit(n int) QueryBuilder {
+	q.SelectQuery.Limit(n)
+	return q
+}
+
+func (q *selectQueryBuilder) Offset(n int) QueryBuilder {
+	q.SelectQuery.Offset(n)
+	return q
+}
+
+func (q *selectQueryBuilder) Group(columns ...string) QueryBuilder {
+	q.SelectQuery.Group(columns...)
+	return q
+}
+
+func (q *selectQueryBuilder) GroupExpr(query string, args ...interface{}) QueryBuilder {
+	q.SelectQuery.GroupExpr(query, args...)
+	return q
+}
+
 func (q *selectQueryBuilder) Unwrap() interface{} {
 	return q.SelectQuery
 }
@@ -1212,3 +2107,53 @@ func (q whereExistsQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byt
 
 	return b, nil
 }
+
+//------------------------------------------------------------------------------
+
+type explainQuery struct {
+	*SelectQuery
+	conf explainConfig
+}
+
+func (q explainQuery) Operation() string {
+	return "EXPLAIN"
+}
+
+func (q explainQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+
+	switch name := fmter.Dialect().Name(); name {
+	case dialect.PG:
+		b = append(b, "EXPLAIN ("...)
+		if q.conf.analyze {
+			b = append(b, "ANALYZE, "...)
+		}
+		if q.conf.buffers {
+			b = append(b, "BUFFERS, "...)
+		}
+		if q.conf.verbose {
+			b = append(b, "VERBOSE, "...)
+		}
+		b = append(b, "FORMAT "...)
+		b = append(b, strings.ToUpper(q.conf.format)...)
+		b = append(b, ") "...)
+	case dialect.MySQL:
+		switch {
+		case q.conf.analyze:
+			// EXPLAIN ANALYZE supports only the tree format.
+			b = append(b, "EXPLAIN ANALYZE "...)
+		case q.conf.format == "json":
+			b = append(b, "EXPLAIN FORMAT=JSON "...)
+		default:
+			b = append(b, "EXPLAIN FORMAT=TREE "...)
+		}
+	case dialect.SQLite:
+		b = append(b, "EXPLAIN QUERY PLAN "...)
+	default:
+		return nil, fmt.Errorf("bun: EXPLAIN is not supported by %s", name)
+	}
+
+	return q.appendQuery(fmter, b, false)
+}
@@ -0,0 +1,451 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/uptrace/bun/schema"
+)
+
+// TypedSelectQuery is a SelectQuery that scans rows into a slice of T,
+// so mismatched models are caught at compile time:
+//
+//	users, err := bun.NewTypedSelect[User](db).Where("active").Order("id").Scan(ctx)
+//
+// The builder methods return *TypedSelectQuery[T] to keep the chain typed.
+// The model is created by NewTypedSelect and must not be changed with Model.
+type TypedSelectQuery[T any] struct {
+	*SelectQuery
+
+	models *[]T
+}
+
+func NewTypedSelect[T any](db *DB) *TypedSelectQuery[T] {
+	models := new([]T)
+	return &TypedSelectQuery[T]{
+		SelectQuery: NewSelectQuery(db).Model(models),
+		models:      models,
+	}
+}
+
+// Scan executes the query and returns the selected rows.
+func (q *TypedSelectQuery[T]) Scan(ctx context.Context) ([]T, error) {
+	// Don't reuse the slice returned by the previous call.
+	*q.models = nil
+	if err := q.SelectQuery.Scan(ctx); err != nil {
+		return nil, err
+	}
+	return *q.models, nil
+}
+
+// First executes the query with LIMIT 1 and returns the first row
+// or sql.ErrNoRows if there are no rows.
+func (q *TypedSelectQuery[T]) First(ctx context.Context) (*T, error) {
+	models, err := q.Limit(1).Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(models) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &models[0], nil
+}
+
+// ScanAndCount executes the query and returns the selected rows
+// and the number of rows ignoring the limit and offset.
+func (q *TypedSelectQuery[T]) ScanAndCount(ctx context.Context) ([]T, int, error) {
+	*q.models = nil
+	count, err := q.SelectQuery.ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return *q.models, count, nil
+}
+
+func (q *TypedSelectQuery[T]) WithCount(countQuery *SelectQuery) *TypedSelectQuery[T] {
+	q.SelectQuery.WithCount(countQuery)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Apply(fn func(*TypedSelectQuery[T]) *TypedSelectQuery[T]) *TypedSelectQuery[T] {
+	if fn != nil {
+		return fn(q)
+	}
+	return q
+}
+
+// ApplyIf calls the fn passing the TypedSelectQuery as an argument only if cond is true.
+func (q *TypedSelectQuery[T]) ApplyIf(cond bool, fn func(*TypedSelectQuery[T]) *TypedSelectQuery[T]) *TypedSelectQuery[T] {
+	if cond {
+		return q.Apply(fn)
+	}
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *TypedSelectQuery[T]) Conn(db IConn) *TypedSelectQuery[T] {
+	q.SelectQuery.Conn(db)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Err(err error) *TypedSelectQuery[T] {
+	q.SelectQuery.Err(err)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Comment(comment string) *TypedSelectQuery[T] {
+	q.SelectQuery.Comment(comment)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) With(name string, query schema.QueryAppender) *TypedSelectQuery[T] {
+	q.SelectQuery.With(name, query)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WithRecursive(name string, query schema.QueryAppender) *TypedSelectQuery[T] {
+	q.SelectQuery.WithRecursive(name, query)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Distinct() *TypedSelectQuery[T] {
+	q.SelectQuery.Distinct()
+	return q
+}
+
+func (q *TypedSelectQuery[T]) DistinctOn(query string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.DistinctOn(query, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Table(tables ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.Table(tables...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) TableExpr(query string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.TableExpr(query, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) ModelTableExpr(query string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.ModelTableExpr(query, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Column(columns ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.Column(columns...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) ColumnExpr(query string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.ColumnExpr(query, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) ExcludeColumn(columns ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.ExcludeColumn(columns...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) ColumnOrder(policy ColumnOrderPolicy) *TypedSelectQuery[T] {
+	q.SelectQuery.ColumnOrder(policy)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) ForceLazy(columns ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.ForceLazy(columns...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WherePK(cols ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.WherePK(cols...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WherePKComposite() *TypedSelectQuery[T] {
+	q.SelectQuery.WherePKComposite()
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WhereID(id interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.WhereID(id)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Where(query string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.Where(query, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WhereIf(cond bool, query string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.WhereIf(cond, query, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WhereOr(query string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.WhereOr(query, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WhereMap(conditions map[string]interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.WhereMap(conditions)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WhereStruct(filter interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.WhereStruct(filter)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WhereIn(column string, values interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.WhereIn(column, values)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WhereNotIn(column string, values interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.WhereNotIn(column, values)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WhereGroup(sep string, fn func(*SelectQuery) *SelectQuery) *TypedSelectQuery[T] {
+	q.SelectQuery.WhereGroup(sep, fn)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WhereDeleted() *TypedSelectQuery[T] {
+	q.SelectQuery.WhereDeleted()
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WhereAllWithDeleted() *TypedSelectQuery[T] {
+	q.SelectQuery.WhereAllWithDeleted()
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Scope(names ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.Scope(names...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) UseIndex(indexes ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.UseIndex(indexes...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) UseIndexForJoin(indexes ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.UseIndexForJoin(indexes...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) UseIndexForOrderBy(indexes ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.UseIndexForOrderBy(indexes...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) UseIndexForGroupBy(indexes ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.UseIndexForGroupBy(indexes...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) IgnoreIndex(indexes ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.IgnoreIndex(indexes...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) IgnoreIndexForJoin(indexes ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.IgnoreIndexForJoin(indexes...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) IgnoreIndexForOrderBy(indexes ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.IgnoreIndexForOrderBy(indexes...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) IgnoreIndexForGroupBy(indexes ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.IgnoreIndexForGroupBy(indexes...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) ForceIndex(indexes ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.ForceIndex(indexes...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) ForceIndexForJoin(indexes ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.ForceIndexForJoin(indexes...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) ForceIndexForOrderBy(indexes ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.ForceIndexForOrderBy(indexes...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) ForceIndexForGroupBy(indexes ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.ForceIndexForGroupBy(indexes...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Group(columns ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.Group(columns...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) GroupExpr(group string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.GroupExpr(group, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Having(having string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.Having(having, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) HavingOr(having string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.HavingOr(having, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) HavingGroup(sep string, fn func(*SelectQuery) *SelectQuery) *TypedSelectQuery[T] {
+	q.SelectQuery.HavingGroup(sep, fn)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Order(orders ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.Order(orders...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) OrderIf(cond bool, orders ...string) *TypedSelectQuery[T] {
+	q.SelectQuery.OrderIf(cond, orders...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) OrderExpr(query string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.OrderExpr(query, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) OrderBy(column string, dir OrderDir) *TypedSelectQuery[T] {
+	q.SelectQuery.OrderBy(column, dir)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) OrderByNullsFirst(column string, dir OrderDir) *TypedSelectQuery[T] {
+	q.SelectQuery.OrderByNullsFirst(column, dir)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) OrderByNullsLast(column string, dir OrderDir) *TypedSelectQuery[T] {
+	q.SelectQuery.OrderByNullsLast(column, dir)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) ClearOrder() *TypedSelectQuery[T] {
+	q.SelectQuery.ClearOrder()
+	return q
+}
+
+func (q *TypedSelectQuery[T]) OrderByRandom() *TypedSelectQuery[T] {
+	q.SelectQuery.OrderByRandom()
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Sample(pct float64) *TypedSelectQuery[T] {
+	q.SelectQuery.Sample(pct)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) TableSample(method, pct string) *TypedSelectQuery[T] {
+	q.SelectQuery.TableSample(method, pct)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) AsOf(tm time.Time) *TypedSelectQuery[T] {
+	q.SelectQuery.AsOf(tm)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Limit(n int) *TypedSelectQuery[T] {
+	q.SelectQuery.Limit(n)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) LimitIf(cond bool, n int) *TypedSelectQuery[T] {
+	q.SelectQuery.LimitIf(cond, n)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Offset(n int) *TypedSelectQuery[T] {
+	q.SelectQuery.Offset(n)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) For(s string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.For(s, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Union(other *SelectQuery) *TypedSelectQuery[T] {
+	q.SelectQuery.Union(other)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) UnionAll(other *SelectQuery) *TypedSelectQuery[T] {
+	q.SelectQuery.UnionAll(other)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Intersect(other *SelectQuery) *TypedSelectQuery[T] {
+	q.SelectQuery.Intersect(other)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) IntersectAll(other *SelectQuery) *TypedSelectQuery[T] {
+	q.SelectQuery.IntersectAll(other)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Except(other *SelectQuery) *TypedSelectQuery[T] {
+	q.SelectQuery.Except(other)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) ExceptAll(other *SelectQuery) *TypedSelectQuery[T] {
+	q.SelectQuery.ExceptAll(other)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Join(join string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.Join(join, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) JoinOn(cond string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.JoinOn(cond, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) JoinOnOr(cond string, args ...interface{}) *TypedSelectQuery[T] {
+	q.SelectQuery.JoinOnOr(cond, args...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) Relation(name string, apply ...func(*SelectQuery) *SelectQuery) *TypedSelectQuery[T] {
+	q.SelectQuery.Relation(name, apply...)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) ApplyQueryBuilder(fn func(QueryBuilder) QueryBuilder) *TypedSelectQuery[T] {
+	q.SelectQuery.ApplyQueryBuilder(fn)
+	return q
+}
+
+func (q *TypedSelectQuery[T]) WithRecursiveRelation(name string, maxDepth int) *TypedSelectQuery[T] {
+	q.SelectQuery.WithRecursiveRelation(name, maxDepth)
+	return q
+}
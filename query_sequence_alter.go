@@ -0,0 +1,169 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+type AlterSequenceQuery struct {
+	baseQuery
+	sequenceOptions
+
+	ifExists bool
+	name     schema.QueryWithArgs
+
+	restart     bool
+	restartWith *int64
+}
+
+var _ Query = (*AlterSequenceQuery)(nil)
+
+func NewAlterSequenceQuery(db *DB, name string) *AlterSequenceQuery {
+	q := &AlterSequenceQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+		name: schema.UnsafeIdent(name),
+	}
+	return q
+}
+
+func (q *AlterSequenceQuery) Conn(db IConn) *AlterSequenceQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *AlterSequenceQuery) Err(err error) *AlterSequenceQuery {
+	q.setErr(err)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *AlterSequenceQuery) IfExists() *AlterSequenceQuery {
+	q.ifExists = true
+	return q
+}
+
+// Start changes the value that Restart restarts the sequence with.
+func (q *AlterSequenceQuery) Start(n int64) *AlterSequenceQuery {
+	q.start = &n
+	return q
+}
+
+func (q *AlterSequenceQuery) Increment(n int64) *AlterSequenceQuery {
+	q.increment = &n
+	return q
+}
+
+func (q *AlterSequenceQuery) Min(n int64) *AlterSequenceQuery {
+	q.min = &n
+	return q
+}
+
+func (q *AlterSequenceQuery) Max(n int64) *AlterSequenceQuery {
+	q.max = &n
+	return q
+}
+
+func (q *AlterSequenceQuery) Cache(n int64) *AlterSequenceQuery {
+	q.cache = &n
+	return q
+}
+
+func (q *AlterSequenceQuery) Cycle() *AlterSequenceQuery {
+	cycle := true
+	q.cycle = &cycle
+	return q
+}
+
+func (q *AlterSequenceQuery) NoCycle() *AlterSequenceQuery {
+	cycle := false
+	q.cycle = &cycle
+	return q
+}
+
+// Restart restarts the sequence with the start value, so the next call
+// of nextval returns the start value.
+func (q *AlterSequenceQuery) Restart() *AlterSequenceQuery {
+	q.restart = true
+	q.restartWith = nil
+	return q
+}
+
+// RestartWith restarts the sequence, so the next call of nextval returns n.
+func (q *AlterSequenceQuery) RestartWith(n int64) *AlterSequenceQuery {
+	q.restart = true
+	q.restartWith = &n
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *AlterSequenceQuery) Operation() string {
+	return "ALTER SEQUENCE"
+}
+
+func (q *AlterSequenceQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if !fmter.HasFeature(feature.Sequence) {
+		return nil, fmt.Errorf("bun: ALTER SEQUENCE is not supported by %s", q.db.dialect.Name())
+	}
+
+	b = append(b, "ALTER SEQUENCE "...)
+	if q.ifExists {
+		b = append(b, "IF EXISTS "...)
+	}
+
+	b, err = q.name.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+	start := len(b)
+
+	if q.start != nil {
+		b = append(b, " START WITH "...)
+		b = strconv.AppendInt(b, *q.start, 10)
+	}
+	b = q.appendOptions(b)
+	if q.restart {
+		b = append(b, " RESTART"...)
+		if q.restartWith != nil {
+			b = append(b, " WITH "...)
+			b = strconv.AppendInt(b, *q.restartWith, 10)
+		}
+	}
+
+	if len(b) == start {
+		return nil, errors.New("bun: ALTER SEQUENCE requires at least one option")
+	}
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (q *AlterSequenceQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	res, err := q.exec(ctx, q, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
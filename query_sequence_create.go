@@ -0,0 +1,179 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+type CreateSequenceQuery struct {
+	baseQuery
+	sequenceOptions
+
+	ifNotExists bool
+	name        schema.QueryWithArgs
+}
+
+var _ Query = (*CreateSequenceQuery)(nil)
+
+func NewCreateSequenceQuery(db *DB, name string) *CreateSequenceQuery {
+	q := &CreateSequenceQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+		name: schema.UnsafeIdent(name),
+	}
+	return q
+}
+
+func (q *CreateSequenceQuery) Conn(db IConn) *CreateSequenceQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *CreateSequenceQuery) Err(err error) *CreateSequenceQuery {
+	q.setErr(err)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *CreateSequenceQuery) IfNotExists() *CreateSequenceQuery {
+	q.ifNotExists = true
+	return q
+}
+
+func (q *CreateSequenceQuery) Start(n int64) *CreateSequenceQuery {
+	q.start = &n
+	return q
+}
+
+func (q *CreateSequenceQuery) Increment(n int64) *CreateSequenceQuery {
+	q.increment = &n
+	return q
+}
+
+func (q *CreateSequenceQuery) Min(n int64) *CreateSequenceQuery {
+	q.min = &n
+	return q
+}
+
+func (q *CreateSequenceQuery) Max(n int64) *CreateSequenceQuery {
+	q.max = &n
+	return q
+}
+
+// Cache sets how many sequence numbers are preallocated in memory.
+func (q *CreateSequenceQuery) Cache(n int64) *CreateSequenceQuery {
+	q.cache = &n
+	return q
+}
+
+// Cycle allows the sequence to wrap around when it reaches the max or min value.
+func (q *CreateSequenceQuery) Cycle() *CreateSequenceQuery {
+	cycle := true
+	q.cycle = &cycle
+	return q
+}
+
+func (q *CreateSequenceQuery) NoCycle() *CreateSequenceQuery {
+	cycle := false
+	q.cycle = &cycle
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *CreateSequenceQuery) Operation() string {
+	return "CREATE SEQUENCE"
+}
+
+func (q *CreateSequenceQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if !fmter.HasFeature(feature.Sequence) {
+		return nil, fmt.Errorf("bun: CREATE SEQUENCE is not supported by %s", q.db.dialect.Name())
+	}
+
+	b = append(b, "CREATE SEQUENCE "...)
+	if q.ifNotExists {
+		b = append(b, "IF NOT EXISTS "...)
+	}
+
+	b, err = q.name.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.start != nil {
+		b = append(b, " START WITH "...)
+		b = strconv.AppendInt(b, *q.start, 10)
+	}
+	b = q.appendOptions(b)
+
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (q *CreateSequenceQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	res, err := q.exec(ctx, q, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+//------------------------------------------------------------------------------
+
+// sequenceOptions are the options shared by CREATE SEQUENCE and ALTER SEQUENCE.
+type sequenceOptions struct {
+	start     *int64
+	increment *int64
+	min       *int64
+	max       *int64
+	cache     *int64
+	cycle     *bool
+}
+
+func (o *sequenceOptions) appendOptions(b []byte) []byte {
+	if o.increment != nil {
+		b = append(b, " INCREMENT BY "...)
+		b = strconv.AppendInt(b, *o.increment, 10)
+	}
+	if o.min != nil {
+		b = append(b, " MINVALUE "...)
+		b = strconv.AppendInt(b, *o.min, 10)
+	}
+	if o.max != nil {
+		b = append(b, " MAXVALUE "...)
+		b = strconv.AppendInt(b, *o.max, 10)
+	}
+	if o.cache != nil {
+		b = append(b, " CACHE "...)
+		b = strconv.AppendInt(b, *o.cache, 10)
+	}
+	if o.cycle != nil {
+		if *o.cycle {
+			b = append(b, " CYCLE"...)
+		} else {
+			b = append(b, " NO CYCLE"...)
+		}
+	}
+	return b
+}
@@ -0,0 +1,107 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+type DropSequenceQuery struct {
+	baseQuery
+	cascadeQuery
+
+	ifExists bool
+	name     schema.QueryWithArgs
+}
+
+var _ Query = (*DropSequenceQuery)(nil)
+
+func NewDropSequenceQuery(db *DB, name string) *DropSequenceQuery {
+	q := &DropSequenceQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+		name: schema.UnsafeIdent(name),
+	}
+	return q
+}
+
+func (q *DropSequenceQuery) Conn(db IConn) *DropSequenceQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *DropSequenceQuery) Err(err error) *DropSequenceQuery {
+	q.setErr(err)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *DropSequenceQuery) IfExists() *DropSequenceQuery {
+	q.ifExists = true
+	return q
+}
+
+// Cascade drops the objects that depend on the sequence, for example, column defaults.
+func (q *DropSequenceQuery) Cascade() *DropSequenceQuery {
+	q.cascade = true
+	return q
+}
+
+func (q *DropSequenceQuery) Restrict() *DropSequenceQuery {
+	q.restrict = true
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *DropSequenceQuery) Operation() string {
+	return "DROP SEQUENCE"
+}
+
+func (q *DropSequenceQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if !fmter.HasFeature(feature.Sequence) {
+		return nil, fmt.Errorf("bun: DROP SEQUENCE is not supported by %s", q.db.dialect.Name())
+	}
+
+	b = append(b, "DROP SEQUENCE "...)
+	if q.ifExists {
+		b = append(b, "IF EXISTS "...)
+	}
+
+	b, err = q.name.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = q.appendCascade(fmter, b)
+
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (q *DropSequenceQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	res, err := q.exec(ctx, q, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/uptrace/bun/dialect"
 	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/dialect/sqltype"
 	"github.com/uptrace/bun/internal"
@@ -21,6 +22,9 @@ type CreateTableQuery struct {
 	temp        bool
 	ifNotExists bool
 	fksFromRel  bool // Create foreign keys captured in table's relations.
+	temporal    bool
+	withIndexes bool
+	withEnums   bool
 
 	// varchar changes the default length for VARCHAR columns.
 	// Because some dialects require that length is always specified for VARCHAR type,
@@ -112,6 +116,9 @@ func (q *CreateTableQuery) ForeignKey(query string, args ...interface{}) *Create
 	return q
 }
 
+// PartitionBy creates a partitioned table with the partitioning strategy and key,
+// for example, PartitionBy("RANGE (created_at)") or PartitionBy("HASH (tenant_id)").
+// Partitions are created with DB.NewCreateTablePartition.
 func (q *CreateTableQuery) PartitionBy(query string, args ...interface{}) *CreateTableQuery {
 	q.partitionBy = schema.SafeQuery(query, args)
 	return q
@@ -123,11 +130,49 @@ func (q *CreateTableQuery) TableSpace(tablespace string) *CreateTableQuery {
 }
 
 // WithForeignKeys adds a FOREIGN KEY clause for each of the model's existing relations.
+// The ON UPDATE and ON DELETE actions are configured with the relation tag, for example,
+// `bun:"rel:belongs-to,fk:on_delete:cascade"`. The constraints are unnamed unless
+// the tag has the fk:name:<name> option or the fk:name option, which names the constraint
+// fk_<table>_<columns> shortened with a hash to fit the identifier length limit.
 func (q *CreateTableQuery) WithForeignKeys() *CreateTableQuery {
 	q.fksFromRel = true
 	return q
 }
 
+// WithIndexes creates the indexes defined with the index tag option after creating the table,
+// see schema.IndexDef. Indexes without a name are named <table>_<columns>_idx,
+// which is shortened with a hash to fit the identifier length limit.
+func (q *CreateTableQuery) WithIndexes() *CreateTableQuery {
+	q.withIndexes = true
+	return q
+}
+
+// WithEnumTypes creates the enum types used by the model fields before creating the table,
+// see schema.RegisterEnum. Existing types are not changed, use migrate.SyncEnums to add
+// the new values. Only PostgreSQL supports enum types.
+func (q *CreateTableQuery) WithEnumTypes() *CreateTableQuery {
+	q.withEnums = true
+	return q
+}
+
+// AsTemporalTable makes the database keep the history of the table rows, see TemporalTable.
+// On MSSQL, it creates a system-versioned table with the <table>_history history table,
+// and insert and update queries skip the valid_from and valid_to columns, which are set
+// by the database.
+// On PostgreSQL, it creates a trigger that keeps old row versions in the same table,
+// so the primary key must include the valid_from column, for example:
+//
+//	type Price struct {
+//		bun.TemporalTable
+//
+//		ID        int64     `bun:",pk"`
+//		ValidFrom time.Time `bun:",pk,default:current_timestamp"`
+//	}
+func (q *CreateTableQuery) AsTemporalTable() *CreateTableQuery {
+	q.temporal = true
+	return q
+}
+
 // ------------------------------------------------------------------------------
 
 func (q *CreateTableQuery) Operation() string {
@@ -155,6 +200,14 @@ func (q *CreateTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 		return nil, err
 	}
 
+	mssqlTemporal := false
+	if q.temporal {
+		if err := checkTemporalTable(q.table, q.db.dialect.Name()); err != nil {
+			return nil, err
+		}
+		mssqlTemporal = q.db.dialect.Name() == dialect.MSSQL
+	}
+
 	b = append(b, " ("...)
 
 	for i, field := range q.table.Fields {
@@ -163,6 +216,24 @@ func (q *CreateTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 		}
 
 		b = append(b, field.SQLName...)
+
+		if mssqlTemporal && (field.Name == temporalFromColumn || field.Name == temporalToColumn) {
+			b = append(b, " DATETIME2 GENERATED ALWAYS AS ROW "...)
+			if field.Name == temporalFromColumn {
+				b = append(b, "START"...)
+			} else {
+				b = append(b, "END"...)
+			}
+			b = append(b, " NOT NULL"...)
+			continue
+		}
+
+		if field.SQLGenerated != "" {
+			b = q.appendGeneratedColumn(b, field)
+			b = q.appendInlineComment(fmter, b, field.Comment)
+			continue
+		}
+
 		b = append(b, " "...)
 		b = q.appendSQLType(b, field)
 		if field.NotNull {
@@ -178,6 +249,14 @@ func (q *CreateTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 			b = append(b, " DEFAULT "...)
 			b = append(b, field.SQLDefault...)
 		}
+
+		if field.SQLCheck != "" {
+			b = append(b, " CHECK ("...)
+			b = append(b, field.SQLCheck...)
+			b = append(b, ')')
+		}
+
+		b = q.appendInlineComment(fmter, b, field.Comment)
 	}
 
 	for i, col := range q.columns {
@@ -200,6 +279,7 @@ func (q *CreateTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 		b = q.appendPKConstraint(b, q.table.PKs)
 	}
 	b = q.appendUniqueConstraints(fmter, b)
+	b = q.appendCheckConstraints(fmter, b)
 
 	if q.fksFromRel {
 		b, err = q.appendFKConstraintsRel(fmter, b)
@@ -212,8 +292,27 @@ func (q *CreateTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 		return nil, err
 	}
 
+	if mssqlTemporal {
+		b = append(b, ", PERIOD FOR SYSTEM_TIME ("...)
+		b = fmter.AppendIdent(b, temporalFromColumn)
+		b = append(b, ", "...)
+		b = fmter.AppendIdent(b, temporalToColumn)
+		b = append(b, ")"...)
+	}
+
 	b = append(b, ")"...)
 
+	if mssqlTemporal {
+		b = append(b, " WITH (SYSTEM_VERSIONING = ON (HISTORY_TABLE = "...)
+		b = fmter.AppendIdent(b, mssqlHistoryTable(q.table.Name))
+		b = append(b, "))"...)
+	}
+
+	if q.table.Comment != "" && q.db.dialect.Name() == dialect.MySQL {
+		b = append(b, " COMMENT = "...)
+		b = schema.Append(fmter, b, q.table.Comment)
+	}
+
 	if !q.partitionBy.IsZero() {
 		b = append(b, " PARTITION BY "...)
 		b, err = q.partitionBy.AppendQuery(fmter, b)
@@ -233,6 +332,36 @@ func (q *CreateTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 	return b, nil
 }
 
+func (q *CreateTableQuery) appendGeneratedColumn(b []byte, field *schema.Field) []byte {
+	// SQL Server computed columns don't have a type.
+	if q.db.dialect.Name() == dialect.MSSQL {
+		b = append(b, " AS ("...)
+		b = append(b, field.SQLGenerated...)
+		b = append(b, ") PERSISTED"...)
+		return b
+	}
+
+	b = append(b, " "...)
+	b = q.appendSQLType(b, field)
+	b = append(b, " GENERATED ALWAYS AS ("...)
+	b = append(b, field.SQLGenerated...)
+	b = append(b, ") STORED"...)
+	if field.NotNull {
+		b = append(b, " NOT NULL"...)
+	}
+	return b
+}
+
+// appendInlineComment appends a column comment for MySQL.
+// PostgreSQL comments are created with separate COMMENT ON queries, see createComments.
+func (q *CreateTableQuery) appendInlineComment(fmter schema.Formatter, b []byte, comment string) []byte {
+	if comment == "" || q.db.dialect.Name() != dialect.MySQL {
+		return b
+	}
+	b = append(b, " COMMENT "...)
+	return schema.Append(fmter, b, comment)
+}
+
 func (q *CreateTableQuery) appendSQLType(b []byte, field *schema.Field) []byte {
 	// Most of the time these two will match, but for the cases where DiscoveredSQLType is dialect-specific,
 	// e.g. pgdialect would change sqltype.SmallInt to pgTypeSmallSerial for columns that have `bun:",autoincrement"`
@@ -275,6 +404,21 @@ func (q *CreateTableQuery) appendUniqueConstraints(fmter schema.Formatter, b []b
 	return b
 }
 
+func (q *CreateTableQuery) appendCheckConstraints(fmter schema.Formatter, b []byte) []byte {
+	for _, check := range q.table.Checks {
+		if check.Name != "" {
+			b = append(b, ", CONSTRAINT "...)
+			b = fmter.AppendIdent(b, check.Name)
+		} else {
+			b = append(b, ","...)
+		}
+		b = append(b, " CHECK ("...)
+		b = append(b, check.Expr...)
+		b = append(b, ')')
+	}
+	return b
+}
+
 func (q *CreateTableQuery) appendUniqueConstraint(
 	fmter schema.Formatter, b []byte, name string, fields ...*schema.Field,
 ) []byte {
@@ -292,18 +436,26 @@ func (q *CreateTableQuery) appendUniqueConstraint(
 
 // appendFKConstraintsRel appends a FOREIGN KEY clause for each of the model's existing relations.
 func (q *CreateTableQuery) appendFKConstraintsRel(fmter schema.Formatter, b []byte) (_ []byte, err error) {
-	for _, rel := range q.tableModel.Table().Relations {
+	table := q.tableModel.Table()
+	for _, rel := range table.Relations {
 		if rel.References() {
-			b, err = q.appendFK(fmter, b, schema.QueryWithArgs{
+			if rel.FKName != "" {
+				b = append(b, ", CONSTRAINT "...)
+				b = fmter.AppendIdent(b, rel.FKName)
+				b = append(b, " FOREIGN KEY "...)
+			} else {
+				b = append(b, ", FOREIGN KEY "...)
+			}
+			b, err = schema.QueryWithArgs{
 				Query: "(?) REFERENCES ? (?) ? ?",
 				Args: []interface{}{
 					Safe(appendColumns(nil, "", rel.BaseFields)),
-					rel.JoinTable.SQLName,
+					fmter.TableName(rel.JoinTable),
 					Safe(appendColumns(nil, "", rel.JoinFields)),
 					Safe(rel.OnUpdate),
 					Safe(rel.OnDelete),
 				},
-			})
+			}.AppendQuery(fmter, b)
 			if err != nil {
 				return nil, err
 			}
@@ -338,11 +490,19 @@ func (q *CreateTableQuery) appendPKConstraint(b []byte, pks []*schema.Field) []b
 // ------------------------------------------------------------------------------
 
 func (q *CreateTableQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
-	if err := q.beforeCreateTableHook(ctx); err != nil {
-		return nil, err
+	if q.table != nil {
+		if err := q.beforeCreateTableHook(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if q.table != nil && q.withEnums && q.hasFeature(feature.EnumType) {
+		if err := q.createEnumTypes(ctx); err != nil {
+			return nil, err
+		}
 	}
 
-	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
 	if err != nil {
 		return nil, err
 	}
@@ -355,6 +515,23 @@ func (q *CreateTableQuery) Exec(ctx context.Context, dest ...interface{}) (sql.R
 	}
 
 	if q.table != nil {
+		if q.db.dialect.Name() == dialect.PG {
+			if err := q.createComments(ctx); err != nil {
+				return nil, err
+			}
+			if q.temporal {
+				if err := q.createTemporalTrigger(ctx); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if q.withIndexes {
+			if err := q.createIndexes(ctx); err != nil {
+				return nil, err
+			}
+		}
+
 		if err := q.afterCreateTableHook(ctx); err != nil {
 			return nil, err
 		}
@@ -363,6 +540,132 @@ func (q *CreateTableQuery) Exec(ctx context.Context, dest ...interface{}) (sql.R
 	return res, nil
 }
 
+// createTemporalTrigger creates the trigger that keeps old row versions, see AsTemporalTable.
+func (q *CreateTableQuery) createTemporalTrigger(ctx context.Context) error {
+	fmter := q.db.formatter(ctx)
+
+	table, err := q.appendFirstTable(fmter, nil)
+	if err != nil {
+		return err
+	}
+	name := fmter.AppendIdent(nil, strings.ReplaceAll(q.table.Name, ".", "_")+"_versioning")
+	afterName := fmter.AppendIdent(nil, strings.ReplaceAll(q.table.Name, ".", "_")+"_versioning_after")
+
+	queries := [][]byte{
+		appendPGTemporalFunction(nil, name, table),
+		[]byte("DROP TRIGGER IF EXISTS " + string(name) + " ON " + string(table)),
+		[]byte("CREATE TRIGGER " + string(name) + " BEFORE UPDATE OR DELETE ON " + string(table) +
+			" FOR EACH ROW EXECUTE PROCEDURE " + string(name) + "()"),
+		[]byte("DROP TRIGGER IF EXISTS " + string(afterName) + " ON " + string(table)),
+		[]byte("CREATE TRIGGER " + string(afterName) + " AFTER UPDATE ON " + string(table) +
+			" FOR EACH ROW EXECUTE PROCEDURE " + string(name) + "()"),
+	}
+	for _, query := range queries {
+		if _, err := q.exec(ctx, q, internal.String(query)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createIndexes creates the indexes defined with the index tag option, see WithIndexes.
+func (q *CreateTableQuery) createIndexes(ctx context.Context) error {
+	fmter := q.db.formatter(ctx)
+
+	table, err := q.appendFirstTable(fmter, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, idx := range q.table.Indexes {
+		name := idx.Name
+		if name == "" {
+			name = internal.TruncateIdent(strings.ReplaceAll(q.table.Name, ".", "_") + "_" +
+				strings.Join(idx.Columns, "_") + "_idx")
+		}
+
+		query := q.db.NewCreateIndex().
+			Conn(q.conn).
+			TableExpr("?", Safe(table)).
+			Index(name).
+			Column(idx.Columns...)
+		if idx.Unique {
+			query.Unique()
+		}
+		// MySQL doesn't support CREATE INDEX IF NOT EXISTS.
+		if q.ifNotExists && q.db.dialect.Name() != dialect.MySQL {
+			query.IfNotExists()
+		}
+
+		if _, err := query.Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createComments creates the table and column comments with COMMENT ON queries.
+func (q *CreateTableQuery) createComments(ctx context.Context) error {
+	fmter := q.db.formatter(ctx)
+
+	table, err := q.appendFirstTable(fmter, nil)
+	if err != nil {
+		return err
+	}
+
+	if q.table.Comment != "" {
+		query := fmter.FormatQuery("COMMENT ON TABLE ? IS ?", Safe(table), q.table.Comment)
+		if _, err := q.exec(ctx, q, query); err != nil {
+			return err
+		}
+	}
+
+	for _, field := range q.table.Fields {
+		if field.Comment == "" {
+			continue
+		}
+		query := fmter.FormatQuery("COMMENT ON COLUMN ?.? IS ?",
+			Safe(table), field.SQLName, field.Comment)
+		if _, err := q.exec(ctx, q, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createEnumTypes creates enum types used by the model's fields, skipping types that already exist.
+func (q *CreateTableQuery) createEnumTypes(ctx context.Context) error {
+	fmter := q.db.formatter(ctx)
+	seen := make(map[string]struct{})
+	for _, field := range q.table.Fields {
+		typeName, ok := field.Tag.Option("type")
+		if !ok || field.Enum == nil {
+			continue
+		}
+		if _, ok := seen[typeName]; ok {
+			continue
+		}
+		seen[typeName] = struct{}{}
+
+		query := internal.String(appendCreateEnumType(fmter, nil, typeName, field.Enum))
+		if _, err := q.exec(ctx, q, query); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendCreateEnumType appends CREATE TYPE wrapped in a DO block,
+// because PostgreSQL does not support CREATE TYPE IF NOT EXISTS.
+func appendCreateEnumType(fmter schema.Formatter, b []byte, typeName string, enum *schema.Enum) []byte {
+	b = append(b, "DO $$ BEGIN CREATE TYPE "...)
+	b = fmter.AppendIdent(b, typeName)
+	b = append(b, " AS ENUM ("...)
+	b = enum.AppendValues(fmter, b)
+	b = append(b, "); EXCEPTION WHEN duplicate_object THEN NULL; END $$"...)
+	return b
+}
+
 func (q *CreateTableQuery) beforeCreateTableHook(ctx context.Context) error {
 	if hook, ok := q.table.ZeroIface.(BeforeCreateTableHook); ok {
 		if err := hook.BeforeCreateTable(ctx, q); err != nil {
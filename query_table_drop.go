@@ -3,7 +3,11 @@ package bun
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
 	"github.com/uptrace/bun/schema"
 )
@@ -12,7 +16,8 @@ type DropTableQuery struct {
 	baseQuery
 	cascadeQuery
 
-	ifExists bool
+	ifExists   bool
+	fksFromRel bool
 }
 
 var _ Query = (*DropTableQuery)(nil)
@@ -68,13 +73,29 @@ func (q *DropTableQuery) IfExists() *DropTableQuery {
 	return q
 }
 
+// Cascade drops the objects that depend on the table, for example, the foreign keys
+// of other tables. The query fails on dialects without the TableCascade feature.
 func (q *DropTableQuery) Cascade() *DropTableQuery {
 	q.cascade = true
+	q.restrict = false
 	return q
 }
 
+// Restrict refuses to drop the table if other objects depend on it.
+// The query fails on dialects without the TableCascade feature.
 func (q *DropTableQuery) Restrict() *DropTableQuery {
 	q.restrict = true
+	q.cascade = false
+	return q
+}
+
+// WithForeignKeys drops the table's FOREIGN KEY constraints, for example, the ones added
+// by CreateTableQuery.WithForeignKeys, before dropping the table, for example, to drop
+// tables that reference each other. The constraint names are read from information_schema,
+// so unnamed constraints are dropped too. SQLite can't drop constraints, so the option
+// is ignored there.
+func (q *DropTableQuery) WithForeignKeys() *DropTableQuery {
+	q.fksFromRel = true
 	return q
 }
 
@@ -88,6 +109,10 @@ func (q *DropTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte
 	if q.err != nil {
 		return nil, q.err
 	}
+	if (q.cascade || q.restrict) && !fmter.HasFeature(feature.TableCascade) {
+		return nil, fmt.Errorf("bun: DROP TABLE ... CASCADE/RESTRICT is not supported by %s",
+			q.db.dialect.Name())
+	}
 
 	b = append(b, "DROP TABLE "...)
 	if q.ifExists {
@@ -113,7 +138,13 @@ func (q *DropTableQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Res
 		}
 	}
 
-	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	if q.fksFromRel && q.table != nil {
+		if err := q.dropFKConstraints(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
 	if err != nil {
 		return nil, err
 	}
@@ -134,6 +165,68 @@ func (q *DropTableQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Res
 	return res, nil
 }
 
+func (q *DropTableQuery) dropFKConstraints(ctx context.Context) error {
+	dialectName := q.db.dialect.Name()
+	if dialectName == dialect.SQLite {
+		return nil
+	}
+
+	names, err := q.fkConstraintNames(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmter := q.db.formatter(ctx)
+	for _, name := range names {
+		b := []byte("ALTER TABLE ")
+		b, err := q.appendFirstTable(fmter, b)
+		if err != nil {
+			return err
+		}
+		if dialectName == dialect.MySQL {
+			b = append(b, " DROP FOREIGN KEY "...)
+		} else {
+			b = append(b, " DROP CONSTRAINT "...)
+		}
+		b = fmter.AppendIdent(b, name)
+
+		if _, err := q.exec(ctx, q, internal.String(b)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fkConstraintNames returns the names of the table's FOREIGN KEY constraints,
+// which can be unnamed in CREATE TABLE and named by the database.
+func (q *DropTableQuery) fkConstraintNames(ctx context.Context) ([]string, error) {
+	sel := q.db.NewSelect().
+		Conn(q.conn).
+		Column("constraint_name").
+		TableExpr("information_schema.table_constraints").
+		Where("constraint_type = 'FOREIGN KEY'")
+
+	if schemaName, tableName, ok := strings.Cut(q.table.Name, "."); ok {
+		sel = sel.Where("table_schema = ?", schemaName).Where("table_name = ?", tableName)
+	} else {
+		sel = sel.Where("table_name = ?", q.table.Name)
+		switch q.db.dialect.Name() {
+		case dialect.PG:
+			sel = sel.Where("table_schema = current_schema()")
+		case dialect.MySQL:
+			sel = sel.Where("table_schema = DATABASE()")
+		case dialect.MSSQL:
+			sel = sel.Where("table_schema = SCHEMA_NAME()")
+		}
+	}
+
+	var names []string
+	if err := sel.Scan(ctx, &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
 func (q *DropTableQuery) beforeDropTableHook(ctx context.Context) error {
 	if hook, ok := q.table.ZeroIface.(BeforeDropTableHook); ok {
 		if err := hook.BeforeDropTable(ctx, q); err != nil {
@@ -16,6 +16,7 @@ import (
 type UpdateQuery struct {
 	whereBaseQuery
 	returningQuery
+	orderLimitQuery
 	customValueQuery
 	setQuery
 	idxHintsQuery
@@ -53,6 +54,14 @@ func (q *UpdateQuery) Err(err error) *UpdateQuery {
 	return q
 }
 
+// Comment prepends the comment to the query, for example, /* comment */ UPDATE ...
+// Comment delimiters are removed from the comment. The comment takes precedence over
+// the comment set with WithQueryComment.
+func (q *UpdateQuery) Comment(comment string) *UpdateQuery {
+	q.setComment(comment)
+	return q
+}
+
 // Apply calls the fn passing the SelectQuery as an argument.
 func (q *UpdateQuery) Apply(fn func(*UpdateQuery) *UpdateQuery) *UpdateQuery {
 	if fn != nil {
@@ -61,6 +70,14 @@ func (q *UpdateQuery) Apply(fn func(*UpdateQuery) *UpdateQuery) *UpdateQuery {
 	return q
 }
 
+// ApplyIf calls the fn passing the UpdateQuery as an argument only if cond is true.
+func (q *UpdateQuery) ApplyIf(cond bool, fn func(*UpdateQuery) *UpdateQuery) *UpdateQuery {
+	if cond {
+		return q.Apply(fn)
+	}
+	return q
+}
+
 func (q *UpdateQuery) With(name string, query schema.QueryAppender) *UpdateQuery {
 	q.addWith(name, query, false)
 	return q
@@ -171,11 +188,27 @@ func (q *UpdateQuery) Where(query string, args ...interface{}) *UpdateQuery {
 	return q
 }
 
+// WhereIf is like Where, but adds the condition only if cond is true, for example,
+// WhereIf(name != "", "name = ?", name).
+func (q *UpdateQuery) WhereIf(cond bool, query string, args ...interface{}) *UpdateQuery {
+	if cond {
+		return q.Where(query, args...)
+	}
+	return q
+}
+
 func (q *UpdateQuery) WhereOr(query string, args ...interface{}) *UpdateQuery {
 	q.addWhere(schema.SafeQueryWithSep(query, args, " OR "))
 	return q
 }
 
+// WhereMap adds a "column = value" condition for each map entry, or "column IS NULL"
+// for nil values. Conditions are sorted by column name and joined with AND.
+func (q *UpdateQuery) WhereMap(conditions map[string]interface{}) *UpdateQuery {
+	q.addWhereMap(conditions)
+	return q
+}
+
 func (q *UpdateQuery) WhereGroup(sep string, fn func(*UpdateQuery) *UpdateQuery) *UpdateQuery {
 	saved := q.where
 	q.where = nil
@@ -202,6 +235,43 @@ func (q *UpdateQuery) WhereAllWithDeleted() *UpdateQuery {
 
 //------------------------------------------------------------------------------
 
+// Order adds an ORDER BY clause to the query. Only MySQL supports ORDER BY
+// in UPDATE queries.
+func (q *UpdateQuery) Order(orders ...string) *UpdateQuery {
+	q.order = appendOrder(q.order, orders...)
+	return q
+}
+
+// OrderIf is like Order, but adds the orders only if cond is true.
+func (q *UpdateQuery) OrderIf(cond bool, orders ...string) *UpdateQuery {
+	if cond {
+		return q.Order(orders...)
+	}
+	return q
+}
+
+func (q *UpdateQuery) OrderExpr(query string, args ...interface{}) *UpdateQuery {
+	q.order = append(q.order, schema.SafeQuery(query, args))
+	return q
+}
+
+// Limit adds a LIMIT clause to the query. Only MySQL supports LIMIT
+// in UPDATE queries.
+func (q *UpdateQuery) Limit(n int) *UpdateQuery {
+	q.limit = int32(n)
+	return q
+}
+
+// LimitIf is like Limit, but sets the limit only if cond is true.
+func (q *UpdateQuery) LimitIf(cond bool, n int) *UpdateQuery {
+	if cond {
+		return q.Limit(n)
+	}
+	return q
+}
+
+//------------------------------------------------------------------------------
+
 // Returning adds a RETURNING clause to the query.
 //
 // To suppress the auto-generated RETURNING clause, use `Returning("NULL")`.
@@ -210,6 +280,20 @@ func (q *UpdateQuery) Returning(query string, args ...interface{}) *UpdateQuery
 	return q
 }
 
+// Output adds an OUTPUT clause written for MSSQL, for example,
+// Output("DELETED.name AS old_name, INSERTED.*"). On the other dialects, it adds
+// a RETURNING clause with the INSERTED qualifiers removed and returns an error
+// for the DELETED qualifiers, because RETURNING only selects the new values.
+func (q *UpdateQuery) Output(query string, args ...interface{}) *UpdateQuery {
+	query, err := outputQuery(q.db, query)
+	if err != nil {
+		q.setErr(err)
+		return q
+	}
+	q.addReturning(schema.SafeQuery(query, args))
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 func (q *UpdateQuery) Operation() string {
@@ -222,6 +306,7 @@ func (q *UpdateQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, e
 	}
 
 	fmter = formatterWithModel(fmter, q)
+	b = q.appendComment(b)
 
 	b, err = q.appendWith(fmter, b)
 	if err != nil {
@@ -278,6 +363,16 @@ func (q *UpdateQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, e
 		return nil, err
 	}
 
+	if q.hasOrderLimit() {
+		if !q.hasFeature(feature.UpdateOrderLimit) {
+			return nil, fmt.Errorf("bun: UPDATE ... ORDER BY/LIMIT is not supported by %s", q.db.dialect.Name())
+		}
+		b, err = q.appendOrderLimit(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if q.hasFeature(feature.Returning) && q.hasReturning() {
 		b = append(b, " RETURNING "...)
 		b, err = q.appendReturning(fmter, b)
@@ -297,6 +392,9 @@ func (q *UpdateQuery) mustAppendSet(fmter schema.Formatter, b []byte) (_ []byte,
 	}
 
 	if m, ok := q.model.(*mapModel); ok {
+		if len(m.m) == 0 {
+			return nil, errors.New("bun: empty SET clause is not allowed in the UPDATE query")
+		}
 		return m.appendSet(fmter, b), nil
 	}
 
@@ -334,6 +432,11 @@ func (q *UpdateQuery) appendSetStruct(
 	if err != nil {
 		return nil, err
 	}
+	fields, err = q.withoutUnloadedLazyFields(fields, true)
+	if err != nil {
+		return nil, err
+	}
+	fields = q.withoutPeriodFields(fields)
 
 	isTemplate := fmter.IsNop()
 	pos := len(b)
@@ -435,6 +538,11 @@ func (q *UpdateQuery) updateSliceSet(
 	if err != nil {
 		return "", err
 	}
+	fields, err = q.withoutUnloadedLazyFields(fields, true)
+	if err != nil {
+		return "", err
+	}
+	fields = q.withoutPeriodFields(fields)
 
 	var b []byte
 	pos := len(b)
@@ -466,7 +574,7 @@ func (q *UpdateQuery) updateSliceWhere(fmter schema.Formatter, model *sliceTable
 		if q.hasTableAlias(fmter) {
 			b = append(b, model.table.SQLAlias...)
 		} else {
-			b = append(b, model.table.SQLName...)
+			b = append(b, "?TableName"...)
 		}
 		b = append(b, '.')
 		b = append(b, pk.SQLName...)
@@ -479,12 +587,16 @@ func (q *UpdateQuery) updateSliceWhere(fmter schema.Formatter, model *sliceTable
 //------------------------------------------------------------------------------
 
 func (q *UpdateQuery) Scan(ctx context.Context, dest ...interface{}) error {
-	_, err := q.scanOrExec(ctx, dest, true)
+	_, err := q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		return q.scanOrExec(ctx, dest, true)
+	})
 	return err
 }
 
 func (q *UpdateQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
-	return q.scanOrExec(ctx, dest, len(dest) > 0)
+	return q.db.handleQuery(ctx, q, func(ctx context.Context) (sql.Result, error) {
+		return q.scanOrExec(ctx, dest, len(dest) > 0)
+	})
 }
 
 func (q *UpdateQuery) scanOrExec(
@@ -498,6 +610,11 @@ func (q *UpdateQuery) scanOrExec(
 		if err := q.beforeUpdateHook(ctx); err != nil {
 			return nil, err
 		}
+		if len(q.set) == 0 && q.tableModel != nil {
+			if err := q.checkRedactedFields(ctx, q.getDataFields); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// Run append model hooks before generating the query.
@@ -506,7 +623,7 @@ func (q *UpdateQuery) scanOrExec(
 	}
 
 	// Generate the query before checking hasReturning.
-	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
 	if err != nil {
 		return nil, err
 	}
@@ -638,6 +755,36 @@ func (q *updateQueryBuilder) WherePK(cols ...string) QueryBuilder {
 	return q
 }
 
+func (q *updateQueryBuilder) Order(orders ...string) QueryBuilder {
+	q.UpdateQuery.Order(orders...)
+	return q
+}
+
+func (q *updateQueryBuilder) OrderExpr(query string, args ...interface{}) QueryBuilder {
+	q.UpdateQuery.OrderExpr(query, args...)
+	return q
+}
+
+func (q *updateQueryBuilder) Limit(n int) QueryBuilder {
+	q.UpdateQuery.Limit(n)
+	return q
+}
+
+func (q *updateQueryBuilder) Offset(n int) QueryBuilder {
+	q.setErr(errors.New("bun: OFFSET is not supported by UPDATE queries"))
+	return q
+}
+
+func (q *updateQueryBuilder) Group(columns ...string) QueryBuilder {
+	q.setErr(errors.New("bun: GROUP BY is not supported by UPDATE queries"))
+	return q
+}
+
+func (q *updateQueryBuilder) GroupExpr(query string, args ...interface{}) QueryBuilder {
+	q.setErr(errors.New("bun: GROUP BY is not supported by UPDATE queries"))
+	return q
+}
+
 func (q *updateQueryBuilder) Unwrap() interface{} {
 	return q.UpdateQuery
 }
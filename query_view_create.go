@@ -0,0 +1,172 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+type CreateViewQuery struct {
+	baseQuery
+
+	replace      bool
+	ifNotExists  bool
+	materialized bool
+	withData     *bool
+
+	name  schema.QueryWithArgs
+	query *SelectQuery
+}
+
+var _ Query = (*CreateViewQuery)(nil)
+
+func NewCreateViewQuery(db *DB, name string) *CreateViewQuery {
+	q := &CreateViewQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+		name: schema.UnsafeIdent(name),
+	}
+	return q
+}
+
+func (q *CreateViewQuery) Conn(db IConn) *CreateViewQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *CreateViewQuery) Err(err error) *CreateViewQuery {
+	q.setErr(err)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+// Query sets the SELECT query of the view.
+func (q *CreateViewQuery) Query(query *SelectQuery) *CreateViewQuery {
+	q.query = query
+	return q
+}
+
+// Replace replaces the view if it exists with CREATE OR REPLACE VIEW.
+// Materialized views can't be replaced and SQLite doesn't support replacing views.
+func (q *CreateViewQuery) Replace() *CreateViewQuery {
+	q.replace = true
+	return q
+}
+
+// IfNotExists adds IF NOT EXISTS, which is supported by SQLite and by PostgreSQL
+// materialized views. Other views return an error, so use Replace instead.
+func (q *CreateViewQuery) IfNotExists() *CreateViewQuery {
+	q.ifNotExists = true
+	return q
+}
+
+// Materialized creates a materialized view that stores the result of the query.
+// Only PostgreSQL supports materialized views.
+func (q *CreateViewQuery) Materialized() *CreateViewQuery {
+	q.materialized = true
+	return q
+}
+
+// WithData sets whether the materialized view is populated when it is created.
+// A view created with WithData(false) can't be queried until it is refreshed.
+func (q *CreateViewQuery) WithData(withData bool) *CreateViewQuery {
+	q.withData = &withData
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *CreateViewQuery) Operation() string {
+	return "CREATE VIEW"
+}
+
+func (q *CreateViewQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if q.query == nil {
+		return nil, errors.New("bun: CREATE VIEW requires a query")
+	}
+	if q.materialized {
+		if !fmter.HasFeature(feature.MaterializedView) {
+			return nil, fmt.Errorf("bun: materialized views are not supported by %s",
+				q.db.dialect.Name())
+		}
+		if q.replace {
+			return nil, errors.New("bun: materialized views can't be replaced")
+		}
+	} else if q.withData != nil {
+		return nil, errors.New("bun: WithData requires a materialized view")
+	}
+	if q.replace && q.db.dialect.Name() == dialect.SQLite {
+		return nil, errors.New("bun: CREATE OR REPLACE VIEW is not supported by sqlite")
+	}
+	if q.ifNotExists && !q.materialized && q.db.dialect.Name() != dialect.SQLite {
+		return nil, fmt.Errorf("bun: CREATE VIEW IF NOT EXISTS is not supported by %s "+
+			"(use Replace or a materialized view)", q.db.dialect.Name())
+	}
+
+	b = append(b, "CREATE "...)
+	if q.replace {
+		if q.db.dialect.Name() == dialect.MSSQL {
+			b = append(b, "OR ALTER "...)
+		} else {
+			b = append(b, "OR REPLACE "...)
+		}
+	}
+	if q.materialized {
+		b = append(b, "MATERIALIZED "...)
+	}
+	b = append(b, "VIEW "...)
+	if q.ifNotExists {
+		b = append(b, "IF NOT EXISTS "...)
+	}
+
+	b, err = q.name.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = append(b, " AS "...)
+	b, err = q.query.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.withData != nil {
+		if *q.withData {
+			b = append(b, " WITH DATA"...)
+		} else {
+			b = append(b, " WITH NO DATA"...)
+		}
+	}
+
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (q *CreateViewQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	res, err := q.exec(ctx, q, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,118 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+type DropViewQuery struct {
+	baseQuery
+	cascadeQuery
+
+	ifExists     bool
+	materialized bool
+
+	name schema.QueryWithArgs
+}
+
+var _ Query = (*DropViewQuery)(nil)
+
+func NewDropViewQuery(db *DB, name string) *DropViewQuery {
+	q := &DropViewQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+		name: schema.UnsafeIdent(name),
+	}
+	return q
+}
+
+func (q *DropViewQuery) Conn(db IConn) *DropViewQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *DropViewQuery) Err(err error) *DropViewQuery {
+	q.setErr(err)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *DropViewQuery) IfExists() *DropViewQuery {
+	q.ifExists = true
+	return q
+}
+
+// Materialized drops a materialized view.
+func (q *DropViewQuery) Materialized() *DropViewQuery {
+	q.materialized = true
+	return q
+}
+
+func (q *DropViewQuery) Cascade() *DropViewQuery {
+	q.cascade = true
+	return q
+}
+
+func (q *DropViewQuery) Restrict() *DropViewQuery {
+	q.restrict = true
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *DropViewQuery) Operation() string {
+	return "DROP VIEW"
+}
+
+func (q *DropViewQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if q.materialized && !fmter.HasFeature(feature.MaterializedView) {
+		return nil, fmt.Errorf("bun: materialized views are not supported by %s", q.db.dialect.Name())
+	}
+
+	b = append(b, "DROP "...)
+	if q.materialized {
+		b = append(b, "MATERIALIZED "...)
+	}
+	b = append(b, "VIEW "...)
+	if q.ifExists {
+		b = append(b, "IF EXISTS "...)
+	}
+
+	b, err = q.name.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = q.appendCascade(fmter, b)
+
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (q *DropViewQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	res, err := q.exec(ctx, q, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,117 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+// RefreshViewQuery refreshes the data of a materialized view.
+type RefreshViewQuery struct {
+	baseQuery
+
+	concurrently bool
+	withData     *bool
+
+	name schema.QueryWithArgs
+}
+
+var _ Query = (*RefreshViewQuery)(nil)
+
+func NewRefreshViewQuery(db *DB, name string) *RefreshViewQuery {
+	q := &RefreshViewQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+		name: schema.UnsafeIdent(name),
+	}
+	return q
+}
+
+func (q *RefreshViewQuery) Conn(db IConn) *RefreshViewQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *RefreshViewQuery) Err(err error) *RefreshViewQuery {
+	q.setErr(err)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+// Concurrently refreshes the view without locking out concurrent selects on the view.
+// The view must have a unique index.
+func (q *RefreshViewQuery) Concurrently() *RefreshViewQuery {
+	q.concurrently = true
+	return q
+}
+
+// WithData sets whether the view is populated. WithData(false) frees the storage
+// of the view and leaves it in an unscannable state.
+func (q *RefreshViewQuery) WithData(withData bool) *RefreshViewQuery {
+	q.withData = &withData
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *RefreshViewQuery) Operation() string {
+	return "REFRESH MATERIALIZED VIEW"
+}
+
+func (q *RefreshViewQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if !fmter.HasFeature(feature.MaterializedView) {
+		return nil, fmt.Errorf("bun: materialized views are not supported by %s", q.db.dialect.Name())
+	}
+	if q.concurrently && q.withData != nil && !*q.withData {
+		return nil, errors.New("bun: REFRESH MATERIALIZED VIEW CONCURRENTLY can't be used WITH NO DATA")
+	}
+
+	b = append(b, "REFRESH MATERIALIZED VIEW "...)
+	if q.concurrently {
+		b = append(b, "CONCURRENTLY "...)
+	}
+
+	b, err = q.name.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.withData != nil {
+		if *q.withData {
+			b = append(b, " WITH DATA"...)
+		} else {
+			b = append(b, " WITH NO DATA"...)
+		}
+	}
+
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (q *RefreshViewQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.formatter(ctx), q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	res, err := q.exec(ctx, q, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,149 @@
+package bun
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/uptrace/bun/schema"
+)
+
+// Redacted is the value of the redacted string fields.
+const Redacted = "REDACTED"
+
+type roleCtxKey struct{}
+
+// WithRole returns a copy of the context with the role used by DB.WithRedactionPolicy.
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleCtxKey{}, role)
+}
+
+// RoleFromContext returns the role set with WithRole or an empty string.
+func RoleFromContext(ctx context.Context) string {
+	role, _ := ctx.Value(roleCtxKey{}).(string)
+	return role
+}
+
+// RedactionPolicy reports whether the role can read the sensitive field of the table.
+type RedactionPolicy func(role string, table *schema.Table, field *schema.Field) bool
+
+// AllowRoles returns a policy that allows the roles to read all sensitive fields.
+func AllowRoles(roles ...string) RedactionPolicy {
+	allowed := make(map[string]struct{}, len(roles))
+	for _, role := range roles {
+		allowed[role] = struct{}{}
+	}
+	return func(role string, _ *schema.Table, _ *schema.Field) bool {
+		_, ok := allowed[role]
+		return ok
+	}
+}
+
+// WithRedactionPolicy returns a copy of the DB that redacts the fields tagged with
+// the sensitive option when the role from the context is not allowed to read them:
+//
+//	type Employee struct {
+//		ID     int64
+//		Salary int64  `bun:",sensitive"`
+//		SSN    string `bun:",sensitive"`
+//	}
+//
+//	db = db.WithRedactionPolicy(bun.AllowRoles("admin"))
+//
+//	ctx = bun.WithRole(ctx, "user")
+//	err := db.NewSelect().Model(&employees).Scan(ctx)
+//
+// The fields are redacted after each row is scanned and before the AfterScanRow model hook,
+// so string fields are set to Redacted and other fields are set to zero values. This includes
+// the rows scanned with DB.ScanRows. Values scanned into maps or primitive destinations
+// are not redacted.
+//
+// Because the redacted values would overwrite the data, INSERT and UPDATE queries
+// that write the sensitive fields the role can't read return an error. Use ExcludeColumn
+// to write the other fields of the model.
+func (db *DB) WithRedactionPolicy(policy RedactionPolicy) *DB {
+	clone := db.clone()
+	clone.redactionPolicy = policy
+	return clone
+}
+
+// FieldRedactionHook sets the redaction policy of the DB it is added to.
+// It is an alternative to DB.WithRedactionPolicy that modifies the DB in place:
+//
+//	db.AddQueryHook(bun.NewFieldRedactionHook(bun.AllowRoles("admin")))
+type FieldRedactionHook struct {
+	policy RedactionPolicy
+}
+
+var _ QueryHook = (*FieldRedactionHook)(nil)
+
+func NewFieldRedactionHook(policy RedactionPolicy) *FieldRedactionHook {
+	return &FieldRedactionHook{policy: policy}
+}
+
+// Init sets the redaction policy of the DB, see DB.WithRedactionPolicy.
+func (h *FieldRedactionHook) Init(db *DB) {
+	db.redactionPolicy = h.policy
+}
+
+func (h *FieldRedactionHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *FieldRedactionHook) AfterQuery(ctx context.Context, event *QueryEvent) {}
+
+// checkRedactedFields returns an error if the fields written by the query include
+// sensitive fields that the role from the context can't read.
+func (q *baseQuery) checkRedactedFields(
+	ctx context.Context, getFields func() ([]*schema.Field, error),
+) error {
+	if q.table == nil || !q.table.HasSensitiveFields() {
+		return nil
+	}
+	policy := q.db.redactionPolicy
+	if policy == nil {
+		return nil
+	}
+
+	fields, err := getFields()
+	if err != nil {
+		return err
+	}
+
+	role := RoleFromContext(ctx)
+	for _, field := range fields {
+		if field.Sensitive && !policy(role, q.table, field) {
+			return fmt.Errorf(
+				"bun: role %q can't write the redacted field %s.%s (use ExcludeColumn)",
+				role, q.table.TypeName, field.GoName)
+		}
+	}
+	return nil
+}
+
+func redactFields(role string, policy RedactionPolicy, table *schema.Table, strct reflect.Value) {
+	if !table.HasSensitiveFields() {
+		return
+	}
+
+	for _, field := range table.Fields {
+		if !field.Sensitive || policy(role, table, field) {
+			continue
+		}
+		redactValue(field.Value(strct))
+	}
+}
+
+func redactValue(v reflect.Value) {
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(Redacted)
+	case v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.String:
+		// Don't modify the string the pointer may share with other values.
+		ptr := reflect.New(v.Type().Elem())
+		ptr.Elem().SetString(Redacted)
+		v.Set(ptr)
+	default:
+		v.Set(reflect.Zero(v.Type()))
+	}
+}
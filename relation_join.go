@@ -2,6 +2,7 @@ package bun
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"time"
 
@@ -10,6 +11,29 @@ import (
 	"github.com/uptrace/bun/schema"
 )
 
+// RelationError is returned when Bun fails to load a has-many or many-to-many relation.
+type RelationError struct {
+	Relation string // relation name, e.g. Orders
+	Model    string // parent model name, e.g. User
+	Cause    error
+}
+
+func newRelationError(j *relationJoin, err error) *RelationError {
+	return &RelationError{
+		Relation: j.Relation.Field.GoName,
+		Model:    j.BaseModel.Table().TypeName,
+		Cause:    err,
+	}
+}
+
+func (e *RelationError) Error() string {
+	return fmt.Sprintf("loading relation %s on %s: %s", e.Relation, e.Model, e.Cause)
+}
+
+func (e *RelationError) Unwrap() error {
+	return e.Cause
+}
+
 type relationJoin struct {
 	Parent    *relationJoin
 	BaseModel TableModel
@@ -50,7 +74,10 @@ func (j *relationJoin) selectMany(ctx context.Context, q *SelectQuery) error {
 	if q == nil {
 		return nil
 	}
-	return q.Scan(ctx)
+	if err := q.Scan(ctx); err != nil {
+		return newRelationError(j, err)
+	}
+	return nil
 }
 
 func (j *relationJoin) manyQuery(q *SelectQuery) *SelectQuery {
@@ -158,15 +185,18 @@ func (j *relationJoin) hasManyColumns(q *SelectQuery) *SelectQuery {
 }
 
 func (j *relationJoin) selectM2M(ctx context.Context, q *SelectQuery) error {
-	q = j.m2mQuery(q)
+	q = j.m2mQuery(ctx, q)
 	if q == nil {
 		return nil
 	}
-	return q.Scan(ctx)
+	if err := q.Scan(ctx); err != nil {
+		return newRelationError(j, err)
+	}
+	return nil
 }
 
-func (j *relationJoin) m2mQuery(q *SelectQuery) *SelectQuery {
-	fmter := q.db.fmter
+func (j *relationJoin) m2mQuery(ctx context.Context, q *SelectQuery) *SelectQuery {
+	fmter := q.db.formatter(ctx)
 
 	m2mModel := newM2MModel(j)
 	if m2mModel == nil {
@@ -184,12 +214,16 @@ func (j *relationJoin) m2mQuery(q *SelectQuery) *SelectQuery {
 		b = appendColumns(b, j.Relation.M2MTable.SQLAlias, fields)
 
 		q = q.ColumnExpr(internal.String(b))
+
+		if j.Relation.M2MPivotIndex != nil {
+			q = q.ColumnExpr(internal.String(j.appendPivotColumns(fmter, nil)))
+		}
 	}
 
 	//nolint
 	var join []byte
 	join = append(join, "JOIN "...)
-	join = fmter.AppendQuery(join, string(j.Relation.M2MTable.SQLName))
+	join = fmter.AppendQuery(join, string(fmter.TableName(j.Relation.M2MTable)))
 	join = append(join, " AS "...)
 	join = append(join, j.Relation.M2MTable.SQLAlias...)
 	join = append(join, " ON ("...)
@@ -220,6 +254,23 @@ func (j *relationJoin) m2mQuery(q *SelectQuery) *SelectQuery {
 	return q
 }
 
+// appendPivotColumns selects all m2m table columns with the m2mPivotPrefix
+// so they don't clash with the join table columns.
+func (j *relationJoin) appendPivotColumns(fmter schema.Formatter, b []byte) []byte {
+	m2mTable := j.Relation.M2MTable
+	for i, f := range m2mTable.Fields {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = append(b, m2mTable.SQLAlias...)
+		b = append(b, '.')
+		b = append(b, f.SQLName...)
+		b = append(b, " AS "...)
+		b = fmter.AppendIdent(b, m2mPivotPrefix+f.Name)
+	}
+	return b
+}
+
 func (j *relationJoin) hasParent() bool {
 	if j.Parent != nil {
 		switch j.Parent.Relation.Type {
@@ -303,7 +354,7 @@ func (j *relationJoin) appendHasOneJoin(
 	isSoftDelete := j.JoinModel.Table().SoftDeleteField != nil && !q.flags.Has(allWithDeletedFlag)
 
 	b = append(b, "LEFT JOIN "...)
-	b = fmter.AppendQuery(b, string(j.JoinModel.Table().SQLNameForSelects))
+	b = fmter.AppendQuery(b, string(fmter.TableNameForSelects(j.JoinModel.Table())))
 	b = append(b, " AS "...)
 	b = j.appendAlias(fmter, b)
 
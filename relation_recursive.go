@@ -0,0 +1,169 @@
+package bun
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/schema"
+)
+
+const recursiveRelationCTE = "_bun_tree"
+
+type recursiveRelation struct {
+	rel      *schema.Relation
+	maxDepth int
+}
+
+// WithRecursiveRelation loads a self-referential has-many relation, for example,
+// Category.Children, using a single WITH RECURSIVE query. Nesting is limited
+// by maxDepth, which also protects against cycles in the data.
+func (q *SelectQuery) WithRecursiveRelation(name string, maxDepth int) *SelectQuery {
+	if q.table == nil {
+		q.setErr(errNilModel)
+		return q
+	}
+
+	rel, ok := q.table.Relations[name]
+	if !ok {
+		q.setErr(fmt.Errorf("%s does not have relation=%q", q.table, name))
+		return q
+	}
+	if rel.Type != schema.HasManyRelation ||
+		rel.JoinTable != q.table ||
+		len(rel.JoinFields) != 1 {
+		q.setErr(fmt.Errorf(
+			"bun: WithRecursiveRelation requires a self-referential has-many relation, got %s", rel))
+		return q
+	}
+	if maxDepth < 1 {
+		q.setErr(fmt.Errorf("bun: WithRecursiveRelation(%q) requires maxDepth >= 1", name))
+		return q
+	}
+	if !q.hasFeature(feature.CTE) {
+		q.setErr(fmt.Errorf("bun: WithRecursiveRelation is not supported by %s", q.db.dialect.Name()))
+		return q
+	}
+
+	q.recursiveRels = append(q.recursiveRels, recursiveRelation{
+		rel:      rel,
+		maxDepth: maxDepth,
+	})
+	return q
+}
+
+func (q *SelectQuery) selectRecursive(
+	ctx context.Context, model TableModel, rr recursiveRelation,
+) error {
+	rel := rr.rel
+	table := rel.JoinTable
+	baseField := rel.BaseFields[0]
+	joinField := rel.JoinFields[0]
+
+	var parents []reflect.Value
+	var ids []interface{}
+	walk(model.rootValue(), nil, func(v reflect.Value) {
+		parents = append(parents, v)
+		ids = append(ids, baseField.Value(v).Interface())
+	})
+	if len(ids) == 0 {
+		return nil
+	}
+
+	zero := reflect.Zero(reflect.PtrTo(table.Type)).Interface()
+	cte := Ident(recursiveRelationCTE)
+
+	anchor := q.db.NewSelect().
+		Model(zero).
+		ColumnExpr("?TableColumns").
+		ColumnExpr("1 AS ?", Ident("depth")).
+		Where("?TableAlias.? IN (?)", joinField.SQLName, In(ids))
+	recursive := q.db.NewSelect().
+		Model(zero).
+		ColumnExpr("?TableColumns").
+		ColumnExpr("?.? + 1", cte, Ident("depth")).
+		Join("JOIN ? ON ?.? = ?TableAlias.?", cte, cte, baseField.SQLName, joinField.SQLName).
+		Where("?.? < ?", cte, Ident("depth"), rr.maxDepth)
+
+	rows := reflect.New(reflect.SliceOf(reflect.PtrTo(table.Type)))
+	if err := q.db.NewSelect().
+		Conn(q.conn).
+		WithRecursive(recursiveRelationCTE, SafeQuery("? UNION ALL ?", anchor, recursive)).
+		Model(rows.Interface()).
+		ModelTableExpr("? AS ?", cte, table.SQLAlias).
+		Scan(ctx); err != nil {
+		return &RelationError{
+			Relation: rel.Field.GoName,
+			Model:    table.TypeName,
+			Cause:    err,
+		}
+	}
+
+	children := make(map[interface{}][]reflect.Value)
+	seen := make(map[interface{}]struct{})
+	rows = rows.Elem()
+	for i := 0; i < rows.Len(); i++ {
+		row := rows.Index(i)
+		id := baseField.Value(row.Elem()).Interface()
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		parentID := joinField.Value(row.Elem())
+		if parentID.Kind() == reflect.Ptr {
+			if parentID.IsNil() {
+				continue
+			}
+			parentID = parentID.Elem()
+		}
+		key := parentID.Interface()
+		children[key] = append(children[key], row)
+	}
+
+	path := make(map[interface{}]struct{})
+	for _, parent := range parents {
+		assignRecursiveChildren(parent, rel, children, path, rr.maxDepth)
+	}
+	return nil
+}
+
+func assignRecursiveChildren(
+	parent reflect.Value,
+	rel *schema.Relation,
+	children map[interface{}][]reflect.Value,
+	path map[interface{}]struct{},
+	depth int,
+) {
+	if depth == 0 {
+		return
+	}
+
+	id := rel.BaseFields[0].Value(parent).Interface()
+	path[id] = struct{}{}
+	defer delete(path, id)
+
+	slice := rel.Field.Value(parent)
+	if slice.Kind() == reflect.Ptr {
+		if slice.IsNil() {
+			slice.Set(reflect.New(slice.Type().Elem()))
+		}
+		slice = slice.Elem()
+	}
+	slice.Set(reflect.Zero(slice.Type()))
+
+	isPtr := slice.Type().Elem().Kind() == reflect.Ptr
+	for _, child := range children[id] {
+		// Skip ancestors to break cycles in the data.
+		if _, ok := path[rel.BaseFields[0].Value(child.Elem()).Interface()]; ok {
+			continue
+		}
+		assignRecursiveChildren(child.Elem(), rel, children, path, depth-1)
+		if isPtr {
+			slice.Set(reflect.Append(slice, child))
+		} else {
+			slice.Set(reflect.Append(slice, child.Elem()))
+		}
+	}
+}
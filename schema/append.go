@@ -70,6 +70,22 @@ type inValues struct {
 
 var _ QueryAppender = (*inValues)(nil)
 
+// SplitIn splits the values of the appender created by In into appenders
+// with at most size values. It reports false for other appenders.
+func SplitIn(appender QueryAppender, size int) ([]QueryAppender, bool) {
+	in, ok := appender.(*inValues)
+	if !ok || in.err != nil || size <= 0 {
+		return nil, false
+	}
+
+	sliceLen := in.slice.Len()
+	chunks := make([]QueryAppender, 0, (sliceLen+size-1)/size)
+	for i := 0; i < sliceLen; i += size {
+		chunks = append(chunks, &inValues{slice: in.slice.Slice(i, min(i+size, sliceLen))})
+	}
+	return chunks, true
+}
+
 func (in *inValues) AppendQuery(fmter Formatter, b []byte) (_ []byte, err error) {
 	if in.err != nil {
 		return nil, in.err
@@ -60,6 +60,10 @@ func FieldAppender(dialect Dialect, field *Field) AppenderFunc {
 
 	fieldType := field.StructField.Type
 
+	if fn := dialectTypes(dialect).Appender(fieldType); fn != nil {
+		return fn
+	}
+
 	switch strings.ToUpper(field.UserSQLType) {
 	case sqltype.JSON, sqltype.JSONB:
 		if fieldType.Implements(driverValuerType) {
@@ -79,6 +83,10 @@ func FieldAppender(dialect Dialect, field *Field) AppenderFunc {
 }
 
 func Appender(dialect Dialect, typ reflect.Type) AppenderFunc {
+	if fn := dialectTypes(dialect).Appender(typ); fn != nil {
+		return fn
+	}
+
 	if v, ok := appenderMap.Load(typ); ok {
 		return v.(AppenderFunc)
 	}
@@ -105,6 +113,10 @@ func appender(dialect Dialect, typ reflect.Type) AppenderFunc {
 		return appendIPNetValue
 	case jsonRawMessageType:
 		return appendJSONRawMessageValue
+	case encryptedFieldType:
+		return appendEncryptedFieldValue
+	case encryptedFieldPtrType:
+		return nilAwareAppender(appendEncryptedFieldValue)
 	}
 
 	kind := typ.Kind()
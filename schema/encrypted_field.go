@@ -0,0 +1,164 @@
+package schema
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/uptrace/bun/dialect"
+)
+
+// AESGCM is the encryption algorithm used by EncryptedField.
+const AESGCM = "aes-gcm"
+
+var (
+	encryptedFieldType    = reflect.TypeOf((*EncryptedField)(nil)).Elem()
+	encryptedFieldPtrType = reflect.PtrTo(encryptedFieldType)
+
+	errEncryptionKeyRequired = errors.New(
+		"bun: EncryptedField requires a key (use NewEncryptedField or DB.WithEncryptionKey)")
+)
+
+// EncryptedField is a value that is encrypted before it is written to the database
+// and decrypted after it is scanned. The ciphertext is stored as binary data,
+// so the column type is BLOB (BYTEA in PostgreSQL).
+//
+// The key is either bound with NewEncryptedField or taken from the query context,
+// see DB.WithEncryptionKey. The key from the context is only used for that query
+// and is not stored in the field.
+type EncryptedField struct {
+	Plaintext string
+
+	key        []byte
+	algo       string
+	ciphertext []byte // scanned, but not yet decrypted
+}
+
+var (
+	_ driver.Valuer = (*EncryptedField)(nil)
+	_ sql.Scanner   = (*EncryptedField)(nil)
+)
+
+// NewEncryptedField returns an EncryptedField that uses the key and the algorithm.
+// AESGCM is the only supported algorithm and the default one.
+// The key length selects AES-128, AES-192, or AES-256.
+func NewEncryptedField(key []byte, algo string) *EncryptedField {
+	return &EncryptedField{
+		key:  key,
+		algo: algo,
+	}
+}
+
+// Decrypt decrypts the scanned value. It uses the field key or, if the field has no key,
+// the key argument. The key argument is not stored in the field.
+func (f *EncryptedField) Decrypt(key []byte) error {
+	if f.ciphertext == nil {
+		return nil
+	}
+
+	plaintext, err := f.decrypt(f.keyOr(key), f.ciphertext)
+	if err != nil {
+		return err
+	}
+
+	f.Plaintext = string(plaintext)
+	f.ciphertext = nil
+	return nil
+}
+
+func (f EncryptedField) Value() (driver.Value, error) {
+	return f.encrypt(f.key)
+}
+
+func (f *EncryptedField) Scan(src interface{}) error {
+	f.Plaintext = ""
+	f.ciphertext = nil
+
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		f.ciphertext = append([]byte(nil), src...)
+	case string:
+		f.ciphertext = []byte(src)
+	default:
+		return fmt.Errorf("bun: EncryptedField can't scan %T", src)
+	}
+
+	if f.key == nil {
+		// Decrypted later with the key from the query context.
+		return nil
+	}
+	return f.Decrypt(nil)
+}
+
+func (f *EncryptedField) keyOr(key []byte) []byte {
+	if f.key != nil {
+		return f.key
+	}
+	return key
+}
+
+func (f *EncryptedField) encrypt(key []byte) ([]byte, error) {
+	aead, err := f.aead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(f.Plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, []byte(f.Plaintext), nil), nil
+}
+
+func (f *EncryptedField) decrypt(key, ciphertext []byte) ([]byte, error) {
+	aead, err := f.aead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("bun: EncryptedField ciphertext is too short")
+	}
+
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("bun: EncryptedField decryption failed: %w", err)
+	}
+	return plaintext, nil
+}
+
+func (f *EncryptedField) aead(key []byte) (cipher.AEAD, error) {
+	if key == nil {
+		return nil, errEncryptionKeyRequired
+	}
+
+	switch f.algo {
+	case "", AESGCM:
+	default:
+		return nil, fmt.Errorf("bun: unsupported encryption algorithm %q", f.algo)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func appendEncryptedFieldValue(fmter Formatter, b []byte, v reflect.Value) []byte {
+	f := reflect.Indirect(v).Interface().(EncryptedField)
+	ciphertext, err := f.encrypt(f.keyOr(fmter.EncryptionKey()))
+	if err != nil {
+		return dialect.AppendError(b, err)
+	}
+	return fmter.Dialect().AppendBytes(b, ciphertext)
+}
@@ -0,0 +1,159 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/uptrace/bun/dialect"
+)
+
+// Enum describes a Go type that is stored as a database enum.
+type Enum struct {
+	Type   reflect.Type
+	Values []string
+
+	index map[string]int
+}
+
+var enumMap sync.Map
+
+// RegisterEnum registers the values of the enum type T. T must be a string or an integer type.
+// String-backed types are stored as is and integer-backed types (usually declared with iota)
+// are stored as Values[n].
+//
+// The enum values are also converted when they are used as query arguments,
+// for example, in Where("mood = ?", MoodHappy). The enum must be registered
+// before the models that use it, for example:
+//
+//	type Mood int
+//
+//	const (
+//		MoodSad Mood = iota
+//		MoodHappy
+//	)
+//
+//	schema.RegisterEnum[Mood]("sad", "happy")
+//
+//	type User struct {
+//		Mood Mood `bun:",type:mood"`
+//	}
+//
+// On PostgreSQL, the type is created by CreateTableQuery.WithEnumTypes or migrate.SyncEnums.
+func RegisterEnum[T any](values ...string) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	switch typ.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		panic(fmt.Errorf("bun: RegisterEnum(unsupported %s)", typ))
+	}
+	if len(values) == 0 {
+		panic(fmt.Errorf("bun: RegisterEnum(%s) requires at least one value", typ))
+	}
+
+	enum := &Enum{
+		Type:   typ,
+		Values: values,
+		index:  make(map[string]int, len(values)),
+	}
+	for i, value := range values {
+		if _, ok := enum.index[value]; ok {
+			panic(fmt.Errorf("bun: RegisterEnum(%s) has duplicated value %q", typ, value))
+		}
+		enum.index[value] = i
+	}
+
+	enumMap.Store(typ, enum)
+	// Query arguments and values appended with AppendValue use the type registry.
+	globalTypes.Register(typ, enumAppender(enum), enumScanner(enum))
+}
+
+// LookupEnum returns the enum registered for the type or nil.
+func LookupEnum(typ reflect.Type) *Enum {
+	if v, ok := enumMap.Load(indirectType(typ)); ok {
+		return v.(*Enum)
+	}
+	return nil
+}
+
+func (e *Enum) String() string {
+	return "enum=" + e.Type.String()
+}
+
+// Index returns the position of the value or -1.
+func (e *Enum) Index(value string) int {
+	if i, ok := e.index[value]; ok {
+		return i
+	}
+	return -1
+}
+
+// AppendValues appends a comma-separated list of quoted enum values, e.g. 'sad', 'happy'.
+func (e *Enum) AppendValues(fmter Formatter, b []byte) []byte {
+	for i, value := range e.Values {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = fmter.Dialect().AppendString(b, value)
+	}
+	return b
+}
+
+//------------------------------------------------------------------------------
+
+func enumAppender(enum *Enum) AppenderFunc {
+	return func(fmter Formatter, b []byte, v reflect.Value) []byte {
+		var i int64
+		switch v.Kind() {
+		case reflect.String:
+			if enum.Index(v.String()) == -1 {
+				return dialect.AppendError(b, fmt.Errorf("bun: %s does not have value %q", enum, v.String()))
+			}
+			return fmter.Dialect().AppendString(b, v.String())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			i = int64(v.Uint())
+		default:
+			i = v.Int()
+		}
+		if i < 0 || i >= int64(len(enum.Values)) {
+			return dialect.AppendError(b, fmt.Errorf("bun: %s does not have value %d", enum, i))
+		}
+		return fmter.Dialect().AppendString(b, enum.Values[i])
+	}
+}
+
+func enumScanner(enum *Enum) ScannerFunc {
+	return func(dest reflect.Value, src interface{}) error {
+		if src == nil {
+			return scanNull(dest)
+		}
+
+		var s string
+		switch src := src.(type) {
+		case string:
+			s = src
+		case []byte:
+			s = string(src)
+		default:
+			return scanError(dest.Type(), src)
+		}
+
+		i := enum.Index(s)
+		if i == -1 {
+			return fmt.Errorf("bun: %s does not have value %q", enum, s)
+		}
+
+		switch dest.Kind() {
+		case reflect.String:
+			dest.SetString(s)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			dest.SetUint(uint64(i))
+		default:
+			dest.SetInt(int64(i))
+		}
+		return nil
+	}
+}
@@ -25,15 +25,33 @@ type Field struct {
 	UserSQLType        string
 	CreateTableSQLType string
 	SQLDefault         string
+	SQLGenerated       string // expression of a GENERATED ALWAYS AS (...) STORED column
+	SQLCheck           string // expression of the CHECK (...) column constraint
+	Comment            string
+	// Pos is the 1-based position of the column set with the pos tag option or 0.
+	Pos int
 
 	OnDelete string
 	OnUpdate string
 
+	Enum *Enum
+
 	IsPK          bool
 	NotNull       bool
 	NullZero      bool
 	AutoIncrement bool
 	Identity      bool
+	// UUIDGenerate is set for uuid columns that default to gen_random_uuid().
+	// Zero values of such fields are replaced with random UUIDs before insert.
+	UUIDGenerate bool
+	// ULIDGenerate is set for ulid columns when RegisterULID is called.
+	// Zero values of such fields are replaced with new ULIDs before insert.
+	ULIDGenerate bool
+	// Sensitive is set with the sensitive tag option. DB.WithRedactionPolicy redacts
+	// such fields for the roles that are not allowed to read them.
+	Sensitive bool
+	// Lazy is set with the lazy tag option. Lazy columns are not selected by default.
+	Lazy bool
 
 	Append AppenderFunc
 	Scan   ScannerFunc
@@ -131,6 +149,7 @@ func (f *Field) ScanWithCheck(fv reflect.Value, src interface{}) error {
 	return f.Scan(fv, src)
 }
 
+// SkipUpdate reports whether the field must not be updated. Generated columns are read-only.
 func (f *Field) SkipUpdate() bool {
-	return f.Tag.HasOption("skipupdate")
+	return f.Tag.HasOption("skipupdate") || f.SQLGenerated != ""
 }
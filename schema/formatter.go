@@ -16,8 +16,10 @@ var nopFormatter = Formatter{
 }
 
 type Formatter struct {
-	dialect Dialect
-	args    *namedArgList
+	dialect       Dialect
+	args          *namedArgList
+	tableNames    map[reflect.Type]Safe
+	encryptionKey []byte
 }
 
 func NewFormatter(dialect Dialect) Formatter {
@@ -50,6 +52,12 @@ func (f Formatter) AppendIdent(b []byte, ident string) []byte {
 	return dialect.AppendIdent(b, ident, f.IdentQuote())
 }
 
+// QuoteIdent returns the identifier quoted with the dialect quote, for example,
+// "users" for PostgreSQL and `users` for MySQL.
+func (f Formatter) QuoteIdent(ident string) string {
+	return internal.String(f.AppendIdent(nil, ident))
+}
+
 func (f Formatter) AppendValue(b []byte, v reflect.Value) []byte {
 	if v.Kind() == reflect.Ptr && v.IsNil() {
 		return dialect.AppendNull(b)
@@ -64,16 +72,88 @@ func (f Formatter) HasFeature(feature feature.Feature) bool {
 
 func (f Formatter) WithArg(arg NamedArgAppender) Formatter {
 	return Formatter{
-		dialect: f.dialect,
-		args:    f.args.WithArg(arg),
+		dialect:       f.dialect,
+		args:          f.args.WithArg(arg),
+		tableNames:    f.tableNames,
+		encryptionKey: f.encryptionKey,
 	}
 }
 
 func (f Formatter) WithNamedArg(name string, value interface{}) Formatter {
 	return Formatter{
-		dialect: f.dialect,
-		args:    f.args.WithArg(&namedArg{name: name, value: value}),
+		dialect:       f.dialect,
+		args:          f.args.WithArg(&namedArg{name: name, value: value}),
+		tableNames:    f.tableNames,
+		encryptionKey: f.encryptionKey,
+	}
+}
+
+// WithNamedArgsFrom returns a formatter that also uses the named args of the other formatter.
+// The named args of f take precedence.
+func (f Formatter) WithNamedArgsFrom(other Formatter) Formatter {
+	args := other.args
+	if f.args != nil {
+		if args == nil {
+			args = f.args
+		} else {
+			args = &namedArgList{arg: f.args, next: args}
+		}
+	}
+	return Formatter{
+		dialect:       f.dialect,
+		args:          args,
+		tableNames:    f.tableNames,
+		encryptionKey: f.encryptionKey,
+	}
+}
+
+// WithTableName returns a formatter that uses the name instead of the table name
+// of the model type. The table alias is not changed.
+func (f Formatter) WithTableName(typ reflect.Type, name string) Formatter {
+	tableNames := make(map[reflect.Type]Safe, len(f.tableNames)+1)
+	for k, v := range f.tableNames {
+		tableNames[k] = v
+	}
+	tableNames[indirectType(typ)] = Safe(f.AppendIdent(nil, name))
+
+	return Formatter{
+		dialect:       f.dialect,
+		args:          f.args,
+		tableNames:    tableNames,
+		encryptionKey: f.encryptionKey,
+	}
+}
+
+// WithEncryptionKey returns a formatter that encrypts EncryptedField values
+// that don't have a key with the key.
+func (f Formatter) WithEncryptionKey(key []byte) Formatter {
+	return Formatter{
+		dialect:       f.dialect,
+		args:          f.args,
+		tableNames:    f.tableNames,
+		encryptionKey: key,
+	}
+}
+
+// EncryptionKey returns the key set with WithEncryptionKey.
+func (f Formatter) EncryptionKey() []byte {
+	return f.encryptionKey
+}
+
+// TableName returns the quoted table name, taking into account names set with WithTableName.
+func (f Formatter) TableName(table *Table) Safe {
+	if name, ok := f.tableNames[table.Type]; ok {
+		return name
+	}
+	return table.SQLName
+}
+
+// TableNameForSelects is like TableName, but returns the name used in SELECT queries.
+func (f Formatter) TableNameForSelects(table *Table) Safe {
+	if name, ok := f.tableNames[table.Type]; ok {
+		return name
 	}
+	return table.SQLNameForSelects
 }
 
 func (f Formatter) FormatQuery(query string, args ...interface{}) string {
@@ -20,7 +20,10 @@ type Relation struct {
 	JoinFields []*Field
 	OnUpdate   string
 	OnDelete   string
-	Condition  []string
+	// FKName is the name of the FOREIGN KEY constraint set with the fk:name option,
+	// or empty if the constraint is unnamed.
+	FKName    string
+	Condition []string
 
 	PolymorphicField *Field
 	PolymorphicValue string
@@ -28,6 +31,32 @@ type Relation struct {
 	M2MTable      *Table
 	M2MBaseFields []*Field
 	M2MJoinFields []*Field
+	// M2MPivotIndex is the index of the base model field that receives
+	// M2MTable rows, e.g. User.UserRoles for the m2m:user_roles relation.
+	M2MPivotIndex []int
+}
+
+// PolymorphicRelation describes the discriminator of a polymorphic has-many relation,
+// for example, commentable_type and commentable_id columns in a comments table.
+type PolymorphicRelation struct {
+	TypeField *Field
+	IDField   *Field
+	Value     string
+}
+
+// Polymorphic returns the polymorphic discriminator or nil if the relation is not polymorphic.
+func (r *Relation) Polymorphic() *PolymorphicRelation {
+	if r.PolymorphicField == nil {
+		return nil
+	}
+	poly := &PolymorphicRelation{
+		TypeField: r.PolymorphicField,
+		Value:     r.PolymorphicValue,
+	}
+	if len(r.JoinFields) == 1 {
+		poly.IDField = r.JoinFields[0]
+	}
+	return poly
 }
 
 // References returns true if the table to which the Relation belongs needs to declare a foreign key constraint to create the relation.
@@ -59,6 +59,9 @@ func FieldScanner(dialect Dialect, field *Field) ScannerFunc {
 	if field.Tag.HasOption("msgpack") {
 		return scanMsgpack
 	}
+	if fn := dialectTypes(dialect).Scanner(field.StructField.Type); fn != nil {
+		return fn
+	}
 	if field.Tag.HasOption("json_use_number") {
 		return scanJSONUseNumber
 	}
@@ -72,6 +75,10 @@ func FieldScanner(dialect Dialect, field *Field) ScannerFunc {
 }
 
 func Scanner(typ reflect.Type) ScannerFunc {
+	if fn := globalTypes.Scanner(typ); fn != nil {
+		return fn
+	}
+
 	if v, ok := scannerMap.Load(typ); ok {
 		return v.(ScannerFunc)
 	}
@@ -61,6 +61,10 @@ func DiscoverSQLType(typ reflect.Type) string {
 		return sqltype.VarChar
 	case jsonRawMessageType:
 		return sqltype.JSON
+	case encryptedFieldType:
+		return sqltype.Blob
+	case ulidType:
+		return ulidSQLType
 	}
 
 	switch typ.Kind() {
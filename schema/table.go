@@ -4,11 +4,15 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/jinzhu/inflection"
 
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/sqltype"
 	"github.com/uptrace/bun/internal"
 	"github.com/uptrace/bun/internal/tagparser"
 )
@@ -19,6 +23,10 @@ const (
 	afterScanHookFlag
 	beforeScanRowHookFlag
 	afterScanRowHookFlag
+	encryptedFieldsFlag
+	generatedIDFieldsFlag
+	sensitiveFieldsFlag
+	lazyFieldsFlag
 )
 
 var (
@@ -62,13 +70,62 @@ type Table struct {
 
 	Relations map[string]*Relation
 	Unique    map[string][]*Field
+	// Indexes are the indexes defined with the index tag option.
+	Indexes []*IndexDef
+	// Checks are the table CHECK constraints returned by the TableChecks method of the model.
+	// Column CHECK constraints are stored in Field.SQLCheck.
+	Checks []CheckDef
+
+	Comment string
 
 	SoftDeleteField       *Field
 	UpdateSoftDeleteField func(fv reflect.Value, tm time.Time) error
 
+	// WasInsertedField is the scanonly field with the was_inserted option that
+	// InsertQuery.ReturnInserted sets to report whether the row was inserted.
+	WasInsertedField *Field
+
 	flags internal.Flag
 }
 
+// IndexDef is an index defined with the index tag option, for example:
+//
+//	type User struct {
+//		bun.BaseModel `bun:"table:users,index:users_name_email_idx,columns:name email"`
+//
+//		Email string `bun:",index:users_email_idx,unique"`
+//		Name  string
+//	}
+//
+// Fields with the same index name are added to the same index. The unique option
+// makes the index unique instead of adding a UNIQUE constraint.
+type IndexDef struct {
+	// Name is empty if the index name is omitted.
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// CheckDef is a named CHECK table constraint. Models define the constraints
+// by implementing TableChecker:
+//
+//	func (*Account) TableChecks() []schema.CheckDef {
+//		return []schema.CheckDef{
+//			bun.Check("check_positive_balance", "balance >= 0"),
+//		}
+//	}
+type CheckDef struct {
+	Name string
+	Expr string
+}
+
+// TableChecker is implemented by models that have CHECK table constraints.
+type TableChecker interface {
+	TableChecks() []CheckDef
+}
+
+var tableCheckerType = reflect.TypeOf((*TableChecker)(nil)).Elem()
+
 type structField struct {
 	Index []int
 	Table *Table
@@ -98,6 +155,7 @@ func newTable(
 	table.Fields = make([]*Field, 0, typ.NumField())
 	table.FieldMap = make(map[string]*Field, typ.NumField())
 	table.processFields(typ, seen, canAddr)
+	table.orderFieldsByPos()
 
 	hooks := []struct {
 		typ  reflect.Type
@@ -116,6 +174,10 @@ func newTable(
 		}
 	}
 
+	if typ.Implements(tableCheckerType) {
+		table.Checks = table.ZeroIface.(TableChecker).TableChecks()
+	}
+
 	return table
 }
 
@@ -269,6 +331,44 @@ func (t *Table) processFields(
 	}
 }
 
+// orderFieldsByPos moves the fields with the pos tag option to their positions.
+// The other fields fill the remaining positions in the struct order.
+func (t *Table) orderFieldsByPos() {
+	var positioned, rest []*Field
+	for _, f := range t.Fields {
+		if f.Pos > 0 {
+			positioned = append(positioned, f)
+		} else {
+			rest = append(rest, f)
+		}
+	}
+	if len(positioned) == 0 {
+		return
+	}
+	sort.SliceStable(positioned, func(i, j int) bool {
+		return positioned[i].Pos < positioned[j].Pos
+	})
+
+	fields := make([]*Field, 0, len(t.Fields))
+	for len(positioned) > 0 || len(rest) > 0 {
+		if len(positioned) > 0 && (positioned[0].Pos <= len(fields)+1 || len(rest) == 0) {
+			fields = append(fields, positioned[0])
+			positioned = positioned[1:]
+		} else {
+			fields = append(fields, rest[0])
+			rest = rest[1:]
+		}
+	}
+	t.Fields = fields
+
+	t.DataFields = t.DataFields[:0]
+	for _, f := range fields {
+		if !f.IsPK {
+			t.DataFields = append(t.DataFields, f)
+		}
+	}
+}
+
 func (t *Table) setName(name string) {
 	t.Name = name
 	t.SQLName = t.quoteIdent(name)
@@ -290,6 +390,67 @@ func (t *Table) CheckPKs() error {
 	return nil
 }
 
+// Validate checks the table for common tag misconfigurations, for example,
+// autoincrement on a string field or soft_delete on a bool field.
+// It is called automatically when the table is registered and the error is logged as a warning.
+// Relations that join missing columns are rejected earlier, when the relations are created.
+func (t *Table) Validate() error {
+	if len(t.PKs) > 1 {
+		for _, pk := range t.PKs {
+			if pk.AutoIncrement || pk.Identity {
+				return fmt.Errorf(
+					"bun: %s.%s: autoincrement/identity can't be used in a composite primary key (%d pk columns)",
+					t.TypeName, pk.GoName, len(t.PKs))
+			}
+		}
+	}
+
+	for _, field := range t.Fields {
+		if field.AutoIncrement && !isIntegerKind(field.IndirectType.Kind()) {
+			return fmt.Errorf("bun: %s.%s: autoincrement requires an integer type, got %s",
+				t.TypeName, field.GoName, field.IndirectType)
+		}
+	}
+
+	if field := t.SoftDeleteField; field != nil && !isSoftDeleteType(field.IndirectType) {
+		return fmt.Errorf("bun: %s.%s: soft_delete requires time.Time, sql.NullTime, or int64, got %s",
+			t.TypeName, field.GoName, field.StructField.Type)
+	}
+
+	if field := t.WasInsertedField; field != nil {
+		if !field.Tag.HasOption("scanonly") {
+			return fmt.Errorf("bun: %s.%s: was_inserted requires the scanonly option",
+				t.TypeName, field.GoName)
+		}
+		if field.IndirectType.Kind() != reflect.Bool {
+			return fmt.Errorf("bun: %s.%s: was_inserted requires bool, got %s",
+				t.TypeName, field.GoName, field.StructField.Type)
+		}
+	}
+
+	return nil
+}
+
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+func isSoftDeleteType(typ reflect.Type) bool {
+	switch typ {
+	case timeType, nullTimeType, nullIntType:
+		return true
+	}
+	if typ.Kind() == reflect.Int64 {
+		return true
+	}
+	return reflect.PtrTo(typ).Implements(scannerType)
+}
+
 func (t *Table) addField(field *Field) {
 	t.allFields = append(t.allFields, field)
 
@@ -310,10 +471,35 @@ func (t *Table) addField(field *Field) {
 		t.FieldMap[altName] = field
 	}
 
+	if field.IndirectType == encryptedFieldType {
+		t.flags = t.flags.Set(encryptedFieldsFlag)
+	}
+	if field.UUIDGenerate || field.ULIDGenerate {
+		t.flags = t.flags.Set(generatedIDFieldsFlag)
+	}
+	if field.Sensitive {
+		t.flags = t.flags.Set(sensitiveFieldsFlag)
+	}
+	if field.Lazy {
+		t.flags = t.flags.Set(lazyFieldsFlag)
+	}
+
+	if field.Tag.HasOption("was_inserted") {
+		t.WasInsertedField = field
+	}
+
 	if field.Tag.HasOption("scanonly") {
 		return
 	}
 
+	if name, ok := field.Tag.Option("index"); ok {
+		columns := []string{field.Name}
+		if s, ok := field.Tag.Option("columns"); ok {
+			columns = strings.Fields(s)
+		}
+		t.addIndex(name, columns, field.Tag.HasOption("unique"))
+	}
+
 	if _, ok := field.Tag.Options["soft_delete"]; ok {
 		t.SoftDeleteField = field
 		t.UpdateSoftDeleteField = softDeleteFieldUpdater(field)
@@ -408,6 +594,33 @@ func (t *Table) processBaseModelField(f reflect.StructField) {
 		t.Alias = s
 		t.SQLAlias = t.quoteIdent(s)
 	}
+
+	if s, ok := tag.Option("comment"); ok {
+		t.Comment = unquoteComment(s)
+	}
+
+	if name, ok := tag.Option("index"); ok {
+		columns, _ := tag.Option("columns")
+		t.addIndex(name, strings.Fields(columns), tag.HasOption("unique"))
+	}
+}
+
+// addIndex adds the columns to the index with the name or creates the index.
+func (t *Table) addIndex(name string, columns []string, unique bool) {
+	if name != "" {
+		for _, idx := range t.Indexes {
+			if idx.Name == name {
+				idx.Columns = append(idx.Columns, columns...)
+				idx.Unique = idx.Unique || unique
+				return
+			}
+		}
+	}
+	t.Indexes = append(t.Indexes, &IndexDef{
+		Name:    name,
+		Columns: columns,
+		Unique:  unique,
+	})
 }
 
 // nolint
@@ -456,7 +669,7 @@ func (t *Table) newField(sf reflect.StructField, tag tagparser.Tag) *Field {
 		field.Identity = true
 	}
 
-	if v, ok := tag.Options["unique"]; ok {
+	if v, ok := tag.Options["unique"]; ok && !tag.HasOption("index") {
 		var names []string
 		if len(v) == 1 {
 			// Split the value by comma, this will allow multiple names to be specified.
@@ -477,14 +690,72 @@ func (t *Table) newField(sf reflect.StructField, tag tagparser.Tag) *Field {
 	if s, ok := tag.Option("default"); ok {
 		field.SQLDefault = s
 	}
+	if s, ok := tag.Option("generated"); ok {
+		if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+			s = s[1 : len(s)-1]
+		}
+		field.SQLGenerated = s
+	}
+	if s, ok := tag.Option("pos"); ok {
+		pos, err := strconv.Atoi(s)
+		if err != nil || pos < 1 {
+			internal.Warn.Printf("%s.%s: pos must be a positive integer, got %q", t.TypeName, sf.Name, s)
+		} else {
+			field.Pos = pos
+		}
+	}
+	if s, ok := tag.Option("check"); ok {
+		field.SQLCheck = unquoteComment(s)
+	}
+	if tag.HasOption("sensitive") {
+		field.Sensitive = true
+	}
+	if tag.HasOption("lazy") {
+		field.Lazy = true
+	}
+	if s, ok := tag.Option("comment"); ok {
+		field.Comment = unquoteComment(s)
+	}
 	if s, ok := field.Tag.Option("type"); ok {
 		field.UserSQLType = s
 	}
+	if strings.EqualFold(field.UserSQLType, "uuid") && isUUIDDefault(field.SQLDefault) {
+		if isUUIDType(field.IndirectType) {
+			field.UUIDGenerate = true
+			if t.dialect.Name() != dialect.PG {
+				// Other databases don't have gen_random_uuid(), so UUIDs are only generated by bun.
+				field.SQLDefault = ""
+			}
+		} else {
+			internal.Warn.Printf("%s.%s: can't generate UUIDs for %s", t.TypeName, field.GoName, field.IndirectType)
+		}
+	}
+	if strings.EqualFold(field.UserSQLType, "ulid") {
+		field.UserSQLType = ulidSQLType
+		if ulidRegistered.Load() {
+			if isULIDType(field.IndirectType) {
+				field.ULIDGenerate = true
+			} else {
+				internal.Warn.Printf("%s.%s: can't generate ULIDs for %s", t.TypeName, field.GoName, field.IndirectType)
+			}
+		}
+	}
 	field.DiscoveredSQLType = DiscoverSQLType(field.IndirectType)
 	field.Append = FieldAppender(t.dialect, field)
 	field.Scan = FieldScanner(t.dialect, field)
 	field.IsZero = zeroChecker(field.StructField.Type)
 
+	if enum := LookupEnum(field.IndirectType); enum != nil {
+		field.Enum = enum
+		field.DiscoveredSQLType = sqltype.VarChar
+		field.Append = enumAppender(enum)
+		field.Scan = enumScanner(enum)
+		if field.IsPtr {
+			field.Append = PtrAppender(field.Append)
+			field.Scan = PtrScanner(field.Scan)
+		}
+	}
+
 	return field
 }
 
@@ -547,14 +818,7 @@ func (t *Table) belongsToRelation(field *Field) *Relation {
 		if len(onUpdate) > 1 {
 			panic(fmt.Errorf("bun: %s belongs-to %s: on_update option must be a single field", t.TypeName, field.GoName))
 		}
-
-		rule := strings.ToUpper(onUpdate[0])
-		if !isKnownFKRule(rule) {
-			internal.Warn.Printf("bun: %s belongs-to %s: unknown on_update rule %s", t.TypeName, field.GoName, rule)
-		}
-
-		s := fmt.Sprintf("ON UPDATE %s", rule)
-		rel.OnUpdate = s
+		rel.OnUpdate = t.fkRule(field, "on_update", onUpdate[0])
 	}
 
 	rel.OnDelete = "ON DELETE NO ACTION"
@@ -562,13 +826,34 @@ func (t *Table) belongsToRelation(field *Field) *Relation {
 		if len(onDelete) > 1 {
 			panic(fmt.Errorf("bun: %s belongs-to %s: on_delete option must be a single field", t.TypeName, field.GoName))
 		}
-
-		rule := strings.ToUpper(onDelete[0])
-		if !isKnownFKRule(rule) {
-			internal.Warn.Printf("bun: %s belongs-to %s: unknown on_delete rule %s", t.TypeName, field.GoName, rule)
+		rel.OnDelete = t.fkRule(field, "on_delete", onDelete[0])
+	}
+
+	// fk:on_delete:cascade is the same as on_delete:cascade.
+	// fk:name:<name> names the constraint and fk:name generates the name.
+	var fkName string
+	var hasFKName bool
+	for _, opt := range field.Tag.Options["fk"] {
+		action, rule, _ := strings.Cut(opt, ":")
+		switch action {
+		case "on_update":
+			rel.OnUpdate = t.fkRule(field, action, rule)
+		case "on_delete":
+			rel.OnDelete = t.fkRule(field, action, rule)
+		case "name":
+			fkName, hasFKName = rule, true
+		default:
+			panic(fmt.Errorf("bun: %s belongs-to %s: unknown fk option %q", t.TypeName, field.GoName, opt))
 		}
-		s := fmt.Sprintf("ON DELETE %s", rule)
-		rel.OnDelete = s
+	}
+
+	if hasFKName {
+		defer func() {
+			rel.FKName = fkName
+			if rel.FKName == "" {
+				rel.FKName = t.fkName(rel)
+			}
+		}()
 	}
 
 	if join, ok := field.Tag.Options["join"]; ok {
@@ -709,6 +994,14 @@ func (t *Table) hasManyRelation(field *Field) *Relation {
 
 	var polymorphicColumn string
 
+	if poly := t.polymorphicByConvention(field, joinTable); poly != nil {
+		rel.BaseFields = t.PKs
+		rel.JoinFields = []*Field{poly.IDField}
+		rel.PolymorphicField = poly.TypeField
+		rel.PolymorphicValue = poly.Value
+		return rel
+	}
+
 	if join, ok := field.Tag.Options["join"]; ok {
 		baseColumns, joinColumns := parseRelationJoin(join)
 		for i, baseColumn := range baseColumns {
@@ -782,6 +1075,29 @@ func (t *Table) hasManyRelation(field *Field) *Relation {
 	return rel
 }
 
+// polymorphicByConvention detects polymorphic relations declared with a tag like
+// `bun:"rel:has-many,polymorphic_prefix:commentable"` where the join table has
+// commentable_type and commentable_id columns. The discriminator value is the table name.
+func (t *Table) polymorphicByConvention(field *Field, joinTable *Table) *PolymorphicRelation {
+	if field.Tag.HasOption("join") || len(t.PKs) != 1 {
+		return nil
+	}
+	prefix, _ := field.Tag.Option("polymorphic_prefix")
+	if prefix == "" {
+		return nil
+	}
+	typeField := joinTable.FieldMap[prefix+"_type"]
+	idField := joinTable.FieldMap[prefix+"_id"]
+	if typeField == nil || idField == nil {
+		return nil
+	}
+	return &PolymorphicRelation{
+		TypeField: typeField,
+		IDField:   idField,
+		Value:     t.Name,
+	}
+}
+
 func (t *Table) m2mRelation(field *Field) *Relation {
 	if field.IndirectType.Kind() != reflect.Slice {
 		panic(fmt.Errorf(
@@ -859,6 +1175,17 @@ func (t *Table) m2mRelation(field *Field) *Relation {
 	rel.JoinFields = rightRel.JoinFields
 	rel.M2MJoinFields = rightRel.BaseFields
 
+	if pivot, ok := field.Tag.Option("pivot"); ok {
+		sf, ok := t.Type.FieldByName(pivot)
+		if !ok || sf.Type.Kind() != reflect.Slice || indirectType(sf.Type.Elem()) != m2mTable.Type {
+			panic(fmt.Errorf(
+				"bun: %s many-to-many %s: %s must have field %s of type []%s",
+				t.TypeName, field.GoName, t.TypeName, pivot, m2mTable.TypeName,
+			))
+		}
+		rel.M2MPivotIndex = sf.Index
+	}
+
 	return rel
 }
 
@@ -877,6 +1204,36 @@ func (t *Table) HasAfterScanHook() bool { return t.flags.Has(afterScanHookFlag)
 func (t *Table) HasBeforeScanRowHook() bool { return t.flags.Has(beforeScanRowHookFlag) }
 func (t *Table) HasAfterScanRowHook() bool  { return t.flags.Has(afterScanRowHookFlag) }
 
+// HasEncryptedFields reports whether the table has EncryptedField columns.
+func (t *Table) HasEncryptedFields() bool { return t.flags.Has(encryptedFieldsFlag) }
+
+// HasGeneratedIDFields reports whether the table has fields with UUIDGenerate or ULIDGenerate set.
+func (t *Table) HasGeneratedIDFields() bool { return t.flags.Has(generatedIDFieldsFlag) }
+
+// HasSensitiveFields reports whether the table has fields with the sensitive tag option.
+func (t *Table) HasSensitiveFields() bool { return t.flags.Has(sensitiveFieldsFlag) }
+
+// HasLazyFields reports whether the table has fields with the lazy tag option.
+func (t *Table) HasLazyFields() bool { return t.flags.Has(lazyFieldsFlag) }
+
+// GenerateIDs sets random UUIDs on the zero UUIDGenerate fields of the struct
+// and new ULIDs on the zero ULIDGenerate fields.
+func (t *Table) GenerateIDs(strct reflect.Value) error {
+	for _, field := range t.Fields {
+		switch {
+		case field.UUIDGenerate:
+			if err := field.GenerateUUID(strct); err != nil {
+				return err
+			}
+		case field.ULIDGenerate:
+			if err := field.GenerateULID(strct); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 //------------------------------------------------------------------------------
 
 func (t *Table) AppendNamedArg(
@@ -902,9 +1259,17 @@ func (t *Table) quoteIdent(s string) Safe {
 	return Safe(NewFormatter(t.dialect).AppendIdent(nil, s))
 }
 
+// unquoteComment removes single quotes around a comment and unescapes doubled quotes.
+func unquoteComment(s string) string {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'")
+	}
+	return s
+}
+
 func isKnownTableOption(name string) bool {
 	switch name {
-	case "table", "alias", "select":
+	case "table", "alias", "select", "comment", "index", "columns", "unique":
 		return true
 	}
 	return false
@@ -924,10 +1289,17 @@ func isKnownFieldOption(name string) bool {
 		"notnull",
 		"nullzero",
 		"default",
+		"generated",
+		"check",
+		"pos",
+		"comment",
 		"unique",
 		"soft_delete",
 		"scanonly",
 		"skipupdate",
+		"sensitive",
+		"lazy",
+		"was_inserted",
 
 		"pk",
 		"autoincrement",
@@ -936,14 +1308,41 @@ func isKnownFieldOption(name string) bool {
 		"join_on",
 		"on_update",
 		"on_delete",
+		"fk",
 		"m2m",
+		"pivot",
 		"polymorphic",
-		"identity":
+		"polymorphic_prefix",
+		"identity",
+		"index",
+		"columns":
 		return true
 	}
 	return false
 }
 
+// fkRule returns the "ON UPDATE rule" or "ON DELETE rule" clause for the on_update
+// or on_delete option.
+// fkName generates the FOREIGN KEY constraint name, for example, fk_books_author_id.
+func (t *Table) fkName(rel *Relation) string {
+	b := make([]byte, 0, 32)
+	b = append(b, "fk_"...)
+	b = append(b, strings.ReplaceAll(t.Name, ".", "_")...)
+	for _, f := range rel.BaseFields {
+		b = append(b, '_')
+		b = append(b, f.Name...)
+	}
+	return internal.TruncateIdent(string(b))
+}
+
+func (t *Table) fkRule(field *Field, option, rule string) string {
+	rule = strings.ToUpper(rule)
+	if !isKnownFKRule(rule) {
+		internal.Warn.Printf("bun: %s belongs-to %s: unknown %s rule %s", t.TypeName, field.GoName, option, rule)
+	}
+	return strings.ToUpper(strings.ReplaceAll(option, "_", " ")) + " " + rule
+}
+
 func isKnownFKRule(name string) bool {
 	switch name {
 	case "CASCADE",
@@ -3,6 +3,7 @@ package schema
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -26,6 +27,93 @@ func TestTable(t *testing.T) {
 		require.Len(t, table.DataFields, 2)
 	})
 
+	t.Run("uuid", func(t *testing.T) {
+		type Model struct {
+			ID   [16]byte `bun:",pk,type:uuid,default:gen_random_uuid()"`
+			Ref  *string  `bun:"type:uuid,default:gen_random_uuid()"`
+			Name string   `bun:"type:uuid"`
+		}
+
+		table := tables.Get(reflect.TypeOf((*Model)(nil)))
+		require.True(t, table.HasGeneratedIDFields())
+		require.True(t, table.FieldMap["id"].UUIDGenerate)
+		require.True(t, table.FieldMap["ref"].UUIDGenerate)
+		require.False(t, table.FieldMap["name"].UUIDGenerate)
+
+		model := new(Model)
+		strct := reflect.ValueOf(model).Elem()
+		require.NoError(t, table.GenerateIDs(strct))
+		require.NotEqual(t, [16]byte{}, model.ID)
+		require.Equal(t, byte(0x40), model.ID[6]&0xf0)
+		require.NotNil(t, model.Ref)
+		require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, *model.Ref)
+
+		// Non-zero values are kept.
+		id, ref := model.ID, *model.Ref
+		require.NoError(t, table.GenerateIDs(strct))
+		require.Equal(t, id, model.ID)
+		require.Equal(t, ref, *model.Ref)
+	})
+
+	t.Run("pos", func(t *testing.T) {
+		type Model struct {
+			ID        int64 `bun:",pk"`
+			Name      string
+			Email     string `bun:",pos:4"`
+			CreatedAt time.Time
+			Status    string `bun:",pos:2"`
+		}
+
+		table := tables.Get(reflect.TypeOf((*Model)(nil)))
+
+		var names []string
+		for _, f := range table.Fields {
+			names = append(names, f.Name)
+		}
+		require.Equal(t, []string{"id", "status", "name", "email", "created_at"}, names)
+		require.Len(t, table.DataFields, 4)
+		require.Equal(t, "status", table.DataFields[0].Name)
+	})
+
+	t.Run("ulid", func(t *testing.T) {
+		RegisterULID()
+
+		type Model struct {
+			ID  ULID   `bun:",pk,type:ulid"`
+			Ref string `bun:"type:ulid"`
+		}
+
+		table := tables.Get(reflect.TypeOf((*Model)(nil)))
+		require.True(t, table.HasGeneratedIDFields())
+		require.True(t, table.FieldMap["id"].ULIDGenerate)
+		require.True(t, table.FieldMap["ref"].ULIDGenerate)
+		require.Equal(t, "char(26)", table.FieldMap["id"].CreateTableSQLType)
+
+		model := new(Model)
+		require.NoError(t, table.GenerateIDs(reflect.ValueOf(model).Elem()))
+		require.False(t, model.ID.IsZero())
+		require.Regexp(t, `^[0-9A-HJKMNP-TV-Z]{26}$`, model.Ref)
+		require.Less(t, model.ID.String(), model.Ref)
+
+		parsed, err := ParseULID(model.ID.String())
+		require.NoError(t, err)
+		require.Equal(t, model.ID, parsed)
+		require.WithinDuration(t, time.Now(), model.ID.Time(), time.Second)
+
+		prev := NewULID()
+		for i := 0; i < 1000; i++ {
+			curr := NewULID()
+			require.Less(t, prev.String(), curr.String())
+			prev = curr
+		}
+
+		_, err = ParseULID("8ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+		require.Error(t, err)
+		max, err := ParseULID("7ZZZZZZZZZZZZZZZZZZZZZZZZZ")
+		require.NoError(t, err)
+		require.Equal(t, ULID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, max)
+	})
+
 	type Model struct {
 		Foo string
 		Bar string
@@ -227,6 +315,59 @@ func TestTable(t *testing.T) {
 		}
 	})
 
+	t.Run("polymorphic relation", func(t *testing.T) {
+		type Comment struct {
+			ID              int64 `bun:",pk"`
+			CommentableType string
+			CommentableID   int64
+		}
+		type Post struct {
+			ID       int64      `bun:",pk"`
+			Comments []*Comment `bun:"rel:has-many,polymorphic_prefix:commentable"`
+		}
+
+		table := tables.Get(reflect.TypeOf((*Post)(nil)))
+
+		rel, ok := table.Relations["Comments"]
+		require.True(t, ok)
+		require.Equal(t, HasManyRelation, rel.Type)
+
+		poly := rel.Polymorphic()
+		require.NotNil(t, poly)
+		require.Equal(t, "commentable_type", poly.TypeField.Name)
+		require.Equal(t, "commentable_id", poly.IDField.Name)
+		require.Equal(t, "posts", poly.Value)
+		require.Equal(t, []*Field{poly.IDField}, rel.JoinFields)
+	})
+
+	t.Run("polymorphic relation with value", func(t *testing.T) {
+		type Comment struct {
+			ID              int64 `bun:",pk"`
+			ImageType       string
+			ImageID         int64
+			CommentableType string
+			CommentableID   int64
+		}
+		type Image struct {
+			ID       int64      `bun:",pk"`
+			Comments []*Comment `bun:"rel:has-many,polymorphic:commentable"`
+			Notes    []*Comment `bun:"rel:has-many,polymorphic"`
+		}
+
+		table := tables.Get(reflect.TypeOf((*Image)(nil)))
+
+		rel, ok := table.Relations["Comments"]
+		require.True(t, ok)
+		require.Equal(t, "image_type", rel.PolymorphicField.Name)
+		require.Equal(t, "commentable", rel.PolymorphicValue)
+		require.Equal(t, "image_id", rel.JoinFields[0].Name)
+
+		rel, ok = table.Relations["Notes"]
+		require.True(t, ok)
+		require.Equal(t, "image_type", rel.PolymorphicField.Name)
+		require.Equal(t, "image", rel.PolymorphicValue)
+	})
+
 	t.Run("alternative name", func(t *testing.T) {
 		type ModelTest struct {
 			Model
@@ -245,4 +386,111 @@ func TestTable(t *testing.T) {
 
 		require.Equal(t, table.FieldMap["foo"].SQLName, table.FieldMap["alt_name"].SQLName)
 	})
+
+	t.Run("comment", func(t *testing.T) {
+		type Account struct {
+			BaseModel `bun:"table:accounts,comment:'User account record'"`
+
+			ID    int64  `bun:",pk"`
+			Email string `bun:",notnull,comment:'Login, lowercase'"`
+			Note  string `bun:"comment:It''s raw"`
+		}
+
+		table := tables.Get(reflect.TypeOf((*Account)(nil)))
+		require.Equal(t, "User account record", table.Comment)
+		require.Equal(t, "Login, lowercase", table.FieldMap["email"].Comment)
+		require.True(t, table.FieldMap["email"].NotNull)
+		require.Equal(t, "It''s raw", table.FieldMap["note"].Comment)
+		require.Equal(t, "", table.FieldMap["id"].Comment)
+	})
+
+	t.Run("index", func(t *testing.T) {
+		type Account struct {
+			BaseModel `bun:"table:accounts,index:idx_name_email,columns:name email"`
+
+			ID        int64  `bun:",pk"`
+			Email     string `bun:",index:idx_email,unique"`
+			Name      string
+			FirstName string `bun:",index:idx_full_name"`
+			LastName  string `bun:",index:idx_full_name"`
+			Age       int    `bun:",index"`
+		}
+
+		table := tables.Get(reflect.TypeOf((*Account)(nil)))
+		require.Equal(t, []*IndexDef{
+			{Name: "idx_name_email", Columns: []string{"name", "email"}},
+			{Name: "idx_email", Columns: []string{"email"}, Unique: true},
+			{Name: "idx_full_name", Columns: []string{"first_name", "last_name"}},
+			{Columns: []string{"age"}},
+		}, table.Indexes)
+		require.Empty(t, table.Unique)
+	})
+}
+
+func TestTableValidate(t *testing.T) {
+	dialect := newNopDialect()
+	tables := NewTables(dialect)
+
+	type Test struct {
+		model interface{}
+		err   string
+	}
+
+	type Valid struct {
+		ID        int64     `bun:",pk,autoincrement"`
+		DeletedAt time.Time `bun:",soft_delete"`
+	}
+	type CompositeAutoIncrement struct {
+		ID     int64 `bun:",pk,autoincrement"`
+		TeamID int64 `bun:",pk"`
+	}
+	type StringAutoIncrement struct {
+		ID string `bun:",pk,autoincrement"`
+	}
+	type BoolSoftDelete struct {
+		ID      int64 `bun:",pk"`
+		Deleted bool  `bun:",soft_delete"`
+	}
+	type ColumnWasInserted struct {
+		ID          int64 `bun:",pk"`
+		WasInserted bool  `bun:",was_inserted"`
+	}
+	type StringWasInserted struct {
+		ID          int64  `bun:",pk"`
+		WasInserted string `bun:",was_inserted,scanonly"`
+	}
+
+	tests := []Test{
+		{model: (*Valid)(nil)},
+		{
+			model: (*CompositeAutoIncrement)(nil),
+			err:   "bun: CompositeAutoIncrement.ID: autoincrement/identity can't be used in a composite primary key (2 pk columns)",
+		},
+		{
+			model: (*StringAutoIncrement)(nil),
+			err:   "bun: StringAutoIncrement.ID: autoincrement requires an integer type, got string",
+		},
+		{
+			model: (*BoolSoftDelete)(nil),
+			err:   "bun: BoolSoftDelete.Deleted: soft_delete requires time.Time, sql.NullTime, or int64, got bool",
+		},
+		{
+			model: (*ColumnWasInserted)(nil),
+			err:   "bun: ColumnWasInserted.WasInserted: was_inserted requires the scanonly option",
+		},
+		{
+			model: (*StringWasInserted)(nil),
+			err:   "bun: StringWasInserted.WasInserted: was_inserted requires bool, got string",
+		},
+	}
+
+	for _, test := range tests {
+		table := tables.Get(reflect.TypeOf(test.model))
+		err := table.Validate()
+		if test.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.EqualError(t, err, test.err)
+		}
+	}
 }
@@ -3,12 +3,16 @@ package schema
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+
+	"github.com/uptrace/bun/internal"
 )
 
 type Tables struct {
 	dialect Dialect
 	tables  sync.Map
+	types   *TypeRegistry
 
 	mu         sync.RWMutex
 	seen       map[reflect.Type]*Table
@@ -18,11 +22,18 @@ type Tables struct {
 func NewTables(dialect Dialect) *Tables {
 	return &Tables{
 		dialect:    dialect,
+		types:      NewTypeRegistry(globalTypes),
 		seen:       make(map[reflect.Type]*Table),
 		inProgress: make(map[reflect.Type]*tableInProgress),
 	}
 }
 
+// Types returns the registry of the types used by the dialect.
+// It falls back to the types registered with RegisterType.
+func (t *Tables) Types() *TypeRegistry {
+	return t.types
+}
+
 func (t *Tables) Register(models ...interface{}) {
 	for _, model := range models {
 		_ = t.Get(reflect.TypeOf(model).Elem())
@@ -75,6 +86,10 @@ func (t *Tables) table(typ reflect.Type, allowInProgress bool) *Table {
 		return table
 	}
 
+	if err := table.Validate(); err != nil {
+		internal.Warn.Printf("%s", strings.TrimPrefix(err.Error(), "bun: "))
+	}
+
 	t.mu.Lock()
 	delete(t.inProgress, typ)
 	t.tables.Store(typ, table)
@@ -0,0 +1,101 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// TypeRegistry maps Go types to the functions that append and scan the values of the types.
+// Registered functions take precedence over sql.Scanner and driver.Valuer implementations.
+type TypeRegistry struct {
+	parent *TypeRegistry
+	types  sync.Map // reflect.Type => *registeredType
+}
+
+type registeredType struct {
+	append AppenderFunc
+	scan   ScannerFunc
+}
+
+var globalTypes = NewTypeRegistry(nil)
+
+// NewTypeRegistry returns a registry that falls back to the parent registry
+// for the types that are not registered in it.
+func NewTypeRegistry(parent *TypeRegistry) *TypeRegistry {
+	return &TypeRegistry{parent: parent}
+}
+
+// Register registers the functions that append and scan the values of the type.
+// The type must be registered before the models that use it.
+func (r *TypeRegistry) Register(typ reflect.Type, append AppenderFunc, scan ScannerFunc) {
+	if append == nil || scan == nil {
+		panic(fmt.Errorf("bun: RegisterType(%s) requires append and scan functions", typ))
+	}
+	r.types.Store(typ, &registeredType{
+		append: append,
+		scan:   scan,
+	})
+}
+
+func (r *TypeRegistry) get(typ reflect.Type) *registeredType {
+	for ; r != nil; r = r.parent {
+		if v, ok := r.types.Load(typ); ok {
+			return v.(*registeredType)
+		}
+	}
+	return nil
+}
+
+// Appender returns the registered appender of the type or of the type the pointer points to.
+// It returns nil if the type is not registered.
+func (r *TypeRegistry) Appender(typ reflect.Type) AppenderFunc {
+	if t := r.get(typ); t != nil {
+		return t.append
+	}
+	if typ.Kind() == reflect.Ptr {
+		if t := r.get(typ.Elem()); t != nil {
+			return PtrAppender(t.append)
+		}
+	}
+	return nil
+}
+
+// Scanner is like Appender, but returns the registered scanner.
+func (r *TypeRegistry) Scanner(typ reflect.Type) ScannerFunc {
+	if t := r.get(typ); t != nil {
+		return t.scan
+	}
+	if typ.Kind() == reflect.Ptr {
+		if t := r.get(typ.Elem()); t != nil {
+			return PtrScanner(t.scan)
+		}
+	}
+	return nil
+}
+
+// RegisterType registers the functions that append and scan the values of the type T
+// for all dialects, so T can be used in models without wrapper types, for example:
+//
+//	schema.RegisterType[netip.Addr](
+//		func(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
+//			return fmter.Dialect().AppendString(b, v.Interface().(netip.Addr).String())
+//		},
+//		func(dest reflect.Value, src interface{}) error {
+//			...
+//		},
+//	)
+//
+// Use Tables.Types to register the type for a single dialect.
+func RegisterType[T any](append AppenderFunc, scan ScannerFunc) {
+	globalTypes.Register(reflect.TypeOf((*T)(nil)).Elem(), append, scan)
+}
+
+func dialectTypes(dialect Dialect) *TypeRegistry {
+	if dialect != nil {
+		if tables := dialect.Tables(); tables != nil {
+			return tables.types
+		}
+	}
+	return globalTypes
+}
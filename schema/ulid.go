@@ -0,0 +1,258 @@
+package schema
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ULID is a Universally Unique Lexicographically Sortable Identifier: a 48-bit timestamp
+// in milliseconds followed by 80 random bits. ULIDs are stored as 26 characters
+// in the Crockford's base32 encoding, so sorting the strings sorts ULIDs by time.
+type ULID [16]byte
+
+var (
+	ulidType = reflect.TypeOf((*ULID)(nil)).Elem()
+
+	_ driver.Valuer = ULID{}
+)
+
+const ulidSQLType = "char(26)"
+
+const ulidEncoding = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var ulidDecoding [256]byte
+
+func init() {
+	for i := range ulidDecoding {
+		ulidDecoding[i] = 0xff
+	}
+	for i := 0; i < len(ulidEncoding); i++ {
+		c := ulidEncoding[i]
+		ulidDecoding[c] = byte(i)
+		if c >= 'A' {
+			ulidDecoding[c+'a'-'A'] = byte(i)
+		}
+	}
+}
+
+var ulidEntropy struct {
+	sync.Mutex
+	last ULID
+}
+
+// NewULID returns a new ULID. ULIDs generated in the same millisecond by the process
+// increment the random part of the previous ULID, so they are strictly increasing.
+func NewULID() ULID {
+	ulidEntropy.Lock()
+	defer ulidEntropy.Unlock()
+
+	ms := uint64(time.Now().UnixMilli())
+	last := &ulidEntropy.last
+
+	var u ULID
+	if ms <= last.ms() && incrementULIDEntropy(last) {
+		u = *last
+	} else {
+		if ms <= last.ms() {
+			// The entropy overflowed, so borrow the next millisecond.
+			ms = last.ms() + 1
+		}
+		u.setMS(ms)
+		if _, err := rand.Read(u[6:]); err != nil {
+			panic(fmt.Errorf("bun: can't generate ULID: %w", err))
+		}
+	}
+
+	*last = u
+	return u
+}
+
+func (u ULID) ms() uint64 {
+	return uint64(u[0])<<40 | uint64(u[1])<<32 | uint64(binary.BigEndian.Uint32(u[2:6]))
+}
+
+func (u *ULID) setMS(ms uint64) {
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	binary.BigEndian.PutUint32(u[2:6], uint32(ms))
+}
+
+// incrementULIDEntropy increments the random part and reports whether it did not overflow.
+func incrementULIDEntropy(u *ULID) bool {
+	for i := len(u) - 1; i >= 6; i-- {
+		u[i]++
+		if u[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseULID parses the ULID in the canonical 26-character representation.
+func ParseULID(s string) (ULID, error) {
+	var u ULID
+	if len(s) != 26 {
+		return u, fmt.Errorf("bun: invalid ULID length: %q", s)
+	}
+	if ulidDecoding[s[0]] > 7 {
+		return u, fmt.Errorf("bun: ULID overflows 128 bits: %q", s)
+	}
+
+	// Decode 5 bits per character, most significant bits first.
+	var acc uint64
+	var bits uint
+	n := len(u)
+	for i := len(s) - 1; i >= 0; i-- {
+		v := ulidDecoding[s[i]]
+		if v == 0xff {
+			return ULID{}, fmt.Errorf("bun: invalid ULID character %q in %q", s[i], s)
+		}
+		acc |= uint64(v) << bits
+		bits += 5
+		for bits >= 8 && n > 0 {
+			n--
+			u[n] = byte(acc)
+			acc >>= 8
+			bits -= 8
+		}
+	}
+	if n > 0 {
+		u[n-1] = byte(acc)
+	}
+	return u, nil
+}
+
+// String returns the canonical 26-character representation of the ULID.
+func (u ULID) String() string {
+	var b [26]byte
+	var acc uint64
+	var bits uint
+	n := len(b)
+	for i := len(u) - 1; i >= 0; i-- {
+		acc |= uint64(u[i]) << bits
+		bits += 8
+		for bits >= 5 {
+			n--
+			b[n] = ulidEncoding[acc&0x1f]
+			acc >>= 5
+			bits -= 5
+		}
+	}
+	// The remaining 3 bits are the most significant bits of the first character.
+	b[0] = ulidEncoding[acc&0x1f]
+	return string(b[:])
+}
+
+// Time returns the time encoded in the ULID.
+func (u ULID) Time() time.Time {
+	return time.UnixMilli(int64(u.ms()))
+}
+
+func (u ULID) IsZero() bool {
+	return u == ULID{}
+}
+
+func (u ULID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// Scan scans ULIDs stored as strings or as 16 bytes.
+func (u *ULID) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*u = ULID{}
+		return nil
+	case string:
+		return u.parse(src)
+	case []byte:
+		if len(src) == len(u) {
+			copy(u[:], src)
+			return nil
+		}
+		return u.parse(string(src))
+	default:
+		return fmt.Errorf("bun: can't scan %T into ULID", src)
+	}
+}
+
+func (u *ULID) parse(s string) error {
+	parsed, err := ParseULID(s)
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+func (u ULID) MarshalJSON() ([]byte, error) {
+	b := make([]byte, 0, 28)
+	b = append(b, '"')
+	b = append(b, u.String()...)
+	b = append(b, '"')
+	return b, nil
+}
+
+func (u *ULID) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*u = ULID{}
+		return nil
+	}
+	if len(b) < 2 || b[0] != '"' || b[len(b)-1] != '"' {
+		return errors.New("bun: ULID must be a JSON string")
+	}
+	return u.parse(string(b[1 : len(b)-1]))
+}
+
+//------------------------------------------------------------------------------
+
+var ulidRegistered atomic.Bool
+
+// RegisterULID makes bun generate ULIDs for the zero values of the fields
+// tagged with type:ulid before insert, for example:
+//
+//	schema.RegisterULID()
+//
+//	type Order struct {
+//		ID bun.ULID `bun:",pk,type:ulid"`
+//	}
+//
+// The fields can be ULIDs or strings and are stored as CHAR(26).
+// RegisterULID must be called before the models are used.
+func RegisterULID() {
+	ulidRegistered.Store(true)
+}
+
+func isULIDType(typ reflect.Type) bool {
+	return typ == ulidType || typ.Kind() == reflect.String
+}
+
+// GenerateULID sets the field to a new ULID if the field value is zero.
+func (f *Field) GenerateULID(strct reflect.Value) error {
+	fv := f.Value(strct)
+	if f.IsPtr {
+		if !fv.IsNil() && !fv.Elem().IsZero() {
+			return nil
+		}
+		if fv.IsNil() {
+			fv.Set(reflect.New(f.IndirectType))
+		}
+		fv = fv.Elem()
+	} else if !fv.IsZero() {
+		return nil
+	}
+
+	u := NewULID()
+	if fv.Kind() == reflect.String {
+		fv.SetString(u.String())
+	} else {
+		fv.Set(reflect.ValueOf(u))
+	}
+	return nil
+}
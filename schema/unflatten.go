@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnflattenRow converts the row scanned into a map with the column names that join
+// relations use, for example, user__id, into nested maps:
+//
+//	row := map[string]interface{}{"id": 1, "user__id": 2, "user__name": "alice"}
+//	m, err := schema.UnflattenRow(row, "__")
+//	// map[id:1 user:map[id:2 name:alice]]
+//
+// It returns an error when a column is both a value and a prefix of other columns,
+// for example, user and user__id.
+func UnflattenRow(row map[string]interface{}, sep string) (map[string]interface{}, error) {
+	if sep == "" {
+		return nil, errors.New("bun: UnflattenRow requires a non-empty separator")
+	}
+
+	keys := make([]string, 0, len(row))
+	for key := range row {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	m := make(map[string]interface{}, len(row))
+	for _, key := range keys {
+		path := strings.Split(key, sep)
+
+		node := m
+		for i, name := range path[:len(path)-1] {
+			switch child := node[name].(type) {
+			case nil:
+				nested := make(map[string]interface{})
+				node[name] = nested
+				node = nested
+			case map[string]interface{}:
+				node = child
+			default:
+				return nil, fmt.Errorf("bun: column %q conflicts with column %q",
+					key, strings.Join(path[:i+1], sep))
+			}
+		}
+
+		name := path[len(path)-1]
+		if _, ok := node[name]; ok {
+			return nil, fmt.Errorf("bun: column %q conflicts with the columns prefixed with it", key)
+		}
+		node[name] = row[key]
+	}
+	return m, nil
+}
+
+// FlattenRow is the inverse of UnflattenRow. It joins the keys of the nested maps
+// with the separator.
+func FlattenRow(row map[string]interface{}, sep string) map[string]interface{} {
+	m := make(map[string]interface{}, len(row))
+	flattenRow(m, row, "", sep)
+	return m
+}
+
+func flattenRow(dest, row map[string]interface{}, prefix, sep string) {
+	for key, value := range row {
+		if nested, ok := value.(map[string]interface{}); ok {
+			flattenRow(dest, nested, prefix+key+sep, sep)
+			continue
+		}
+		dest[prefix+key] = value
+	}
+}
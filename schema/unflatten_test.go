@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnflattenRow(t *testing.T) {
+	row := map[string]interface{}{
+		"id":                 int64(1),
+		"user__id":           int64(2),
+		"user__name":         "alice",
+		"user__profile__bio": "hello",
+		"order__id":          int64(3),
+	}
+
+	m, err := UnflattenRow(row, "__")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"id": int64(1),
+		"user": map[string]interface{}{
+			"id":   int64(2),
+			"name": "alice",
+			"profile": map[string]interface{}{
+				"bio": "hello",
+			},
+		},
+		"order": map[string]interface{}{
+			"id": int64(3),
+		},
+	}, m)
+	require.Equal(t, row, FlattenRow(m, "__"))
+
+	t.Run("collision", func(t *testing.T) {
+		_, err := UnflattenRow(map[string]interface{}{
+			"user":     int64(1),
+			"user__id": int64(2),
+		}, "__")
+		require.EqualError(t, err, `bun: column "user__id" conflicts with column "user"`)
+	})
+
+	t.Run("custom separator", func(t *testing.T) {
+		m, err := UnflattenRow(map[string]interface{}{
+			"user.id":   int64(1),
+			"user.name": "alice",
+		}, ".")
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"user": map[string]interface{}{"id": int64(1), "name": "alice"},
+		}, m)
+
+		_, err = UnflattenRow(m, "")
+		require.Error(t, err)
+	})
+}
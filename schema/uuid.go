@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// isUUIDDefault reports whether the default generates random UUIDs.
+func isUUIDDefault(s string) bool {
+	switch strings.ToLower(s) {
+	case "gen_random_uuid()", "uuid_generate_v4()":
+		return true
+	}
+	return false
+}
+
+// isUUIDType reports whether GenerateUUID supports the type, that is,
+// strings and 16-byte arrays like uuid.UUID.
+func isUUIDType(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.String:
+		return true
+	case reflect.Array:
+		return typ.Len() == 16 && typ.Elem().Kind() == reflect.Uint8
+	}
+	return false
+}
+
+// GenerateUUID sets the field to a random (version 4) UUID if the field value is zero.
+func (f *Field) GenerateUUID(strct reflect.Value) error {
+	fv := f.Value(strct)
+	if f.IsPtr {
+		if !fv.IsNil() && !fv.Elem().IsZero() {
+			return nil
+		}
+		if fv.IsNil() {
+			fv.Set(reflect.New(f.IndirectType))
+		}
+		fv = fv.Elem()
+	} else if !fv.IsZero() {
+		return nil
+	}
+
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return fmt.Errorf("bun: can't generate UUID for %s: %w", f.GoName, err)
+	}
+	u[6] = (u[6] & 0x0f) | 0x40 // version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(formatUUID(u))
+	case reflect.Array:
+		reflect.Copy(fv, reflect.ValueOf(u))
+	}
+	return nil
+}
+
+func formatUUID(u [16]byte) string {
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
@@ -0,0 +1,55 @@
+package bun
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+type scopeKey struct {
+	typ  reflect.Type
+	name string
+}
+
+var scopes sync.Map // map[scopeKey]func(*SelectQuery) *SelectQuery
+
+// RegisterScope registers a reusable query snippet for the model, so it can be
+// applied by name with SelectQuery.Scope:
+//
+//	bun.RegisterScope((*User)(nil), "active", func(q *bun.SelectQuery) *bun.SelectQuery {
+//		return q.Where("?TableAlias.active = TRUE")
+//	})
+//
+//	err := db.NewSelect().Model(&users).Scope("active").Scan(ctx)
+//
+// Registering a scope with the same name replaces the previous one.
+func RegisterScope(model interface{}, name string, fn func(*SelectQuery) *SelectQuery) {
+	typ := reflect.TypeOf(model)
+	for typ != nil && (typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice) {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		panic(fmt.Errorf("bun: RegisterScope(unsupported %T)", model))
+	}
+	scopes.Store(scopeKey{typ: typ, name: name}, fn)
+}
+
+// Scope applies the scopes registered for the query model with RegisterScope.
+// The WHERE conditions of each scope are grouped in parentheses, so a scope
+// using WhereOr does not change the conditions of other scopes.
+func (q *SelectQuery) Scope(names ...string) *SelectQuery {
+	if q.table == nil {
+		q.setErr(errNilModel)
+		return q
+	}
+
+	for _, name := range names {
+		fn, ok := scopes.Load(scopeKey{typ: q.table.Type, name: name})
+		if !ok {
+			q.setErr(fmt.Errorf("bun: %s does not have scope %q", q.table.TypeName, name))
+			return q
+		}
+		q = q.WhereGroup(" AND ", fn.(func(*SelectQuery) *SelectQuery))
+	}
+	return q
+}
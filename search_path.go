@@ -0,0 +1,246 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+
+	"github.com/uptrace/bun/dialect"
+)
+
+type searchPathKey struct{}
+
+// WithSearchPath returns a context that makes PostgreSQL resolve unqualified table names
+// using the schemas, for example, to route the queries of a tenant to the tenant schema:
+//
+//	ctx = bun.WithSearchPath(ctx, "tenant_1", "public")
+//	err := db.NewSelect().Model(&users).Relation("Profile").Scan(ctx)
+//
+// If the pool is opened with NewConnector, bun tracks the search path of each connection
+// and only executes SET search_path when the connection has a different search path,
+// including inside transactions, which keep the search path they were started with.
+//
+// Otherwise, inside transactions, bun executes SET LOCAL search_path before the first query
+// that uses the search path, so the search path is reset when the transaction ends. Other queries
+// are executed on a connection from the pool that has the search path set with SET search_path,
+// and the search path is reset before the connection is returned to the pool.
+// Queries that return *sql.Rows, for example, SelectQuery.Rows and DB.QueryContext,
+// can't reset the search path while the rows are open, so the connection is closed
+// after the rows are closed. On connections returned by DB.Conn, bun only executes SET
+// when the search path changes.
+//
+// Other dialects ignore the search path.
+func WithSearchPath(ctx context.Context, schemas ...string) context.Context {
+	return context.WithValue(ctx, searchPathKey{}, schemas)
+}
+
+// SearchPathFromContext returns the schemas set with WithSearchPath.
+func SearchPathFromContext(ctx context.Context) []string {
+	schemas, _ := ctx.Value(searchPathKey{}).([]string)
+	return schemas
+}
+
+// sessions tracks the search path set on transactions and the session settings changed
+// on the connections that are checked out from pools not opened with NewConnector.
+// It is shared by the DB clones that use the same connection pool.
+type sessions struct {
+	mu    sync.Mutex
+	conns map[*sql.Conn]string
+	dirty map[*sql.Conn]struct{}
+	txs   map[*sql.Tx]string
+}
+
+func (s *sessions) getConn(conn *sql.Conn) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conns[conn]
+}
+
+func (s *sessions) setConn(conn *sql.Conn, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if path == "" {
+		delete(s.conns, conn)
+		return
+	}
+	if s.conns == nil {
+		s.conns = make(map[*sql.Conn]string)
+	}
+	s.conns[conn] = path
+}
+
+func (s *sessions) setDirty(conn *sql.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dirty == nil {
+		s.dirty = make(map[*sql.Conn]struct{})
+	}
+	s.dirty[conn] = struct{}{}
+}
+
+// forgetConn returns the search path and reports whether the session settings
+// of the connection were changed, and forgets the connection.
+func (s *sessions) forgetConn(conn *sql.Conn) (path string, dirty bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path = s.conns[conn]
+	_, dirty = s.dirty[conn]
+	delete(s.conns, conn)
+	delete(s.dirty, conn)
+	return path, dirty
+}
+
+func (s *sessions) getTx(tx *sql.Tx) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.txs[tx]
+}
+
+func (s *sessions) setTx(tx *sql.Tx, path string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if path == "" {
+		delete(s.txs, tx)
+		return
+	}
+	if s.txs == nil {
+		s.txs = make(map[*sql.Tx]string)
+	}
+	s.txs[tx] = path
+}
+
+//------------------------------------------------------------------------------
+
+func (db *DB) searchPath(ctx context.Context) string {
+	schemas := SearchPathFromContext(ctx)
+	if len(schemas) == 0 {
+		return ""
+	}
+
+	var b []byte
+	for i, schema := range schemas {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = db.fmter.AppendIdent(b, schema)
+	}
+	return string(b)
+}
+
+// withSearchPath calls fn with a connection that uses the search path from the context.
+// Pools opened with NewConnector set the search path of the connections themselves.
+// Otherwise, queries outside transactions run on a connection checked out from the pool,
+// see withPinnedConn.
+func (db *DB) withSearchPath(
+	ctx context.Context, conn IConn, returnsRows bool, fn func(context.Context, IConn) error,
+) error {
+	if db.dialect.Name() != dialect.PG {
+		return fn(ctx, conn)
+	}
+
+	path := db.searchPath(ctx)
+	switch c := conn.(type) {
+	case *sql.Tx:
+		if err := db.setTxSearchPath(ctx, c, path); err != nil {
+			return err
+		}
+		return fn(ctx, conn)
+	case *sql.DB:
+		if path == "" || db.tracked {
+			return fn(ctx, conn)
+		}
+		return db.withPinnedConn(ctx, returnsRows, func(conn *sql.Conn) error {
+			if err := db.setConnSearchPath(ctx, conn, path); err != nil {
+				return err
+			}
+			return fn(ctx, conn)
+		})
+	case *sql.Conn:
+		if db.tracked {
+			return fn(ctx, conn)
+		}
+		if err := db.setConnSearchPath(ctx, c, path); err != nil {
+			return err
+		}
+		return fn(ctx, conn)
+	default:
+		return fn(ctx, conn)
+	}
+}
+
+// setTxSearchPath sets the search path of the transaction with SET LOCAL
+// unless it is already set. The search path is reset when the transaction ends.
+func (db *DB) setTxSearchPath(ctx context.Context, tx *sql.Tx, path string) error {
+	if db.sessions.getTx(tx) == path {
+		return nil
+	}
+
+	query := "SET LOCAL search_path TO DEFAULT"
+	if path != "" {
+		query = "SET LOCAL search_path TO " + path
+	}
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	db.sessions.setTx(tx, path)
+	return nil
+}
+
+// setConnSearchPath sets the search path of the connection unless it is already set.
+// An empty path resets the search path to the server default.
+func (db *DB) setConnSearchPath(ctx context.Context, conn *sql.Conn, path string) error {
+	if db.sessions.getConn(conn) == path {
+		return nil
+	}
+
+	query := "RESET search_path"
+	if path != "" {
+		query = "SET search_path TO " + path
+	}
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	db.sessions.setConn(conn, path)
+	return nil
+}
+
+// discardConn closes the connection instead of returning it to the pool,
+// because it may still have the session settings changed. It blocks until the rows
+// read from the connection are closed.
+func discardConn(conn *sql.Conn) {
+	_ = conn.Raw(func(interface{}) error {
+		return driver.ErrBadConn
+	})
+}
+
+// errRow returns a Row that returns the error from Scan. database/sql can't create such rows,
+// so the query is executed with a pool that fails to connect with the error.
+// No connection is opened.
+func errRow(err error) *sql.Row {
+	db := sql.OpenDB(errConnector{err: err})
+	defer db.Close()
+	return db.QueryRow("")
+}
+
+type errConnector struct {
+	err error
+}
+
+func (c errConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, c.err
+}
+
+func (c errConnector) Driver() driver.Driver {
+	return errDriver(c)
+}
+
+type errDriver struct {
+	err error
+}
+
+func (d errDriver) Open(string) (driver.Conn, error) {
+	return nil, d.err
+}
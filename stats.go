@@ -0,0 +1,150 @@
+package bun
+
+import (
+	"database/sql"
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// Stats contains the connection pool stats and the stats of the queries executed with the DB.
+type Stats struct {
+	sql.DBStats
+
+	TotalQueries uint64
+	TotalErrors  uint64
+	// SlowQueries is the number of queries that took longer than the threshold
+	// set with WithSlowQueryThreshold.
+	SlowQueries uint64
+
+	MeanQueryDuration time.Duration
+	// P99QueryDuration is approximated with a histogram with a relative error of about 6%.
+	P99QueryDuration time.Duration
+}
+
+// WithSlowQueryThreshold sets the duration above which queries are counted
+// in Stats.SlowQueries. By default, no queries are counted as slow.
+func WithSlowQueryThreshold(d time.Duration) DBOption {
+	return func(db *DB) {
+		db.queryStats.slowThreshold = d
+	}
+}
+
+// Stats returns the connection pool stats and the stats of the queries executed with the DB.
+// It shadows sql.DB.Stats, whose stats are embedded in the result.
+func (db *DB) Stats() Stats {
+	stats := db.queryStats.stats()
+	stats.DBStats = db.DB.Stats()
+	return stats
+}
+
+// ResetStats zeroes the query stats returned by Stats and DBStats.
+// The connection pool stats are not affected.
+func (db *DB) ResetStats() {
+	db.queryStats.reset()
+}
+
+//------------------------------------------------------------------------------
+
+const (
+	// Each power of two is split into histSubBuckets buckets.
+	histSubBits    = 3
+	histSubBuckets = 1 << histSubBits
+	histBuckets    = (64 - histSubBits + 1) * histSubBuckets
+)
+
+// queryStats is shared by the DB clones that use the same connection pool.
+type queryStats struct {
+	slowThreshold time.Duration
+
+	queries  atomic.Uint64
+	errors   atomic.Uint64
+	slow     atomic.Uint64
+	duration atomic.Uint64
+
+	hist [histBuckets]atomic.Uint64
+}
+
+func (s *queryStats) record(dur time.Duration, err error) {
+	s.queries.Add(1)
+	switch err {
+	case nil, sql.ErrNoRows:
+		// nothing
+	default:
+		s.errors.Add(1)
+	}
+	if s.slowThreshold > 0 && dur > s.slowThreshold {
+		s.slow.Add(1)
+	}
+	if dur < 0 {
+		dur = 0
+	}
+	s.duration.Add(uint64(dur))
+	s.hist[histBucket(uint64(dur))].Add(1)
+}
+
+func (s *queryStats) stats() Stats {
+	stats := Stats{
+		TotalQueries: s.queries.Load(),
+		TotalErrors:  s.errors.Load(),
+		SlowQueries:  s.slow.Load(),
+	}
+	if stats.TotalQueries == 0 {
+		return stats
+	}
+
+	stats.MeanQueryDuration = time.Duration(s.duration.Load() / stats.TotalQueries)
+
+	var counts [histBuckets]uint64
+	var total uint64
+	for i := range s.hist {
+		counts[i] = s.hist[i].Load()
+		total += counts[i]
+	}
+
+	rank := (total*99 + 99) / 100
+	var n uint64
+	for i, count := range counts {
+		n += count
+		if n >= rank && count > 0 {
+			stats.P99QueryDuration = time.Duration(histValue(i))
+			break
+		}
+	}
+
+	return stats
+}
+
+func (s *queryStats) reset() {
+	s.queries.Store(0)
+	s.errors.Store(0)
+	s.slow.Store(0)
+	s.duration.Store(0)
+	for i := range s.hist {
+		s.hist[i].Store(0)
+	}
+}
+
+// histBucket returns the histogram bucket for the value. Values below histSubBuckets
+// have their own buckets and larger values are grouped by the power of two and
+// the next histSubBits bits.
+func histBucket(v uint64) int {
+	if v < histSubBuckets {
+		return int(v)
+	}
+	exp := bits.Len64(v) - 1
+	sub := (v >> (exp - histSubBits)) & (histSubBuckets - 1)
+	return (exp-histSubBits+1)*histSubBuckets + int(sub)
+}
+
+// histValue returns the middle of the values in the histogram bucket.
+func histValue(bucket int) uint64 {
+	if bucket < histSubBuckets {
+		return uint64(bucket)
+	}
+	exp := bucket/histSubBuckets + histSubBits - 1
+	sub := uint64(bucket % histSubBuckets)
+	lower := (histSubBuckets + sub) << (exp - histSubBits)
+	width := uint64(1) << (exp - histSubBits)
+	return lower + width/2
+}
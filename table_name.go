@@ -0,0 +1,82 @@
+package bun
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/uptrace/bun/schema"
+)
+
+type tableNameOverridesCtxKey struct{}
+
+type tableNameOverride struct {
+	typ  reflect.Type
+	name string
+}
+
+// WithTableNameOverride returns a context that makes queries use the table name instead of
+// the table name of the model, for example, to store data of each tenant in a separate table:
+//
+//	ctx = bun.WithTableNameOverride(ctx, (*Order)(nil), "tenant_42_orders")
+//	err := db.NewSelect().Model(&orders).Scan(ctx)
+//
+// The model can be a struct, a pointer to a struct, or a slice of structs.
+// The override also applies to relations that use the model and
+// the table alias stays the same.
+func WithTableNameOverride(ctx context.Context, model interface{}, tableName string) context.Context {
+	overrides := tableNameOverrides(ctx)
+	overrides = append(overrides[:len(overrides):len(overrides)], tableNameOverride{
+		typ:  modelType(model),
+		name: tableName,
+	})
+	return context.WithValue(ctx, tableNameOverridesCtxKey{}, overrides)
+}
+
+func tableNameOverrides(ctx context.Context) []tableNameOverride {
+	overrides, _ := ctx.Value(tableNameOverridesCtxKey{}).([]tableNameOverride)
+	return overrides
+}
+
+func modelType(model interface{}) reflect.Type {
+	typ := reflect.TypeOf(model)
+	for typ != nil {
+		switch typ.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			typ = typ.Elem()
+		default:
+			return typ
+		}
+	}
+	return typ
+}
+
+type dialectFormatterCtxKey struct{}
+
+// WithDialectFormatter returns a context that makes queries use the formatter
+// instead of the DB formatter, for example, to test how a query is quoted by another dialect:
+//
+//	ctx = bun.WithDialectFormatter(ctx, schema.NewFormatter(mysqldialect.New()))
+//
+// Only query formatting is affected, for example, bun.Ident arguments. Table and column names
+// of models are quoted by the DB dialect when the model is registered. The query builders
+// format the queries using the features of the formatter dialect, for example, RETURNING
+// or OUTPUT, but the queries are still executed by the DB. The named args set with
+// DB.WithNamedArg are used too.
+func WithDialectFormatter(ctx context.Context, fmter schema.Formatter) context.Context {
+	return context.WithValue(ctx, dialectFormatterCtxKey{}, fmter)
+}
+
+// formatter returns the DB formatter with the overrides from the context.
+func (db *DB) formatter(ctx context.Context) schema.Formatter {
+	fmter := db.fmter
+	if override, ok := ctx.Value(dialectFormatterCtxKey{}).(schema.Formatter); ok {
+		fmter = override.WithNamedArgsFrom(db.fmter)
+	}
+	for _, override := range tableNameOverrides(ctx) {
+		fmter = fmter.WithTableName(override.typ, override.name)
+	}
+	if key := encryptionKey(ctx); key != nil {
+		fmter = fmter.WithEncryptionKey(key)
+	}
+	return fmter
+}
@@ -0,0 +1,139 @@
+package bun
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/schema"
+)
+
+const (
+	temporalFromColumn = "valid_from"
+	temporalToColumn   = "valid_to"
+)
+
+// TemporalTable adds the validity period of a row version to a model:
+//
+//	type Price struct {
+//		bun.TemporalTable
+//
+//		ID    int64     `bun:",pk"`
+//		Value int64
+//	}
+//
+// A row version is valid from ValidFrom (inclusive) until ValidTo (exclusive).
+// Current versions have ValidTo set to 9999-12-31. Use SelectQuery.AsOf to select
+// the versions valid at the given time and CreateTableQuery.AsTemporalTable to let
+// the database keep the history.
+type TemporalTable struct {
+	ValidFrom time.Time `bun:"valid_from,notnull,default:current_timestamp"`
+	ValidTo   time.Time `bun:"valid_to,notnull,default:'9999-12-31 00:00:00'"`
+}
+
+var temporalTableType = reflect.TypeOf((*TemporalTable)(nil)).Elem()
+
+// withoutPeriodFields removes the valid_from and valid_to fields of the models that embed
+// TemporalTable on MSSQL, because system-versioned tables reject explicit period values.
+func (q *baseQuery) withoutPeriodFields(fields []*schema.Field) []*schema.Field {
+	if q.table == nil || q.db.dialect.Name() != dialect.MSSQL || !embedsTemporalTable(q.table.Type) {
+		return fields
+	}
+
+	filtered := make([]*schema.Field, 0, len(fields))
+	for _, f := range fields {
+		if f.Name == temporalFromColumn || f.Name == temporalToColumn {
+			continue
+		}
+		filtered = append(filtered, f)
+	}
+	return filtered
+}
+
+func embedsTemporalTable(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		if f := typ.Field(i); f.Anonymous && f.Type == temporalTableType {
+			return true
+		}
+	}
+	return false
+}
+
+func checkTemporalTable(table *schema.Table, dialectName dialect.Name) error {
+	if _, ok := table.FieldMap[temporalFromColumn]; !ok {
+		return errors.New("bun: temporal table requires valid_from and valid_to columns (embed bun.TemporalTable)")
+	}
+	if _, ok := table.FieldMap[temporalToColumn]; !ok {
+		return errors.New("bun: temporal table requires valid_from and valid_to columns (embed bun.TemporalTable)")
+	}
+
+	switch dialectName {
+	case dialect.MSSQL:
+		return nil
+	case dialect.PG:
+		// Old row versions are kept in the same table.
+		for _, pk := range table.PKs {
+			if pk.Name == temporalFromColumn {
+				return nil
+			}
+		}
+		return errors.New("bun: temporal table primary key must include valid_from")
+	default:
+		return fmt.Errorf("bun: temporal tables are not supported by %s", dialectName)
+	}
+}
+
+// mssqlHistoryTable returns the name of the history table for the system-versioned table.
+func mssqlHistoryTable(table string) string {
+	if !strings.Contains(table, ".") {
+		// MSSQL requires a schema-qualified history table name.
+		table = "dbo." + table
+	}
+	return table + "_history"
+}
+
+// appendPGTemporalFunction appends a trigger function that emulates system versioning:
+// UPDATE keeps a copy of the old row version that is valid until now and DELETE only ends
+// the validity of the current row version. Old row versions can be updated, but not deleted.
+// The function is used by a BEFORE UPDATE OR DELETE trigger and an AFTER UPDATE trigger.
+func appendPGTemporalFunction(b, funcName, tableName []byte) []byte {
+	b = append(b, "CREATE OR REPLACE FUNCTION "...)
+	b = append(b, funcName...)
+	b = append(b, `() RETURNS trigger AS $$
+BEGIN
+	IF TG_OP = 'DELETE' THEN
+		IF OLD.valid_to > clock_timestamp() THEN
+			UPDATE `...)
+	b = append(b, tableName...)
+	b = append(b, ` SET valid_to = clock_timestamp() WHERE ctid = OLD.ctid;
+		END IF;
+		RETURN NULL;
+	END IF;
+
+	-- Skip the UPDATE executed by the DELETE branch above.
+	IF pg_trigger_depth() > 1 OR OLD.valid_to <= clock_timestamp() THEN
+		RETURN NEW;
+	END IF;
+
+	-- The old row version is inserted after the update, when the current row version
+	-- has the new valid_from and doesn't collide with the old one on the primary key.
+	IF TG_WHEN = 'BEFORE' THEN
+		NEW.valid_from := clock_timestamp();
+		RETURN NEW;
+	END IF;
+
+	IF NEW.valid_from = OLD.valid_from THEN
+		RETURN NULL;
+	END IF;
+	OLD.valid_to := NEW.valid_from;
+	INSERT INTO `...)
+	b = append(b, tableName...)
+	b = append(b, ` VALUES (OLD.*);
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql`...)
+	return b
+}